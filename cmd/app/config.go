@@ -27,6 +27,8 @@ var (
 	httpServerShutdownTimeout = env.GetDuration("HTTP_SERVER_SHUTDOWN_TIMEOUT", time.Second*5)
 	httpRateLimit             = env.GetInt("HTTP_RATE_LIMIT", 100)
 	httpRateLimitDuration     = env.GetDuration("HTTP_RATE_LIMIT_DURATION", time.Minute)
+	httpTLSCertFile           = env.GetString("HTTP_TLS_CERT_FILE", "") // serve HTTPS and request the client certificates for mutual TLS
+	httpTLSKeyFile            = env.GetString("HTTP_TLS_KEY_FILE", "")
 
 	// Cors
 	corsAllowedOrigins     = env.GetStrings("CORS_ALLOWED_ORIGINS", ",", []string{"*"})
@@ -55,8 +57,25 @@ var (
 	queueMaxRetry     = env.GetInt("QUEUE_TASK_RETRY_LIMIT", 3)
 
 	// Auth
-	oauthSigningKey   = env.MustString("OAUTH_SIGNING_KEY")
-	authorizedHomeURI = env.GetString("AUTHORIZED_HOME_URI", "http://localhost:3000")
+	oauthSigningKey              = env.MustString("OAUTH_SIGNING_KEY")                               // secret to encrypt the signing keys and client secrets at rest
+	oauthSigningAlg              = env.GetString("OAUTH_SIGNING_ALG", "RS256")                       // RS256, ES256 or EdDSA, a change takes effect on the next key rotation
+	oauthKeyRotationInterval     = env.GetDuration("OAUTH_KEY_ROTATION_INTERVAL", time.Hour*24*30)   // how often the signing keys are rotated
+	oauthKeyRetention            = env.GetDuration("OAUTH_KEY_RETENTION", time.Hour*24*7)            // how long the retired keys are published
+	oauthIssuer                  = env.GetString("OAUTH_ISSUER", appBaseURL)                         // OpenID Connect issuer identifier
+	oauthDeviceCodeTTL           = env.GetDuration("OAUTH_DEVICE_CODE_TTL", time.Minute*10)          // device authorization request lifetime
+	oauthDevicePollInterval      = env.GetDuration("OAUTH_DEVICE_POLL_INTERVAL", time.Second*5)      // minimal interval between device token requests
+	oauthBackchannelAuthTTL      = env.GetDuration("OAUTH_BACKCHANNEL_AUTH_TTL", time.Minute*5)      // maximal lifetime of the backchannel authentication request
+	oauthBackchannelPollInterval = env.GetDuration("OAUTH_BACKCHANNEL_POLL_INTERVAL", time.Second*5) // minimal interval between backchannel token requests
+	oauthClientCAFile            = env.GetString("OAUTH_CLIENT_CA_FILE", "")                         // PEM encoded CAs which issue the tls_client_auth client certificates
+	oauthDPoPNonceTTL            = env.GetDuration("OAUTH_DPOP_NONCE_TTL", time.Minute*5)            // how long the DPoP server nonce is accepted
+	oauthPushedRequestTTL        = env.GetDuration("OAUTH_PAR_TTL", time.Second*60)                  // lifetime of the pushed authorization request_uri
+	oauthRequirePAR              = env.GetBool("OAUTH_REQUIRE_PAR", false)                           // all clients must use the pushed authorization requests
+	oauthRegistrationToken       = env.GetString("OAUTH_REGISTRATION_TOKEN", "")                     // initial access token of the dynamic client registration, required unless the open registration is enabled
+	oauthOpenRegistration        = env.GetBool("OAUTH_OPEN_REGISTRATION", false)                     // allows the dynamic client registration without the initial access token
+	oauthAccessTokenAudience     = env.GetString("OAUTH_ACCESS_TOKEN_AUDIENCE", appBaseURL+"/api")   // default aud claim of the access tokens, the API accepts only the tokens aimed at it
+	oauthScopeCacheTTL           = env.GetDuration("OAUTH_SCOPE_CACHE_TTL", time.Minute)             // how long the registered scopes are cached by the server instance
+	oauthAdminToken              = env.GetString("OAUTH_ADMIN_TOKEN", "")                            // bearer token of the admin API, the API is disabled if empty
+	authorizedHomeURI            = env.GetString("AUTHORIZED_HOME_URI", "http://localhost:3000")
 
 	// Postmark
 	postmarkServerToken  = env.MustString("POSTMARK_SERVER_TOKEN")
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -59,7 +60,9 @@ func initRouter(log *logrus.Entry) *chi.Mux {
 }
 
 // Run HTTP server
-func runServer(ctx context.Context, httpPort int, router http.Handler, log *logrus.Entry) func() error {
+// The server is run over TLS if the certificate is set, the client certificates are requested
+// but not verified by the server: the client authentication verifies them.
+func runServer(ctx context.Context, httpPort int, router http.Handler, certFile, keyFile string, log *logrus.Entry) func() error {
 	return func() error {
 		log = log.WithField("port", httpPort)
 		log.Info("Starting HTTP server")
@@ -85,7 +88,15 @@ func runServer(ctx context.Context, httpPort int, router http.Handler, log *logr
 		}()
 
 		// Run the server
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		listen := httpServer.ListenAndServe
+		if certFile != "" {
+			httpServer.TLSConfig = &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				ClientAuth: tls.RequestClientCert,
+			}
+			listen = func() error { return httpServer.ListenAndServeTLS(certFile, keyFile) }
+		}
+		if err := listen(); err != nil && err != http.ErrServerClosed {
 			return fmt.Errorf("HTTP server shut down with an error: %w", err)
 		}
 
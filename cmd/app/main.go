@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
 	"os"
@@ -11,17 +12,20 @@ import (
 
 	"github.com/dmitrymomot/oauth2-server/internal/mdw"
 	postmarkClient "github.com/dmitrymomot/oauth2-server/internal/postmark"
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
 	"github.com/dmitrymomot/oauth2-server/lib/middleware"
 	"github.com/dmitrymomot/oauth2-server/repository"
 	"github.com/dmitrymomot/oauth2-server/svc/api/client"
+	"github.com/dmitrymomot/oauth2-server/svc/api/scope"
+	"github.com/dmitrymomot/oauth2-server/svc/api/token"
 	"github.com/dmitrymomot/oauth2-server/svc/api/user"
 	"github.com/dmitrymomot/oauth2-server/svc/auth"
+	"github.com/dmitrymomot/oauth2-server/svc/keystore"
 	"github.com/dmitrymomot/oauth2-server/svc/mailer"
 	"github.com/dmitrymomot/oauth2-server/svc/oauth"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-oauth2/oauth2/v4/generates"
 	"github.com/go-redis/redis/v8"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/hibiken/asynq"
 	"github.com/keighl/postmark"
 	_ "github.com/lib/pq" // init pg driver
@@ -40,6 +44,16 @@ func main() {
 	// Errgroup with context
 	eg, ctx := errgroup.WithContext(newCtx(logger))
 
+	// The dynamic client registration is open only if it's enabled explicitly
+	if oauthRegistrationToken == "" && !oauthOpenRegistration {
+		logger.Fatal("OAUTH_REGISTRATION_TOKEN must be set unless OAUTH_OPEN_REGISTRATION is enabled")
+	}
+
+	// The API accepts only the access tokens aimed at it
+	if oauthAccessTokenAudience == "" {
+		logger.Fatal("OAUTH_ACCESS_TOKEN_AUDIENCE must not be empty")
+	}
+
 	// Init DB connection
 	db, err := sql.Open("postgres", dbConnString)
 	if err != nil {
@@ -60,8 +74,27 @@ func main() {
 		logger.WithError(err).Fatal("Failed to init repository")
 	}
 
+	// Init signing keys store
+	keyStore, err := keystore.NewStore(
+		repo, db, oauthSigningKey,
+		keystore.WithAlgorithm(oauthSigningAlg),
+		keystore.WithRetention(oauthKeyRetention),
+	)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to init signing keys store")
+	}
+	if err := keyStore.Init(ctx); err != nil {
+		logger.WithError(err).Fatal("Failed to init signing keys")
+	}
+
+	// The logout tokens are posted to the back-channel logout clients directly,
+	// or by the queue worker with retries if redis is configured
+	backchannelLogout := oauth.NewBackchannelLogout(repo, oauthIssuer, keyStore)
+	var logoutNotifier oauth.BackchannelLogoutNotifier = backchannelLogout
+
 	// mail enqueuer
 	var mailEnqueuer *mailer.Enqueuer
+	var redisClient *redis.Client
 	if redisConnString != "" {
 		// Redis connect options for asynq client
 		redisConnOpt, err := asynq.ParseRedisURI(redisConnString)
@@ -69,7 +102,8 @@ func main() {
 			logger.WithError(err).Fatal("Failed to parse redis connection string")
 		}
 		redisConn := redisConnOpt.MakeRedisClient()
-		redisClient, ok := redisConn.(*redis.Client)
+		var ok bool
+		redisClient, ok = redisConn.(*redis.Client)
 		if !ok {
 			logger.Fatal("Failed to cast redis connection to *redis.Client")
 		}
@@ -90,6 +124,9 @@ func main() {
 			mailer.WithTaskDeadline(queueTaskDeadline),
 		)
 
+		// Back-channel logout enqueuer
+		logoutNotifier = oauth.NewLogoutEnqueuer(asynqClient, queueName, queueMaxRetry)
+
 		baseURL := strings.TrimSuffix(appBaseURL, "/")
 		if baseURL == "" || baseURL == "/" || !strings.HasPrefix(baseURL, "http") {
 			logger.Fatal("Application base URL is invalid")
@@ -113,18 +150,33 @@ func main() {
 			},
 		)
 
+		// Signing keys rotation worker
+		keysWorker := keystore.NewWorker(
+			keyStore,
+			logger.WithField("component", "keys-worker"),
+			queueName,
+			oauthKeyRotationInterval,
+		)
+
+		// Expired auth data clean up worker
+		authWorker := auth.NewWorker(repo, logger.WithField("component", "auth-worker"))
+
 		// Run asynq worker
 		eg.Go(runQueueServer(
 			redisConnOpt,
 			logger.WithField("component", "queue-worker"),
 			mailer.NewWorker(pc),
+			keysWorker,
+			authWorker,
+			oauth.NewLogoutWorker(backchannelLogout, queueName),
 		))
 
 		// Run asynq scheduler
 		eg.Go(runScheduler(
 			redisConnOpt,
 			logger.WithField("component", "scheduler"),
-			auth.NewWorker(repo, logger.WithField("component", "auth-worker")),
+			authWorker,
+			keysWorker,
 		))
 	} else {
 		logger.Warn("Redis connection string is empty, skipping asynq client")
@@ -133,35 +185,193 @@ func main() {
 	// Init HTTP router
 	r := initRouter(logger.WithField("component", "http-router"))
 
+	// DPoP proof IDs are shared between the server instances in redis,
+	// the single instance keeps them in memory
+	var dpopReplay dpop.ReplayCache = dpop.NewMemoryReplayCache()
+	if redisClient != nil {
+		dpopReplay = oauth.NewRedisReplayCache(redisClient, "dpop:")
+	}
+
+	// Scopes which can be granted by the server, managed with the scope API
+	scopeRegistry := oauth.NewScopeRegistry(repo, oauth.WithScopeRegistryCacheTTL(oauthScopeCacheTTL))
+
+	// Tokens are revoked by the clients, the users and the server administrator
+	tokenRevocation := oauth.NewTokenRevocation(repo)
+
+	// Client service is shared by the client API and the dynamic client registration,
+	// the clients registered without the initial access token are limited
+	clientOpts := []client.ServiceOption{}
+	if oauthRegistrationToken == "" {
+		clientOpts = append(clientOpts, client.WithOpenRegistration())
+	}
+	clientService := client.NewService(repo, scopeRegistry, oauthSigningKey, clientOpts...)
+
+	// The scopes approved by the user are remembered on the consent page and on the device approval page
+	consentManager := oauth.NewConsentManager(repo, oauth.WithConsentScopeRegistry(scopeRegistry))
+
+	// The ping mode CIBA clients are notified when the user approves the request on the auth service page
+	authOpts := []auth.ServiceOption{auth.WithConsentManager(consentManager)}
+
 	// Mount oauth2 server
 	{
-		storage := oauth.NewStore(repo)
+		storage := oauth.NewStore(repo, oauth.WithStoreLogger(logger.WithField("component", "oauth2-store")))
+		idTokenGen := oauth.NewIDTokenGenerator(oauthIssuer, keyStore)
+		oauthHandler := oauth.NewHandlerLogger(
+			oauth.NewHandler(
+				repo,
+				oauth.WithScopeRegistry(scopeRegistry),
+				oauth.WithIDTokenGenerator(idTokenGen),
+				oauth.WithHandlerLogger(logger.WithField("component", "oauth2-handler")),
+			),
+			logger.WithField("component", "oauth2"),
+		)
 		srv, manager := oauth.NewOauth2Server(
-			generates.NewJWTAccessGenerate("", []byte(oauthSigningKey), jwt.SigningMethodHS512),
+			oauth.NewJWTAccessGenerate(
+				keyStore,
+				oauth.WithAccessTokenIssuer(oauthIssuer),
+				oauth.WithAccessTokenAudience(oauthAccessTokenAudience),
+			),
 			generates.NewAuthorizeGenerate(),
 			storage, storage,
-			oauth.NewHandlerLogger(
-				oauth.NewHandler(
-					repo,
-					oauth.WithClientScope("user:read client:read"),
-					oauth.WithPasswordScope("user:*"),
-					oauth.WithCodeScope("user:* client:*"),
-				),
-				logger.WithField("component", "oauth2"),
-			),
+			oauthHandler,
+		)
+
+		// Device authorization grant, the user enters the code on the auth service page
+		srv.RegisterGrant(oauth.NewDeviceGrant(
+			repo, manager,
+			strings.TrimSuffix(appBaseURL, "/")+"/auth/device",
+			oauth.WithDeviceCodeTTL(oauthDeviceCodeTTL),
+			oauth.WithDevicePollInterval(oauthDevicePollInterval),
+		))
+		// Client initiated backchannel authentication, the user gets the approval link by email
+		if mailEnqueuer != nil {
+			cibaGrant := oauth.NewBackchannelAuthGrant(
+				repo, manager,
+				oauth.NewMailNotifier(mailEnqueuer),
+				strings.TrimSuffix(appBaseURL, "/")+"/auth/backchannel",
+				oauth.WithBackchannelAuthTTL(oauthBackchannelAuthTTL),
+				oauth.WithBackchannelPollInterval(oauthBackchannelPollInterval),
+				oauth.WithBackchannelLogger(logger.WithField("component", "oauth2-ciba")),
+			)
+			srv.RegisterGrant(cibaGrant)
+			authOpts = append(authOpts, auth.WithBackchannelNotifier(cibaGrant))
+		}
+		// Token exchange grant, allowed by the client token exchange policy
+		srv.RegisterGrant(oauth.NewTokenExchangeGrant(repo, manager))
+
+		// The assertions are accepted if they are aimed at the token endpoint or the issuer,
+		// the assertion IDs are stored in redis to prevent the replay,
+		// the single instance keeps the JWT bearer assertion IDs in memory as the DPoP proof IDs
+		assertionAudience := []string{strings.TrimSuffix(appBaseURL, "/") + "/oauth" + oauth.TokenPath, oauthIssuer}
+		clientAuthOpts := []oauth.ClientAuthenticatorOption{}
+		var jwtBearerReplay dpop.ReplayCache = dpop.NewMemoryReplayCache()
+		if redisClient != nil {
+			clientAuthOpts = append(clientAuthOpts, oauth.WithReplayCache(oauth.NewRedisReplayCache(redisClient, "jti:")))
+			jwtBearerReplay = oauth.NewRedisReplayCache(redisClient, "jwt-bearer:")
+		}
+		if oauthClientCAFile != "" {
+			pem, err := os.ReadFile(oauthClientCAFile)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to read client CA file")
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(pem) {
+				logger.Fatal("Client CA file has no certificates")
+			}
+			clientAuthOpts = append(clientAuthOpts, oauth.WithClientCAs(clientCAs))
+		}
+
+		// JWT bearer grant, the assertion is issued by a trusted issuer for the local user
+		srv.RegisterGrant(oauth.NewJWTBearerGrant(repo, manager, assertionAudience, oauth.WithJWTBearerReplayCache(jwtBearerReplay)))
+
+		// Client authentication with the method registered for the client,
+		// the tls_client_auth certificates are verified with the client CAs
+		clientAuth := oauth.NewClientAuthenticator(
+			repo, oauthSigningKey, assertionAudience,
+			clientAuthOpts...,
+		)
+		srv.SetClientInfoHandler(clientAuth.ClientInfoHandler)
+
+		// Signed request objects are verified with the client keys,
+		// the authorization responses in the jwt response modes are signed with the server key
+		srv.SetRequestObjectVerifier(oauth.NewRequestObjectVerifier(repo, clientAuth, oauthIssuer))
+		srv.SetResponseSigner(oauth.NewResponseSigner(oauthIssuer, keyStore))
+
+		// Access tokens restricted to the registered resource servers
+		srv.SetResourceIndicators(oauth.NewResourceIndicators(repo))
+
+		// Token introspection for the clients and the resource servers linked to them,
+		// the signed responses are aimed at the caller
+		srv.SetTokenIntrospection(oauth.NewTokenIntrospection(
+			repo,
+			oauthIssuer,
+			keyStore,
+			oauth.WithIntrospectionAudience(oauthAccessTokenAudience),
+		))
+
+		// Token revocation by the clients the tokens are issued to
+		srv.SetTokenRevocation(tokenRevocation)
+
+		// RP-initiated logout, the clients the user has signed in to are notified
+		srv.SetLogout(oauth.NewLogout(
+			repo, oauthIssuer, keyStore, logoutNotifier,
+			oauth.WithLogoutLogger(logger.WithField("component", "oauth2-logout")),
+		))
+
+		// DPoP-bound tokens, the proofs must contain the server nonce
+		dpopVerifier := oauth.NewDPoPVerifier(
+			strings.TrimSuffix(appBaseURL, "/")+"/oauth"+oauth.TokenPath,
+			dpop.NewNonces(oauthSigningKey, oauthDPoPNonceTTL),
+			dpopReplay,
 		)
+		srv.SetDPoPVerifier(dpopVerifier)
+
+		// Pushed authorization requests, mandatory for all clients if required
+		srv.SetPushedAuthorizations(oauth.NewPushedAuthorizations(
+			repo,
+			oauth.WithPushedRequestTTL(oauthPushedRequestTTL),
+			oauth.WithPushedAuthorizationRequired(oauthRequirePAR),
+		))
+
+		// Dynamic client registration, gated by the initial access token unless it's open
+		registrationURI := strings.TrimSuffix(appBaseURL, "/") + "/oauth" + oauth.RegistrationPath
+		r.Mount("/oauth"+oauth.RegistrationPath, client.MakeRegistrationHTTPHandler(
+			client.MakeRegistrationEndpoints(clientService, registrationURI, oauthRegistrationToken),
+			logger.WithField("component", "client-registration"),
+		))
 
 		r.Mount("/oauth", oauth.MakeHTTPHandler(
 			srv,
 			manager,
+			repo,
+			consentManager,
 			logger.WithField("component", "oauth2"),
 			"/auth/login",
+			oauth.WithUserInfoDPoP(dpopVerifier, strings.TrimSuffix(appBaseURL, "/")+"/oauth"+oauth.UserInfoPath),
+		))
+
+		// Authorization server metadata, OpenID Connect discovery and public signing keys
+		serverMetadata := oauth.NewServerMetadata(
+			oauthIssuer,
+			strings.TrimSuffix(appBaseURL, "/")+"/oauth",
+			srv.Config,
+		)
+		serverMetadata.RequirePushedAuthorizationRequests = oauthRequirePAR
+		// the tokens are bound to the client certificate requested by the TLS listener
+		serverMetadata.TLSClientCertificateBoundAccessTokens = httpTLSCertFile != ""
+		serverMetadata.RegistrationEndpoint = registrationURI
+		serverMetadata.AuthorizationDetailsTypesSupported = srv.AuthorizationDetailsTypes()
+		r.Mount(oauth.WellKnownPath, oauth.MakeDiscoveryHTTPHandler(
+			oauth.NewOpenIDConfiguration(serverMetadata, keyStore.Algorithm()),
+			keyStore,
+			scopeRegistry,
+			logger.WithField("component", "discovery"),
 		))
 	}
 
 	// Mount auth service
 	r.Mount("/auth", auth.MakeHTTPHandler(
-		auth.NewService(repo, db, mailEnqueuer),
+		auth.NewService(repo, db, mailEnqueuer, authOpts...),
 		"/oauth/authorize",
 		mdw.NotAuthOnly(authorizedHomeURI),
 	))
@@ -170,9 +380,14 @@ func main() {
 	r.Route("/api", func(api chi.Router) {
 		api.Mount("/user", user.MakeHTTPHandler(
 			user.MakeEndpoints(
-				user.NewService(repo, mailEnqueuer, db),
+				user.NewService(repo, mailEnqueuer, db, tokenRevocation),
 				middleware.GokitAuthMiddleware(
-					middleware.VerifyJWT(oauthSigningKey),
+					middleware.VerifyJWTWithKeys(
+						keyStore,
+						middleware.WithIssuer(oauthIssuer),
+						middleware.WithAudience(oauthAccessTokenAudience),
+					),
+					middleware.WithDPoP(dpopReplay),
 				),
 			),
 			logger.WithField("component", "api-user"),
@@ -180,17 +395,32 @@ func main() {
 
 		api.Mount("/client", client.MakeHTTPHandler(
 			client.MakeEndpoints(
-				client.NewService(repo),
+				clientService,
 				middleware.GokitAuthMiddleware(
-					middleware.VerifyJWT(oauthSigningKey),
+					middleware.VerifyJWTWithKeys(
+						keyStore,
+						middleware.WithIssuer(oauthIssuer),
+						middleware.WithAudience(oauthAccessTokenAudience),
+					),
+					middleware.WithDPoP(dpopReplay),
 				),
 			),
 			logger.WithField("component", "api-client"),
 		))
+
+		api.Mount("/scope", scope.MakeHTTPHandler(
+			scope.MakeEndpoints(scope.NewService(repo), oauthAdminToken),
+			logger.WithField("component", "api-scope"),
+		))
+
+		api.Mount("/token", token.MakeHTTPHandler(
+			token.MakeEndpoints(token.NewService(tokenRevocation), oauthAdminToken),
+			logger.WithField("component", "api-token"),
+		))
 	})
 
 	// Run HTTP server
-	eg.Go(runServer(ctx, httpPort, r, logger.WithField("component", "http-server")))
+	eg.Go(runServer(ctx, httpPort, r, httpTLSCertFile, httpTLSKeyFile, logger.WithField("component", "http-server")))
 
 	// Run all goroutines
 	if err := eg.Wait(); err != nil {
@@ -9,20 +9,37 @@ type (
 	taskHandler interface {
 		Register(*asynq.ServeMux)
 	}
+
+	// queuesProvider is implemented by task handlers
+	// which process tasks from their own queues.
+	queuesProvider interface {
+		Queues() []string
+	}
 )
 
 // setupQueue creates a new queue client and registers task handlers.
 func runQueueServer(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, handlers ...taskHandler) func() error {
 	return func() error {
+		queues := map[string]int{
+			queueName: workerConcurrency,
+		}
+		for _, h := range handlers {
+			if qp, ok := h.(queuesProvider); ok {
+				for _, q := range qp.Queues() {
+					if _, ok := queues[q]; !ok {
+						queues[q] = 1
+					}
+				}
+			}
+		}
+
 		// Setup asynq server
 		srv := asynq.NewServer(
 			redisConnOpt,
 			asynq.Config{
 				Concurrency: workerConcurrency,
 				Logger:      log,
-				Queues: map[string]int{
-					queueName: workerConcurrency,
-				},
+				Queues:      queues,
 			},
 		)
 
@@ -7,12 +7,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
 	_ "github.com/lib/pq"                 // init pg driver
 
 	"github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
 	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
 	"github.com/dmitrymomot/random"
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -35,12 +38,61 @@ var newClientCmd = &cobra.Command{
 		}
 
 		isPublic, _ := cmd.Flags().GetBool("public")
+		redirectURIs, _ := cmd.Flags().GetStringSlice("redirect_uri")
+		requirePAR, _ := cmd.Flags().GetBool("require_par")
+		implicit, _ := cmd.Flags().GetBool("implicit")
+		responseModes, _ := cmd.Flags().GetStringSlice("response_mode")
+		postLogoutRedirectURIs, _ := cmd.Flags().GetStringSlice("post_logout_redirect_uri")
+		accessTokenTTL, _ := cmd.Flags().GetDuration("access_token_ttl")
+		refreshTokenTTL, _ := cmd.Flags().GetDuration("refresh_token_ttl")
+		codeTTL, _ := cmd.Flags().GetDuration("code_ttl")
+		noRefreshTokens, _ := cmd.Flags().GetBool("no_refresh_tokens")
+
+		refreshExpiry := cmd.Flag("refresh_token_expiry").Value.String()
+		if refreshExpiry == "" {
+			refreshExpiry = "absolute"
+		}
+
+		bcMode := cmd.Flag("backchannel_mode").Value.String()
+		if bcMode == "" {
+			bcMode = "poll"
+		}
+
+		authMethod := cmd.Flag("auth_method").Value.String()
+		if authMethod == "" {
+			authMethod = oauth.DefaultClientAuthMethod
+			if isPublic {
+				authMethod = "none"
+			}
+		}
 
 		clientID, clientSecret, err := createNewClient(
 			connStr,
 			isPublic,
+			cmd.Flag("name").Value.String(),
 			cmd.Flag("domain").Value.String(),
 			cmd.Flag("user_id").Value.String(),
+			redirectURIs,
+			authMethod,
+			cmd.Flag("jwks").Value.String(),
+			cmd.Flag("jwks_uri").Value.String(),
+			cmd.Flag("tls_subject_dn").Value.String(),
+			requirePAR,
+			implicit,
+			responseModes,
+			bcMode,
+			cmd.Flag("backchannel_endpoint").Value.String(),
+			postLogoutRedirectURIs,
+			cmd.Flag("frontchannel_logout_uri").Value.String(),
+			cmd.Flag("backchannel_logout_uri").Value.String(),
+			cmd.Flag("scope").Value.String(),
+			repository.UpdateClientTokenLifetimesParams{
+				AccessTokenTtl:     int64(accessTokenTTL.Seconds()),
+				RefreshTokenTtl:    int64(refreshTokenTTL.Seconds()),
+				CodeTtl:            int64(codeTTL.Seconds()),
+				IssueRefreshTokens: !noRefreshTokens,
+				RefreshTokenExpiry: repository.RefreshTokenExpiry(refreshExpiry),
+			},
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create new client: %w", err)
@@ -62,11 +114,58 @@ func init() {
 	rootCmd.AddCommand(newClientCmd)
 	newClientCmd.Flags().BoolP("public", "t", false, "Is the client public?")
 	newClientCmd.Flags().String("db", "", "Database connection string")
+	newClientCmd.Flags().StringP("name", "n", "", "Client name displayed on the consent page")
 	newClientCmd.Flags().StringP("domain", "d", "", "Client domain")
 	newClientCmd.Flags().StringP("user_id", "u", "", "User ID")
+	newClientCmd.Flags().StringSliceP("redirect_uri", "r", nil, "Registered redirect URI, can be repeated")
+	newClientCmd.Flags().StringP("auth_method", "a", "", "Token endpoint auth method: client_secret_basic, client_secret_post, client_secret_jwt, private_key_jwt, tls_client_auth, self_signed_tls_client_auth or none")
+	newClientCmd.Flags().String("jwks", "", "Public JSON Web Key Set of the private_key_jwt or self_signed_tls_client_auth client")
+	newClientCmd.Flags().String("jwks_uri", "", "Public JSON Web Key Set URI of the private_key_jwt or self_signed_tls_client_auth client")
+	newClientCmd.Flags().String("tls_subject_dn", "", "Subject DN of the tls_client_auth client certificate, e.g. CN=client.example.com,O=Example")
+	newClientCmd.Flags().Bool("require_par", false, "The client must use the pushed authorization requests")
+	newClientCmd.Flags().Bool("implicit", false, "Enable the implicit grant for the client")
+	newClientCmd.Flags().StringSlice("response_mode", nil, "Response mode the client may use, can be repeated, all supported modes if not set")
+	newClientCmd.Flags().String("backchannel_mode", "", "Backchannel token delivery mode of the CIBA client: poll or ping")
+	newClientCmd.Flags().String("backchannel_endpoint", "", "Client notification endpoint of the ping mode CIBA client")
+	newClientCmd.Flags().StringSlice("post_logout_redirect_uri", nil, "Registered post logout redirect URI, can be repeated")
+	newClientCmd.Flags().String("frontchannel_logout_uri", "", "URI loaded in the iframe when the user logs out")
+	newClientCmd.Flags().String("backchannel_logout_uri", "", "URI the logout token is posted to when the user logs out")
+	newClientCmd.Flags().StringP("scope", "s", "", "Scopes the client can request, the default scopes if empty")
+	newClientCmd.Flags().Duration("access_token_ttl", 0, "Access token lifetime, e.g. 15m, the server default if zero")
+	newClientCmd.Flags().Duration("refresh_token_ttl", 0, "Refresh token lifetime, e.g. 720h, the server default if zero")
+	newClientCmd.Flags().Duration("code_ttl", 0, "Authorization code lifetime, e.g. 1m, the server default if zero")
+	newClientCmd.Flags().Bool("no_refresh_tokens", false, "Don't issue the refresh tokens to the client")
+	newClientCmd.Flags().String("refresh_token_expiry", "", "Refresh token expiry: absolute since the authorization or idle since the last refresh")
 }
 
-func createNewClient(dbConnString string, public bool, domain, userID string) (id, secret string, err error) {
+func createNewClient(dbConnString string, public bool, name, domain, userID string, redirectURIs []string, authMethod, jwks, jwksURI, tlsSubjectDN string, requirePAR, implicit bool, responseModes []string, bcMode, bcEndpoint string, postLogoutRedirectURIs []string, frontchannelLogoutURI, backchannelLogoutURI, scope string, lifetimes repository.UpdateClientTokenLifetimesParams) (id, secret string, err error) {
+	if (authMethod == "private_key_jwt" || authMethod == "self_signed_tls_client_auth") && jwks == "" && jwksURI == "" {
+		return "", "", fmt.Errorf("jwks or jwks_uri is required for %s client", authMethod)
+	}
+	if jwksURI != "" && !strings.HasPrefix(jwksURI, "https://") {
+		return "", "", fmt.Errorf("jwks_uri must be an https url")
+	}
+	if authMethod == "tls_client_auth" && tlsSubjectDN == "" {
+		return "", "", fmt.Errorf("tls_subject_dn is required for tls_client_auth client")
+	}
+	for _, mode := range responseModes {
+		if !isSupportedResponseMode(mode) {
+			return "", "", fmt.Errorf("unsupported response mode: %s", mode)
+		}
+	}
+	if bcMode != "poll" && bcMode != "ping" {
+		return "", "", fmt.Errorf("unsupported backchannel token delivery mode: %s", bcMode)
+	}
+	if bcMode == "ping" && bcEndpoint == "" {
+		return "", "", fmt.Errorf("backchannel_endpoint is required for ping mode client")
+	}
+	if lifetimes.RefreshTokenExpiry != repository.RefreshTokenExpiryAbsolute && lifetimes.RefreshTokenExpiry != repository.RefreshTokenExpiryIdle {
+		return "", "", fmt.Errorf("unsupported refresh token expiry: %s", lifetimes.RefreshTokenExpiry)
+	}
+	if lifetimes.AccessTokenTtl < 0 || lifetimes.RefreshTokenTtl < 0 || lifetimes.CodeTtl < 0 {
+		return "", "", fmt.Errorf("token lifetimes must not be negative")
+	}
+
 	// Init DB connection
 	db, err := sql.Open("postgres", dbConnString)
 	if err != nil {
@@ -86,6 +185,17 @@ func createNewClient(dbConnString string, public bool, domain, userID string) (i
 		return "", "", fmt.Errorf("failed to prepare repository: %w", err)
 	}
 
+	// the client can request the default scopes if the scope is omitted, as the clients created with the API
+	scopes := oauth.NewScopeRegistry(repo)
+	if scope = strings.Join(strings.Fields(scope), " "); scope == "" {
+		scope, err = scopes.ClientScope(ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get default scopes: %w", err)
+		}
+	} else if err := scopes.Validate(ctx, scope, true); err != nil {
+		return "", "", fmt.Errorf("invalid scope: %w", err)
+	}
+
 	clientID := fmt.Sprintf("id_%s", random.String(32))
 	clientSecret := fmt.Sprintf("secret_%s", random.String(32))
 
@@ -99,24 +209,84 @@ func createNewClient(dbConnString string, public bool, domain, userID string) (i
 		return "", "", fmt.Errorf("failed to parse user id: %w", err)
 	}
 
+	// client_secret_jwt client secret is needed to verify the client assertion
+	var encryptedSecret []byte
+	if authMethod == "client_secret_jwt" {
+		encryptionKey := env.GetString("OAUTH_SIGNING_KEY", "")
+		if encryptionKey == "" {
+			return "", "", fmt.Errorf("OAUTH_SIGNING_KEY is required to encrypt the client secret")
+		}
+		if encryptedSecret, err = utils.Encrypt([]byte(clientSecret), []byte(encryptionKey)); err != nil {
+			return "", "", fmt.Errorf("failed to encrypt client secret: %w", err)
+		}
+	}
+
+	allowedGrants := []string{
+		"authorization_code",
+		"refresh_token",
+		"password",
+		"client_credentials",
+		"urn:ietf:params:oauth:grant-type:device_code",
+		"urn:openid:params:grant-type:ciba",
+	}
+	if implicit {
+		allowedGrants = append(allowedGrants, "__implicit")
+	}
+
 	// Create client
 	if _, err := repo.CreateClient(ctx, repository.CreateClientParams{
-		ID:       clientID,
-		Secret:   clientSecretHash,
-		Domain:   domain,
-		IsPublic: public,
-		UserID:   uid,
-		AllowedGrants: []string{
-			"authorization_code",
-			"refresh_token",
-			"password",
-			"client_credentials",
-			"__implicit",
-		},
-		Scope: "client:* user:*",
+		ID:            clientID,
+		Name:          name,
+		Secret:        clientSecretHash,
+		Domain:        domain,
+		IsPublic:      public,
+		UserID:        uuid.NullUUID{UUID: uid, Valid: true},
+		AllowedGrants: allowedGrants,
+		Scope:         scope,
+		RedirectUris:  redirectURIs,
+
+		TokenEndpointAuthMethod: authMethod,
+		Jwks:                    jwks,
+		JwksUri:                 jwksURI,
+		TlsClientAuthSubjectDn:  tlsSubjectDN,
+		EncryptedSecret:         encryptedSecret,
+
+		RequirePushedAuthorizationRequests: requirePAR,
+
+		BackchannelTokenDeliveryMode:          bcMode,
+		BackchannelClientNotificationEndpoint: bcEndpoint,
+
+		PostLogoutRedirectUris: append([]string{}, postLogoutRedirectURIs...),
+		FrontchannelLogoutUri:  frontchannelLogoutURI,
+		BackchannelLogoutUri:   backchannelLogoutURI,
 	}); err != nil {
 		return "", "", fmt.Errorf("failed to create client: %w", err)
 	}
 
+	// the zero lifetimes fall back to the server defaults
+	lifetimes.ID = clientID
+	if _, err := repo.UpdateClientTokenLifetimes(ctx, lifetimes); err != nil {
+		return "", "", fmt.Errorf("failed to update client token lifetimes: %w", err)
+	}
+
+	if len(responseModes) > 0 {
+		if _, err := repo.UpdateClientResponseModes(ctx, repository.UpdateClientResponseModesParams{
+			ID:            clientID,
+			ResponseModes: responseModes,
+		}); err != nil {
+			return "", "", fmt.Errorf("failed to update client response modes: %w", err)
+		}
+	}
+
 	return clientID, clientSecret, nil
 }
+
+// isSupportedResponseMode checks the response mode is one of the plain or jwt response modes
+func isSupportedResponseMode(mode string) bool {
+	for _, m := range append(oauth.ResponseModes, oauth.JWTResponseModes...) {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
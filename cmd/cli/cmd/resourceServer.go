@@ -0,0 +1,122 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
+	_ "github.com/lib/pq"                 // init pg driver
+
+	"github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// resourceServerCmd represents the resourceServer command
+var resourceServerCmd = &cobra.Command{
+	Use:   "resource-server",
+	Short: "Register a resource server the access tokens can be restricted to",
+	Long: `Register the resource server identifier, which the clients pass in the resource parameter
+of the authorization and token requests, and the scopes of the tokens issued for it.
+The access token aud claim is set to the requested resource server identifiers.
+The resource server authenticates on the introspection endpoint as the client set with --client_id.
+Pass the --delete flag to remove the resource server.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connStr := cmd.Flag("db").Value.String()
+		if connStr == "" {
+			connStr = env.GetString("DATABASE_URL", "")
+			if connStr == "" {
+				return fmt.Errorf("db connection string is required")
+			}
+		}
+
+		del, _ := cmd.Flags().GetBool("delete")
+		if err := setResourceServer(
+			connStr,
+			cmd.Flag("identifier").Value.String(),
+			cmd.Flag("name").Value.String(),
+			cmd.Flag("scope").Value.String(),
+			cmd.Flag("client_id").Value.String(),
+			del,
+		); err != nil {
+			return fmt.Errorf("failed to set resource server: %w", err)
+		}
+
+		if del {
+			color.Green("\nResource server is deleted")
+		} else {
+			color.Green("\nResource server is registered")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resourceServerCmd)
+	resourceServerCmd.Flags().String("db", "", "Database connection string")
+	resourceServerCmd.Flags().StringP("identifier", "i", "", "Resource server identifier, an absolute URI without a fragment")
+	resourceServerCmd.Flags().StringP("name", "n", "", "Resource server name")
+	resourceServerCmd.Flags().StringP("scope", "s", "", "Scopes of the tokens issued for the resource server")
+	resourceServerCmd.Flags().StringP("client_id", "c", "", "Client ID the resource server introspects the tokens with")
+	resourceServerCmd.Flags().Bool("delete", false, "Delete the resource server")
+}
+
+func setResourceServer(dbConnString, identifier, name, scope, clientID string, del bool) error {
+	if identifier == "" {
+		return fmt.Errorf("identifier is required")
+	}
+	if u, err := url.Parse(identifier); err != nil || !u.IsAbs() || u.Fragment != "" {
+		return fmt.Errorf("identifier must be an absolute URI without a fragment")
+	}
+
+	// Init DB connection
+	db, err := sql.Open("postgres", dbConnString)
+	if err != nil {
+		return fmt.Errorf("failed to open db connection: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping db: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Init repository
+	repo, err := repository.Prepare(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to prepare repository: %w", err)
+	}
+
+	if del {
+		if err := repo.DeleteResourceServer(ctx, identifier); err != nil {
+			return fmt.Errorf("failed to delete resource server: %w", err)
+		}
+		return nil
+	}
+
+	if clientID != "" {
+		if _, err := repo.GetClientByID(ctx, clientID); err != nil {
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+	}
+
+	if _, err := repo.UpsertResourceServer(ctx, repository.UpsertResourceServerParams{
+		Identifier: identifier,
+		Name:       name,
+		Scope:      strings.Join(strings.Fields(scope), " "),
+		ClientID:   clientID,
+	}); err != nil {
+		return fmt.Errorf("failed to upsert resource server: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
+	_ "github.com/lib/pq"                 // init pg driver
+
+	"github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// revokeTokensCmd represents the revokeTokens command
+var revokeTokensCmd = &cobra.Command{
+	Use:   "revoke-tokens",
+	Short: "Revoke all tokens of a user, a client or a user of a client",
+	Long: `Revoke the access and refresh tokens issued on behalf of the user set with --email,
+the tokens issued to the client set with --client_id, or, if both are set,
+the tokens issued to the client on behalf of the user.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connStr := cmd.Flag("db").Value.String()
+		if connStr == "" {
+			connStr = env.GetString("DATABASE_URL", "")
+			if connStr == "" {
+				return fmt.Errorf("db connection string is required")
+			}
+		}
+
+		n, err := revokeTokens(
+			connStr,
+			cmd.Flag("email").Value.String(),
+			cmd.Flag("client_id").Value.String(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+
+		color.Green("\n%d tokens are revoked", n)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revokeTokensCmd)
+	revokeTokensCmd.Flags().String("db", "", "Database connection string")
+	revokeTokensCmd.Flags().StringP("email", "e", "", "Email of the user the tokens are issued on behalf of")
+	revokeTokensCmd.Flags().StringP("client_id", "c", "", "Client ID the tokens are issued to")
+}
+
+func revokeTokens(dbConnString, email, clientID string) (int64, error) {
+	if email == "" && clientID == "" {
+		return 0, fmt.Errorf("email or client_id is required")
+	}
+
+	// Init DB connection
+	db, err := sql.Open("postgres", dbConnString)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open db connection: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return 0, fmt.Errorf("failed to ping db: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Init repository
+	repo, err := repository.Prepare(ctx, db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare repository: %w", err)
+	}
+
+	revocation := oauth.NewTokenRevocation(repo)
+
+	if email == "" {
+		return revocation.RevokeClientTokens(ctx, clientID)
+	}
+
+	user, err := repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if clientID == "" {
+		return revocation.RevokeUserTokens(ctx, user.ID)
+	}
+
+	return revocation.RevokeUserClientTokens(ctx, user.ID, clientID)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
+	_ "github.com/lib/pq"                 // init pg driver
+
+	"github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// tokenExchangePolicyCmd represents the tokenExchangePolicy command
+var tokenExchangePolicyCmd = &cobra.Command{
+	Use:   "token-exchange-policy",
+	Short: "Allow the client to exchange tokens",
+	Long: `Set the token exchange policy of the client: subject token types, audiences and scopes
+the client may exchange for. The token exchange grant is added to the client allowed grants.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connStr := cmd.Flag("db").Value.String()
+		if connStr == "" {
+			connStr = env.GetString("DATABASE_URL", "")
+			if connStr == "" {
+				return fmt.Errorf("db connection string is required")
+			}
+		}
+
+		subjectTokenTypes, _ := cmd.Flags().GetStringSlice("subject_token_type")
+		audiences, _ := cmd.Flags().GetStringSlice("audience")
+
+		if err := setTokenExchangePolicy(
+			connStr,
+			cmd.Flag("client_id").Value.String(),
+			subjectTokenTypes,
+			audiences,
+			cmd.Flag("scope").Value.String(),
+		); err != nil {
+			return fmt.Errorf("failed to set token exchange policy: %w", err)
+		}
+
+		color.Green("\nToken exchange policy is set")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tokenExchangePolicyCmd)
+	tokenExchangePolicyCmd.Flags().String("db", "", "Database connection string")
+	tokenExchangePolicyCmd.Flags().StringP("client_id", "c", "", "Client ID")
+	tokenExchangePolicyCmd.Flags().StringSliceP("subject_token_type", "t", []string{"urn:ietf:params:oauth:token-type:access_token"}, "Allowed subject token type, can be repeated")
+	tokenExchangePolicyCmd.Flags().StringSliceP("audience", "a", nil, "Allowed audience of the exchanged token, can be repeated")
+	tokenExchangePolicyCmd.Flags().StringP("scope", "s", "", "Scopes the client may exchange for")
+}
+
+func setTokenExchangePolicy(dbConnString, clientID string, subjectTokenTypes, audiences []string, scope string) error {
+	// Init DB connection
+	db, err := sql.Open("postgres", dbConnString)
+	if err != nil {
+		return fmt.Errorf("failed to open db connection: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping db: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Init repository
+	repo, err := repository.Prepare(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to prepare repository: %w", err)
+	}
+
+	client, err := repo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+	if client.IsPublic {
+		return fmt.Errorf("public client can't exchange tokens")
+	}
+
+	if _, err := repo.UpsertTokenExchangePolicy(ctx, repository.UpsertTokenExchangePolicyParams{
+		ClientID:          client.ID,
+		SubjectTokenTypes: subjectTokenTypes,
+		Audiences:         audiences,
+		Scope:             strings.Join(strings.Fields(scope), " "),
+	}); err != nil {
+		return fmt.Errorf("failed to upsert token exchange policy: %w", err)
+	}
+
+	for _, g := range client.AllowedGrants {
+		if g == tokenExchangeGrantType {
+			return nil
+		}
+	}
+	if _, err := repo.UpdateClientAllowedGrants(ctx, repository.UpdateClientAllowedGrantsParams{
+		ID:            client.ID,
+		AllowedGrants: append(client.AllowedGrants, tokenExchangeGrantType),
+	}); err != nil {
+		return fmt.Errorf("failed to update client allowed grants: %w", err)
+	}
+
+	return nil
+}
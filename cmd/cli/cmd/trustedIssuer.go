@@ -0,0 +1,121 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
+	_ "github.com/lib/pq"                 // init pg driver
+
+	"github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// trustedIssuerCmd represents the trustedIssuer command
+var trustedIssuerCmd = &cobra.Command{
+	Use:   "trusted-issuer",
+	Short: "Register a trusted issuer of the JWT bearer assertions",
+	Long: `Register the issuer of the JWT assertions accepted by the JWT bearer grant,
+its public keys and how the assertion subject is mapped to the local user: by the user id or email.
+The client must be allowed to use the urn:ietf:params:oauth:grant-type:jwt-bearer grant type.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connStr := cmd.Flag("db").Value.String()
+		if connStr == "" {
+			connStr = env.GetString("DATABASE_URL", "")
+			if connStr == "" {
+				return fmt.Errorf("db connection string is required")
+			}
+		}
+
+		jwks, err := os.ReadFile(cmd.Flag("jwks_file").Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to read jwks file: %w", err)
+		}
+
+		if err := setTrustedIssuer(
+			connStr,
+			cmd.Flag("issuer").Value.String(),
+			jwks,
+			cmd.Flag("subject_type").Value.String(),
+			cmd.Flag("scope").Value.String(),
+		); err != nil {
+			return fmt.Errorf("failed to register trusted issuer: %w", err)
+		}
+
+		color.Green("\nTrusted issuer is registered")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trustedIssuerCmd)
+	trustedIssuerCmd.Flags().String("db", "", "Database connection string")
+	trustedIssuerCmd.Flags().StringP("issuer", "i", "", "Issuer identifier, the iss claim of the assertions")
+	trustedIssuerCmd.Flags().StringP("jwks_file", "f", "", "Path to the JSON Web Key Set with the issuer public keys")
+	trustedIssuerCmd.Flags().StringP("subject_type", "t", "email", "The assertion subject is the local user: id or email")
+	trustedIssuerCmd.Flags().StringP("scope", "s", "", "Scopes the issuer may grant")
+}
+
+func setTrustedIssuer(dbConnString, issuer string, jwks []byte, subjectType, scope string) error {
+	if issuer == "" {
+		return fmt.Errorf("issuer is required")
+	}
+	if subjectType != "id" && subjectType != "email" {
+		return fmt.Errorf("subject type must be id or email")
+	}
+
+	// Validate the issuer keys
+	var set jwk.Set
+	if err := json.Unmarshal(jwks, &set); err != nil {
+		return fmt.Errorf("failed to parse jwks: %w", err)
+	}
+	if len(set.Keys) == 0 {
+		return fmt.Errorf("jwks has no keys")
+	}
+	for _, k := range set.Keys {
+		if _, err := k.PublicKey(); err != nil {
+			return fmt.Errorf("invalid key %s: %w", k.KeyID, err)
+		}
+	}
+
+	// Init DB connection
+	db, err := sql.Open("postgres", dbConnString)
+	if err != nil {
+		return fmt.Errorf("failed to open db connection: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping db: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Init repository
+	repo, err := repository.Prepare(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to prepare repository: %w", err)
+	}
+
+	if _, err := repo.UpsertTrustedIssuer(ctx, repository.UpsertTrustedIssuerParams{
+		Issuer:      issuer,
+		Jwks:        string(jwks),
+		SubjectType: subjectType,
+		Scope:       strings.Join(strings.Fields(scope), " "),
+	}); err != nil {
+		return fmt.Errorf("failed to upsert trusted issuer: %w", err)
+	}
+
+	return nil
+}
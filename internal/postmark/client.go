@@ -13,6 +13,7 @@ var (
 	VerificationCodeTmpl = "verification_code"
 	PasswordResetTmpl    = "password_reset"
 	DestroyUserCodeTmpl  = "destroy_account"
+	BackchannelAuthTmpl  = "backchannel_auth"
 )
 
 type (
@@ -118,6 +119,21 @@ func (c *Client) SendDestroyProfileCode(ctx context.Context, uid, email, otp str
 	)
 }
 
+// SendBackchannelAuthLink sends the link to approve the sign-in request of the client,
+// the approval URL is built by the authorization server.
+func (c *Client) SendBackchannelAuthLink(ctx context.Context, uid, email, clientName, bindingMessage, approvalURL string) error {
+	return c.send(
+		BackchannelAuthTmpl,
+		"backchannel_auth",
+		email,
+		map[string]interface{}{
+			"client_name":     clientName,
+			"binding_message": bindingMessage,
+			"action_url":      approvalURL,
+		},
+	)
+}
+
 // send email
 func (c *Client) send(tpl, tag, email string, data map[string]interface{}) error {
 	// Default model data
@@ -1,11 +1,14 @@
 package session
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/dmitrymomot/random"
 	"github.com/go-session/session/v3"
 )
 
@@ -16,6 +19,16 @@ const (
 	RedirectDataKey = "redirect_data"
 	// LoggedInUserIDKey is the key used to store the logged in user ID in the session.
 	LoggedInUserIDKey = "logged_in_user_id"
+	// AuthTimeKey is the key used to store the time of the user authentication in the session.
+	AuthTimeKey = "auth_time"
+	// CSRFTokenKey is the key used to store the CSRF token in the session.
+	CSRFTokenKey = "csrf_token"
+	// SessionIDKey is the key used to store the OpenID Connect session ID (sid) in the session.
+	SessionIDKey = "sid"
+	// SignedInClientsKey is the key used to store the clients the user has signed in to in the session.
+	SignedInClientsKey = "signed_in_clients"
+	// UserCodeFailuresKey is the key used to store the times of the failed user code lookups in the session.
+	UserCodeFailuresKey = "user_code_failures"
 )
 
 // StoreReturnURI stores the return URI in the session.
@@ -118,7 +131,12 @@ func StoreLoggedInUserID(r *http.Request, w http.ResponseWriter, userID string)
 		return fmt.Errorf("session start: %w", err)
 	}
 
+	// a new session ID is issued on each login, so the logout notifications
+	// never reach the clients signed in with the previous one
 	store.Set(LoggedInUserIDKey, userID)
+	store.Set(AuthTimeKey, time.Now().Unix())
+	store.Set(SessionIDKey, random.String(32))
+	store.Delete(SignedInClientsKey)
 	if err := store.Save(); err != nil {
 		return fmt.Errorf("session save: %w", err)
 	}
@@ -146,6 +164,202 @@ func GetLoggedInUserID(r *http.Request, w http.ResponseWriter) (string, bool) {
 	return result, true
 }
 
+// GetAuthTime gets the time when the user was authenticated.
+func GetAuthTime(r *http.Request, w http.ResponseWriter) (time.Time, bool) {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	authTime, ok := store.Get(AuthTimeKey)
+	if !ok || authTime == nil {
+		return time.Time{}, false
+	}
+
+	// the value type depends on the session store encoder
+	switch v := authTime.(type) {
+	case int64:
+		return time.Unix(v, 0), true
+	case float64:
+		return time.Unix(int64(v), 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// GetSessionID gets the OpenID Connect session ID of the logged in user.
+func GetSessionID(r *http.Request, w http.ResponseWriter) (string, bool) {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return "", false
+	}
+
+	sid, ok := store.Get(SessionIDKey)
+	if !ok || sid == nil {
+		return "", false
+	}
+
+	result, ok := sid.(string)
+	if !ok || result == "" {
+		return "", false
+	}
+
+	return result, true
+}
+
+// AddSignedInClient remembers the client the user has signed in to within the session,
+// so the client is notified when the user logs out.
+func AddSignedInClient(r *http.Request, w http.ResponseWriter, clientID string) error {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return fmt.Errorf("session start: %w", err)
+	}
+
+	clients := signedInClients(store)
+	for _, id := range clients {
+		if id == clientID {
+			return nil
+		}
+	}
+
+	store.Set(SignedInClientsKey, append(clients, clientID))
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("session save: %w", err)
+	}
+
+	return nil
+}
+
+// GetSignedInClients gets the clients the user has signed in to within the session.
+func GetSignedInClients(r *http.Request, w http.ResponseWriter) []string {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return nil
+	}
+
+	return signedInClients(store)
+}
+
+// signedInClients returns the client IDs stored in the session
+func signedInClients(store session.Store) []string {
+	clients, ok := store.Get(SignedInClientsKey)
+	if !ok || clients == nil {
+		return nil
+	}
+
+	// the value type depends on the session store encoder
+	switch v := clients.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// AddUserCodeFailure remembers the failed device user code lookup within the session,
+// the failures before the given time are forgotten.
+func AddUserCodeFailure(r *http.Request, w http.ResponseWriter, since time.Time) error {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return fmt.Errorf("session start: %w", err)
+	}
+
+	store.Set(UserCodeFailuresKey, append(userCodeFailures(store, since), time.Now().Unix()))
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("session save: %w", err)
+	}
+
+	return nil
+}
+
+// CountUserCodeFailures returns the number of the failed device user code lookups
+// within the session since the given time.
+func CountUserCodeFailures(r *http.Request, w http.ResponseWriter, since time.Time) int {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return 0
+	}
+
+	return len(userCodeFailures(store, since))
+}
+
+// userCodeFailures returns the unix times of the failed user code lookups since the given time
+func userCodeFailures(store session.Store, since time.Time) []int64 {
+	failures, ok := store.Get(UserCodeFailuresKey)
+	if !ok || failures == nil {
+		return nil
+	}
+
+	// the value type depends on the session store encoder
+	var times []int64
+	switch v := failures.(type) {
+	case []int64:
+		times = v
+	case []interface{}:
+		for _, t := range v {
+			switch t := t.(type) {
+			case int64:
+				times = append(times, t)
+			case float64:
+				times = append(times, int64(t))
+			}
+		}
+	}
+
+	result := make([]int64, 0, len(times))
+	for _, t := range times {
+		if t >= since.Unix() {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// StoreCSRFToken generates a new CSRF token and stores it in the session.
+func StoreCSRFToken(r *http.Request, w http.ResponseWriter) (string, error) {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return "", fmt.Errorf("session start: %w", err)
+	}
+
+	token := random.String(32)
+	store.Set(CSRFTokenKey, token)
+	if err := store.Save(); err != nil {
+		return "", fmt.Errorf("session save: %w", err)
+	}
+
+	return token, nil
+}
+
+// VerifyCSRFToken checks the token matches the one stored in the session.
+// The stored token can be used only once.
+func VerifyCSRFToken(r *http.Request, w http.ResponseWriter, token string) bool {
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return false
+	}
+
+	stored, ok := store.Get(CSRFTokenKey)
+	if !ok {
+		return false
+	}
+
+	// Delete the token from the session after it has been retrieved.
+	store.Delete(CSRFTokenKey)
+	store.Save()
+
+	result, ok := stored.(string)
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(result), []byte(token)) == 1
+}
+
 // IsLoggedIn checks if the user is logged in.
 func IsLoggedIn(r *http.Request, w http.ResponseWriter) bool {
 	_, ok := GetLoggedInUserID(r, w)
@@ -19,6 +19,8 @@ type (
 		introspectEndpoint string
 		userAPIEndpoint    string
 		clientAPIEndpoint  string
+
+		introspectOptions []IntrospectOption
 	}
 )
 
@@ -37,5 +39,6 @@ func NewClient(opts ...ClientOption) Client {
 
 // Introspect returns the token introspection response
 func (c *client) Introspect(ctx context.Context, token string, tokenType TokenType) (*TokenInfo, error) {
-	return Introspect(c.introspectEndpoint)(token, tokenType)
+	opts := append([]IntrospectOption{WithIntrospectHTTPClient(c.httpClient)}, c.introspectOptions...)
+	return Introspect(c.introspectEndpoint, opts...)(token, tokenType)
 }
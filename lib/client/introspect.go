@@ -1,31 +1,164 @@
 package client
 
 import (
+	"context"
+	"crypto"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Client authentication methods of the introspection request
+const (
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
 )
 
-// Introspect returns the token introspection response
-func Introspect(endpoint string) func(string, TokenType) (*TokenInfo, error) {
+// introspectionJWTType is the typ header and the media type suffix
+// of the signed introspection response, see: https://www.rfc-editor.org/rfc/rfc9701
+const introspectionJWTType = "token-introspection+jwt"
+
+// clientAssertionType is the client_assertion_type of the JWT client authentication,
+// see: https://www.rfc-editor.org/rfc/rfc7523#section-2.2
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ErrInvalidIntrospectionResponse is returned when the signed introspection response
+// can't be verified.
+var ErrInvalidIntrospectionResponse = errors.New("invalid introspection response")
+
+type (
+	// PublicKeyProvider returns the public key by the key id from the JWT header.
+	// It's implemented by jwk.RemoteSet with the authorization server jwks_uri.
+	PublicKeyProvider interface {
+		PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+	}
+
+	// IntrospectOption is a function that configures the introspection request.
+	IntrospectOption func(*introspectOptions)
+
+	introspectOptions struct {
+		httpClient   *http.Client
+		authMethod   string
+		clientID     string
+		clientSecret string
+		assertion    func() (string, error)
+		keys         PublicKeyProvider
+		issuer       string
+	}
+
+	// introspectionClaims represents the claims of the signed introspection response
+	introspectionClaims struct {
+		jwt.RegisteredClaims
+		TokenIntrospection *TokenInfo `json:"token_introspection"`
+	}
+)
+
+// WithIntrospectHTTPClient sets the HTTP client of the introspection requests.
+func WithIntrospectHTTPClient(httpClient *http.Client) IntrospectOption {
+	return func(o *introspectOptions) {
+		if httpClient != nil {
+			o.httpClient = httpClient
+		}
+	}
+}
+
+// WithClientSecretBasic authenticates the introspection request
+// with the client credentials in the Authorization header.
+func WithClientSecretBasic(clientID, clientSecret string) IntrospectOption {
+	return func(o *introspectOptions) {
+		o.authMethod = AuthMethodClientSecretBasic
+		o.clientID, o.clientSecret = clientID, clientSecret
+	}
+}
+
+// WithClientSecretPost authenticates the introspection request
+// with the client credentials in the request body.
+func WithClientSecretPost(clientID, clientSecret string) IntrospectOption {
+	return func(o *introspectOptions) {
+		o.authMethod = AuthMethodClientSecretPost
+		o.clientID, o.clientSecret = clientID, clientSecret
+	}
+}
+
+// WithClientAssertion authenticates the introspection request with the JWT
+// signed by the client, the assertion func is called on each request,
+// since the assertion can be used only once.
+// See: https://www.rfc-editor.org/rfc/rfc7523#section-2.2
+func WithClientAssertion(clientID string, assertion func() (string, error)) IntrospectOption {
+	return func(o *introspectOptions) {
+		o.authMethod = AuthMethodPrivateKeyJWT
+		o.clientID, o.assertion = clientID, assertion
+	}
+}
+
+// WithSignedResponse requests the introspection response as the JWT signed by the issuer.
+// The response is verified with the issuer keys and must be aimed at the client.
+// See: https://www.rfc-editor.org/rfc/rfc9701
+func WithSignedResponse(keys PublicKeyProvider, issuer string) IntrospectOption {
+	return func(o *introspectOptions) {
+		o.keys, o.issuer = keys, issuer
+	}
+}
+
+// Introspect returns the token introspection response.
+// The introspection endpoint requires the client authentication,
+// see WithClientSecretBasic, WithClientSecretPost and WithClientAssertion.
+func Introspect(endpoint string, opts ...IntrospectOption) func(string, TokenType) (*TokenInfo, error) {
+	o := &introspectOptions{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return func(token string, tokenType TokenType) (*TokenInfo, error) {
-		// use the default http client, because token introspection does not require
-		// any authentication headers.
-		resp, err := http.Post(
-			endpoint,
-			"application/x-www-form-urlencoded",
-			strings.NewReader(url.Values{
-				"token":           {token},
-				"token_type_hint": {string(tokenType)},
-			}.Encode()),
-		)
+		form := url.Values{
+			"token":           {token},
+			"token_type_hint": {string(tokenType)},
+		}
+		switch o.authMethod {
+		case AuthMethodClientSecretPost:
+			form.Set("client_id", o.clientID)
+			form.Set("client_secret", o.clientSecret)
+		case AuthMethodPrivateKeyJWT:
+			assertion, err := o.assertion()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client assertion: %w", err)
+			}
+			form.Set("client_id", o.clientID)
+			form.Set("client_assertion_type", clientAssertionType)
+			form.Set("client_assertion", assertion)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if o.authMethod == AuthMethodClientSecretBasic {
+			req.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+		}
+		if o.keys != nil {
+			req.Header.Set("Accept", "application/"+introspectionJWTType)
+		}
+
+		resp, err := o.httpClient.Do(req)
 		if err != nil {
 			return nil, err
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusOK {
+			if o.keys != nil {
+				return o.verify(resp)
+			}
+
 			var ti TokenInfo
 			if err := json.NewDecoder(resp.Body).Decode(&ti); err != nil {
 				return nil, err
@@ -40,3 +173,47 @@ func Introspect(endpoint string) func(string, TokenType) (*TokenInfo, error) {
 		return nil, errResp
 	}
 }
+
+// verify verifies the signed introspection response and returns the token info,
+// see: https://www.rfc-editor.org/rfc/rfc9701#section-6
+func (o *introspectOptions) verify(resp *http.Response) (*TokenInfo, error) {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mediaType != "application/"+introspectionJWTType {
+		return nil, ErrInvalidIntrospectionResponse
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuedAt(),
+	}
+	if o.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(o.issuer))
+	}
+	if o.clientID != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(o.clientID))
+	}
+
+	claims := &introspectionClaims{}
+	if _, err := jwt.ParseWithClaims(string(body), claims, func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); strings.TrimPrefix(strings.ToLower(typ), "application/") != introspectionJWTType {
+			return nil, ErrInvalidIntrospectionResponse
+		}
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, ErrInvalidIntrospectionResponse
+		}
+		return o.keys.PublicKey(context.Background(), kid)
+	}, parserOpts...); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIntrospectionResponse, err.Error())
+	}
+
+	if claims.TokenIntrospection == nil {
+		return nil, ErrInvalidIntrospectionResponse
+	}
+
+	return claims.TokenIntrospection, nil
+}
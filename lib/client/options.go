@@ -40,3 +40,11 @@ func SetIntrospectEndpoint(endpoint string) ClientOption {
 		}
 	}
 }
+
+// SetIntrospectOptions sets the client authentication and the response verification
+// of the introspection requests, e.g. WithClientSecretBasic and WithSignedResponse.
+func SetIntrospectOptions(opts ...IntrospectOption) ClientOption {
+	return func(c *client) {
+		c.introspectOptions = append(c.introspectOptions, opts...)
+	}
+}
@@ -1,6 +1,10 @@
 package client
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // TokenType is a type of token.
 type TokenType string
@@ -13,18 +17,80 @@ const (
 
 // TokenInfo is a struct that contains information about a token.
 type TokenInfo struct {
-	Active    bool   `json:"active"`
-	Scope     string `json:"scope,omitempty"`
-	ClientID  string `json:"client_id,omitempty"`
-	UserID    string `json:"user_id,omitempty"`
-	TokenType string `json:"token_type,omitempty"`
-	ExpiresAt int64  `json:"exp,omitempty"`
-	IssuedAt  int64  `json:"iat,omitempty"`
-	NotBefore int64  `json:"nbf,omitempty"`
-	Subject   string `json:"sub,omitempty"`
-	Audience  string `json:"aud,omitempty"`
-	Issuer    string `json:"iss,omitempty"`
-	TokenID   string `json:"jti,omitempty"`
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	TokenID   string   `json:"jti,omitempty"`
+
+	// User authentication of the authorization the token is issued for,
+	// see: https://www.rfc-editor.org/rfc/rfc9068#section-2.2.1
+	AuthTime int64    `json:"auth_time,omitempty"`
+	ACR      string   `json:"acr,omitempty"`
+	AMR      []string `json:"amr,omitempty"`
+
+	// Confirmation binds the token to the client certificate or the DPoP key
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+}
+
+// Scopes returns the list of the token scopes.
+func (t TokenInfo) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// HasScope checks the token has the scope.
+func (t TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Audience is the list of the token recipients, the aud claim.
+// The single recipient may be encoded as a string, see: https://www.rfc-editor.org/rfc/rfc7519#section-4.1.3
+type Audience []string
+
+// Contains checks the token is aimed at the recipient.
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes the audience from a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var aud string
+	if err := json.Unmarshal(data, &aud); err == nil {
+		*a = Audience{aud}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Confirmation is the token confirmation claim.
+// See: https://www.rfc-editor.org/rfc/rfc8705#section-3.1
+// and https://www.rfc-editor.org/rfc/rfc9449#section-6.1
+type Confirmation struct {
+	X5tS256 string `json:"x5t#S256,omitempty"`
+	JKT     string `json:"jkt,omitempty"`
 }
 
 // ErrorResponse is a struct that contains an error message.
@@ -0,0 +1,140 @@
+// Package dpop implements the verification of the DPoP proofs,
+// which bind the access tokens to the client key.
+// See: https://www.rfc-editor.org/rfc/rfc9449
+package dpop
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DPoP headers and token type
+const (
+	HeaderName      = "DPoP"
+	NonceHeaderName = "DPoP-Nonce"
+	TokenType       = "DPoP"
+
+	proofType = "dpop+jwt"
+
+	// DefaultMaxAge is the default lifetime of the proof since it's issued
+	DefaultMaxAge = 5 * time.Minute
+	// the proof may be issued a bit in the future due to the clock skew
+	clockSkew = 30 * time.Second
+)
+
+// SigningAlgs is the list of supported proof signing algorithms.
+var SigningAlgs = []string{"RS256", "PS256", "ES256", "EdDSA"}
+
+// Predefined errors
+var (
+	ErrInvalidProof = errors.New("invalid dpop proof")
+)
+
+type (
+	// Claims represents the DPoP proof claims.
+	// See: https://www.rfc-editor.org/rfc/rfc9449#section-4.2
+	Claims struct {
+		jwt.RegisteredClaims
+		Method          string `json:"htm"`
+		URI             string `json:"htu"`
+		AccessTokenHash string `json:"ath,omitempty"`
+		Nonce           string `json:"nonce,omitempty"`
+	}
+
+	// Proof is the verified DPoP proof.
+	Proof struct {
+		Claims
+		// Thumbprint is the JWK SHA-256 thumbprint of the proof key, the cnf.jkt value.
+		Thumbprint string
+	}
+)
+
+// Parse verifies the DPoP proof as described in RFC 9449, section 4.3:
+// the proof is signed with the public key from its header and is issued for the request
+// HTTP method and URI, the query and fragment of the URI are ignored.
+// The accessToken is checked against the ath claim on the protected resources,
+// it's empty on the token endpoint.
+// The nonce and jti replay checks are left to the caller.
+func Parse(proof, method, uri, accessToken string, maxAge time.Duration) (*Proof, error) {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	var key jwk.Key
+	result := &Proof{}
+	if _, err := jwt.ParseWithClaims(proof, &result.Claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != proofType {
+			return nil, fmt.Errorf("unexpected proof type: %s", typ)
+		}
+		// the header must contain the public key only
+		header, ok := t.Header["jwk"].(map[string]interface{})
+		if !ok || header["d"] != nil {
+			return nil, fmt.Errorf("invalid proof key")
+		}
+		raw, err := json.Marshal(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return nil, err
+		}
+		return key.PublicKey()
+	}, jwt.WithValidMethods(SigningAlgs)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+
+	claims := result.Claims
+	if claims.ID == "" || claims.IssuedAt == nil {
+		return nil, fmt.Errorf("%w: missing jti or iat", ErrInvalidProof)
+	}
+	if iat := claims.IssuedAt.Time; time.Since(iat) > maxAge || time.Until(iat) > clockSkew {
+		return nil, fmt.Errorf("%w: proof is expired", ErrInvalidProof)
+	}
+	if claims.Method != method || !matchURI(claims.URI, uri) {
+		return nil, fmt.Errorf("%w: proof is issued for another request", ErrInvalidProof)
+	}
+	if accessToken != "" && subtle.ConstantTimeCompare([]byte(claims.AccessTokenHash), []byte(AccessTokenHash(accessToken))) != 1 {
+		return nil, fmt.Errorf("%w: proof is issued for another access token", ErrInvalidProof)
+	}
+
+	thumbprint, err := key.Thumbprint()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	result.Thumbprint = thumbprint
+
+	return result, nil
+}
+
+// AccessTokenHash returns the ath claim value of the access token:
+// base64url encoded SHA-256 hash of the token.
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// matchURI compares the URIs without the query and fragment,
+// the scheme and host are case-insensitive.
+func matchURI(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Host, ub.Host) &&
+		ua.EscapedPath() == ub.EscapedPath()
+}
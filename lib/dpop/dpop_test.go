@@ -0,0 +1,177 @@
+package dpop_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testURI = "https://server.example.com/api/user"
+
+func TestParse(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwk.NewKey("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbprint, err := pub.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func(fn func(c *dpop.Claims)) dpop.Claims {
+		c := dpop.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:       uuid.NewString(),
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+			},
+			Method:          "GET",
+			URI:             testURI,
+			AccessTokenHash: dpop.AccessTokenHash("access-token"),
+		}
+		if fn != nil {
+			fn(&c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		proof   string
+		method  string
+		uri     string
+		wantErr bool
+	}{
+		{
+			name:   "valid proof",
+			proof:  newProof(t, key, pub, "dpop+jwt", claims(nil)),
+			method: "GET",
+			uri:    testURI,
+		},
+		{
+			name:   "query is ignored",
+			proof:  newProof(t, key, pub, "dpop+jwt", claims(nil)),
+			method: "GET",
+			uri:    testURI + "?page=2",
+		},
+		{
+			name:    "wrong type",
+			proof:   newProof(t, key, pub, "JWT", claims(nil)),
+			method:  "GET",
+			uri:     testURI,
+			wantErr: true,
+		},
+		{
+			name:    "another method",
+			proof:   newProof(t, key, pub, "dpop+jwt", claims(nil)),
+			method:  "POST",
+			uri:     testURI,
+			wantErr: true,
+		},
+		{
+			name:    "another uri",
+			proof:   newProof(t, key, pub, "dpop+jwt", claims(nil)),
+			method:  "GET",
+			uri:     "https://server.example.com/api/client",
+			wantErr: true,
+		},
+		{
+			name: "another access token",
+			proof: newProof(t, key, pub, "dpop+jwt", claims(func(c *dpop.Claims) {
+				c.AccessTokenHash = dpop.AccessTokenHash("another-token")
+			})),
+			method:  "GET",
+			uri:     testURI,
+			wantErr: true,
+		},
+		{
+			name: "expired proof",
+			proof: newProof(t, key, pub, "dpop+jwt", claims(func(c *dpop.Claims) {
+				c.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			})),
+			method:  "GET",
+			uri:     testURI,
+			wantErr: true,
+		},
+		{
+			name: "missing jti",
+			proof: newProof(t, key, pub, "dpop+jwt", claims(func(c *dpop.Claims) {
+				c.ID = ""
+			})),
+			method:  "GET",
+			uri:     testURI,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := dpop.Parse(tt.proof, tt.method, tt.uri, "access-token", 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, dpop.ErrInvalidProof) {
+					t.Errorf("Parse() error = %v, want %v", err, dpop.ErrInvalidProof)
+				}
+				return
+			}
+			if proof.Thumbprint != thumbprint {
+				t.Errorf("Parse() thumbprint = %s, want %s", proof.Thumbprint, thumbprint)
+			}
+		})
+	}
+}
+
+func TestNonces(t *testing.T) {
+	nonces := dpop.NewNonces("secret", time.Minute)
+
+	nonce := nonces.New()
+	if !nonces.Valid(nonce) {
+		t.Errorf("Valid() = false for the issued nonce")
+	}
+	if dpop.NewNonces("another-secret", time.Minute).Valid(nonce) {
+		t.Errorf("Valid() = true for the nonce issued with another secret")
+	}
+	if nonces.Valid("invalid") {
+		t.Errorf("Valid() = true for the invalid nonce")
+	}
+}
+
+func TestMemoryReplayCache(t *testing.T) {
+	cache := dpop.NewMemoryReplayCache()
+	exp := time.Now().Add(time.Minute)
+
+	if ok, _ := cache.Use(context.Background(), "jti", exp); !ok {
+		t.Errorf("Use() = false for the new key")
+	}
+	if ok, _ := cache.Use(context.Background(), "jti", exp); ok {
+		t.Errorf("Use() = true for the used key")
+	}
+}
+
+// newProof signs the DPoP proof with the key published in the proof header
+func newProof(t *testing.T, key *ecdsa.PrivateKey, pub jwk.Key, typ string, claims dpop.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = typ
+	token.Header["jwk"] = pub
+
+	proof, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
@@ -0,0 +1,56 @@
+package dpop
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"time"
+)
+
+// Default server nonce lifetime
+const DefaultNonceTTL = 5 * time.Minute
+
+// Nonces issues and checks the server-provided nonces, see RFC 9449, section 8.
+// The nonce is the issue time signed with HMAC-SHA256, so nothing is stored
+// and the nonces are accepted by all server instances sharing the secret.
+type Nonces struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewNonces creates a new nonce issuer.
+func NewNonces(secret string, ttl time.Duration) *Nonces {
+	if ttl <= 0 {
+		ttl = DefaultNonceTTL
+	}
+	return &Nonces{secret: []byte(secret), ttl: ttl}
+}
+
+// New returns a new nonce.
+func (n *Nonces) New() string {
+	b := make([]byte, 8, 8+sha256.Size)
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(b, n.sign(b)...))
+}
+
+// Valid checks the nonce is issued by this issuer and isn't expired.
+func (n *Nonces) Valid(nonce string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(b) != 8+sha256.Size {
+		return false
+	}
+	if !hmac.Equal(b[8:], n.sign(b[:8])) {
+		return false
+	}
+
+	issuedAt := time.Unix(int64(binary.BigEndian.Uint64(b[:8])), 0)
+	return time.Since(issuedAt) <= n.ttl && time.Until(issuedAt) <= clockSkew
+}
+
+func (n *Nonces) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, n.secret)
+	mac.Write([]byte("dpop-nonce:"))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,53 @@
+package dpop
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// the expired proof IDs are removed from the memory cache not more often than this interval
+const pruneInterval = time.Minute
+
+type (
+	// ReplayCache remembers the used proof IDs until they expire.
+	// Use returns false if the key has been already used.
+	ReplayCache interface {
+		Use(ctx context.Context, key string, exp time.Time) (bool, error)
+	}
+
+	// MemoryReplayCache is the in-memory ReplayCache for a single server instance.
+	MemoryReplayCache struct {
+		mu       sync.Mutex
+		used     map[string]time.Time
+		prunedAt time.Time
+	}
+)
+
+// NewMemoryReplayCache creates a new in-memory replay cache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{used: make(map[string]time.Time)}
+}
+
+// Use marks the key as used until the expiration time.
+func (c *MemoryReplayCache) Use(ctx context.Context, key string, exp time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.prunedAt) > pruneInterval {
+		for k, e := range c.used {
+			if now.After(e) {
+				delete(c.used, k)
+			}
+		}
+		c.prunedAt = now
+	}
+
+	if e, ok := c.used[key]; ok && now.Before(e) {
+		return false, nil
+	}
+	c.used[key] = exp
+
+	return true, nil
+}
@@ -0,0 +1,190 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Predefined errors
+var (
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	ErrInvalidKey         = errors.New("invalid key")
+	ErrKeyNotFound        = errors.New("key not found")
+)
+
+type (
+	// Key represents a public JSON Web Key (RFC 7517).
+	Key struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid,omitempty"`
+		Use       string `json:"use,omitempty"`
+		Algorithm string `json:"alg,omitempty"`
+
+		// RSA public key
+		N string `json:"n,omitempty"`
+		E string `json:"e,omitempty"`
+
+		// EC and OKP public key
+		Curve string `json:"crv,omitempty"`
+		X     string `json:"x,omitempty"`
+		Y     string `json:"y,omitempty"`
+	}
+
+	// Set represents a JSON Web Key Set.
+	Set struct {
+		Keys []Key `json:"keys"`
+	}
+)
+
+// NewKey creates a new JSON Web Key from the public key.
+// Supported key types: *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey.
+func NewKey(kid, alg string, pub crypto.PublicKey) (Key, error) {
+	k := Key{KeyID: kid, Algorithm: alg, Use: "sig"}
+
+	switch pk := pub.(type) {
+	case *rsa.PublicKey:
+		k.KeyType = "RSA"
+		k.N = encodeBigInt(pk.N)
+		k.E = encodeBigInt(big.NewInt(int64(pk.E)))
+	case *ecdsa.PublicKey:
+		k.KeyType = "EC"
+		k.Curve = pk.Curve.Params().Name
+		size := (pk.Curve.Params().BitSize + 7) / 8
+		k.X = base64.RawURLEncoding.EncodeToString(pk.X.FillBytes(make([]byte, size)))
+		k.Y = base64.RawURLEncoding.EncodeToString(pk.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		k.KeyType = "OKP"
+		k.Curve = "Ed25519"
+		k.X = base64.RawURLEncoding.EncodeToString(pk)
+	default:
+		return Key{}, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, pub)
+	}
+
+	return k, nil
+}
+
+// PublicKey returns the crypto.PublicKey represented by the JSON Web Key.
+func (k Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKeyType, k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, ErrInvalidKey
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKeyType, k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, k.KeyType)
+}
+
+// Thumbprint returns the base64url encoded SHA-256 JWK thumbprint (RFC 7638).
+func (k Key) Thumbprint() (string, error) {
+	var members interface{}
+	switch k.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.KeyType, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Curve, k.KeyType, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Curve, k.KeyType, k.X}
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedKeyType, k.KeyType)
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// CertificateThumbprint returns the base64url encoded SHA-256 thumbprint
+// of the DER encoded X.509 certificate, the x5t#S256 value (RFC 7515, section 4.1.8).
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Lookup returns the key with the given key id.
+func (s Set) Lookup(kid string) (Key, bool) {
+	for _, k := range s.Keys {
+		if k.KeyID == kid {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(b), nil
+}
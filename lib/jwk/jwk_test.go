@@ -0,0 +1,123 @@
+package jwk_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+)
+
+func TestKeyRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		alg  string
+		pub  crypto.PublicKey
+	}{
+		{name: "rsa", alg: "RS256", pub: &rsaKey.PublicKey},
+		{name: "ec", alg: "ES256", pub: &ecKey.PublicKey},
+		{name: "ed25519", alg: "EdDSA", pub: edPub},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := jwk.NewKey("kid-"+tt.name, tt.alg, tt.pub)
+			if err != nil {
+				t.Fatalf("NewKey() error = %v", err)
+			}
+
+			b, err := json.Marshal(jwk.Set{Keys: []jwk.Key{k}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var set jwk.Set
+			if err := json.Unmarshal(b, &set); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := set.Lookup("kid-" + tt.name)
+			if !ok {
+				t.Fatalf("Lookup() key not found")
+			}
+
+			pub, err := got.PublicKey()
+			if err != nil {
+				t.Fatalf("PublicKey() error = %v", err)
+			}
+
+			if eq, ok := pub.(interface{ Equal(crypto.PublicKey) bool }); !ok || !eq.Equal(tt.pub) {
+				t.Errorf("PublicKey() = %v, want %v", pub, tt.pub)
+			}
+		})
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// Example from RFC 7638, section 3.1
+	k := jwk.Key{
+		KeyType: "RSA",
+		N:       "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:       "AQAB",
+	}
+
+	got, err := k.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint() = %s, want %s", got, want)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.NewKey("key-1", "ES256", pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+	// the valid key set padded with the whitespaces above the size limit
+	large := append([]byte(`{"keys":[`+strings.Repeat(" ", 64<<10)), small[len(`{"keys":[`):]...)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.Write(large)
+			return
+		}
+		w.Write(small)
+	}))
+	defer srv.Close()
+
+	set, err := jwk.Fetch(context.Background(), srv.Client(), srv.URL+"/small")
+	if err != nil || len(set.Keys) != 1 {
+		t.Errorf("Fetch() = %v, %v; want the key set", set, err)
+	}
+	if _, err := jwk.Fetch(context.Background(), srv.Client(), srv.URL+"/large"); err == nil {
+		t.Errorf("Fetch() accepted the key set above the size limit")
+	}
+}
@@ -0,0 +1,157 @@
+package jwk
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Remote key set defaults
+const (
+	defaultCacheTTL = 10 * time.Minute
+	// the jwks_uri is provided by the client, so the request is limited
+	// to not let it hang the token endpoint or exhaust the memory
+	defaultFetchTimeout = 5 * time.Second
+	maxKeySetSize       = 64 << 10
+)
+
+// defaultHTTPClient is the HTTP client of the remote key sets without WithHTTPClient
+var defaultHTTPClient = &http.Client{Timeout: defaultFetchTimeout}
+
+type (
+	// RemoteSet fetches and caches a JSON Web Key Set from the jwks_uri.
+	// The key set is refetched when the cache is expired or an unknown key id is requested,
+	// so rotated keys are picked up without restart.
+	RemoteSet struct {
+		url        string
+		httpClient *http.Client
+		ttl        time.Duration
+
+		mu        sync.RWMutex
+		set       Set
+		fetchedAt time.Time
+	}
+
+	// RemoteSetOption is a function that configures a RemoteSet.
+	RemoteSetOption func(*RemoteSet)
+)
+
+// WithHTTPClient sets the HTTP client to use for requests.
+func WithHTTPClient(httpClient *http.Client) RemoteSetOption {
+	return func(r *RemoteSet) {
+		if httpClient != nil {
+			r.httpClient = httpClient
+		}
+	}
+}
+
+// WithCacheTTL sets the key set cache lifetime.
+func WithCacheTTL(ttl time.Duration) RemoteSetOption {
+	return func(r *RemoteSet) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// NewRemoteSet returns a new RemoteSet for the given jwks_uri.
+func NewRemoteSet(url string, opts ...RemoteSetOption) *RemoteSet {
+	r := &RemoteSet{
+		url:        url,
+		httpClient: defaultHTTPClient,
+		ttl:        defaultCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// PublicKey returns the public key with the given key id.
+func (r *RemoteSet) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	r.mu.RLock()
+	key, found := r.set.Lookup(kid)
+	expired := time.Since(r.fetchedAt) > r.ttl
+	r.mu.RUnlock()
+
+	if !found || expired {
+		set, err := r.refresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if key, found = set.Lookup(kid); !found {
+			return nil, ErrKeyNotFound
+		}
+	}
+
+	return key.PublicKey()
+}
+
+// Keys returns the cached key set, the key set is refetched if the cache is expired.
+func (r *RemoteSet) Keys(ctx context.Context) (Set, error) {
+	r.mu.RLock()
+	set := r.set
+	expired := time.Since(r.fetchedAt) > r.ttl
+	r.mu.RUnlock()
+
+	if expired {
+		return r.refresh(ctx)
+	}
+
+	return set, nil
+}
+
+// refresh fetches the key set and updates the cache.
+func (r *RemoteSet) refresh(ctx context.Context) (Set, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// avoid hammering the jwks_uri with unknown key ids
+	if time.Since(r.fetchedAt) < time.Second {
+		return r.set, nil
+	}
+
+	set, err := Fetch(ctx, r.httpClient, r.url)
+	if err != nil {
+		return Set{}, err
+	}
+
+	r.set = set
+	r.fetchedAt = time.Now()
+
+	return set, nil
+}
+
+// Fetch fetches a JSON Web Key Set from the given URL.
+// The key set larger than 64 KiB is rejected.
+func Fetch(ctx context.Context, httpClient *http.Client, url string) (Set, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Set{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Set{}, fmt.Errorf("failed to fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Set{}, fmt.Errorf("failed to fetch key set: unexpected status code %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxKeySetSize)).Decode(&set); err != nil {
+		return Set{}, fmt.Errorf("failed to decode key set: %w", err)
+	}
+
+	return set, nil
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/lib/client"
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+)
+
+// Authorization header schemes
+const (
+	bearerScheme = "Bearer"
+	dpopScheme   = "DPoP"
+)
+
+type (
+	// AuthOption is a function that configures the auth middleware.
+	AuthOption func(*authOptions)
+
+	authOptions struct {
+		dpopReplay dpop.ReplayCache
+	}
+
+	// dpopRequest is the part of the request the DPoP proof is verified against
+	dpopRequest struct {
+		scheme string
+		proofs []string
+		method string
+		uri    string
+	}
+)
+
+// WithDPoP enables the DPoP-bound access tokens: the token is presented with
+// the DPoP authorization scheme and the proof of the key it's bound to.
+// The proof IDs are stored in the replay cache until the proofs expire.
+// See: https://www.rfc-editor.org/rfc/rfc9449#section-7
+func WithDPoP(replay dpop.ReplayCache) AuthOption {
+	return func(o *authOptions) {
+		o.dpopReplay = replay
+	}
+}
+
+func newAuthOptions(opts []AuthOption) *authOptions {
+	o := &authOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// verifyDPoPBinding checks the DPoP-bound access token is presented with the proof
+// of the same key. The bound token presented as the bearer token is rejected,
+// as well as any bound token if the DPoP mode is disabled.
+func (o *authOptions) verifyDPoPBinding(ctx context.Context, info *client.TokenInfo, token string, req dpopRequest) error {
+	jkt := ""
+	if info.Confirmation != nil {
+		jkt = info.Confirmation.JKT
+	}
+
+	if req.scheme != dpopScheme {
+		if jkt != "" {
+			return errors.New("dpop-bound access token is presented as bearer token")
+		}
+		return nil
+	}
+	if o.dpopReplay == nil {
+		return errors.New("dpop is not supported")
+	}
+	if len(req.proofs) != 1 {
+		return errors.New("exactly one dpop proof is required")
+	}
+
+	proof, err := dpop.Parse(req.proofs[0], req.method, req.uri, token, dpop.DefaultMaxAge)
+	if err != nil {
+		return err
+	}
+	if jkt == "" || proof.Thumbprint != jkt {
+		return errors.New("access token is bound to another key")
+	}
+
+	ok, err := o.dpopReplay.Use(ctx, proof.Thumbprint+":"+proof.ID, proof.IssuedAt.Add(dpop.DefaultMaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to check dpop proof replay: %w", err)
+	}
+	if !ok {
+		return errors.New("dpop proof is already used")
+	}
+
+	return nil
+}
+
+// newDPoPRequest returns the request data the DPoP proof is verified against.
+// The request URI is built from the request host, so the service behind
+// the reverse proxy must preserve the original Host header.
+func newDPoPRequest(r *http.Request, scheme string) dpopRequest {
+	proto := "http"
+	if r.TLS != nil {
+		proto = "https"
+	}
+	return dpopRequest{
+		scheme: scheme,
+		proofs: r.Header.Values(dpop.HeaderName),
+		method: r.Method,
+		uri:    proto + "://" + r.Host + r.URL.EscapedPath(),
+	}
+}
@@ -2,15 +2,24 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"net/http"
 
 	"github.com/dmitrymomot/oauth2-server/lib/client"
 	"github.com/dmitrymomot/oauth2-server/svc/oauth"
 	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
 )
 
+// ErrInvalidAdminToken is returned by GokitAdminTokenMiddleware if the admin token is missed or invalid.
+var ErrInvalidAdminToken = errors.New("invalid_token")
+
 // GokitAuthMiddleware is a middleware for gokit
-func GokitAuthMiddleware(verifyFn VerifyTokenFunc) endpoint.Middleware {
+func GokitAuthMiddleware(verifyFn VerifyTokenFunc, opts ...AuthOption) endpoint.Middleware {
+	o := newAuthOptions(opts)
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 			token, ok := ctx.Value(jwt.JWTContextKey).(string)
@@ -23,7 +32,59 @@ func GokitAuthMiddleware(verifyFn VerifyTokenFunc) endpoint.Middleware {
 				return nil, oauth.ErrInvalidAccessToken
 			}
 
+			// the client certificate is set by ClientCertificateToContext
+			cert, _ := ctx.Value(clientCertificateKey{}).(*x509.Certificate)
+			if !verifyCertificateBinding(info, cert) {
+				return nil, oauth.ErrInvalidAccessToken
+			}
+
+			// the DPoP proof is set by DPoPToContext
+			req, _ := ctx.Value(dpopRequestKey{}).(dpopRequest)
+			if err := o.verifyDPoPBinding(ctx, info, token, req); err != nil {
+				return nil, oauth.ErrInvalidAccessToken
+			}
+
 			return next(SetTokenInfoToContext(ctx, info), request)
 		}
 	}
 }
+
+// GokitAdminTokenMiddleware verifies the bearer token is the admin token of the admin API,
+// all requests are rejected if the admin token is empty.
+func GokitAdminTokenMiddleware(adminToken string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			token, _ := ctx.Value(jwt.JWTContextKey).(string)
+			if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+				return nil, ErrInvalidAdminToken
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// ClientCertificateToContext moves the TLS client certificate from the request to the context,
+// so GokitAuthMiddleware can check the certificate-bound access tokens.
+func ClientCertificateToContext() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if cert := peerCertificate(r.TLS); cert != nil {
+			return context.WithValue(ctx, clientCertificateKey{}, cert)
+		}
+		return ctx
+	}
+}
+
+// DPoPToContext moves the DPoP access token and proof from the request to the context,
+// so GokitAuthMiddleware can check the DPoP-bound access tokens.
+// jwt.HTTPToContext extracts only the bearer tokens.
+func DPoPToContext() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		scheme, token := getAccessToken(r)
+		if scheme != dpopScheme {
+			return ctx
+		}
+		ctx = context.WithValue(ctx, jwt.JWTContextKey, token)
+		return context.WithValue(ctx, dpopRequestKey{}, newDPoPRequest(r, scheme))
+	}
+}
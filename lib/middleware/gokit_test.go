@@ -0,0 +1,36 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+	"github.com/go-kit/kit/auth/jwt"
+)
+
+func TestGokitAdminTokenMiddleware(t *testing.T) {
+	next := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return true, nil
+	}
+
+	tests := []struct {
+		name       string
+		adminToken string
+		token      string
+		wantErr    error
+	}{
+		{name: "admin token", adminToken: "admin-token", token: "admin-token"},
+		{name: "invalid token", adminToken: "admin-token", token: "other-token", wantErr: middleware.ErrInvalidAdminToken},
+		{name: "missed token", adminToken: "admin-token", wantErr: middleware.ErrInvalidAdminToken},
+		{name: "admin API is disabled", wantErr: middleware.ErrInvalidAdminToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), jwt.JWTContextKey, tt.token)
+			if _, err := middleware.GokitAdminTokenMiddleware(tt.adminToken)(next)(ctx, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GokitAdminTokenMiddleware() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,19 +1,24 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"strings"
 
 	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
 	"github.com/dmitrymomot/oauth2-server/lib/client"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
 // AuthMiddleware is a middleware that checks if the request is authorized.
-func AuthMiddleware(verifyFn VerifyTokenFunc) func(next http.Handler) http.Handler {
+func AuthMiddleware(verifyFn VerifyTokenFunc, opts ...AuthOption) func(next http.Handler) http.Handler {
+	o := newAuthOptions(opts)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			token := getBearerToken(r)
+			scheme, token := getAccessToken(r)
 			if token == "" {
 				httpencoder.EncodeResponse(r.Context(), w, httpencoder.ErrorResponse{
 					Code:      http.StatusUnauthorized,
@@ -35,6 +40,27 @@ func AuthMiddleware(verifyFn VerifyTokenFunc) func(next http.Handler) http.Handl
 				return
 			}
 
+			if !verifyCertificateBinding(info, peerCertificate(r.TLS)) {
+				httpencoder.EncodeResponse(r.Context(), w, httpencoder.ErrorResponse{
+					Code:      http.StatusUnauthorized,
+					Err:       "unauthorized",
+					Message:   "Access token is bound to another client certificate",
+					RequestID: middleware.GetReqID(r.Context()),
+				})
+				return
+			}
+
+			if err := o.verifyDPoPBinding(r.Context(), info, token, newDPoPRequest(r, scheme)); err != nil {
+				w.Header().Set("WWW-Authenticate", `DPoP error="invalid_dpop_proof"`)
+				httpencoder.EncodeResponse(r.Context(), w, httpencoder.ErrorResponse{
+					Code:      http.StatusUnauthorized,
+					Err:       "invalid_dpop_proof",
+					Message:   "Access token is not presented with a valid DPoP proof",
+					RequestID: middleware.GetReqID(r.Context()),
+				})
+				return
+			}
+
 			ctx := SetTokenInfoToContext(r.Context(), info)
 			r = r.WithContext(ctx)
 
@@ -44,17 +70,38 @@ func AuthMiddleware(verifyFn VerifyTokenFunc) func(next http.Handler) http.Handl
 	}
 }
 
-// get bearer token from request
-func getBearerToken(r *http.Request) string {
+// get the bearer or DPoP access token and its scheme from request
+func getAccessToken(r *http.Request) (string, string) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return ""
+		return "", ""
 	}
 
 	authHeaderParts := strings.Split(authHeader, " ")
-	if len(authHeaderParts) != 2 || authHeaderParts[0] != "Bearer" {
-		return ""
+	if len(authHeaderParts) != 2 || (authHeaderParts[0] != bearerScheme && authHeaderParts[0] != dpopScheme) {
+		return "", ""
 	}
 
-	return authHeaderParts[1]
+	return authHeaderParts[0], authHeaderParts[1]
+}
+
+// verifyCertificateBinding checks the certificate-bound access token
+// is presented with the same client certificate, see RFC 8705, section 3.
+// Tokens without the confirmation claim aren't bound.
+func verifyCertificateBinding(info *client.TokenInfo, cert *x509.Certificate) bool {
+	if info.Confirmation == nil || info.Confirmation.X5tS256 == "" {
+		return true
+	}
+	if cert == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(jwk.CertificateThumbprint(cert)), []byte(info.Confirmation.X5tS256)) == 1
+}
+
+// peerCertificate returns the client certificate of the TLS connection
+func peerCertificate(state *tls.ConnectionState) *x509.Certificate {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	return state.PeerCertificates[0]
 }
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/internal/testutil"
+	"github.com/dmitrymomot/oauth2-server/lib/client"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+)
+
+func TestAuthMiddlewareCertificateBinding(t *testing.T) {
+	ca, caKey := testutil.NewCertificate(t, "Test CA", nil, nil)
+	clientCert, clientKey := testutil.NewCertificate(t, "client", ca, caKey)
+	otherCert, otherKey := testutil.NewCertificate(t, "other", ca, caKey)
+
+	tokens := map[string]*client.TokenInfo{
+		"bound": {
+			Active:       true,
+			Confirmation: &client.Confirmation{X5tS256: jwk.CertificateThumbprint(clientCert)},
+		},
+		"unbound": {Active: true},
+	}
+	verify := func(token string, tokenType client.TokenType) (*client.TokenInfo, error) {
+		return tokens[token], nil
+	}
+
+	srv := httptest.NewUnstartedServer(middleware.AuthMiddleware(verify)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		token  string
+		cert   *x509.Certificate
+		key    *ecdsa.PrivateKey
+		status int
+	}{
+		{name: "bound token with the same certificate", token: "bound", cert: clientCert, key: clientKey, status: http.StatusOK},
+		{name: "bound token with another certificate", token: "bound", cert: otherCert, key: otherKey, status: http.StatusUnauthorized},
+		{name: "bound token without certificate", token: "bound", status: http.StatusUnauthorized},
+		{name: "unbound token without certificate", token: "unbound", status: http.StatusOK},
+		{name: "unbound token with certificate", token: "unbound", cert: otherCert, key: otherKey, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// new connection for each certificate
+			tr := srv.Client().Transport.(*http.Transport).Clone()
+			if tt.cert != nil {
+				tr.TLSClientConfig.Certificates = []tls.Certificate{
+					{Certificate: [][]byte{tt.cert.Raw}, PrivateKey: tt.key},
+				}
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			resp, err := (&http.Client{Transport: tr}).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("AuthMiddleware() status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
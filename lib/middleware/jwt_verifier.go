@@ -1,19 +1,120 @@
 package middleware
 
 import (
+	"context"
+	"crypto"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/dmitrymomot/oauth2-server/lib/client"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// VerifyJWT verifies a token and returns the token info.
+// PublicKeyProvider returns the public key by the key id from the JWT header.
+// It's implemented by jwk.RemoteSet, so resource servers can verify tokens
+// with the authorization server jwks_uri.
+type PublicKeyProvider interface {
+	PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// ErrMissingKeyID is returned when the JWT header has no kid.
+var ErrMissingKeyID = errors.New("missing key id")
+
+// ErrInvalidTokenType is returned when the JWT isn't an access token:
+// the typ header must be at+jwt, see: https://www.rfc-editor.org/rfc/rfc9068#section-4
+var ErrInvalidTokenType = errors.New("invalid token type")
+
+// accessTokenJWTType is the typ header of the JWT access tokens
+const accessTokenJWTType = "at+jwt"
+
+type (
+	// VerifyOption is a function that configures the JWT verifier.
+	VerifyOption func(*verifyOptions)
+
+	verifyOptions struct {
+		issuer   string
+		audience string
+	}
+)
+
+// WithIssuer restricts the accepted tokens to the ones issued by the authorization server.
+// The iss claim isn't checked if the issuer is empty.
+func WithIssuer(iss string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.issuer = iss
+	}
+}
+
+// WithAudience restricts the accepted tokens to the ones issued for the resource server:
+// the aud claim must contain its resource identifier.
+// The aud claim isn't checked if the audience is empty.
+// See: https://www.rfc-editor.org/rfc/rfc8707
+func WithAudience(aud string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.audience = aud
+	}
+}
+
+// parserOptions returns the JWT parser options
+func parserOptions(opts []VerifyOption, parserOpts ...jwt.ParserOption) []jwt.ParserOption {
+	o := &verifyOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(o.issuer))
+	}
+	if o.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(o.audience))
+	}
+	return parserOpts
+}
+
+// VerifyJWTWithKeys verifies the JWT access token signed with an asymmetric key
+// and returns the token info. The token must have the at+jwt typ header
+// and the verification key is looked up by the kid header.
+// Use WithIssuer and WithAudience to accept only the tokens of the authorization server
+// aimed at the resource server.
+// This function is compatible with the VerifyTokenFunc interface.
+func VerifyJWTWithKeys(keys PublicKeyProvider, opts ...VerifyOption) func(string, client.TokenType) (*client.TokenInfo, error) {
+	return func(tokenString string, tokenType client.TokenType) (*client.TokenInfo, error) {
+		token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+			if typ, _ := token.Header["typ"].(string); !isAccessTokenType(typ) {
+				return nil, ErrInvalidTokenType
+			}
+			kid, ok := token.Header["kid"].(string)
+			if !ok || kid == "" {
+				return nil, ErrMissingKeyID
+			}
+			return keys.PublicKey(context.Background(), kid)
+		}, parserOptions(opts, jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}))...)
+		if err != nil {
+			return nil, err
+		}
+
+		if !token.Valid {
+			return nil, jwt.ErrTokenMalformed
+		}
+
+		claims, ok := token.Claims.(*jwt.MapClaims)
+		if !ok {
+			return nil, jwt.ErrTokenRequiredClaimMissing
+		}
+
+		return castMapClaimsToTokenInfo(claims), nil
+	}
+}
+
+// VerifyJWT verifies a token signed with a shared HMAC secret and returns the token info.
 // This function is compatible with the VerifyTokenFunc interface.
-func VerifyJWT(signingKey string) func(string, client.TokenType) (*client.TokenInfo, error) {
+//
+// Deprecated: tokens are signed with asymmetric keys, use VerifyJWTWithKeys instead.
+func VerifyJWT(signingKey string, opts ...VerifyOption) func(string, client.TokenType) (*client.TokenInfo, error) {
 	return func(tokenString string, tokenType client.TokenType) (*client.TokenInfo, error) {
 		token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
 			return []byte(signingKey), nil
-		})
+		}, parserOptions(opts)...)
 		if err != nil {
 			return nil, err
 		}
@@ -37,13 +138,48 @@ func castMapClaimsToTokenInfo(claims *jwt.MapClaims) *client.TokenInfo {
 		Active: false,
 	}
 
-	if audArr, err := claims.GetAudience(); err == nil && len(audArr) > 0 {
-		result.Audience = audArr[0]
-		result.ClientID = audArr[0]
+	// the client is identified only by the client_id claim,
+	// the aud claim lists the resource servers the token is aimed at
+	if aud, err := claims.GetAudience(); err == nil && len(aud) > 0 {
+		result.Audience = client.Audience(aud)
 	}
+	result.ClientID, _ = (*claims)["client_id"].(string)
+	// the token issued to the client on its own behalf has the client as the subject
 	if sub, err := claims.GetSubject(); err == nil {
 		result.Subject = sub
-		result.UserID = sub
+		if sub != result.ClientID {
+			result.UserID = sub
+		}
+	}
+	if iss, err := claims.GetIssuer(); err == nil {
+		result.Issuer = iss
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Unix()
+	}
+	if nbf, err := claims.GetNotBefore(); err == nil && nbf != nil {
+		result.NotBefore = nbf.Unix()
+	}
+	result.TokenID, _ = (*claims)["jti"].(string)
+	result.Scope, _ = (*claims)["scope"].(string)
+	result.ACR, _ = (*claims)["acr"].(string)
+	if authTime, ok := (*claims)["auth_time"].(float64); ok {
+		result.AuthTime = int64(authTime)
+	}
+	if amr, ok := (*claims)["amr"].([]interface{}); ok {
+		for _, m := range amr {
+			if s, ok := m.(string); ok {
+				result.AMR = append(result.AMR, s)
+			}
+		}
+	}
+
+	if cnf, ok := (*claims)["cnf"].(map[string]interface{}); ok {
+		x5t, _ := cnf["x5t#S256"].(string)
+		jkt, _ := cnf["jkt"].(string)
+		if x5t != "" || jkt != "" {
+			result.Confirmation = &client.Confirmation{X5tS256: x5t, JKT: jkt}
+		}
 	}
 	if ext, err := claims.GetExpirationTime(); err == nil && !ext.IsZero() {
 		result.ExpiresAt = ext.Unix()
@@ -54,3 +190,10 @@ func castMapClaimsToTokenInfo(claims *jwt.MapClaims) *client.TokenInfo {
 
 	return result
 }
+
+// isAccessTokenType checks the typ header of the JWT access token,
+// the media type may have the application/ prefix, see: https://www.rfc-editor.org/rfc/rfc7515#section-4.1.9
+func isAccessTokenType(typ string) bool {
+	typ = strings.ToLower(typ)
+	return typ == accessTokenJWTType || typ == "application/"+accessTokenJWTType
+}
@@ -0,0 +1,168 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/lib/client"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type publicKeyProvider struct {
+	key crypto.PublicKey
+}
+
+func (p publicKeyProvider) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	return p.key, nil
+}
+
+func TestVerifyJWTWithKeys(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	sign := func(typ string, modify func(claims jwt.MapClaims)) string {
+		claims := jwt.MapClaims{
+			"iss":       "https://auth.example.com",
+			"aud":       "https://api.example.com",
+			"sub":       "user",
+			"client_id": "client",
+			"scope":     "orders:read orders:write",
+			"exp":       now.Add(time.Hour).Unix(),
+			"iat":       now.Unix(),
+			"jti":       "token-1",
+			"auth_time": now.Add(-time.Minute).Unix(),
+			"amr":       []string{"pwd"},
+		}
+		if modify != nil {
+			modify(claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "key-1"
+		token.Header["typ"] = typ
+		s, err := token.SignedString(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	verify := middleware.VerifyJWTWithKeys(
+		publicKeyProvider{key: pk.Public()},
+		middleware.WithIssuer("https://auth.example.com"),
+		middleware.WithAudience("https://api.example.com"),
+	)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantErr    bool
+		wantUserID string
+	}{
+		{name: "access token", token: sign("at+jwt", nil), wantUserID: "user"},
+		{name: "media type", token: sign("application/at+jwt", nil), wantUserID: "user"},
+		{name: "client token", token: sign("at+jwt", func(c jwt.MapClaims) { c["sub"] = "client" })},
+		{name: "not an access token", token: sign("JWT", nil), wantErr: true},
+		{name: "another issuer", token: sign("at+jwt", func(c jwt.MapClaims) { c["iss"] = "https://another.example.com" }), wantErr: true},
+		{name: "another audience", token: sign("at+jwt", func(c jwt.MapClaims) { c["aud"] = "https://billing.example.com" }), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := verify(tt.token, client.TokenTypeAccessToken)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !info.Active || info.ClientID != "client" || info.UserID != tt.wantUserID {
+				t.Errorf("verify() = %+v, want active token of client with user %q", info, tt.wantUserID)
+			}
+			if info.Issuer != "https://auth.example.com" || info.TokenID != "token-1" || info.IssuedAt != now.Unix() {
+				t.Errorf("verify() = %+v, want iss, jti and iat", info)
+			}
+			if !info.HasScope("orders:write") || !reflect.DeepEqual(info.AMR, []string{"pwd"}) || info.AuthTime == 0 {
+				t.Errorf("verify() = %+v, want scope, amr and auth_time", info)
+			}
+		})
+	}
+
+	if _, err := verify(sign("JWT", nil), client.TokenTypeAccessToken); !errors.Is(err, middleware.ErrInvalidTokenType) {
+		t.Errorf("verify() error = %v, want %v", err, middleware.ErrInvalidTokenType)
+	}
+
+	// the client is taken only from the client_id claim, the aud claim is kept as is
+	info, err := verify(sign("at+jwt", func(c jwt.MapClaims) {
+		c["aud"] = []string{"https://api.example.com", "legacy-client"}
+		delete(c, "client_id")
+	}), client.TokenTypeAccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ClientID != "" || !reflect.DeepEqual(info.Audience, client.Audience{"https://api.example.com", "legacy-client"}) {
+		t.Errorf("verify() client_id = %q, aud = %v; want no client and the full audience", info.ClientID, info.Audience)
+	}
+}
+
+func TestAuthMiddlewareAudience(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(aud string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"iss":       "https://auth.example.com",
+			"aud":       aud,
+			"sub":       "user",
+			"client_id": "client",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "key-1"
+		token.Header["typ"] = "at+jwt"
+		s, err := token.SignedString(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	h := middleware.AuthMiddleware(middleware.VerifyJWTWithKeys(
+		publicKeyProvider{key: pk.Public()},
+		middleware.WithIssuer("https://auth.example.com"),
+		middleware.WithAudience("https://auth.example.com/api"),
+	))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name   string
+		aud    string
+		status int
+	}{
+		{name: "token for the api", aud: "https://auth.example.com/api", status: http.StatusOK},
+		{name: "token for another resource", aud: "https://orders.example.com", status: http.StatusUnauthorized},
+		{name: "token for the client", aud: "client", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+			r.Header.Set("Authorization", "Bearer "+sign(tt.aud))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("AuthMiddleware() status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
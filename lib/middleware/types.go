@@ -8,5 +8,11 @@ type ContextKey struct{}
 // TokenInfoKey is a key for token info in context.
 var TokenInfoKey = ContextKey{}
 
+// clientCertificateKey is a key for the TLS client certificate in context.
+type clientCertificateKey struct{}
+
+// dpopRequestKey is a key for the DPoP proof request data in context.
+type dpopRequestKey struct{}
+
 // TokenVerifier is a function interface that can be used to verify tokens.
 type VerifyTokenFunc func(token string, tokenType client.TokenType) (*client.TokenInfo, error)
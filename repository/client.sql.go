@@ -13,29 +13,57 @@ import (
 )
 
 const createClient = `-- name: CreateClient :one
-INSERT INTO clients (id, secret, domain, is_public, user_id, allowed_grants, scope) 
-VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at
+INSERT INTO clients (id, name, secret, domain, is_public, user_id, allowed_grants, scope, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
 `
 
 type CreateClientParams struct {
-	ID            string    `json:"id"`
-	Secret        []byte    `json:"secret"`
-	Domain        string    `json:"domain"`
-	IsPublic      bool      `json:"is_public"`
-	UserID        uuid.UUID `json:"user_id"`
-	AllowedGrants []string  `json:"allowed_grants"`
-	Scope         string    `json:"scope"`
+	ID                                    string        `json:"id"`
+	Name                                  string        `json:"name"`
+	Secret                                []byte        `json:"secret"`
+	Domain                                string        `json:"domain"`
+	IsPublic                              bool          `json:"is_public"`
+	UserID                                uuid.NullUUID `json:"user_id"`
+	AllowedGrants                         []string      `json:"allowed_grants"`
+	Scope                                 string        `json:"scope"`
+	RedirectUris                          []string      `json:"redirect_uris"`
+	TokenEndpointAuthMethod               string        `json:"token_endpoint_auth_method"`
+	Jwks                                  string        `json:"jwks"`
+	JwksUri                               string        `json:"jwks_uri"`
+	EncryptedSecret                       []byte        `json:"encrypted_secret"`
+	TlsClientAuthSubjectDn                string        `json:"tls_client_auth_subject_dn"`
+	RequirePushedAuthorizationRequests    bool          `json:"require_pushed_authorization_requests"`
+	RegistrationAccessToken               string        `json:"registration_access_token"`
+	BackchannelTokenDeliveryMode          string        `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint string        `json:"backchannel_client_notification_endpoint"`
+	PostLogoutRedirectUris                []string      `json:"post_logout_redirect_uris"`
+	FrontchannelLogoutUri                 string        `json:"frontchannel_logout_uri"`
+	BackchannelLogoutUri                  string        `json:"backchannel_logout_uri"`
 }
 
 func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Client, error) {
 	row := q.queryRow(ctx, q.createClientStmt, createClient,
 		arg.ID,
+		arg.Name,
 		arg.Secret,
 		arg.Domain,
 		arg.IsPublic,
 		arg.UserID,
 		pq.Array(arg.AllowedGrants),
 		arg.Scope,
+		pq.Array(arg.RedirectUris),
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.JwksUri,
+		arg.EncryptedSecret,
+		arg.TlsClientAuthSubjectDn,
+		arg.RequirePushedAuthorizationRequests,
+		arg.RegistrationAccessToken,
+		arg.BackchannelTokenDeliveryMode,
+		arg.BackchannelClientNotificationEndpoint,
+		pq.Array(arg.PostLogoutRedirectUris),
+		arg.FrontchannelLogoutUri,
+		arg.BackchannelLogoutUri,
 	)
 	var i Client
 	err := row.Scan(
@@ -47,6 +75,26 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		pq.Array(&i.AllowedGrants),
 		&i.Scope,
 		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
 	)
 	return i, err
 }
@@ -61,7 +109,7 @@ func (q *Queries) DeleteClient(ctx context.Context, id string) error {
 }
 
 const getClientByID = `-- name: GetClientByID :one
-SELECT id, secret, domain, is_public, user_id, allowed_grants, scope, created_at FROM clients WHERE id = $1
+SELECT id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry FROM clients WHERE id = $1
 `
 
 func (q *Queries) GetClientByID(ctx context.Context, id string) (Client, error) {
@@ -76,15 +124,35 @@ func (q *Queries) GetClientByID(ctx context.Context, id string) (Client, error)
 		pq.Array(&i.AllowedGrants),
 		&i.Scope,
 		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
 	)
 	return i, err
 }
 
 const getClientByUserID = `-- name: GetClientByUserID :many
-SELECT id, secret, domain, is_public, user_id, allowed_grants, scope, created_at FROM clients WHERE user_id = $1 ORDER BY created_at DESC
+SELECT id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry FROM clients WHERE user_id = $1 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetClientByUserID(ctx context.Context, userID uuid.UUID) ([]Client, error) {
+func (q *Queries) GetClientByUserID(ctx context.Context, userID uuid.NullUUID) ([]Client, error) {
 	rows, err := q.query(ctx, q.getClientByUserIDStmt, getClientByUserID, userID)
 	if err != nil {
 		return nil, err
@@ -102,6 +170,26 @@ func (q *Queries) GetClientByUserID(ctx context.Context, userID uuid.UUID) ([]Cl
 			pq.Array(&i.AllowedGrants),
 			&i.Scope,
 			&i.CreatedAt,
+			&i.Name,
+			pq.Array(&i.RedirectUris),
+			&i.TokenEndpointAuthMethod,
+			&i.Jwks,
+			&i.JwksUri,
+			&i.EncryptedSecret,
+			&i.TlsClientAuthSubjectDn,
+			&i.RequirePushedAuthorizationRequests,
+			&i.RegistrationAccessToken,
+			&i.BackchannelTokenDeliveryMode,
+			&i.BackchannelClientNotificationEndpoint,
+			pq.Array(&i.ResponseModes),
+			pq.Array(&i.PostLogoutRedirectUris),
+			&i.FrontchannelLogoutUri,
+			&i.BackchannelLogoutUri,
+			&i.AccessTokenTtl,
+			&i.RefreshTokenTtl,
+			&i.CodeTtl,
+			&i.IssueRefreshTokens,
+			&i.RefreshTokenExpiry,
 		); err != nil {
 			return nil, err
 		}
@@ -116,8 +204,354 @@ func (q *Queries) GetClientByUserID(ctx context.Context, userID uuid.UUID) ([]Cl
 	return items, nil
 }
 
+const updateClientAllowedGrants = `-- name: UpdateClientAllowedGrants :one
+UPDATE clients SET allowed_grants = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientAllowedGrantsParams struct {
+	AllowedGrants []string `json:"allowed_grants"`
+	ID            string   `json:"id"`
+}
+
+func (q *Queries) UpdateClientAllowedGrants(ctx context.Context, arg UpdateClientAllowedGrantsParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientAllowedGrantsStmt, updateClientAllowedGrants, pq.Array(arg.AllowedGrants), arg.ID)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientAuthentication = `-- name: UpdateClientAuthentication :one
+UPDATE clients 
+SET token_endpoint_auth_method = $1, 
+    jwks = $2, 
+    jwks_uri = $3, 
+    secret = $4, 
+    encrypted_secret = $5, 
+    tls_client_auth_subject_dn = $6 
+WHERE id = $7 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientAuthenticationParams struct {
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
+	Jwks                    string `json:"jwks"`
+	JwksUri                 string `json:"jwks_uri"`
+	Secret                  []byte `json:"secret"`
+	EncryptedSecret         []byte `json:"encrypted_secret"`
+	TlsClientAuthSubjectDn  string `json:"tls_client_auth_subject_dn"`
+	ID                      string `json:"id"`
+}
+
+func (q *Queries) UpdateClientAuthentication(ctx context.Context, arg UpdateClientAuthenticationParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientAuthenticationStmt, updateClientAuthentication,
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.JwksUri,
+		arg.Secret,
+		arg.EncryptedSecret,
+		arg.TlsClientAuthSubjectDn,
+		arg.ID,
+	)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientMetadata = `-- name: UpdateClientMetadata :one
+UPDATE clients 
+SET name = $1, 
+    domain = $2, 
+    is_public = $3, 
+    allowed_grants = $4, 
+    scope = $5, 
+    redirect_uris = $6, 
+    token_endpoint_auth_method = $7, 
+    jwks = $8, 
+    jwks_uri = $9, 
+    secret = $10, 
+    encrypted_secret = $11, 
+    tls_client_auth_subject_dn = $12, 
+    require_pushed_authorization_requests = $13, 
+    backchannel_token_delivery_mode = $14, 
+    backchannel_client_notification_endpoint = $15, 
+    post_logout_redirect_uris = $16, 
+    frontchannel_logout_uri = $17, 
+    backchannel_logout_uri = $18 
+WHERE id = $19 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientMetadataParams struct {
+	Name                                  string   `json:"name"`
+	Domain                                string   `json:"domain"`
+	IsPublic                              bool     `json:"is_public"`
+	AllowedGrants                         []string `json:"allowed_grants"`
+	Scope                                 string   `json:"scope"`
+	RedirectUris                          []string `json:"redirect_uris"`
+	TokenEndpointAuthMethod               string   `json:"token_endpoint_auth_method"`
+	Jwks                                  string   `json:"jwks"`
+	JwksUri                               string   `json:"jwks_uri"`
+	Secret                                []byte   `json:"secret"`
+	EncryptedSecret                       []byte   `json:"encrypted_secret"`
+	TlsClientAuthSubjectDn                string   `json:"tls_client_auth_subject_dn"`
+	RequirePushedAuthorizationRequests    bool     `json:"require_pushed_authorization_requests"`
+	BackchannelTokenDeliveryMode          string   `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint string   `json:"backchannel_client_notification_endpoint"`
+	PostLogoutRedirectUris                []string `json:"post_logout_redirect_uris"`
+	FrontchannelLogoutUri                 string   `json:"frontchannel_logout_uri"`
+	BackchannelLogoutUri                  string   `json:"backchannel_logout_uri"`
+	ID                                    string   `json:"id"`
+}
+
+func (q *Queries) UpdateClientMetadata(ctx context.Context, arg UpdateClientMetadataParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientMetadataStmt, updateClientMetadata,
+		arg.Name,
+		arg.Domain,
+		arg.IsPublic,
+		pq.Array(arg.AllowedGrants),
+		arg.Scope,
+		pq.Array(arg.RedirectUris),
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.JwksUri,
+		arg.Secret,
+		arg.EncryptedSecret,
+		arg.TlsClientAuthSubjectDn,
+		arg.RequirePushedAuthorizationRequests,
+		arg.BackchannelTokenDeliveryMode,
+		arg.BackchannelClientNotificationEndpoint,
+		pq.Array(arg.PostLogoutRedirectUris),
+		arg.FrontchannelLogoutUri,
+		arg.BackchannelLogoutUri,
+		arg.ID,
+	)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientRedirectURIs = `-- name: UpdateClientRedirectURIs :one
+UPDATE clients SET redirect_uris = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientRedirectURIsParams struct {
+	RedirectUris []string `json:"redirect_uris"`
+	ID           string   `json:"id"`
+}
+
+func (q *Queries) UpdateClientRedirectURIs(ctx context.Context, arg UpdateClientRedirectURIsParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientRedirectURIsStmt, updateClientRedirectURIs, pq.Array(arg.RedirectUris), arg.ID)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientRequirePushedAuthorizationRequests = `-- name: UpdateClientRequirePushedAuthorizationRequests :one
+UPDATE clients SET require_pushed_authorization_requests = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientRequirePushedAuthorizationRequestsParams struct {
+	RequirePushedAuthorizationRequests bool   `json:"require_pushed_authorization_requests"`
+	ID                                 string `json:"id"`
+}
+
+func (q *Queries) UpdateClientRequirePushedAuthorizationRequests(ctx context.Context, arg UpdateClientRequirePushedAuthorizationRequestsParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientRequirePushedAuthorizationRequestsStmt, updateClientRequirePushedAuthorizationRequests, arg.RequirePushedAuthorizationRequests, arg.ID)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientResponseModes = `-- name: UpdateClientResponseModes :one
+UPDATE clients SET response_modes = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientResponseModesParams struct {
+	ResponseModes []string `json:"response_modes"`
+	ID            string   `json:"id"`
+}
+
+func (q *Queries) UpdateClientResponseModes(ctx context.Context, arg UpdateClientResponseModesParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientResponseModesStmt, updateClientResponseModes, pq.Array(arg.ResponseModes), arg.ID)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
 const updateClientSecret = `-- name: UpdateClientSecret :one
-UPDATE clients SET secret = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at
+UPDATE clients SET secret = $1 WHERE id = $2 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
 `
 
 type UpdateClientSecretParams struct {
@@ -137,6 +571,88 @@ func (q *Queries) UpdateClientSecret(ctx context.Context, arg UpdateClientSecret
 		pq.Array(&i.AllowedGrants),
 		&i.Scope,
 		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
+	)
+	return i, err
+}
+
+const updateClientTokenLifetimes = `-- name: UpdateClientTokenLifetimes :one
+UPDATE clients 
+SET access_token_ttl = $1, 
+    refresh_token_ttl = $2, 
+    code_ttl = $3, 
+    issue_refresh_tokens = $4, 
+    refresh_token_expiry = $5 
+WHERE id = $6 RETURNING id, secret, domain, is_public, user_id, allowed_grants, scope, created_at, name, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, response_modes, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, access_token_ttl, refresh_token_ttl, code_ttl, issue_refresh_tokens, refresh_token_expiry
+`
+
+type UpdateClientTokenLifetimesParams struct {
+	AccessTokenTtl     int64              `json:"access_token_ttl"`
+	RefreshTokenTtl    int64              `json:"refresh_token_ttl"`
+	CodeTtl            int64              `json:"code_ttl"`
+	IssueRefreshTokens bool               `json:"issue_refresh_tokens"`
+	RefreshTokenExpiry RefreshTokenExpiry `json:"refresh_token_expiry"`
+	ID                 string             `json:"id"`
+}
+
+func (q *Queries) UpdateClientTokenLifetimes(ctx context.Context, arg UpdateClientTokenLifetimesParams) (Client, error) {
+	row := q.queryRow(ctx, q.updateClientTokenLifetimesStmt, updateClientTokenLifetimes,
+		arg.AccessTokenTtl,
+		arg.RefreshTokenTtl,
+		arg.CodeTtl,
+		arg.IssueRefreshTokens,
+		arg.RefreshTokenExpiry,
+		arg.ID,
+	)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.Secret,
+		&i.Domain,
+		&i.IsPublic,
+		&i.UserID,
+		pq.Array(&i.AllowedGrants),
+		&i.Scope,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.RedirectUris),
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.JwksUri,
+		&i.EncryptedSecret,
+		&i.TlsClientAuthSubjectDn,
+		&i.RequirePushedAuthorizationRequests,
+		&i.RegistrationAccessToken,
+		&i.BackchannelTokenDeliveryMode,
+		&i.BackchannelClientNotificationEndpoint,
+		pq.Array(&i.ResponseModes),
+		pq.Array(&i.PostLogoutRedirectUris),
+		&i.FrontchannelLogoutUri,
+		&i.BackchannelLogoutUri,
+		&i.AccessTokenTtl,
+		&i.RefreshTokenTtl,
+		&i.CodeTtl,
+		&i.IssueRefreshTokens,
+		&i.RefreshTokenExpiry,
 	)
 	return i, err
 }
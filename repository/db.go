@@ -24,21 +24,45 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.activateNextSigningKeysStmt, err = db.PrepareContext(ctx, activateNextSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query ActivateNextSigningKeys: %w", err)
+	}
 	if q.cleanUpExpiredUserVerificationsStmt, err = db.PrepareContext(ctx, cleanUpExpiredUserVerifications); err != nil {
 		return nil, fmt.Errorf("error preparing query CleanUpExpiredUserVerifications: %w", err)
 	}
+	if q.createBackchannelAuthRequestStmt, err = db.PrepareContext(ctx, createBackchannelAuthRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBackchannelAuthRequest: %w", err)
+	}
 	if q.createClientStmt, err = db.PrepareContext(ctx, createClient); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateClient: %w", err)
 	}
+	if q.createDeviceCodeStmt, err = db.PrepareContext(ctx, createDeviceCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeviceCode: %w", err)
+	}
+	if q.createPushedAuthorizationRequestStmt, err = db.PrepareContext(ctx, createPushedAuthorizationRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePushedAuthorizationRequest: %w", err)
+	}
+	if q.createScopeStmt, err = db.PrepareContext(ctx, createScope); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateScope: %w", err)
+	}
+	if q.createSigningKeyStmt, err = db.PrepareContext(ctx, createSigningKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSigningKey: %w", err)
+	}
 	if q.createTokenStmt, err = db.PrepareContext(ctx, createToken); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateToken: %w", err)
 	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createUserConsentStmt, err = db.PrepareContext(ctx, createUserConsent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserConsent: %w", err)
+	}
 	if q.createUserVerificationStmt, err = db.PrepareContext(ctx, createUserVerification); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUserVerification: %w", err)
 	}
+	if q.deleteBackchannelAuthRequestStmt, err = db.PrepareContext(ctx, deleteBackchannelAuthRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBackchannelAuthRequest: %w", err)
+	}
 	if q.deleteByAccessStmt, err = db.PrepareContext(ctx, deleteByAccess); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteByAccess: %w", err)
 	}
@@ -51,9 +75,54 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteClientStmt, err = db.PrepareContext(ctx, deleteClient); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteClient: %w", err)
 	}
+	if q.deleteDeviceCodeStmt, err = db.PrepareContext(ctx, deleteDeviceCode); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDeviceCode: %w", err)
+	}
+	if q.deleteExpiredBackchannelAuthRequestsStmt, err = db.PrepareContext(ctx, deleteExpiredBackchannelAuthRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredBackchannelAuthRequests: %w", err)
+	}
+	if q.deleteExpiredDeviceCodesStmt, err = db.PrepareContext(ctx, deleteExpiredDeviceCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredDeviceCodes: %w", err)
+	}
+	if q.deleteExpiredPushedAuthorizationRequestsStmt, err = db.PrepareContext(ctx, deleteExpiredPushedAuthorizationRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredPushedAuthorizationRequests: %w", err)
+	}
 	if q.deleteExpiredTokensStmt, err = db.PrepareContext(ctx, deleteExpiredTokens); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteExpiredTokens: %w", err)
 	}
+	if q.deleteNextSigningKeysStmt, err = db.PrepareContext(ctx, deleteNextSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteNextSigningKeys: %w", err)
+	}
+	if q.deletePushedAuthorizationRequestStmt, err = db.PrepareContext(ctx, deletePushedAuthorizationRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePushedAuthorizationRequest: %w", err)
+	}
+	if q.deleteResourceServerStmt, err = db.PrepareContext(ctx, deleteResourceServer); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteResourceServer: %w", err)
+	}
+	if q.deleteRetiredSigningKeysStmt, err = db.PrepareContext(ctx, deleteRetiredSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRetiredSigningKeys: %w", err)
+	}
+	if q.deleteScopeStmt, err = db.PrepareContext(ctx, deleteScope); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteScope: %w", err)
+	}
+	if q.deleteTokensByClientIDStmt, err = db.PrepareContext(ctx, deleteTokensByClientID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTokensByClientID: %w", err)
+	}
+	if q.deleteTokensByFamilyStmt, err = db.PrepareContext(ctx, deleteTokensByFamily); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTokensByFamily: %w", err)
+	}
+	if q.deleteTokensBySIDStmt, err = db.PrepareContext(ctx, deleteTokensBySID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTokensBySID: %w", err)
+	}
+	if q.deleteTokensByUserIDStmt, err = db.PrepareContext(ctx, deleteTokensByUserID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTokensByUserID: %w", err)
+	}
+	if q.deleteTokensByUserIDAndClientIDStmt, err = db.PrepareContext(ctx, deleteTokensByUserIDAndClientID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTokensByUserIDAndClientID: %w", err)
+	}
+	if q.deleteTrustedIssuerStmt, err = db.PrepareContext(ctx, deleteTrustedIssuer); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTrustedIssuer: %w", err)
+	}
 	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
 	}
@@ -63,12 +132,42 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteUserVerificationsByUserIDStmt, err = db.PrepareContext(ctx, deleteUserVerificationsByUserID); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUserVerificationsByUserID: %w", err)
 	}
+	if q.getBackchannelAuthRequestStmt, err = db.PrepareContext(ctx, getBackchannelAuthRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBackchannelAuthRequest: %w", err)
+	}
+	if q.getBackchannelAuthRequestByApprovalCodeStmt, err = db.PrepareContext(ctx, getBackchannelAuthRequestByApprovalCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBackchannelAuthRequestByApprovalCode: %w", err)
+	}
 	if q.getClientByIDStmt, err = db.PrepareContext(ctx, getClientByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetClientByID: %w", err)
 	}
 	if q.getClientByUserIDStmt, err = db.PrepareContext(ctx, getClientByUserID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetClientByUserID: %w", err)
 	}
+	if q.getDeviceCodeStmt, err = db.PrepareContext(ctx, getDeviceCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeviceCode: %w", err)
+	}
+	if q.getDeviceCodeByUserCodeStmt, err = db.PrepareContext(ctx, getDeviceCodeByUserCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeviceCodeByUserCode: %w", err)
+	}
+	if q.getPushedAuthorizationRequestStmt, err = db.PrepareContext(ctx, getPushedAuthorizationRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPushedAuthorizationRequest: %w", err)
+	}
+	if q.getResourceServerStmt, err = db.PrepareContext(ctx, getResourceServer); err != nil {
+		return nil, fmt.Errorf("error preparing query GetResourceServer: %w", err)
+	}
+	if q.getResourceServersByClientIDStmt, err = db.PrepareContext(ctx, getResourceServersByClientID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetResourceServersByClientID: %w", err)
+	}
+	if q.getScopeStmt, err = db.PrepareContext(ctx, getScope); err != nil {
+		return nil, fmt.Errorf("error preparing query GetScope: %w", err)
+	}
+	if q.getScopesStmt, err = db.PrepareContext(ctx, getScopes); err != nil {
+		return nil, fmt.Errorf("error preparing query GetScopes: %w", err)
+	}
+	if q.getSigningKeysStmt, err = db.PrepareContext(ctx, getSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSigningKeys: %w", err)
+	}
 	if q.getTokenByAccessStmt, err = db.PrepareContext(ctx, getTokenByAccess); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTokenByAccess: %w", err)
 	}
@@ -78,12 +177,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTokenByRefreshStmt, err = db.PrepareContext(ctx, getTokenByRefresh); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTokenByRefresh: %w", err)
 	}
+	if q.getTokenExchangePolicyStmt, err = db.PrepareContext(ctx, getTokenExchangePolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTokenExchangePolicy: %w", err)
+	}
+	if q.getTrustedIssuerStmt, err = db.PrepareContext(ctx, getTrustedIssuer); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrustedIssuer: %w", err)
+	}
 	if q.getUserByEmailStmt, err = db.PrepareContext(ctx, getUserByEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByEmail: %w", err)
 	}
 	if q.getUserByIDStmt, err = db.PrepareContext(ctx, getUserByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByID: %w", err)
 	}
+	if q.getUserConsentsStmt, err = db.PrepareContext(ctx, getUserConsents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserConsents: %w", err)
+	}
 	if q.getUserVerificationByEmailStmt, err = db.PrepareContext(ctx, getUserVerificationByEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserVerificationByEmail: %w", err)
 	}
@@ -93,9 +201,54 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getVerificationByUserIDAndEmailStmt, err = db.PrepareContext(ctx, getVerificationByUserIDAndEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query GetVerificationByUserIDAndEmail: %w", err)
 	}
+	if q.lockSigningKeysStmt, err = db.PrepareContext(ctx, lockSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query LockSigningKeys: %w", err)
+	}
+	if q.retireActiveSigningKeysStmt, err = db.PrepareContext(ctx, retireActiveSigningKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query RetireActiveSigningKeys: %w", err)
+	}
+	if q.rotateTokenStmt, err = db.PrepareContext(ctx, rotateToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateToken: %w", err)
+	}
+	if q.updateBackchannelAuthRequestPollingStmt, err = db.PrepareContext(ctx, updateBackchannelAuthRequestPolling); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateBackchannelAuthRequestPolling: %w", err)
+	}
+	if q.updateBackchannelAuthRequestStatusStmt, err = db.PrepareContext(ctx, updateBackchannelAuthRequestStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateBackchannelAuthRequestStatus: %w", err)
+	}
+	if q.updateClientAllowedGrantsStmt, err = db.PrepareContext(ctx, updateClientAllowedGrants); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientAllowedGrants: %w", err)
+	}
+	if q.updateClientAuthenticationStmt, err = db.PrepareContext(ctx, updateClientAuthentication); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientAuthentication: %w", err)
+	}
+	if q.updateClientMetadataStmt, err = db.PrepareContext(ctx, updateClientMetadata); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientMetadata: %w", err)
+	}
+	if q.updateClientRedirectURIsStmt, err = db.PrepareContext(ctx, updateClientRedirectURIs); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientRedirectURIs: %w", err)
+	}
+	if q.updateClientRequirePushedAuthorizationRequestsStmt, err = db.PrepareContext(ctx, updateClientRequirePushedAuthorizationRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientRequirePushedAuthorizationRequests: %w", err)
+	}
+	if q.updateClientResponseModesStmt, err = db.PrepareContext(ctx, updateClientResponseModes); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientResponseModes: %w", err)
+	}
 	if q.updateClientSecretStmt, err = db.PrepareContext(ctx, updateClientSecret); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateClientSecret: %w", err)
 	}
+	if q.updateClientTokenLifetimesStmt, err = db.PrepareContext(ctx, updateClientTokenLifetimes); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClientTokenLifetimes: %w", err)
+	}
+	if q.updateDeviceCodePollingStmt, err = db.PrepareContext(ctx, updateDeviceCodePolling); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDeviceCodePolling: %w", err)
+	}
+	if q.updateDeviceCodeStatusStmt, err = db.PrepareContext(ctx, updateDeviceCodeStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDeviceCodeStatus: %w", err)
+	}
+	if q.updateScopeStmt, err = db.PrepareContext(ctx, updateScope); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateScope: %w", err)
+	}
 	if q.updateUserEmailStmt, err = db.PrepareContext(ctx, updateUserEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserEmail: %w", err)
 	}
@@ -105,21 +258,60 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateUserVerifiedAtStmt, err = db.PrepareContext(ctx, updateUserVerifiedAt); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserVerifiedAt: %w", err)
 	}
+	if q.upsertResourceServerStmt, err = db.PrepareContext(ctx, upsertResourceServer); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertResourceServer: %w", err)
+	}
+	if q.upsertTokenExchangePolicyStmt, err = db.PrepareContext(ctx, upsertTokenExchangePolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertTokenExchangePolicy: %w", err)
+	}
+	if q.upsertTrustedIssuerStmt, err = db.PrepareContext(ctx, upsertTrustedIssuer); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertTrustedIssuer: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.activateNextSigningKeysStmt != nil {
+		if cerr := q.activateNextSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing activateNextSigningKeysStmt: %w", cerr)
+		}
+	}
 	if q.cleanUpExpiredUserVerificationsStmt != nil {
 		if cerr := q.cleanUpExpiredUserVerificationsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing cleanUpExpiredUserVerificationsStmt: %w", cerr)
 		}
 	}
+	if q.createBackchannelAuthRequestStmt != nil {
+		if cerr := q.createBackchannelAuthRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBackchannelAuthRequestStmt: %w", cerr)
+		}
+	}
 	if q.createClientStmt != nil {
 		if cerr := q.createClientStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createClientStmt: %w", cerr)
 		}
 	}
+	if q.createDeviceCodeStmt != nil {
+		if cerr := q.createDeviceCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeviceCodeStmt: %w", cerr)
+		}
+	}
+	if q.createPushedAuthorizationRequestStmt != nil {
+		if cerr := q.createPushedAuthorizationRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPushedAuthorizationRequestStmt: %w", cerr)
+		}
+	}
+	if q.createScopeStmt != nil {
+		if cerr := q.createScopeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createScopeStmt: %w", cerr)
+		}
+	}
+	if q.createSigningKeyStmt != nil {
+		if cerr := q.createSigningKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSigningKeyStmt: %w", cerr)
+		}
+	}
 	if q.createTokenStmt != nil {
 		if cerr := q.createTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTokenStmt: %w", cerr)
@@ -130,11 +322,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
+	if q.createUserConsentStmt != nil {
+		if cerr := q.createUserConsentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserConsentStmt: %w", cerr)
+		}
+	}
 	if q.createUserVerificationStmt != nil {
 		if cerr := q.createUserVerificationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserVerificationStmt: %w", cerr)
 		}
 	}
+	if q.deleteBackchannelAuthRequestStmt != nil {
+		if cerr := q.deleteBackchannelAuthRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBackchannelAuthRequestStmt: %w", cerr)
+		}
+	}
 	if q.deleteByAccessStmt != nil {
 		if cerr := q.deleteByAccessStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteByAccessStmt: %w", cerr)
@@ -155,11 +357,86 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteClientStmt: %w", cerr)
 		}
 	}
+	if q.deleteDeviceCodeStmt != nil {
+		if cerr := q.deleteDeviceCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDeviceCodeStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredBackchannelAuthRequestsStmt != nil {
+		if cerr := q.deleteExpiredBackchannelAuthRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredBackchannelAuthRequestsStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredDeviceCodesStmt != nil {
+		if cerr := q.deleteExpiredDeviceCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredDeviceCodesStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredPushedAuthorizationRequestsStmt != nil {
+		if cerr := q.deleteExpiredPushedAuthorizationRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredPushedAuthorizationRequestsStmt: %w", cerr)
+		}
+	}
 	if q.deleteExpiredTokensStmt != nil {
 		if cerr := q.deleteExpiredTokensStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteExpiredTokensStmt: %w", cerr)
 		}
 	}
+	if q.deleteNextSigningKeysStmt != nil {
+		if cerr := q.deleteNextSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteNextSigningKeysStmt: %w", cerr)
+		}
+	}
+	if q.deletePushedAuthorizationRequestStmt != nil {
+		if cerr := q.deletePushedAuthorizationRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePushedAuthorizationRequestStmt: %w", cerr)
+		}
+	}
+	if q.deleteResourceServerStmt != nil {
+		if cerr := q.deleteResourceServerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteResourceServerStmt: %w", cerr)
+		}
+	}
+	if q.deleteRetiredSigningKeysStmt != nil {
+		if cerr := q.deleteRetiredSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRetiredSigningKeysStmt: %w", cerr)
+		}
+	}
+	if q.deleteScopeStmt != nil {
+		if cerr := q.deleteScopeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteScopeStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokensByClientIDStmt != nil {
+		if cerr := q.deleteTokensByClientIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokensByClientIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokensByFamilyStmt != nil {
+		if cerr := q.deleteTokensByFamilyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokensByFamilyStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokensBySIDStmt != nil {
+		if cerr := q.deleteTokensBySIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokensBySIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokensByUserIDStmt != nil {
+		if cerr := q.deleteTokensByUserIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokensByUserIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokensByUserIDAndClientIDStmt != nil {
+		if cerr := q.deleteTokensByUserIDAndClientIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokensByUserIDAndClientIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteTrustedIssuerStmt != nil {
+		if cerr := q.deleteTrustedIssuerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTrustedIssuerStmt: %w", cerr)
+		}
+	}
 	if q.deleteUserStmt != nil {
 		if cerr := q.deleteUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
@@ -175,6 +452,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteUserVerificationsByUserIDStmt: %w", cerr)
 		}
 	}
+	if q.getBackchannelAuthRequestStmt != nil {
+		if cerr := q.getBackchannelAuthRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBackchannelAuthRequestStmt: %w", cerr)
+		}
+	}
+	if q.getBackchannelAuthRequestByApprovalCodeStmt != nil {
+		if cerr := q.getBackchannelAuthRequestByApprovalCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBackchannelAuthRequestByApprovalCodeStmt: %w", cerr)
+		}
+	}
 	if q.getClientByIDStmt != nil {
 		if cerr := q.getClientByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getClientByIDStmt: %w", cerr)
@@ -185,6 +472,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getClientByUserIDStmt: %w", cerr)
 		}
 	}
+	if q.getDeviceCodeStmt != nil {
+		if cerr := q.getDeviceCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeviceCodeStmt: %w", cerr)
+		}
+	}
+	if q.getDeviceCodeByUserCodeStmt != nil {
+		if cerr := q.getDeviceCodeByUserCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeviceCodeByUserCodeStmt: %w", cerr)
+		}
+	}
+	if q.getPushedAuthorizationRequestStmt != nil {
+		if cerr := q.getPushedAuthorizationRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPushedAuthorizationRequestStmt: %w", cerr)
+		}
+	}
+	if q.getResourceServerStmt != nil {
+		if cerr := q.getResourceServerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getResourceServerStmt: %w", cerr)
+		}
+	}
+	if q.getResourceServersByClientIDStmt != nil {
+		if cerr := q.getResourceServersByClientIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getResourceServersByClientIDStmt: %w", cerr)
+		}
+	}
+	if q.getScopeStmt != nil {
+		if cerr := q.getScopeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getScopeStmt: %w", cerr)
+		}
+	}
+	if q.getScopesStmt != nil {
+		if cerr := q.getScopesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getScopesStmt: %w", cerr)
+		}
+	}
+	if q.getSigningKeysStmt != nil {
+		if cerr := q.getSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSigningKeysStmt: %w", cerr)
+		}
+	}
 	if q.getTokenByAccessStmt != nil {
 		if cerr := q.getTokenByAccessStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTokenByAccessStmt: %w", cerr)
@@ -200,6 +527,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTokenByRefreshStmt: %w", cerr)
 		}
 	}
+	if q.getTokenExchangePolicyStmt != nil {
+		if cerr := q.getTokenExchangePolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTokenExchangePolicyStmt: %w", cerr)
+		}
+	}
+	if q.getTrustedIssuerStmt != nil {
+		if cerr := q.getTrustedIssuerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrustedIssuerStmt: %w", cerr)
+		}
+	}
 	if q.getUserByEmailStmt != nil {
 		if cerr := q.getUserByEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserByEmailStmt: %w", cerr)
@@ -210,6 +547,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserByIDStmt: %w", cerr)
 		}
 	}
+	if q.getUserConsentsStmt != nil {
+		if cerr := q.getUserConsentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserConsentsStmt: %w", cerr)
+		}
+	}
 	if q.getUserVerificationByEmailStmt != nil {
 		if cerr := q.getUserVerificationByEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserVerificationByEmailStmt: %w", cerr)
@@ -225,11 +567,86 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getVerificationByUserIDAndEmailStmt: %w", cerr)
 		}
 	}
+	if q.lockSigningKeysStmt != nil {
+		if cerr := q.lockSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockSigningKeysStmt: %w", cerr)
+		}
+	}
+	if q.retireActiveSigningKeysStmt != nil {
+		if cerr := q.retireActiveSigningKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing retireActiveSigningKeysStmt: %w", cerr)
+		}
+	}
+	if q.rotateTokenStmt != nil {
+		if cerr := q.rotateTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateTokenStmt: %w", cerr)
+		}
+	}
+	if q.updateBackchannelAuthRequestPollingStmt != nil {
+		if cerr := q.updateBackchannelAuthRequestPollingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateBackchannelAuthRequestPollingStmt: %w", cerr)
+		}
+	}
+	if q.updateBackchannelAuthRequestStatusStmt != nil {
+		if cerr := q.updateBackchannelAuthRequestStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateBackchannelAuthRequestStatusStmt: %w", cerr)
+		}
+	}
+	if q.updateClientAllowedGrantsStmt != nil {
+		if cerr := q.updateClientAllowedGrantsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientAllowedGrantsStmt: %w", cerr)
+		}
+	}
+	if q.updateClientAuthenticationStmt != nil {
+		if cerr := q.updateClientAuthenticationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientAuthenticationStmt: %w", cerr)
+		}
+	}
+	if q.updateClientMetadataStmt != nil {
+		if cerr := q.updateClientMetadataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientMetadataStmt: %w", cerr)
+		}
+	}
+	if q.updateClientRedirectURIsStmt != nil {
+		if cerr := q.updateClientRedirectURIsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientRedirectURIsStmt: %w", cerr)
+		}
+	}
+	if q.updateClientRequirePushedAuthorizationRequestsStmt != nil {
+		if cerr := q.updateClientRequirePushedAuthorizationRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientRequirePushedAuthorizationRequestsStmt: %w", cerr)
+		}
+	}
+	if q.updateClientResponseModesStmt != nil {
+		if cerr := q.updateClientResponseModesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientResponseModesStmt: %w", cerr)
+		}
+	}
 	if q.updateClientSecretStmt != nil {
 		if cerr := q.updateClientSecretStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateClientSecretStmt: %w", cerr)
 		}
 	}
+	if q.updateClientTokenLifetimesStmt != nil {
+		if cerr := q.updateClientTokenLifetimesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClientTokenLifetimesStmt: %w", cerr)
+		}
+	}
+	if q.updateDeviceCodePollingStmt != nil {
+		if cerr := q.updateDeviceCodePollingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDeviceCodePollingStmt: %w", cerr)
+		}
+	}
+	if q.updateDeviceCodeStatusStmt != nil {
+		if cerr := q.updateDeviceCodeStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDeviceCodeStatusStmt: %w", cerr)
+		}
+	}
+	if q.updateScopeStmt != nil {
+		if cerr := q.updateScopeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateScopeStmt: %w", cerr)
+		}
+	}
 	if q.updateUserEmailStmt != nil {
 		if cerr := q.updateUserEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateUserEmailStmt: %w", cerr)
@@ -245,6 +662,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserVerifiedAtStmt: %w", cerr)
 		}
 	}
+	if q.upsertResourceServerStmt != nil {
+		if cerr := q.upsertResourceServerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertResourceServerStmt: %w", cerr)
+		}
+	}
+	if q.upsertTokenExchangePolicyStmt != nil {
+		if cerr := q.upsertTokenExchangePolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertTokenExchangePolicyStmt: %w", cerr)
+		}
+	}
+	if q.upsertTrustedIssuerStmt != nil {
+		if cerr := q.upsertTrustedIssuerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertTrustedIssuerStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -282,67 +714,175 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                                  DBTX
-	tx                                  *sql.Tx
-	cleanUpExpiredUserVerificationsStmt *sql.Stmt
-	createClientStmt                    *sql.Stmt
-	createTokenStmt                     *sql.Stmt
-	createUserStmt                      *sql.Stmt
-	createUserVerificationStmt          *sql.Stmt
-	deleteByAccessStmt                  *sql.Stmt
-	deleteByCodeStmt                    *sql.Stmt
-	deleteByRefreshStmt                 *sql.Stmt
-	deleteClientStmt                    *sql.Stmt
-	deleteExpiredTokensStmt             *sql.Stmt
-	deleteUserStmt                      *sql.Stmt
-	deleteUserVerificationsByEmailStmt  *sql.Stmt
-	deleteUserVerificationsByUserIDStmt *sql.Stmt
-	getClientByIDStmt                   *sql.Stmt
-	getClientByUserIDStmt               *sql.Stmt
-	getTokenByAccessStmt                *sql.Stmt
-	getTokenByCodeStmt                  *sql.Stmt
-	getTokenByRefreshStmt               *sql.Stmt
-	getUserByEmailStmt                  *sql.Stmt
-	getUserByIDStmt                     *sql.Stmt
-	getUserVerificationByEmailStmt      *sql.Stmt
-	getUserVerificationByUserIDStmt     *sql.Stmt
-	getVerificationByUserIDAndEmailStmt *sql.Stmt
-	updateClientSecretStmt              *sql.Stmt
-	updateUserEmailStmt                 *sql.Stmt
-	updateUserPasswordStmt              *sql.Stmt
-	updateUserVerifiedAtStmt            *sql.Stmt
+	db                                                 DBTX
+	tx                                                 *sql.Tx
+	activateNextSigningKeysStmt                        *sql.Stmt
+	cleanUpExpiredUserVerificationsStmt                *sql.Stmt
+	createBackchannelAuthRequestStmt                   *sql.Stmt
+	createClientStmt                                   *sql.Stmt
+	createDeviceCodeStmt                               *sql.Stmt
+	createPushedAuthorizationRequestStmt               *sql.Stmt
+	createScopeStmt                                    *sql.Stmt
+	createSigningKeyStmt                               *sql.Stmt
+	createTokenStmt                                    *sql.Stmt
+	createUserStmt                                     *sql.Stmt
+	createUserConsentStmt                              *sql.Stmt
+	createUserVerificationStmt                         *sql.Stmt
+	deleteBackchannelAuthRequestStmt                   *sql.Stmt
+	deleteByAccessStmt                                 *sql.Stmt
+	deleteByCodeStmt                                   *sql.Stmt
+	deleteByRefreshStmt                                *sql.Stmt
+	deleteClientStmt                                   *sql.Stmt
+	deleteDeviceCodeStmt                               *sql.Stmt
+	deleteExpiredBackchannelAuthRequestsStmt           *sql.Stmt
+	deleteExpiredDeviceCodesStmt                       *sql.Stmt
+	deleteExpiredPushedAuthorizationRequestsStmt       *sql.Stmt
+	deleteExpiredTokensStmt                            *sql.Stmt
+	deleteNextSigningKeysStmt                          *sql.Stmt
+	deletePushedAuthorizationRequestStmt               *sql.Stmt
+	deleteResourceServerStmt                           *sql.Stmt
+	deleteRetiredSigningKeysStmt                       *sql.Stmt
+	deleteScopeStmt                                    *sql.Stmt
+	deleteTokensByClientIDStmt                         *sql.Stmt
+	deleteTokensByFamilyStmt                           *sql.Stmt
+	deleteTokensBySIDStmt                              *sql.Stmt
+	deleteTokensByUserIDStmt                           *sql.Stmt
+	deleteTokensByUserIDAndClientIDStmt                *sql.Stmt
+	deleteTrustedIssuerStmt                            *sql.Stmt
+	deleteUserStmt                                     *sql.Stmt
+	deleteUserVerificationsByEmailStmt                 *sql.Stmt
+	deleteUserVerificationsByUserIDStmt                *sql.Stmt
+	getBackchannelAuthRequestStmt                      *sql.Stmt
+	getBackchannelAuthRequestByApprovalCodeStmt        *sql.Stmt
+	getClientByIDStmt                                  *sql.Stmt
+	getClientByUserIDStmt                              *sql.Stmt
+	getDeviceCodeStmt                                  *sql.Stmt
+	getDeviceCodeByUserCodeStmt                        *sql.Stmt
+	getPushedAuthorizationRequestStmt                  *sql.Stmt
+	getResourceServerStmt                              *sql.Stmt
+	getResourceServersByClientIDStmt                   *sql.Stmt
+	getScopeStmt                                       *sql.Stmt
+	getScopesStmt                                      *sql.Stmt
+	getSigningKeysStmt                                 *sql.Stmt
+	getTokenByAccessStmt                               *sql.Stmt
+	getTokenByCodeStmt                                 *sql.Stmt
+	getTokenByRefreshStmt                              *sql.Stmt
+	getTokenExchangePolicyStmt                         *sql.Stmt
+	getTrustedIssuerStmt                               *sql.Stmt
+	getUserByEmailStmt                                 *sql.Stmt
+	getUserByIDStmt                                    *sql.Stmt
+	getUserConsentsStmt                                *sql.Stmt
+	getUserVerificationByEmailStmt                     *sql.Stmt
+	getUserVerificationByUserIDStmt                    *sql.Stmt
+	getVerificationByUserIDAndEmailStmt                *sql.Stmt
+	lockSigningKeysStmt                                *sql.Stmt
+	retireActiveSigningKeysStmt                        *sql.Stmt
+	rotateTokenStmt                                    *sql.Stmt
+	updateBackchannelAuthRequestPollingStmt            *sql.Stmt
+	updateBackchannelAuthRequestStatusStmt             *sql.Stmt
+	updateClientAllowedGrantsStmt                      *sql.Stmt
+	updateClientAuthenticationStmt                     *sql.Stmt
+	updateClientMetadataStmt                           *sql.Stmt
+	updateClientRedirectURIsStmt                       *sql.Stmt
+	updateClientRequirePushedAuthorizationRequestsStmt *sql.Stmt
+	updateClientResponseModesStmt                      *sql.Stmt
+	updateClientSecretStmt                             *sql.Stmt
+	updateClientTokenLifetimesStmt                     *sql.Stmt
+	updateDeviceCodePollingStmt                        *sql.Stmt
+	updateDeviceCodeStatusStmt                         *sql.Stmt
+	updateScopeStmt                                    *sql.Stmt
+	updateUserEmailStmt                                *sql.Stmt
+	updateUserPasswordStmt                             *sql.Stmt
+	updateUserVerifiedAtStmt                           *sql.Stmt
+	upsertResourceServerStmt                           *sql.Stmt
+	upsertTokenExchangePolicyStmt                      *sql.Stmt
+	upsertTrustedIssuerStmt                            *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                                  tx,
-		tx:                                  tx,
-		cleanUpExpiredUserVerificationsStmt: q.cleanUpExpiredUserVerificationsStmt,
-		createClientStmt:                    q.createClientStmt,
-		createTokenStmt:                     q.createTokenStmt,
-		createUserStmt:                      q.createUserStmt,
-		createUserVerificationStmt:          q.createUserVerificationStmt,
-		deleteByAccessStmt:                  q.deleteByAccessStmt,
-		deleteByCodeStmt:                    q.deleteByCodeStmt,
-		deleteByRefreshStmt:                 q.deleteByRefreshStmt,
-		deleteClientStmt:                    q.deleteClientStmt,
-		deleteExpiredTokensStmt:             q.deleteExpiredTokensStmt,
-		deleteUserStmt:                      q.deleteUserStmt,
-		deleteUserVerificationsByEmailStmt:  q.deleteUserVerificationsByEmailStmt,
-		deleteUserVerificationsByUserIDStmt: q.deleteUserVerificationsByUserIDStmt,
-		getClientByIDStmt:                   q.getClientByIDStmt,
-		getClientByUserIDStmt:               q.getClientByUserIDStmt,
-		getTokenByAccessStmt:                q.getTokenByAccessStmt,
-		getTokenByCodeStmt:                  q.getTokenByCodeStmt,
-		getTokenByRefreshStmt:               q.getTokenByRefreshStmt,
-		getUserByEmailStmt:                  q.getUserByEmailStmt,
-		getUserByIDStmt:                     q.getUserByIDStmt,
-		getUserVerificationByEmailStmt:      q.getUserVerificationByEmailStmt,
-		getUserVerificationByUserIDStmt:     q.getUserVerificationByUserIDStmt,
-		getVerificationByUserIDAndEmailStmt: q.getVerificationByUserIDAndEmailStmt,
-		updateClientSecretStmt:              q.updateClientSecretStmt,
-		updateUserEmailStmt:                 q.updateUserEmailStmt,
-		updateUserPasswordStmt:              q.updateUserPasswordStmt,
-		updateUserVerifiedAtStmt:            q.updateUserVerifiedAtStmt,
+		db:                                                 tx,
+		tx:                                                 tx,
+		activateNextSigningKeysStmt:                        q.activateNextSigningKeysStmt,
+		cleanUpExpiredUserVerificationsStmt:                q.cleanUpExpiredUserVerificationsStmt,
+		createBackchannelAuthRequestStmt:                   q.createBackchannelAuthRequestStmt,
+		createClientStmt:                                   q.createClientStmt,
+		createDeviceCodeStmt:                               q.createDeviceCodeStmt,
+		createPushedAuthorizationRequestStmt:               q.createPushedAuthorizationRequestStmt,
+		createScopeStmt:                                    q.createScopeStmt,
+		createSigningKeyStmt:                               q.createSigningKeyStmt,
+		createTokenStmt:                                    q.createTokenStmt,
+		createUserStmt:                                     q.createUserStmt,
+		createUserConsentStmt:                              q.createUserConsentStmt,
+		createUserVerificationStmt:                         q.createUserVerificationStmt,
+		deleteBackchannelAuthRequestStmt:                   q.deleteBackchannelAuthRequestStmt,
+		deleteByAccessStmt:                                 q.deleteByAccessStmt,
+		deleteByCodeStmt:                                   q.deleteByCodeStmt,
+		deleteByRefreshStmt:                                q.deleteByRefreshStmt,
+		deleteClientStmt:                                   q.deleteClientStmt,
+		deleteDeviceCodeStmt:                               q.deleteDeviceCodeStmt,
+		deleteExpiredBackchannelAuthRequestsStmt:           q.deleteExpiredBackchannelAuthRequestsStmt,
+		deleteExpiredDeviceCodesStmt:                       q.deleteExpiredDeviceCodesStmt,
+		deleteExpiredPushedAuthorizationRequestsStmt:       q.deleteExpiredPushedAuthorizationRequestsStmt,
+		deleteExpiredTokensStmt:                            q.deleteExpiredTokensStmt,
+		deleteNextSigningKeysStmt:                          q.deleteNextSigningKeysStmt,
+		deletePushedAuthorizationRequestStmt:               q.deletePushedAuthorizationRequestStmt,
+		deleteResourceServerStmt:                           q.deleteResourceServerStmt,
+		deleteRetiredSigningKeysStmt:                       q.deleteRetiredSigningKeysStmt,
+		deleteScopeStmt:                                    q.deleteScopeStmt,
+		deleteTokensByClientIDStmt:                         q.deleteTokensByClientIDStmt,
+		deleteTokensByFamilyStmt:                           q.deleteTokensByFamilyStmt,
+		deleteTokensBySIDStmt:                              q.deleteTokensBySIDStmt,
+		deleteTokensByUserIDStmt:                           q.deleteTokensByUserIDStmt,
+		deleteTokensByUserIDAndClientIDStmt:                q.deleteTokensByUserIDAndClientIDStmt,
+		deleteTrustedIssuerStmt:                            q.deleteTrustedIssuerStmt,
+		deleteUserStmt:                                     q.deleteUserStmt,
+		deleteUserVerificationsByEmailStmt:                 q.deleteUserVerificationsByEmailStmt,
+		deleteUserVerificationsByUserIDStmt:                q.deleteUserVerificationsByUserIDStmt,
+		getBackchannelAuthRequestStmt:                      q.getBackchannelAuthRequestStmt,
+		getBackchannelAuthRequestByApprovalCodeStmt:        q.getBackchannelAuthRequestByApprovalCodeStmt,
+		getClientByIDStmt:                                  q.getClientByIDStmt,
+		getClientByUserIDStmt:                              q.getClientByUserIDStmt,
+		getDeviceCodeStmt:                                  q.getDeviceCodeStmt,
+		getDeviceCodeByUserCodeStmt:                        q.getDeviceCodeByUserCodeStmt,
+		getPushedAuthorizationRequestStmt:                  q.getPushedAuthorizationRequestStmt,
+		getResourceServerStmt:                              q.getResourceServerStmt,
+		getResourceServersByClientIDStmt:                   q.getResourceServersByClientIDStmt,
+		getScopeStmt:                                       q.getScopeStmt,
+		getScopesStmt:                                      q.getScopesStmt,
+		getSigningKeysStmt:                                 q.getSigningKeysStmt,
+		getTokenByAccessStmt:                               q.getTokenByAccessStmt,
+		getTokenByCodeStmt:                                 q.getTokenByCodeStmt,
+		getTokenByRefreshStmt:                              q.getTokenByRefreshStmt,
+		getTokenExchangePolicyStmt:                         q.getTokenExchangePolicyStmt,
+		getTrustedIssuerStmt:                               q.getTrustedIssuerStmt,
+		getUserByEmailStmt:                                 q.getUserByEmailStmt,
+		getUserByIDStmt:                                    q.getUserByIDStmt,
+		getUserConsentsStmt:                                q.getUserConsentsStmt,
+		getUserVerificationByEmailStmt:                     q.getUserVerificationByEmailStmt,
+		getUserVerificationByUserIDStmt:                    q.getUserVerificationByUserIDStmt,
+		getVerificationByUserIDAndEmailStmt:                q.getVerificationByUserIDAndEmailStmt,
+		lockSigningKeysStmt:                                q.lockSigningKeysStmt,
+		retireActiveSigningKeysStmt:                        q.retireActiveSigningKeysStmt,
+		rotateTokenStmt:                                    q.rotateTokenStmt,
+		updateBackchannelAuthRequestPollingStmt:            q.updateBackchannelAuthRequestPollingStmt,
+		updateBackchannelAuthRequestStatusStmt:             q.updateBackchannelAuthRequestStatusStmt,
+		updateClientAllowedGrantsStmt:                      q.updateClientAllowedGrantsStmt,
+		updateClientAuthenticationStmt:                     q.updateClientAuthenticationStmt,
+		updateClientMetadataStmt:                           q.updateClientMetadataStmt,
+		updateClientRedirectURIsStmt:                       q.updateClientRedirectURIsStmt,
+		updateClientRequirePushedAuthorizationRequestsStmt: q.updateClientRequirePushedAuthorizationRequestsStmt,
+		updateClientResponseModesStmt:                      q.updateClientResponseModesStmt,
+		updateClientSecretStmt:                             q.updateClientSecretStmt,
+		updateClientTokenLifetimesStmt:                     q.updateClientTokenLifetimesStmt,
+		updateDeviceCodePollingStmt:                        q.updateDeviceCodePollingStmt,
+		updateDeviceCodeStatusStmt:                         q.updateDeviceCodeStatusStmt,
+		updateScopeStmt:                                    q.updateScopeStmt,
+		updateUserEmailStmt:                                q.updateUserEmailStmt,
+		updateUserPasswordStmt:                             q.updateUserPasswordStmt,
+		updateUserVerifiedAtStmt:                           q.updateUserVerifiedAtStmt,
+		upsertResourceServerStmt:                           q.upsertResourceServerStmt,
+		upsertTokenExchangePolicyStmt:                      q.upsertTokenExchangePolicyStmt,
+		upsertTrustedIssuerStmt:                            q.upsertTrustedIssuerStmt,
 	}
 }
@@ -13,6 +13,219 @@ import (
 	"github.com/google/uuid"
 )
 
+type BackchannelAuthStatus string
+
+const (
+	BackchannelAuthStatusPending  BackchannelAuthStatus = "pending"
+	BackchannelAuthStatusApproved BackchannelAuthStatus = "approved"
+	BackchannelAuthStatusDenied   BackchannelAuthStatus = "denied"
+)
+
+func (e *BackchannelAuthStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BackchannelAuthStatus(s)
+	case string:
+		*e = BackchannelAuthStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BackchannelAuthStatus: %T", src)
+	}
+	return nil
+}
+
+type NullBackchannelAuthStatus struct {
+	BackchannelAuthStatus BackchannelAuthStatus
+	Valid                 bool // Valid is true if BackchannelAuthStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBackchannelAuthStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.BackchannelAuthStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BackchannelAuthStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBackchannelAuthStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.BackchannelAuthStatus, nil
+}
+
+type DeviceCodeStatus string
+
+const (
+	DeviceCodeStatusPending  DeviceCodeStatus = "pending"
+	DeviceCodeStatusApproved DeviceCodeStatus = "approved"
+	DeviceCodeStatusDenied   DeviceCodeStatus = "denied"
+)
+
+func (e *DeviceCodeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DeviceCodeStatus(s)
+	case string:
+		*e = DeviceCodeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DeviceCodeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDeviceCodeStatus struct {
+	DeviceCodeStatus DeviceCodeStatus
+	Valid            bool // Valid is true if DeviceCodeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDeviceCodeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DeviceCodeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DeviceCodeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDeviceCodeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.DeviceCodeStatus, nil
+}
+
+type RefreshTokenExpiry string
+
+const (
+	RefreshTokenExpiryAbsolute RefreshTokenExpiry = "absolute"
+	RefreshTokenExpiryIdle     RefreshTokenExpiry = "idle"
+)
+
+func (e *RefreshTokenExpiry) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RefreshTokenExpiry(s)
+	case string:
+		*e = RefreshTokenExpiry(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RefreshTokenExpiry: %T", src)
+	}
+	return nil
+}
+
+type NullRefreshTokenExpiry struct {
+	RefreshTokenExpiry RefreshTokenExpiry
+	Valid              bool // Valid is true if RefreshTokenExpiry is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRefreshTokenExpiry) Scan(value interface{}) error {
+	if value == nil {
+		ns.RefreshTokenExpiry, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RefreshTokenExpiry.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRefreshTokenExpiry) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.RefreshTokenExpiry, nil
+}
+
+type ScopeResourceOwner string
+
+const (
+	ScopeResourceOwnerUser   ScopeResourceOwner = "user"
+	ScopeResourceOwnerClient ScopeResourceOwner = "client"
+)
+
+func (e *ScopeResourceOwner) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScopeResourceOwner(s)
+	case string:
+		*e = ScopeResourceOwner(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScopeResourceOwner: %T", src)
+	}
+	return nil
+}
+
+type NullScopeResourceOwner struct {
+	ScopeResourceOwner ScopeResourceOwner
+	Valid              bool // Valid is true if ScopeResourceOwner is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScopeResourceOwner) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScopeResourceOwner, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScopeResourceOwner.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScopeResourceOwner) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.ScopeResourceOwner, nil
+}
+
+type SigningKeyStatus string
+
+const (
+	SigningKeyStatusNext    SigningKeyStatus = "next"
+	SigningKeyStatusActive  SigningKeyStatus = "active"
+	SigningKeyStatusRetired SigningKeyStatus = "retired"
+)
+
+func (e *SigningKeyStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SigningKeyStatus(s)
+	case string:
+		*e = SigningKeyStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SigningKeyStatus: %T", src)
+	}
+	return nil
+}
+
+type NullSigningKeyStatus struct {
+	SigningKeyStatus SigningKeyStatus
+	Valid            bool // Valid is true if SigningKeyStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSigningKeyStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.SigningKeyStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SigningKeyStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSigningKeyStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.SigningKeyStatus, nil
+}
+
 type UserVerificationRequestType string
 
 const (
@@ -57,35 +270,150 @@ func (ns NullUserVerificationRequestType) Value() (driver.Value, error) {
 	return ns.UserVerificationRequestType, nil
 }
 
+type BackchannelAuthRequest struct {
+	AuthReqID               string                `json:"auth_req_id"`
+	ApprovalCode            string                `json:"approval_code"`
+	ClientID                string                `json:"client_id"`
+	UserID                  uuid.UUID             `json:"user_id"`
+	Scope                   string                `json:"scope"`
+	BindingMessage          string                `json:"binding_message"`
+	ClientNotificationToken string                `json:"client_notification_token"`
+	Status                  BackchannelAuthStatus `json:"status"`
+	PollInterval            int64                 `json:"poll_interval"`
+	LastPolledAt            sql.NullTime          `json:"last_polled_at"`
+	ExpiresAt               time.Time             `json:"expires_at"`
+	CreatedAt               time.Time             `json:"created_at"`
+}
+
 type Client struct {
-	ID            string    `json:"id"`
-	Secret        []byte    `json:"secret"`
-	Domain        string    `json:"domain"`
-	IsPublic      bool      `json:"is_public"`
-	UserID        uuid.UUID `json:"user_id"`
-	AllowedGrants []string  `json:"allowed_grants"`
-	Scope         string    `json:"scope"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                                    string             `json:"id"`
+	Secret                                []byte             `json:"secret"`
+	Domain                                string             `json:"domain"`
+	IsPublic                              bool               `json:"is_public"`
+	UserID                                uuid.NullUUID      `json:"user_id"`
+	AllowedGrants                         []string           `json:"allowed_grants"`
+	Scope                                 string             `json:"scope"`
+	CreatedAt                             time.Time          `json:"created_at"`
+	Name                                  string             `json:"name"`
+	RedirectUris                          []string           `json:"redirect_uris"`
+	TokenEndpointAuthMethod               string             `json:"token_endpoint_auth_method"`
+	Jwks                                  string             `json:"jwks"`
+	JwksUri                               string             `json:"jwks_uri"`
+	EncryptedSecret                       []byte             `json:"encrypted_secret"`
+	TlsClientAuthSubjectDn                string             `json:"tls_client_auth_subject_dn"`
+	RequirePushedAuthorizationRequests    bool               `json:"require_pushed_authorization_requests"`
+	RegistrationAccessToken               string             `json:"registration_access_token"`
+	BackchannelTokenDeliveryMode          string             `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint string             `json:"backchannel_client_notification_endpoint"`
+	ResponseModes                         []string           `json:"response_modes"`
+	PostLogoutRedirectUris                []string           `json:"post_logout_redirect_uris"`
+	FrontchannelLogoutUri                 string             `json:"frontchannel_logout_uri"`
+	BackchannelLogoutUri                  string             `json:"backchannel_logout_uri"`
+	AccessTokenTtl                        int64              `json:"access_token_ttl"`
+	RefreshTokenTtl                       int64              `json:"refresh_token_ttl"`
+	CodeTtl                               int64              `json:"code_ttl"`
+	IssueRefreshTokens                    bool               `json:"issue_refresh_tokens"`
+	RefreshTokenExpiry                    RefreshTokenExpiry `json:"refresh_token_expiry"`
+}
+
+type DeviceCode struct {
+	DeviceCode   string           `json:"device_code"`
+	UserCode     string           `json:"user_code"`
+	ClientID     string           `json:"client_id"`
+	UserID       uuid.NullUUID    `json:"user_id"`
+	Scope        string           `json:"scope"`
+	Status       DeviceCodeStatus `json:"status"`
+	PollInterval int64            `json:"poll_interval"`
+	LastPolledAt sql.NullTime     `json:"last_polled_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+type PushedAuthorizationRequest struct {
+	RequestUri string    `json:"request_uri"`
+	ClientID   string    `json:"client_id"`
+	Params     string    `json:"params"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ResourceServer struct {
+	Identifier string    `json:"identifier"`
+	Name       string    `json:"name"`
+	Scope      string    `json:"scope"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	ClientID   string    `json:"client_id"`
+}
+
+type Scope struct {
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	RequiresConsent bool               `json:"requires_consent"`
+	IsDefault       bool               `json:"is_default"`
+	ResourceOwner   ScopeResourceOwner `json:"resource_owner"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type SigningKey struct {
+	ID          string           `json:"id"`
+	Algorithm   string           `json:"algorithm"`
+	PrivateKey  []byte           `json:"private_key"`
+	PublicKey   []byte           `json:"public_key"`
+	Status      SigningKeyStatus `json:"status"`
+	ActivatedAt sql.NullTime     `json:"activated_at"`
+	RetiredAt   sql.NullTime     `json:"retired_at"`
+	CreatedAt   time.Time        `json:"created_at"`
 }
 
 type Token struct {
-	ID                  uuid.UUID     `json:"id"`
-	ClientID            string        `json:"client_id"`
-	UserID              uuid.NullUUID `json:"user_id"`
-	RedirectURI         string        `json:"redirect_uri"`
-	Scope               string        `json:"scope"`
-	Code                string        `json:"code"`
-	CodeCreatedAt       sql.NullTime  `json:"code_created_at"`
-	CodeExpiresIn       int64         `json:"code_expires_in"`
-	CodeChallenge       string        `json:"code_challenge"`
-	CodeChallengeMethod string        `json:"code_challenge_method"`
-	Access              string        `json:"access"`
-	AccessCreatedAt     sql.NullTime  `json:"access_created_at"`
-	AccessExpiresIn     int64         `json:"access_expires_in"`
-	Refresh             string        `json:"refresh"`
-	RefreshCreatedAt    sql.NullTime  `json:"refresh_created_at"`
-	RefreshExpiresIn    int64         `json:"refresh_expires_in"`
-	CreatedAt           time.Time     `json:"created_at"`
+	ID                   uuid.UUID     `json:"id"`
+	ClientID             string        `json:"client_id"`
+	UserID               uuid.NullUUID `json:"user_id"`
+	RedirectURI          string        `json:"redirect_uri"`
+	Scope                string        `json:"scope"`
+	Code                 string        `json:"code"`
+	CodeCreatedAt        sql.NullTime  `json:"code_created_at"`
+	CodeExpiresIn        int64         `json:"code_expires_in"`
+	CodeChallenge        string        `json:"code_challenge"`
+	CodeChallengeMethod  string        `json:"code_challenge_method"`
+	Access               string        `json:"access"`
+	AccessCreatedAt      sql.NullTime  `json:"access_created_at"`
+	AccessExpiresIn      int64         `json:"access_expires_in"`
+	Refresh              string        `json:"refresh"`
+	RefreshCreatedAt     sql.NullTime  `json:"refresh_created_at"`
+	RefreshExpiresIn     int64         `json:"refresh_expires_in"`
+	CreatedAt            time.Time     `json:"created_at"`
+	Nonce                string        `json:"nonce"`
+	AuthTime             sql.NullTime  `json:"auth_time"`
+	FamilyID             uuid.UUID     `json:"family_id"`
+	ParentID             uuid.NullUUID `json:"parent_id"`
+	RotatedAt            sql.NullTime  `json:"rotated_at"`
+	DpopJkt              string        `json:"dpop_jkt"`
+	Resources            []string      `json:"resources"`
+	AuthorizationDetails string        `json:"authorization_details"`
+	Acr                  string        `json:"acr"`
+	Amr                  []string      `json:"amr"`
+	Sid                  string        `json:"sid"`
+}
+
+type TokenExchangePolicy struct {
+	ClientID          string    `json:"client_id"`
+	SubjectTokenTypes []string  `json:"subject_token_types"`
+	Audiences         []string  `json:"audiences"`
+	Scope             string    `json:"scope"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type TrustedIssuer struct {
+	Issuer      string    `json:"issuer"`
+	Jwks        string    `json:"jwks"`
+	SubjectType string    `json:"subject_type"`
+	Scope       string    `json:"scope"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -97,6 +425,13 @@ type User struct {
 	VerifiedAt sql.NullTime `json:"verified_at"`
 }
 
+type UserConsent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	ClientID  string    `json:"client_id"`
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type UserVerification struct {
 	RequestType      UserVerificationRequestType `json:"request_type"`
 	UserID           uuid.UUID                   `json:"user_id"`
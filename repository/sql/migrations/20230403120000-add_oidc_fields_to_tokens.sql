@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE tokens 
+    ADD COLUMN nonce VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN auth_time TIMESTAMP DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE tokens 
+    DROP COLUMN IF EXISTS nonce,
+    DROP COLUMN IF EXISTS auth_time;
@@ -0,0 +1,24 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TYPE signing_key_status AS ENUM (
+  'next',
+  'active',
+  'retired'
+);
+
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id VARCHAR PRIMARY KEY,
+    algorithm VARCHAR NOT NULL,
+    private_key bytea NOT NULL,
+    public_key bytea NOT NULL,
+    status signing_key_status NOT NULL DEFAULT 'next',
+    activated_at TIMESTAMP DEFAULT NULL,
+    retired_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX signing_keys_status ON signing_keys USING BTREE (status);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS signing_keys;
+DROP TYPE IF EXISTS signing_key_status;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TYPE device_code_status AS ENUM (
+  'pending',
+  'approved',
+  'denied'
+);
+
+CREATE TABLE IF NOT EXISTS device_codes (
+    device_code VARCHAR PRIMARY KEY,
+    user_code VARCHAR NOT NULL,
+    client_id VARCHAR NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+    user_id uuid DEFAULT NULL REFERENCES users (id) ON DELETE CASCADE,
+    scope VARCHAR NOT NULL DEFAULT '',
+    status device_code_status NOT NULL DEFAULT 'pending',
+    poll_interval BIGINT NOT NULL DEFAULT 5,
+    last_polled_at TIMESTAMP DEFAULT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX device_codes_user_code ON device_codes USING BTREE (user_code);
+CREATE INDEX device_codes_expires_at ON device_codes USING BTREE (expires_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS device_codes;
+DROP TYPE IF EXISTS device_code_status;
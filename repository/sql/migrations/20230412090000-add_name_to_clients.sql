@@ -0,0 +1,9 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN name VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS user_consents (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    client_id VARCHAR NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+    scope VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, client_id, scope)
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS user_consents;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE tokens 
+    ADD COLUMN family_id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    ADD COLUMN parent_id uuid DEFAULT NULL REFERENCES tokens (id) ON DELETE SET NULL,
+    ADD COLUMN rotated_at TIMESTAMP DEFAULT NULL;
+CREATE INDEX tokens_family_id ON tokens USING BTREE (family_id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP INDEX IF EXISTS tokens_family_id;
+ALTER TABLE tokens 
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS rotated_at;
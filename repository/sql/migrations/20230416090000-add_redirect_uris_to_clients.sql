@@ -0,0 +1,9 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN redirect_uris VARCHAR[] NOT NULL DEFAULT '{}';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS redirect_uris;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS token_exchange_policies (
+    client_id VARCHAR PRIMARY KEY REFERENCES clients (id) ON DELETE CASCADE,
+    subject_token_types VARCHAR[] NOT NULL DEFAULT ARRAY['urn:ietf:params:oauth:token-type:access_token'],
+    audiences VARCHAR[] NOT NULL DEFAULT '{}',
+    scope VARCHAR NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS token_exchange_policies;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN token_endpoint_auth_method VARCHAR NOT NULL DEFAULT 'client_secret_post';
+ALTER TABLE clients 
+    ADD COLUMN jwks VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE clients 
+    ADD COLUMN jwks_uri VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE clients 
+    ADD COLUMN encrypted_secret bytea;
+UPDATE clients SET token_endpoint_auth_method = 'none' WHERE is_public;
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS encrypted_secret;
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS jwks_uri;
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS jwks;
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS token_endpoint_auth_method;
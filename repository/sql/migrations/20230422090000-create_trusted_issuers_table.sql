@@ -0,0 +1,14 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS trusted_issuers (
+    issuer VARCHAR PRIMARY KEY,
+    jwks VARCHAR NOT NULL,
+    subject_type VARCHAR NOT NULL DEFAULT 'email',
+    scope VARCHAR NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS trusted_issuers;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN tls_client_auth_subject_dn VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS tls_client_auth_subject_dn;
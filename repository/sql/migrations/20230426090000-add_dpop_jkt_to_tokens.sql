@@ -0,0 +1,9 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE tokens 
+    ADD COLUMN dpop_jkt VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE tokens 
+    DROP COLUMN IF EXISTS dpop_jkt;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS pushed_authorization_requests (
+    request_uri VARCHAR PRIMARY KEY,
+    client_id VARCHAR NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+    params TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX pushed_authorization_requests_expires_at ON pushed_authorization_requests USING BTREE (expires_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS pushed_authorization_requests;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN require_pushed_authorization_requests BOOLEAN NOT NULL DEFAULT false;
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS require_pushed_authorization_requests;
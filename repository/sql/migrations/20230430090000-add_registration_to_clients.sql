@@ -0,0 +1,12 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN registration_access_token VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+DELETE FROM clients WHERE user_id IS NULL;
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS registration_access_token,
+    ALTER COLUMN user_id SET NOT NULL;
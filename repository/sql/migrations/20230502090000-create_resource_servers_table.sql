@@ -0,0 +1,13 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS resource_servers (
+    identifier VARCHAR PRIMARY KEY,
+    name VARCHAR NOT NULL DEFAULT '',
+    scope VARCHAR NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS resource_servers;
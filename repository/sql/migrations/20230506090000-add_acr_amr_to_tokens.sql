@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE tokens 
+    ADD COLUMN acr VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN amr VARCHAR[] NOT NULL DEFAULT '{}';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE tokens 
+    DROP COLUMN IF EXISTS acr,
+    DROP COLUMN IF EXISTS amr;
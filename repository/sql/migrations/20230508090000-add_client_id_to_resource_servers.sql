@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE resource_servers 
+    ADD COLUMN client_id VARCHAR NOT NULL DEFAULT '';
+CREATE INDEX resource_servers_client_id ON resource_servers USING BTREE (client_id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP INDEX IF EXISTS resource_servers_client_id;
+ALTER TABLE resource_servers 
+    DROP COLUMN IF EXISTS client_id;
@@ -0,0 +1,29 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TYPE backchannel_auth_status AS ENUM (
+  'pending',
+  'approved',
+  'denied'
+);
+
+CREATE TABLE IF NOT EXISTS backchannel_auth_requests (
+    auth_req_id VARCHAR PRIMARY KEY,
+    approval_code VARCHAR NOT NULL,
+    client_id VARCHAR NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    scope VARCHAR NOT NULL DEFAULT '',
+    binding_message VARCHAR NOT NULL DEFAULT '',
+    client_notification_token VARCHAR NOT NULL DEFAULT '',
+    status backchannel_auth_status NOT NULL DEFAULT 'pending',
+    poll_interval BIGINT NOT NULL DEFAULT 5,
+    last_polled_at TIMESTAMP DEFAULT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX backchannel_auth_requests_approval_code ON backchannel_auth_requests USING BTREE (approval_code);
+CREATE INDEX backchannel_auth_requests_expires_at ON backchannel_auth_requests USING BTREE (expires_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS backchannel_auth_requests;
+DROP TYPE IF EXISTS backchannel_auth_status;
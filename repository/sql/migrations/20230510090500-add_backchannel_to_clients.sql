@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN backchannel_token_delivery_mode VARCHAR NOT NULL DEFAULT 'poll',
+    ADD COLUMN backchannel_client_notification_endpoint VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS backchannel_token_delivery_mode,
+    DROP COLUMN IF EXISTS backchannel_client_notification_endpoint;
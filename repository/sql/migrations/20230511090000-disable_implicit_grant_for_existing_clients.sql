@@ -0,0 +1,7 @@
+-- +migrate Up
+-- the implicit grant is disabled by default,
+-- the clients created before it used to have it enabled
+UPDATE clients SET allowed_grants = array_remove(allowed_grants, '__implicit');
+
+-- +migrate Down
+-- the implicit grant is enabled back per client with the client API or the cli
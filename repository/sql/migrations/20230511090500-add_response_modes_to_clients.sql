@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the client may use all supported response modes if empty
+ALTER TABLE clients 
+    ADD COLUMN response_modes VARCHAR[] NOT NULL DEFAULT '{}';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS response_modes;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE tokens 
+    ADD COLUMN sid VARCHAR NOT NULL DEFAULT '';
+CREATE INDEX tokens_sid ON tokens USING BTREE (sid) WHERE sid <> '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP INDEX IF EXISTS tokens_sid;
+ALTER TABLE tokens 
+    DROP COLUMN IF EXISTS sid;
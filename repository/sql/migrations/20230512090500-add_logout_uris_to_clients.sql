@@ -0,0 +1,13 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE clients 
+    ADD COLUMN post_logout_redirect_uris VARCHAR[] NOT NULL DEFAULT '{}',
+    ADD COLUMN frontchannel_logout_uri VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN backchannel_logout_uri VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS post_logout_redirect_uris,
+    DROP COLUMN IF EXISTS frontchannel_logout_uri,
+    DROP COLUMN IF EXISTS backchannel_logout_uri;
@@ -0,0 +1,31 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TYPE scope_resource_owner AS ENUM (
+  'user',
+  'client'
+);
+
+CREATE TABLE IF NOT EXISTS scopes (
+    name VARCHAR PRIMARY KEY,
+    description VARCHAR NOT NULL DEFAULT '',
+    requires_consent BOOLEAN NOT NULL DEFAULT true,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    resource_owner scope_resource_owner NOT NULL DEFAULT 'user',
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- the scopes which were hard-coded in the server options before the registry
+INSERT INTO scopes (name, description, requires_consent, is_default, resource_owner) VALUES
+    ('openid', 'Sign you in with your account', false, true, 'user'),
+    ('email', 'View your email address', true, true, 'user'),
+    ('user:read', 'View your profile', true, true, 'user'),
+    ('user:*', 'Manage your profile', true, false, 'user'),
+    ('client:read', 'View the applications you have registered', true, false, 'client'),
+    ('client:*', 'Manage the applications you have registered', true, false, 'client')
+ON CONFLICT (name) DO NOTHING;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TABLE IF EXISTS scopes;
+DROP TYPE IF EXISTS scope_resource_owner;
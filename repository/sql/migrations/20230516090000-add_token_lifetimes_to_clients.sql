@@ -0,0 +1,24 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TYPE refresh_token_expiry AS ENUM (
+  'absolute',
+  'idle'
+);
+
+-- the lifetimes are in seconds, the server defaults are applied if zero
+ALTER TABLE clients 
+    ADD COLUMN access_token_ttl BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN refresh_token_ttl BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN code_ttl BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN issue_refresh_tokens BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN refresh_token_expiry refresh_token_expiry NOT NULL DEFAULT 'absolute';
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE clients 
+    DROP COLUMN IF EXISTS access_token_ttl,
+    DROP COLUMN IF EXISTS refresh_token_ttl,
+    DROP COLUMN IF EXISTS code_ttl,
+    DROP COLUMN IF EXISTS issue_refresh_tokens,
+    DROP COLUMN IF EXISTS refresh_token_expiry;
+DROP TYPE IF EXISTS refresh_token_expiry;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE clients 
+    ALTER COLUMN token_endpoint_auth_method SET DEFAULT 'client_secret_basic';
+
+-- +migrate Down
+ALTER TABLE clients 
+    ALTER COLUMN token_endpoint_auth_method SET DEFAULT 'client_secret_post';
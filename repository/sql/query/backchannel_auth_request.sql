@@ -0,0 +1,21 @@
+-- name: CreateBackchannelAuthRequest :one
+INSERT INTO backchannel_auth_requests (auth_req_id, approval_code, client_id, user_id, scope, binding_message, client_notification_token, poll_interval, expires_at) 
+VALUES (@auth_req_id, @approval_code, @client_id, @user_id, @scope, @binding_message, @client_notification_token, @poll_interval, @expires_at) RETURNING *;
+
+-- name: GetBackchannelAuthRequest :one
+SELECT * FROM backchannel_auth_requests WHERE auth_req_id = @auth_req_id;
+
+-- name: GetBackchannelAuthRequestByApprovalCode :one
+SELECT * FROM backchannel_auth_requests WHERE approval_code = @approval_code;
+
+-- name: UpdateBackchannelAuthRequestStatus :execrows
+UPDATE backchannel_auth_requests SET status = @status WHERE approval_code = @approval_code AND user_id = @user_id AND status = 'pending';
+
+-- name: UpdateBackchannelAuthRequestPolling :exec
+UPDATE backchannel_auth_requests SET last_polled_at = now(), poll_interval = @poll_interval WHERE auth_req_id = @auth_req_id;
+
+-- name: DeleteBackchannelAuthRequest :execrows
+DELETE FROM backchannel_auth_requests WHERE auth_req_id = @auth_req_id;
+
+-- name: DeleteExpiredBackchannelAuthRequests :exec
+DELETE FROM backchannel_auth_requests WHERE expires_at < now();
@@ -1,6 +1,6 @@
 -- name: CreateClient :one
-INSERT INTO clients (id, secret, domain, is_public, user_id, allowed_grants, scope) 
-VALUES (@id, @secret, @domain, @is_public, @user_id, @allowed_grants, @scope) RETURNING *;
+INSERT INTO clients (id, name, secret, domain, is_public, user_id, allowed_grants, scope, redirect_uris, token_endpoint_auth_method, jwks, jwks_uri, encrypted_secret, tls_client_auth_subject_dn, require_pushed_authorization_requests, registration_access_token, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri) 
+VALUES (@id, @name, @secret, @domain, @is_public, @user_id, @allowed_grants, @scope, @redirect_uris, @token_endpoint_auth_method, @jwks, @jwks_uri, @encrypted_secret, @tls_client_auth_subject_dn, @require_pushed_authorization_requests, @registration_access_token, @backchannel_token_delivery_mode, @backchannel_client_notification_endpoint, @post_logout_redirect_uris, @frontchannel_logout_uri, @backchannel_logout_uri) RETURNING *;
 
 -- name: GetClientByID :one
 SELECT * FROM clients WHERE id = $1;
@@ -11,5 +11,58 @@ SELECT * FROM clients WHERE user_id = $1 ORDER BY created_at DESC;
 -- name: UpdateClientSecret :one
 UPDATE clients SET secret = $1 WHERE id = $2 RETURNING *;
 
+-- name: UpdateClientRedirectURIs :one
+UPDATE clients SET redirect_uris = @redirect_uris WHERE id = @id RETURNING *;
+
+-- name: UpdateClientAuthentication :one
+UPDATE clients 
+SET token_endpoint_auth_method = @token_endpoint_auth_method, 
+    jwks = @jwks, 
+    jwks_uri = @jwks_uri, 
+    secret = @secret, 
+    encrypted_secret = @encrypted_secret, 
+    tls_client_auth_subject_dn = @tls_client_auth_subject_dn 
+WHERE id = @id RETURNING *;
+
 -- name: DeleteClient :exec
-DELETE FROM clients WHERE id = $1;
\ No newline at end of file
+DELETE FROM clients WHERE id = $1;
+
+-- name: UpdateClientAllowedGrants :one
+UPDATE clients SET allowed_grants = @allowed_grants WHERE id = @id RETURNING *;
+
+-- name: UpdateClientResponseModes :one
+UPDATE clients SET response_modes = @response_modes WHERE id = @id RETURNING *;
+
+-- name: UpdateClientRequirePushedAuthorizationRequests :one
+UPDATE clients SET require_pushed_authorization_requests = @require_pushed_authorization_requests WHERE id = @id RETURNING *;
+
+-- name: UpdateClientMetadata :one
+UPDATE clients 
+SET name = @name, 
+    domain = @domain, 
+    is_public = @is_public, 
+    allowed_grants = @allowed_grants, 
+    scope = @scope, 
+    redirect_uris = @redirect_uris, 
+    token_endpoint_auth_method = @token_endpoint_auth_method, 
+    jwks = @jwks, 
+    jwks_uri = @jwks_uri, 
+    secret = @secret, 
+    encrypted_secret = @encrypted_secret, 
+    tls_client_auth_subject_dn = @tls_client_auth_subject_dn, 
+    require_pushed_authorization_requests = @require_pushed_authorization_requests, 
+    backchannel_token_delivery_mode = @backchannel_token_delivery_mode, 
+    backchannel_client_notification_endpoint = @backchannel_client_notification_endpoint, 
+    post_logout_redirect_uris = @post_logout_redirect_uris, 
+    frontchannel_logout_uri = @frontchannel_logout_uri, 
+    backchannel_logout_uri = @backchannel_logout_uri 
+WHERE id = @id RETURNING *;
+
+-- name: UpdateClientTokenLifetimes :one
+UPDATE clients 
+SET access_token_ttl = @access_token_ttl, 
+    refresh_token_ttl = @refresh_token_ttl, 
+    code_ttl = @code_ttl, 
+    issue_refresh_tokens = @issue_refresh_tokens, 
+    refresh_token_expiry = @refresh_token_expiry 
+WHERE id = @id RETURNING *;
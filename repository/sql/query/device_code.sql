@@ -0,0 +1,21 @@
+-- name: CreateDeviceCode :one
+INSERT INTO device_codes (device_code, user_code, client_id, scope, poll_interval, expires_at) 
+VALUES (@device_code, @user_code, @client_id, @scope, @poll_interval, @expires_at) RETURNING *;
+
+-- name: GetDeviceCode :one
+SELECT * FROM device_codes WHERE device_code = @device_code;
+
+-- name: GetDeviceCodeByUserCode :one
+SELECT * FROM device_codes WHERE user_code = @user_code;
+
+-- name: UpdateDeviceCodeStatus :execrows
+UPDATE device_codes SET status = @status, user_id = @user_id WHERE user_code = @user_code AND status = 'pending';
+
+-- name: UpdateDeviceCodePolling :exec
+UPDATE device_codes SET last_polled_at = now(), poll_interval = @poll_interval WHERE device_code = @device_code;
+
+-- name: DeleteDeviceCode :execrows
+DELETE FROM device_codes WHERE device_code = @device_code;
+
+-- name: DeleteExpiredDeviceCodes :exec
+DELETE FROM device_codes WHERE expires_at < now();
@@ -0,0 +1,12 @@
+-- name: CreatePushedAuthorizationRequest :one
+INSERT INTO pushed_authorization_requests (request_uri, client_id, params, expires_at) 
+VALUES (@request_uri, @client_id, @params, @expires_at) RETURNING *;
+
+-- name: GetPushedAuthorizationRequest :one
+SELECT * FROM pushed_authorization_requests WHERE request_uri = @request_uri;
+
+-- name: DeletePushedAuthorizationRequest :exec
+DELETE FROM pushed_authorization_requests WHERE request_uri = @request_uri;
+
+-- name: DeleteExpiredPushedAuthorizationRequests :exec
+DELETE FROM pushed_authorization_requests WHERE expires_at < now();
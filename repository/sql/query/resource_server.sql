@@ -0,0 +1,18 @@
+-- name: UpsertResourceServer :one
+INSERT INTO resource_servers (identifier, name, scope, client_id) 
+VALUES (@identifier, @name, @scope, @client_id) 
+ON CONFLICT (identifier) DO UPDATE 
+SET name = EXCLUDED.name, 
+    scope = EXCLUDED.scope, 
+    client_id = EXCLUDED.client_id, 
+    updated_at = now() 
+RETURNING *;
+
+-- name: GetResourceServer :one
+SELECT * FROM resource_servers WHERE identifier = @identifier;
+
+-- name: GetResourceServersByClientID :many
+SELECT * FROM resource_servers WHERE client_id = @client_id;
+
+-- name: DeleteResourceServer :exec
+DELETE FROM resource_servers WHERE identifier = @identifier;
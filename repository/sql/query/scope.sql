@@ -0,0 +1,21 @@
+-- name: CreateScope :one
+INSERT INTO scopes (name, description, requires_consent, is_default, resource_owner) 
+VALUES (@name, @description, @requires_consent, @is_default, @resource_owner) RETURNING *;
+
+-- name: GetScope :one
+SELECT * FROM scopes WHERE name = @name;
+
+-- name: GetScopes :many
+SELECT * FROM scopes ORDER BY name;
+
+-- name: UpdateScope :one
+UPDATE scopes 
+SET description = @description, 
+    requires_consent = @requires_consent, 
+    is_default = @is_default, 
+    resource_owner = @resource_owner, 
+    updated_at = now() 
+WHERE name = @name RETURNING *;
+
+-- name: DeleteScope :exec
+DELETE FROM scopes WHERE name = @name;
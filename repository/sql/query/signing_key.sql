@@ -0,0 +1,21 @@
+-- name: CreateSigningKey :one
+INSERT INTO signing_keys (id, algorithm, private_key, public_key, status, activated_at) 
+VALUES (@id, @algorithm, @private_key, @public_key, @status, @activated_at) RETURNING *;
+
+-- name: GetSigningKeys :many
+SELECT * FROM signing_keys ORDER BY created_at DESC;
+
+-- name: RetireActiveSigningKeys :exec
+UPDATE signing_keys SET status = 'retired', retired_at = now() WHERE status = 'active';
+
+-- name: ActivateNextSigningKeys :execrows
+UPDATE signing_keys SET status = 'active', activated_at = now() WHERE status = 'next';
+
+-- name: DeleteRetiredSigningKeys :exec
+DELETE FROM signing_keys WHERE status = 'retired' AND retired_at < @retired_before::timestamp;
+
+-- name: DeleteNextSigningKeys :exec
+DELETE FROM signing_keys WHERE status = 'next';
+
+-- name: LockSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('signing_keys'));
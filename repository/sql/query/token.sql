@@ -14,7 +14,17 @@ INSERT INTO tokens (
     access_expires_in,
     refresh,
     refresh_created_at,
-    refresh_expires_in
+    refresh_expires_in,
+    nonce,
+    auth_time,
+    family_id,
+    parent_id,
+    dpop_jkt,
+    resources,
+    authorization_details,
+    acr,
+    amr,
+    sid
 ) VALUES (
     @client_id, 
     @user_id, 
@@ -30,7 +40,17 @@ INSERT INTO tokens (
     @access_expires_in,
     @refresh,
     @refresh_created_at,
-    @refresh_expires_in
+    @refresh_expires_in,
+    @nonce,
+    @auth_time,
+    @family_id,
+    @parent_id,
+    @dpop_jkt,
+    @resources,
+    @authorization_details,
+    @acr,
+    @amr,
+    @sid
 ) RETURNING *;
 
 -- name: GetTokenByCode :one
@@ -49,9 +69,27 @@ DELETE FROM tokens WHERE code = @code;
 DELETE FROM tokens WHERE access = @access;
 
 -- name: DeleteByRefresh :exec
-DELETE FROM tokens WHERE refresh = @refresh;
+DELETE FROM tokens WHERE refresh = @refresh AND rotated_at IS NULL;
+
+-- name: RotateToken :execrows
+UPDATE tokens SET access = '', rotated_at = now() WHERE id = @id AND rotated_at IS NULL;
+
+-- name: DeleteTokensByFamily :exec
+DELETE FROM tokens WHERE family_id = @family_id;
+
+-- name: DeleteTokensByUserID :execrows
+DELETE FROM tokens WHERE user_id = @user_id;
+
+-- name: DeleteTokensByClientID :execrows
+DELETE FROM tokens WHERE client_id = @client_id;
+
+-- name: DeleteTokensByUserIDAndClientID :execrows
+DELETE FROM tokens WHERE user_id = @user_id AND client_id = @client_id;
 
 -- name: DeleteExpiredTokens :exec
 DELETE FROM tokens 
 WHERE (code_expires_in > 0 AND code_created_at + code_expires_in * interval '1 second' < now())
-OR (refresh_expires_in > 0 AND refresh_created_at + refresh_expires_in * interval '1 second' < now());
\ No newline at end of file
+OR (refresh_expires_in > 0 AND refresh_created_at + refresh_expires_in * interval '1 second' < now());
+
+-- name: DeleteTokensBySID :execrows
+DELETE FROM tokens WHERE sid = @sid AND sid <> '';
@@ -0,0 +1,12 @@
+-- name: UpsertTokenExchangePolicy :one
+INSERT INTO token_exchange_policies (client_id, subject_token_types, audiences, scope) 
+VALUES (@client_id, @subject_token_types, @audiences, @scope) 
+ON CONFLICT (client_id) DO UPDATE 
+SET subject_token_types = EXCLUDED.subject_token_types, 
+    audiences = EXCLUDED.audiences, 
+    scope = EXCLUDED.scope, 
+    updated_at = now() 
+RETURNING *;
+
+-- name: GetTokenExchangePolicy :one
+SELECT * FROM token_exchange_policies WHERE client_id = @client_id;
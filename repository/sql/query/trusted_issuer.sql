@@ -0,0 +1,15 @@
+-- name: UpsertTrustedIssuer :one
+INSERT INTO trusted_issuers (issuer, jwks, subject_type, scope) 
+VALUES (@issuer, @jwks, @subject_type, @scope) 
+ON CONFLICT (issuer) DO UPDATE 
+SET jwks = EXCLUDED.jwks, 
+    subject_type = EXCLUDED.subject_type, 
+    scope = EXCLUDED.scope, 
+    updated_at = now() 
+RETURNING *;
+
+-- name: GetTrustedIssuer :one
+SELECT * FROM trusted_issuers WHERE issuer = @issuer;
+
+-- name: DeleteTrustedIssuer :exec
+DELETE FROM trusted_issuers WHERE issuer = @issuer;
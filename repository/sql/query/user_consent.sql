@@ -0,0 +1,6 @@
+-- name: CreateUserConsent :exec
+INSERT INTO user_consents (user_id, client_id, scope) 
+VALUES (@user_id, @client_id, @scope) ON CONFLICT (user_id, client_id, scope) DO NOTHING;
+
+-- name: GetUserConsents :many
+SELECT * FROM user_consents WHERE user_id = @user_id AND client_id = @client_id;
@@ -10,6 +10,7 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createToken = `-- name: CreateToken :one
@@ -28,7 +29,17 @@ INSERT INTO tokens (
     access_expires_in,
     refresh,
     refresh_created_at,
-    refresh_expires_in
+    refresh_expires_in,
+    nonce,
+    auth_time,
+    family_id,
+    parent_id,
+    dpop_jkt,
+    resources,
+    authorization_details,
+    acr,
+    amr,
+    sid
 ) VALUES (
     $1, 
     $2, 
@@ -44,26 +55,46 @@ INSERT INTO tokens (
     $12,
     $13,
     $14,
-    $15
-) RETURNING id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at
+    $15,
+    $16,
+    $17,
+    $18,
+    $19,
+    $20,
+    $21,
+    $22,
+    $23,
+    $24,
+    $25
+) RETURNING id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at, nonce, auth_time, family_id, parent_id, rotated_at, dpop_jkt, resources, authorization_details, acr, amr, sid
 `
 
 type CreateTokenParams struct {
-	ClientID            string        `json:"client_id"`
-	UserID              uuid.NullUUID `json:"user_id"`
-	RedirectURI         string        `json:"redirect_uri"`
-	Scope               string        `json:"scope"`
-	Code                string        `json:"code"`
-	CodeCreatedAt       sql.NullTime  `json:"code_created_at"`
-	CodeExpiresIn       int64         `json:"code_expires_in"`
-	CodeChallenge       string        `json:"code_challenge"`
-	CodeChallengeMethod string        `json:"code_challenge_method"`
-	Access              string        `json:"access"`
-	AccessCreatedAt     sql.NullTime  `json:"access_created_at"`
-	AccessExpiresIn     int64         `json:"access_expires_in"`
-	Refresh             string        `json:"refresh"`
-	RefreshCreatedAt    sql.NullTime  `json:"refresh_created_at"`
-	RefreshExpiresIn    int64         `json:"refresh_expires_in"`
+	ClientID             string        `json:"client_id"`
+	UserID               uuid.NullUUID `json:"user_id"`
+	RedirectURI          string        `json:"redirect_uri"`
+	Scope                string        `json:"scope"`
+	Code                 string        `json:"code"`
+	CodeCreatedAt        sql.NullTime  `json:"code_created_at"`
+	CodeExpiresIn        int64         `json:"code_expires_in"`
+	CodeChallenge        string        `json:"code_challenge"`
+	CodeChallengeMethod  string        `json:"code_challenge_method"`
+	Access               string        `json:"access"`
+	AccessCreatedAt      sql.NullTime  `json:"access_created_at"`
+	AccessExpiresIn      int64         `json:"access_expires_in"`
+	Refresh              string        `json:"refresh"`
+	RefreshCreatedAt     sql.NullTime  `json:"refresh_created_at"`
+	RefreshExpiresIn     int64         `json:"refresh_expires_in"`
+	Nonce                string        `json:"nonce"`
+	AuthTime             sql.NullTime  `json:"auth_time"`
+	FamilyID             uuid.UUID     `json:"family_id"`
+	ParentID             uuid.NullUUID `json:"parent_id"`
+	DpopJkt              string        `json:"dpop_jkt"`
+	Resources            []string      `json:"resources"`
+	AuthorizationDetails string        `json:"authorization_details"`
+	Acr                  string        `json:"acr"`
+	Amr                  []string      `json:"amr"`
+	Sid                  string        `json:"sid"`
 }
 
 func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token, error) {
@@ -83,6 +114,16 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token
 		arg.Refresh,
 		arg.RefreshCreatedAt,
 		arg.RefreshExpiresIn,
+		arg.Nonce,
+		arg.AuthTime,
+		arg.FamilyID,
+		arg.ParentID,
+		arg.DpopJkt,
+		pq.Array(arg.Resources),
+		arg.AuthorizationDetails,
+		arg.Acr,
+		pq.Array(arg.Amr),
+		arg.Sid,
 	)
 	var i Token
 	err := row.Scan(
@@ -103,6 +144,17 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token
 		&i.RefreshCreatedAt,
 		&i.RefreshExpiresIn,
 		&i.CreatedAt,
+		&i.Nonce,
+		&i.AuthTime,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+		&i.DpopJkt,
+		pq.Array(&i.Resources),
+		&i.AuthorizationDetails,
+		&i.Acr,
+		pq.Array(&i.Amr),
+		&i.Sid,
 	)
 	return i, err
 }
@@ -126,7 +178,7 @@ func (q *Queries) DeleteByCode(ctx context.Context, code string) error {
 }
 
 const deleteByRefresh = `-- name: DeleteByRefresh :exec
-DELETE FROM tokens WHERE refresh = $1
+DELETE FROM tokens WHERE refresh = $1 AND rotated_at IS NULL
 `
 
 func (q *Queries) DeleteByRefresh(ctx context.Context, refresh string) error {
@@ -145,8 +197,70 @@ func (q *Queries) DeleteExpiredTokens(ctx context.Context) error {
 	return err
 }
 
+const deleteTokensByClientID = `-- name: DeleteTokensByClientID :execrows
+DELETE FROM tokens WHERE client_id = $1
+`
+
+func (q *Queries) DeleteTokensByClientID(ctx context.Context, clientID string) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTokensByClientIDStmt, deleteTokensByClientID, clientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTokensByFamily = `-- name: DeleteTokensByFamily :exec
+DELETE FROM tokens WHERE family_id = $1
+`
+
+func (q *Queries) DeleteTokensByFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.exec(ctx, q.deleteTokensByFamilyStmt, deleteTokensByFamily, familyID)
+	return err
+}
+
+const deleteTokensBySID = `-- name: DeleteTokensBySID :execrows
+DELETE FROM tokens WHERE sid = $1 AND sid <> ''
+`
+
+func (q *Queries) DeleteTokensBySID(ctx context.Context, sid string) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTokensBySIDStmt, deleteTokensBySID, sid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTokensByUserID = `-- name: DeleteTokensByUserID :execrows
+DELETE FROM tokens WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTokensByUserID(ctx context.Context, userID uuid.NullUUID) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTokensByUserIDStmt, deleteTokensByUserID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTokensByUserIDAndClientID = `-- name: DeleteTokensByUserIDAndClientID :execrows
+DELETE FROM tokens WHERE user_id = $1 AND client_id = $2
+`
+
+type DeleteTokensByUserIDAndClientIDParams struct {
+	UserID   uuid.NullUUID `json:"user_id"`
+	ClientID string        `json:"client_id"`
+}
+
+func (q *Queries) DeleteTokensByUserIDAndClientID(ctx context.Context, arg DeleteTokensByUserIDAndClientIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTokensByUserIDAndClientIDStmt, deleteTokensByUserIDAndClientID, arg.UserID, arg.ClientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getTokenByAccess = `-- name: GetTokenByAccess :one
-SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at FROM tokens WHERE access = $1
+SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at, nonce, auth_time, family_id, parent_id, rotated_at, dpop_jkt, resources, authorization_details, acr, amr, sid FROM tokens WHERE access = $1
 `
 
 func (q *Queries) GetTokenByAccess(ctx context.Context, access string) (Token, error) {
@@ -170,12 +284,23 @@ func (q *Queries) GetTokenByAccess(ctx context.Context, access string) (Token, e
 		&i.RefreshCreatedAt,
 		&i.RefreshExpiresIn,
 		&i.CreatedAt,
+		&i.Nonce,
+		&i.AuthTime,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+		&i.DpopJkt,
+		pq.Array(&i.Resources),
+		&i.AuthorizationDetails,
+		&i.Acr,
+		pq.Array(&i.Amr),
+		&i.Sid,
 	)
 	return i, err
 }
 
 const getTokenByCode = `-- name: GetTokenByCode :one
-SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at FROM tokens WHERE code = $1
+SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at, nonce, auth_time, family_id, parent_id, rotated_at, dpop_jkt, resources, authorization_details, acr, amr, sid FROM tokens WHERE code = $1
 `
 
 func (q *Queries) GetTokenByCode(ctx context.Context, code string) (Token, error) {
@@ -199,12 +324,23 @@ func (q *Queries) GetTokenByCode(ctx context.Context, code string) (Token, error
 		&i.RefreshCreatedAt,
 		&i.RefreshExpiresIn,
 		&i.CreatedAt,
+		&i.Nonce,
+		&i.AuthTime,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+		&i.DpopJkt,
+		pq.Array(&i.Resources),
+		&i.AuthorizationDetails,
+		&i.Acr,
+		pq.Array(&i.Amr),
+		&i.Sid,
 	)
 	return i, err
 }
 
 const getTokenByRefresh = `-- name: GetTokenByRefresh :one
-SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at FROM tokens WHERE refresh = $1
+SELECT id, client_id, user_id, redirect_uri, scope, code, code_created_at, code_expires_in, code_challenge, code_challenge_method, access, access_created_at, access_expires_in, refresh, refresh_created_at, refresh_expires_in, created_at, nonce, auth_time, family_id, parent_id, rotated_at, dpop_jkt, resources, authorization_details, acr, amr, sid FROM tokens WHERE refresh = $1
 `
 
 func (q *Queries) GetTokenByRefresh(ctx context.Context, refresh string) (Token, error) {
@@ -228,6 +364,29 @@ func (q *Queries) GetTokenByRefresh(ctx context.Context, refresh string) (Token,
 		&i.RefreshCreatedAt,
 		&i.RefreshExpiresIn,
 		&i.CreatedAt,
+		&i.Nonce,
+		&i.AuthTime,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+		&i.DpopJkt,
+		pq.Array(&i.Resources),
+		&i.AuthorizationDetails,
+		&i.Acr,
+		pq.Array(&i.Amr),
+		&i.Sid,
 	)
 	return i, err
 }
+
+const rotateToken = `-- name: RotateToken :execrows
+UPDATE tokens SET access = '', rotated_at = now() WHERE id = $1 AND rotated_at IS NULL
+`
+
+func (q *Queries) RotateToken(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.exec(ctx, q.rotateTokenStmt, rotateToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,9 +2,13 @@ package client
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"strings"
 
 	"github.com/dmitrymomot/oauth2-server/internal/validator"
 	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+	jwtkit "github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -17,6 +21,14 @@ type (
 		GetByID     endpoint.Endpoint
 		GetByUserID endpoint.Endpoint
 		Delete      endpoint.Endpoint
+
+		UpdateRedirectURIs   endpoint.Endpoint
+		UpdateAuthentication endpoint.Endpoint
+
+		UpdatePushedAuthorization endpoint.Endpoint
+		UpdateImplicitGrant       endpoint.Endpoint
+		UpdateResponseModes       endpoint.Endpoint
+		UpdateTokenLifetimes      endpoint.Endpoint
 	}
 
 	ClientResponse struct {
@@ -33,6 +45,14 @@ func MakeEndpoints(s Service, m ...endpoint.Middleware) Endpoints {
 		GetByID:     MakeGetByIDEndpoint(s),
 		Delete:      MakeDeleteEndpoint(s),
 		GetByUserID: MakeGetByUserIDEndpoint(s),
+
+		UpdateRedirectURIs:   MakeUpdateRedirectURIsEndpoint(s),
+		UpdateAuthentication: MakeUpdateAuthenticationEndpoint(s),
+
+		UpdatePushedAuthorization: MakeUpdatePushedAuthorizationEndpoint(s),
+		UpdateImplicitGrant:       MakeUpdateImplicitGrantEndpoint(s),
+		UpdateResponseModes:       MakeUpdateResponseModesEndpoint(s),
+		UpdateTokenLifetimes:      MakeUpdateTokenLifetimesEndpoint(s),
 	}
 
 	for _, mdw := range m {
@@ -40,6 +60,12 @@ func MakeEndpoints(s Service, m ...endpoint.Middleware) Endpoints {
 		e.GetByID = mdw(e.GetByID)
 		e.Delete = mdw(e.Delete)
 		e.GetByUserID = mdw(e.GetByUserID)
+		e.UpdateRedirectURIs = mdw(e.UpdateRedirectURIs)
+		e.UpdateAuthentication = mdw(e.UpdateAuthentication)
+		e.UpdatePushedAuthorization = mdw(e.UpdatePushedAuthorization)
+		e.UpdateImplicitGrant = mdw(e.UpdateImplicitGrant)
+		e.UpdateResponseModes = mdw(e.UpdateResponseModes)
+		e.UpdateTokenLifetimes = mdw(e.UpdateTokenLifetimes)
 	}
 
 	return e
@@ -47,8 +73,16 @@ func MakeEndpoints(s Service, m ...endpoint.Middleware) Endpoints {
 
 // CreateRequest is a request for the Create method.
 type CreateRequest struct {
+	Name   string `json:"name" validate:"maxLen:100" filter:"trim|escapeJs|escapeHtml" label:"Name"`
 	Domain string `json:"domain" validate:"required|fullUrl" filter:"trim|lower|escapeJs|escapeHtml" label:"Domain"`
 	Public bool   `json:"is_public" validate:"bool" label:"Is Public"`
+
+	RedirectURIs []string `json:"redirect_uris" label:"Redirect URIs"`
+
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method" label:"Token Endpoint Auth Method"`
+	JWKS                    json.RawMessage `json:"jwks" label:"JWKS"`
+	JWKSURI                 string          `json:"jwks_uri" label:"JWKS URI"`
+	TLSClientAuthSubjectDN  string          `json:"tls_client_auth_subject_dn" label:"TLS Client Auth Subject DN"`
 }
 
 // MakeCreateEndpoint returns an endpoint via the passed service.
@@ -67,7 +101,12 @@ func MakeCreateEndpoint(s Service) endpoint.Endpoint {
 			return nil, validator.NewValidationError(v)
 		}
 
-		client, err := s.Create(ctx, tokenInfo.UserID, req.Domain, req.Public)
+		client, err := s.Create(ctx, tokenInfo.UserID, req.Name, req.Domain, req.Public, req.RedirectURIs, Authentication{
+			Method:    req.TokenEndpointAuthMethod,
+			JWKS:      req.JWKS,
+			JWKSURI:   req.JWKSURI,
+			SubjectDN: req.TLSClientAuthSubjectDN,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -93,7 +132,8 @@ func MakeGetByIDEndpoint(s Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if tokenInfo.ClientID != client.ID && tokenInfo.UserID != client.UserID {
+		// the dynamically registered clients have no owner
+		if tokenInfo.ClientID != client.ID && (client.UserID == "" || tokenInfo.UserID != client.UserID) {
 			return nil, ErrForbidden
 		}
 
@@ -118,6 +158,247 @@ func MakeGetByUserIDEndpoint(s Service) endpoint.Endpoint {
 	}
 }
 
+// UpdateRedirectURIsRequest is a request for the UpdateRedirectURIs method.
+type UpdateRedirectURIsRequest struct {
+	ID           string   `json:"-"`
+	RedirectURIs []string `json:"redirect_uris" label:"Redirect URIs"`
+}
+
+// MakeUpdateRedirectURIsEndpoint returns an endpoint via the passed service.
+func MakeUpdateRedirectURIsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdateRedirectURIsRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdateRedirectURIs(ctx, client.ID, req.RedirectURIs)
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
+// UpdateAuthenticationRequest is a request for the UpdateAuthentication method.
+type UpdateAuthenticationRequest struct {
+	ID                      string          `json:"-"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method" label:"Token Endpoint Auth Method"`
+	JWKS                    json.RawMessage `json:"jwks" label:"JWKS"`
+	JWKSURI                 string          `json:"jwks_uri" label:"JWKS URI"`
+	TLSClientAuthSubjectDN  string          `json:"tls_client_auth_subject_dn" label:"TLS Client Auth Subject DN"`
+}
+
+// MakeUpdateAuthenticationEndpoint returns an endpoint via the passed service.
+func MakeUpdateAuthenticationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdateAuthenticationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdateAuthentication(ctx, client.ID, Authentication{
+			Method:    req.TokenEndpointAuthMethod,
+			JWKS:      req.JWKS,
+			JWKSURI:   req.JWKSURI,
+			SubjectDN: req.TLSClientAuthSubjectDN,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
+// UpdatePushedAuthorizationRequest is a request for the UpdatePushedAuthorization method.
+type UpdatePushedAuthorizationRequest struct {
+	ID                                 string `json:"-"`
+	RequirePushedAuthorizationRequests bool   `json:"require_pushed_authorization_requests" label:"Require Pushed Authorization Requests"`
+}
+
+// MakeUpdatePushedAuthorizationEndpoint returns an endpoint via the passed service.
+func MakeUpdatePushedAuthorizationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdatePushedAuthorizationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdatePushedAuthorization(ctx, client.ID, req.RequirePushedAuthorizationRequests)
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
+// UpdateImplicitGrantRequest is a request for the UpdateImplicitGrant method.
+type UpdateImplicitGrantRequest struct {
+	ID       string `json:"-"`
+	Implicit bool   `json:"implicit" label:"Implicit"`
+}
+
+// MakeUpdateImplicitGrantEndpoint returns an endpoint via the passed service.
+func MakeUpdateImplicitGrantEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdateImplicitGrantRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdateImplicitGrant(ctx, client.ID, req.Implicit)
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
+// UpdateResponseModesRequest is a request for the UpdateResponseModes method.
+type UpdateResponseModesRequest struct {
+	ID            string   `json:"-"`
+	ResponseModes []string `json:"response_modes" label:"Response Modes"`
+}
+
+// MakeUpdateResponseModesEndpoint returns an endpoint via the passed service.
+func MakeUpdateResponseModesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdateResponseModesRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdateResponseModes(ctx, client.ID, req.ResponseModes)
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
+// UpdateTokenLifetimesRequest is a request for the UpdateTokenLifetimes method.
+// The refresh tokens are issued if IssueRefreshTokens is omitted.
+type UpdateTokenLifetimesRequest struct {
+	ID                 string `json:"-"`
+	AccessTokenTTL     int64  `json:"access_token_ttl" label:"Access Token TTL"`
+	RefreshTokenTTL    int64  `json:"refresh_token_ttl" label:"Refresh Token TTL"`
+	CodeTTL            int64  `json:"code_ttl" label:"Code TTL"`
+	IssueRefreshTokens *bool  `json:"issue_refresh_tokens" label:"Issue Refresh Tokens"`
+	RefreshTokenExpiry string `json:"refresh_token_expiry" label:"Refresh Token Expiry"`
+}
+
+// MakeUpdateTokenLifetimesEndpoint returns an endpoint via the passed service.
+func MakeUpdateTokenLifetimesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(UpdateTokenLifetimesRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		client, err := s.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenInfo.UserID != client.UserID {
+			return nil, ErrForbidden
+		}
+
+		client, err = s.UpdateTokenLifetimes(ctx, client.ID, TokenLifetimes{
+			AccessTokenTTL:     req.AccessTokenTTL,
+			RefreshTokenTTL:    req.RefreshTokenTTL,
+			CodeTTL:            req.CodeTTL,
+			IssueRefreshTokens: req.IssueRefreshTokens == nil || *req.IssueRefreshTokens,
+			RefreshTokenExpiry: req.RefreshTokenExpiry,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return ClientResponse{Client: client}, nil
+	}
+}
+
 // MakeDeleteEndpoint returns an endpoint via the passed service.
 func MakeDeleteEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -147,3 +428,125 @@ func MakeDeleteEndpoint(s Service) endpoint.Endpoint {
 		return true, nil
 	}
 }
+
+// RegistrationEndpoints collects the endpoints of the dynamic client registration
+// and the client configuration, see RFC 7591 and RFC 7592.
+type RegistrationEndpoints struct {
+	Register           endpoint.Endpoint
+	GetRegistration    endpoint.Endpoint
+	UpdateRegistration endpoint.Endpoint
+	DeleteRegistration endpoint.Endpoint
+}
+
+// MakeRegistrationEndpoints returns a RegistrationEndpoints struct where each endpoint invokes the
+// corresponding method on the provided service. The registrationURI is the registration endpoint URI
+// used to build the client configuration endpoint URI.
+// The registration is open if the initialAccessToken is empty.
+func MakeRegistrationEndpoints(s Service, registrationURI, initialAccessToken string) RegistrationEndpoints {
+	registrationURI = strings.TrimSuffix(registrationURI, "/")
+
+	return RegistrationEndpoints{
+		Register:           MakeRegisterEndpoint(s, registrationURI, initialAccessToken),
+		GetRegistration:    MakeGetRegistrationEndpoint(s, registrationURI),
+		UpdateRegistration: MakeUpdateRegistrationEndpoint(s, registrationURI),
+		DeleteRegistration: MakeDeleteRegistrationEndpoint(s),
+	}
+}
+
+// MakeRegisterEndpoint returns an endpoint via the passed service.
+// The initial access token is passed as the bearer token, see RFC 7591, section 3.
+func MakeRegisterEndpoint(s Service, registrationURI, initialAccessToken string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if initialAccessToken != "" {
+			token, _ := ctx.Value(jwtkit.JWTContextKey).(string)
+			if subtle.ConstantTimeCompare([]byte(token), []byte(initialAccessToken)) != 1 {
+				return nil, ErrInvalidRegistrationToken
+			}
+		}
+
+		req, ok := request.(Metadata)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		reg, err := s.Register(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		reg.RegistrationClientURI = registrationURI + "/" + reg.ClientID
+
+		return reg, nil
+	}
+}
+
+// MakeGetRegistrationEndpoint returns an endpoint via the passed service.
+func MakeGetRegistrationEndpoint(s Service, registrationURI string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(string)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		token, _ := ctx.Value(jwtkit.JWTContextKey).(string)
+		reg, err := s.GetRegistration(ctx, req, token)
+		if err != nil {
+			return nil, err
+		}
+		reg.RegistrationClientURI = registrationURI + "/" + reg.ClientID
+
+		return reg, nil
+	}
+}
+
+// UpdateRegistrationRequest is a request for the UpdateRegistration method.
+type UpdateRegistrationRequest struct {
+	ID       string `json:"-"`
+	ClientID string `json:"client_id"`
+	Metadata
+}
+
+// MakeUpdateRegistrationEndpoint returns an endpoint via the passed service.
+func MakeUpdateRegistrationEndpoint(s Service, registrationURI string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateRegistrationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		token, _ := ctx.Value(jwtkit.JWTContextKey).(string)
+		client, err := s.GetRegistration(ctx, req.ID, token)
+		if err != nil {
+			return nil, err
+		}
+
+		// the request must contain the client identifier, see RFC 7592, section 2.2
+		if req.ClientID != client.ClientID {
+			return nil, ErrInvalidClientMetadata
+		}
+
+		reg, err := s.UpdateRegistration(ctx, client.ClientID, token, req.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		reg.RegistrationClientURI = registrationURI + "/" + reg.ClientID
+
+		return reg, nil
+	}
+}
+
+// MakeDeleteRegistrationEndpoint returns an endpoint via the passed service.
+func MakeDeleteRegistrationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(string)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		token, _ := ctx.Value(jwtkit.JWTContextKey).(string)
+		if err := s.DeleteRegistration(ctx, req, token); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
@@ -13,6 +13,17 @@ var (
 	ErrInvalidRequest   = errors.New("invalid_request")
 	ErrInvalidParameter = errors.New("invalid_parameter")
 	ErrForbidden        = errors.New("forbidden")
+	ErrInvalidRedirect  = errors.New("invalid_redirect_uri")
+	ErrInvalidAuth      = errors.New("invalid_token_endpoint_auth_method")
+	ErrInvalidJWKS      = errors.New("invalid_jwks")
+	ErrInvalidSubjectDN = errors.New("invalid_tls_client_auth_subject_dn")
+	ErrInvalidLifetimes = errors.New("invalid_token_lifetimes")
+	ErrInvalidModes     = errors.New("invalid_response_modes")
+
+	// dynamic client registration errors,
+	// see: https://www.rfc-editor.org/rfc/rfc7591#section-3.2.2
+	ErrInvalidClientMetadata    = errors.New("invalid_client_metadata")
+	ErrInvalidRegistrationToken = errors.New("invalid_token")
 )
 
 // Error codes map
@@ -21,6 +32,15 @@ var ErrorCodes = map[error]int{
 	ErrInvalidRequest:   http.StatusBadRequest,
 	ErrInvalidParameter: http.StatusBadRequest,
 	ErrForbidden:        http.StatusForbidden,
+	ErrInvalidRedirect:  http.StatusBadRequest,
+	ErrInvalidAuth:      http.StatusBadRequest,
+	ErrInvalidJWKS:      http.StatusBadRequest,
+	ErrInvalidSubjectDN: http.StatusBadRequest,
+	ErrInvalidLifetimes: http.StatusBadRequest,
+	ErrInvalidModes:     http.StatusBadRequest,
+
+	ErrInvalidClientMetadata:    http.StatusBadRequest,
+	ErrInvalidRegistrationToken: http.StatusUnauthorized,
 }
 
 // Error messages
@@ -29,6 +49,15 @@ var ErrorMessages = map[error]string{
 	ErrInvalidRequest:   "Invalid request",
 	ErrInvalidParameter: "Invalid parameter",
 	ErrForbidden:        "Forbidden action",
+	ErrInvalidRedirect:  "Redirect URI must be an absolute URI without a fragment",
+	ErrInvalidAuth:      "Token endpoint authentication method is not supported by the client",
+	ErrInvalidJWKS:      "Client must register either a valid JWKS or a JWKS URI",
+	ErrInvalidSubjectDN: "Only tls_client_auth client must register the certificate subject DN",
+	ErrInvalidLifetimes: "Token lifetimes must not be negative or exceed the maximum, refresh token expiry must be absolute or idle",
+	ErrInvalidModes:     "Response modes must be supported by the server",
+
+	ErrInvalidClientMetadata:    "Client metadata is invalid",
+	ErrInvalidRegistrationToken: "Missed or invalid registration access token",
 }
 
 // NewError creates a new error
@@ -2,10 +2,23 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
 	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
 	"github.com/dmitrymomot/random"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -14,42 +27,138 @@ type (
 	// Service is the client service interface.
 	Service interface {
 		// Create creates a new client.
-		Create(ctx context.Context, uid string, domain string, isPublic bool) (*Client, error)
+		Create(ctx context.Context, uid, name, domain string, isPublic bool, redirectURIs []string, auth Authentication) (*Client, error)
 		// GetByID returns a client by its ID.
 		GetByID(ctx context.Context, id string) (*Client, error)
 		// GetByUserID returns a clients list by its user ID.
 		GetByUserID(ctx context.Context, uid string) ([]*Client, error)
+		// UpdateRedirectURIs replaces the registered client redirect URIs.
+		UpdateRedirectURIs(ctx context.Context, id string, redirectURIs []string) (*Client, error)
+		// UpdateAuthentication changes the client authentication method at the token endpoint.
+		UpdateAuthentication(ctx context.Context, id string, auth Authentication) (*Client, error)
+		// UpdatePushedAuthorization makes the pushed authorization requests mandatory for the client.
+		UpdatePushedAuthorization(ctx context.Context, id string, required bool) (*Client, error)
+		// UpdateImplicitGrant enables or disables the implicit grant for the client.
+		UpdateImplicitGrant(ctx context.Context, id string, enabled bool) (*Client, error)
+		// UpdateResponseModes restricts the response modes the client may use.
+		UpdateResponseModes(ctx context.Context, id string, modes []string) (*Client, error)
+		// UpdateTokenLifetimes changes the lifetimes of the tokens issued to the client.
+		UpdateTokenLifetimes(ctx context.Context, id string, lifetimes TokenLifetimes) (*Client, error)
 		// Delete deletes a client by its ID.
 		Delete(ctx context.Context, id string) error
+
+		// Register registers a new client with the client metadata.
+		Register(ctx context.Context, m Metadata) (*Registration, error)
+		// GetRegistration returns the metadata of the dynamically registered client.
+		GetRegistration(ctx context.Context, id, registrationToken string) (*Registration, error)
+		// UpdateRegistration replaces the metadata of the dynamically registered client.
+		UpdateRegistration(ctx context.Context, id, registrationToken string, m Metadata) (*Registration, error)
+		// DeleteRegistration deletes the dynamically registered client.
+		DeleteRegistration(ctx context.Context, id, registrationToken string) error
 	}
 
 	service struct {
-		repo clientRepository
+		repo             clientRepository
+		scopes           scopeRegistry
+		encryptionKey    []byte
+		openRegistration bool
+	}
+
+	// ServiceOption is a function that configures the client service.
+	ServiceOption func(s *service)
+
+	// scopeRegistry is the list of scopes the clients can request, see oauth.ScopeRegistry.
+	scopeRegistry interface {
+		Validate(ctx context.Context, scope string, withUser bool) error
+		ClientScope(ctx context.Context) (string, error)
 	}
 
 	clientRepository interface {
 		CreateClient(ctx context.Context, arg repository.CreateClientParams) (repository.Client, error)
 		DeleteClient(ctx context.Context, id string) error
 		GetClientByID(ctx context.Context, id string) (repository.Client, error)
-		GetClientByUserID(ctx context.Context, userID uuid.UUID) ([]repository.Client, error)
+		GetClientByUserID(ctx context.Context, userID uuid.NullUUID) ([]repository.Client, error)
+		UpdateClientRedirectURIs(ctx context.Context, arg repository.UpdateClientRedirectURIsParams) (repository.Client, error)
+		UpdateClientAuthentication(ctx context.Context, arg repository.UpdateClientAuthenticationParams) (repository.Client, error)
+		UpdateClientRequirePushedAuthorizationRequests(ctx context.Context, arg repository.UpdateClientRequirePushedAuthorizationRequestsParams) (repository.Client, error)
+		UpdateClientMetadata(ctx context.Context, arg repository.UpdateClientMetadataParams) (repository.Client, error)
+		UpdateClientAllowedGrants(ctx context.Context, arg repository.UpdateClientAllowedGrantsParams) (repository.Client, error)
+		UpdateClientTokenLifetimes(ctx context.Context, arg repository.UpdateClientTokenLifetimesParams) (repository.Client, error)
+		UpdateClientResponseModes(ctx context.Context, arg repository.UpdateClientResponseModesParams) (repository.Client, error)
 	}
 )
 
+// Dynamic client registration defaults
+const (
+	// the grant type name of the implicit grant in the client metadata
+	grantTypeImplicit = "implicit"
+
+	registrationTokenPrefix = "reg_"
+)
+
+// Client token lifetimes limits in seconds, the refresh token lifetime is not limited
+const (
+	maxAccessTokenTTL = 24 * 60 * 60
+	// the authorization code must be short lived,
+	// see: https://www.rfc-editor.org/rfc/rfc6749#section-4.1.2
+	maxCodeTTL = 10 * 60
+)
+
+// registrationGrantTypes maps the grant types of the client metadata to the allowed client grants.
+// GrantType.String of go-oauth2 is empty for the implicit and the extension grants,
+// so the grant types are converted to string explicitly.
+var registrationGrantTypes = map[string]oauth2.GrantType{
+	string(oauth2.AuthorizationCode):  oauth2.AuthorizationCode,
+	grantTypeImplicit:                 oauth2.Implicit,
+	string(oauth2.Refreshing):         oauth2.Refreshing,
+	string(oauth2.ClientCredentials):  oauth2.ClientCredentials,
+	string(oauth.DeviceCodeGrantType): oauth.DeviceCodeGrantType,
+	string(oauth.CIBAGrantType):       oauth.CIBAGrantType,
+}
+
+// WithOpenRegistration limits the clients registered without the initial access token:
+// they get only the default scopes and can't use the backchannel authentication,
+// since its error responses reveal whether the user with the login hint exists.
+func WithOpenRegistration() ServiceOption {
+	return func(s *service) {
+		s.openRegistration = true
+	}
+}
+
 // NewService returns a new instance of a service.
-func NewService(repo clientRepository) Service {
-	return &service{
-		repo: repo,
+// The encryption key is used to encrypt the secrets of the client_secret_jwt clients,
+// since the secret is needed to verify the client assertion.
+// The clients are allowed to request the scopes registered in the scope registry.
+func NewService(repo clientRepository, scopes scopeRegistry, encryptionKey string, opts ...ServiceOption) Service {
+	s := &service{
+		repo:          repo,
+		scopes:        scopes,
+		encryptionKey: []byte(encryptionKey),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Create creates a new client.
-func (s *service) Create(ctx context.Context, userID string, domain string, isPublic bool) (*Client, error) {
-	clientID := fmt.Sprintf("id_%s", random.String(32))
-	clientSecret := fmt.Sprintf("secret_%s", random.String(32))
+func (s *service) Create(ctx context.Context, userID, name, domain string, isPublic bool, redirectURIs []string, auth Authentication) (*Client, error) {
+	redirectURIs, err := validateRedirectURIs(redirectURIs)
+	if err != nil {
+		return nil, err
+	}
 
-	clientSecretHash, err := bcrypt.GenerateFromPassword([]byte(clientSecret), bcrypt.DefaultCost)
+	auth, err = validateAuthentication(auth, isPublic)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash client secret: %w", err)
+		return nil, err
+	}
+
+	clientID := fmt.Sprintf("id_%s", random.String(32))
+	clientSecret, clientSecretHash, encryptedSecret, err := s.newSecret(auth.Method)
+	if err != nil {
+		return nil, err
 	}
 
 	uid, err := uuid.Parse(userID)
@@ -57,24 +166,41 @@ func (s *service) Create(ctx context.Context, userID string, domain string, isPu
 		return nil, fmt.Errorf("failed to parse user id: %w", err)
 	}
 
+	scope, err := s.validateScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// the implicit grant is disabled by default, see UpdateImplicitGrant
 	allowedGrants := []string{
 		"authorization_code",
 		"refresh_token",
-		"__implicit",
+		"urn:ietf:params:oauth:grant-type:device_code",
 	}
 	if !isPublic {
-		allowedGrants = append(allowedGrants, "client_credentials")
+		allowedGrants = append(allowedGrants, "client_credentials", "urn:openid:params:grant-type:ciba")
 	}
 
 	// Create client
 	c, err := s.repo.CreateClient(ctx, repository.CreateClientParams{
 		ID:            clientID,
+		Name:          name,
 		Secret:        clientSecretHash,
 		Domain:        domain,
 		IsPublic:      isPublic,
-		UserID:        uid,
+		UserID:        uuid.NullUUID{UUID: uid, Valid: true},
 		AllowedGrants: allowedGrants,
-		Scope:         "client:* user:*",
+		Scope:         scope,
+		RedirectUris:  redirectURIs,
+
+		TokenEndpointAuthMethod: auth.Method,
+		Jwks:                    string(auth.JWKS),
+		JwksUri:                 auth.JWKSURI,
+		TlsClientAuthSubjectDn:  auth.SubjectDN,
+		EncryptedSecret:         encryptedSecret,
+
+		BackchannelTokenDeliveryMode: oauth.BackchannelTokenDeliveryModePoll,
+		PostLogoutRedirectUris:       []string{},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
@@ -100,7 +226,7 @@ func (s *service) GetByUserID(ctx context.Context, uid string) ([]*Client, error
 		return nil, fmt.Errorf("failed to parse user id: %w", err)
 	}
 
-	clients, err := s.repo.GetClientByUserID(ctx, userID)
+	clients, err := s.repo.GetClientByUserID(ctx, uuid.NullUUID{UUID: userID, Valid: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get clients by user id: %w", err)
 	}
@@ -113,6 +239,188 @@ func (s *service) GetByUserID(ctx context.Context, uid string) ([]*Client, error
 	return result, nil
 }
 
+// UpdateRedirectURIs replaces the registered client redirect URIs.
+// The empty list makes the client domain be used to validate the redirect URI.
+func (s *service) UpdateRedirectURIs(ctx context.Context, id string, redirectURIs []string) (*Client, error) {
+	redirectURIs, err := validateRedirectURIs(redirectURIs)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.UpdateClientRedirectURIs(ctx, repository.UpdateClientRedirectURIsParams{
+		ID:           id,
+		RedirectUris: redirectURIs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client redirect uris: %w", err)
+	}
+
+	return NewClient(client, ""), nil
+}
+
+// UpdateAuthentication changes the client authentication method at the token endpoint.
+// A new client secret is generated and returned once if the client switches to client_secret_jwt,
+// since only the hash of the current secret is stored.
+func (s *service) UpdateAuthentication(ctx context.Context, id string, auth Authentication) (*Client, error) {
+	client, err := s.repo.GetClientByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	auth, err = validateAuthentication(auth, client.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientSecret string
+	var encryptedSecret []byte
+	clientSecretHash := client.Secret
+	if auth.Method == oauth.AuthMethodClientSecretJWT {
+		if clientSecret, clientSecretHash, encryptedSecret, err = s.newSecret(auth.Method); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err = s.repo.UpdateClientAuthentication(ctx, repository.UpdateClientAuthenticationParams{
+		ID:                      client.ID,
+		TokenEndpointAuthMethod: auth.Method,
+		Jwks:                    string(auth.JWKS),
+		JwksUri:                 auth.JWKSURI,
+		TlsClientAuthSubjectDn:  auth.SubjectDN,
+		Secret:                  clientSecretHash,
+		EncryptedSecret:         encryptedSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client authentication: %w", err)
+	}
+
+	return NewClient(client, clientSecret), nil
+}
+
+// UpdatePushedAuthorization makes the pushed authorization requests mandatory for the client:
+// the authorization request without the request_uri issued by the PAR endpoint is rejected.
+func (s *service) UpdatePushedAuthorization(ctx context.Context, id string, required bool) (*Client, error) {
+	client, err := s.repo.UpdateClientRequirePushedAuthorizationRequests(ctx, repository.UpdateClientRequirePushedAuthorizationRequestsParams{
+		ID:                                 id,
+		RequirePushedAuthorizationRequests: required,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client pushed authorization requirement: %w", err)
+	}
+
+	return NewClient(client, ""), nil
+}
+
+// UpdateImplicitGrant enables or disables the implicit grant for the client,
+// so the client can request the access token with response_type=token.
+func (s *service) UpdateImplicitGrant(ctx context.Context, id string, enabled bool) (*Client, error) {
+	client, err := s.repo.GetClientByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	// GrantType.String of go-oauth2 is empty for the implicit grant
+	grants := make([]string, 0, len(client.AllowedGrants)+1)
+	for _, g := range client.AllowedGrants {
+		if g != string(oauth2.Implicit) {
+			grants = append(grants, g)
+		}
+	}
+	if enabled {
+		grants = append(grants, string(oauth2.Implicit))
+	}
+
+	client, err = s.repo.UpdateClientAllowedGrants(ctx, repository.UpdateClientAllowedGrantsParams{
+		ID:            client.ID,
+		AllowedGrants: grants,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client allowed grants: %w", err)
+	}
+
+	return NewClient(client, ""), nil
+}
+
+// UpdateResponseModes restricts the response modes the client may use
+// in the authorization request, the empty list allows all supported response modes.
+func (s *service) UpdateResponseModes(ctx context.Context, id string, modes []string) (*Client, error) {
+	modes, err := validateResponseModes(modes)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.UpdateClientResponseModes(ctx, repository.UpdateClientResponseModesParams{
+		ID:            id,
+		ResponseModes: modes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client response modes: %w", err)
+	}
+
+	return NewClient(client, ""), nil
+}
+
+// UpdateTokenLifetimes changes the lifetimes of the tokens issued to the client,
+// the tokens issued before keep their lifetimes.
+func (s *service) UpdateTokenLifetimes(ctx context.Context, id string, lifetimes TokenLifetimes) (*Client, error) {
+	expiry, err := validateTokenLifetimes(lifetimes)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.UpdateClientTokenLifetimes(ctx, repository.UpdateClientTokenLifetimesParams{
+		ID:                 id,
+		AccessTokenTtl:     lifetimes.AccessTokenTTL,
+		RefreshTokenTtl:    lifetimes.RefreshTokenTTL,
+		CodeTtl:            lifetimes.CodeTTL,
+		IssueRefreshTokens: lifetimes.IssueRefreshTokens,
+		RefreshTokenExpiry: expiry,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client token lifetimes: %w", err)
+	}
+
+	return NewClient(client, ""), nil
+}
+
+// validateTokenLifetimes checks the lifetimes are within the limits
+// and returns the refresh token expiry, absolute by default.
+func validateTokenLifetimes(l TokenLifetimes) (repository.RefreshTokenExpiry, error) {
+	if l.AccessTokenTTL < 0 || l.AccessTokenTTL > maxAccessTokenTTL ||
+		l.CodeTTL < 0 || l.CodeTTL > maxCodeTTL ||
+		l.RefreshTokenTTL < 0 {
+		return "", ErrInvalidLifetimes
+	}
+
+	switch expiry := repository.RefreshTokenExpiry(l.RefreshTokenExpiry); expiry {
+	case "":
+		return repository.RefreshTokenExpiryAbsolute, nil
+	case repository.RefreshTokenExpiryAbsolute, repository.RefreshTokenExpiryIdle:
+		return expiry, nil
+	}
+
+	return "", ErrInvalidLifetimes
+}
+
+// newSecret generates a new client secret and its hash.
+// The secret of the client_secret_jwt client is also encrypted to be stored.
+func (s *service) newSecret(method string) (secret string, hash, encrypted []byte, err error) {
+	secret = fmt.Sprintf("secret_%s", random.String(32))
+
+	hash, err = bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to hash client secret: %w", err)
+	}
+
+	if method == oauth.AuthMethodClientSecretJWT {
+		if encrypted, err = utils.Encrypt([]byte(secret), s.encryptionKey); err != nil {
+			return "", nil, nil, fmt.Errorf("failed to encrypt client secret: %w", err)
+		}
+	}
+
+	return secret, hash, encrypted, nil
+}
+
 // Delete deletes a client by its ID.
 func (s *service) Delete(ctx context.Context, id string) error {
 	if err := s.repo.DeleteClient(ctx, id); err != nil {
@@ -121,3 +429,527 @@ func (s *service) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// Register registers a new client with the client metadata as described in RFC 7591.
+// The registered client has no owner, it's managed with the returned registration access token.
+func (s *service) Register(ctx context.Context, m Metadata) (*Registration, error) {
+	m, grants, err := validateMetadata(m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Scope, err = s.validateScope(ctx, m.Scope); err != nil {
+		return nil, err
+	}
+	if err := s.validateOpenRegistration(ctx, m.Scope, grants); err != nil {
+		return nil, err
+	}
+
+	isPublic := m.TokenEndpointAuthMethod == oauth.AuthMethodNone
+	auth, err := validateAuthentication(Authentication{
+		Method:    m.TokenEndpointAuthMethod,
+		JWKS:      m.JWKS,
+		JWKSURI:   m.JWKSURI,
+		SubjectDN: m.TLSClientAuthSubjectDN,
+	}, isPublic)
+	if err != nil {
+		return nil, metadataError(err)
+	}
+
+	clientSecret, clientSecretHash, encryptedSecret, err := s.newSecret(auth.Method)
+	if err != nil {
+		return nil, err
+	}
+	if isPublic {
+		// public clients can't keep the secret
+		clientSecret = ""
+	}
+
+	registrationToken := registrationTokenPrefix + random.String(40)
+
+	c, err := s.repo.CreateClient(ctx, repository.CreateClientParams{
+		ID:            fmt.Sprintf("id_%s", random.String(32)),
+		Name:          m.ClientName,
+		Secret:        clientSecretHash,
+		Domain:        m.ClientURI,
+		IsPublic:      isPublic,
+		AllowedGrants: grants,
+		Scope:         m.Scope,
+		RedirectUris:  m.RedirectURIs,
+
+		TokenEndpointAuthMethod: auth.Method,
+		Jwks:                    string(auth.JWKS),
+		JwksUri:                 auth.JWKSURI,
+		TlsClientAuthSubjectDn:  auth.SubjectDN,
+		EncryptedSecret:         encryptedSecret,
+
+		RequirePushedAuthorizationRequests: m.RequirePushedAuthorizationRequests,
+		RegistrationAccessToken:            hashRegistrationToken(registrationToken),
+
+		BackchannelTokenDeliveryMode:          m.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: m.BackchannelClientNotificationEndpoint,
+
+		PostLogoutRedirectUris: m.PostLogoutRedirectURIs,
+		FrontchannelLogoutUri:  m.FrontchannelLogoutURI,
+		BackchannelLogoutUri:   m.BackchannelLogoutURI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return NewRegistration(c, clientSecret, registrationToken), nil
+}
+
+// GetRegistration returns the metadata of the dynamically registered client,
+// see RFC 7592, section 2.1.
+func (s *service) GetRegistration(ctx context.Context, id, registrationToken string) (*Registration, error) {
+	client, err := s.registeredClient(ctx, id, registrationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRegistration(client, "", ""), nil
+}
+
+// UpdateRegistration replaces the metadata of the dynamically registered client,
+// the omitted fields are reset to the default values, see RFC 7592, section 2.2.
+// A new client secret is generated and returned once if the client becomes confidential
+// or switches to client_secret_jwt.
+func (s *service) UpdateRegistration(ctx context.Context, id, registrationToken string, m Metadata) (*Registration, error) {
+	client, err := s.registeredClient(ctx, id, registrationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	m, grants, err := validateMetadata(m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Scope, err = s.validateScope(ctx, m.Scope); err != nil {
+		return nil, err
+	}
+	if err := s.validateOpenRegistration(ctx, m.Scope, grants); err != nil {
+		return nil, err
+	}
+
+	isPublic := m.TokenEndpointAuthMethod == oauth.AuthMethodNone
+	auth, err := validateAuthentication(Authentication{
+		Method:    m.TokenEndpointAuthMethod,
+		JWKS:      m.JWKS,
+		JWKSURI:   m.JWKSURI,
+		SubjectDN: m.TLSClientAuthSubjectDN,
+	}, isPublic)
+	if err != nil {
+		return nil, metadataError(err)
+	}
+
+	var clientSecret string
+	var encryptedSecret []byte
+	clientSecretHash := client.Secret
+	if auth.Method == oauth.AuthMethodClientSecretJWT || (client.IsPublic && !isPublic) {
+		if clientSecret, clientSecretHash, encryptedSecret, err = s.newSecret(auth.Method); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err = s.repo.UpdateClientMetadata(ctx, repository.UpdateClientMetadataParams{
+		ID:            client.ID,
+		Name:          m.ClientName,
+		Domain:        m.ClientURI,
+		IsPublic:      isPublic,
+		AllowedGrants: grants,
+		Scope:         m.Scope,
+		RedirectUris:  m.RedirectURIs,
+
+		TokenEndpointAuthMethod: auth.Method,
+		Jwks:                    string(auth.JWKS),
+		JwksUri:                 auth.JWKSURI,
+		Secret:                  clientSecretHash,
+		EncryptedSecret:         encryptedSecret,
+		TlsClientAuthSubjectDn:  auth.SubjectDN,
+
+		RequirePushedAuthorizationRequests: m.RequirePushedAuthorizationRequests,
+
+		BackchannelTokenDeliveryMode:          m.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: m.BackchannelClientNotificationEndpoint,
+
+		PostLogoutRedirectUris: m.PostLogoutRedirectURIs,
+		FrontchannelLogoutUri:  m.FrontchannelLogoutURI,
+		BackchannelLogoutUri:   m.BackchannelLogoutURI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update client metadata: %w", err)
+	}
+
+	return NewRegistration(client, clientSecret, ""), nil
+}
+
+// DeleteRegistration deletes the dynamically registered client, see RFC 7592, section 2.3.
+func (s *service) DeleteRegistration(ctx context.Context, id, registrationToken string) error {
+	client, err := s.registeredClient(ctx, id, registrationToken)
+	if err != nil {
+		return err
+	}
+
+	return s.Delete(ctx, client.ID)
+}
+
+// registeredClient returns the client the registration access token is issued for.
+// The unknown client is reported as the invalid token, see RFC 7592, section 3.
+func (s *service) registeredClient(ctx context.Context, id, registrationToken string) (repository.Client, error) {
+	if registrationToken == "" {
+		return repository.Client{}, ErrInvalidRegistrationToken
+	}
+
+	client, err := s.repo.GetClientByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.Client{}, ErrInvalidRegistrationToken
+		}
+		return repository.Client{}, fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	if client.RegistrationAccessToken == "" ||
+		subtle.ConstantTimeCompare([]byte(client.RegistrationAccessToken), []byte(hashRegistrationToken(registrationToken))) != 1 {
+		return repository.Client{}, ErrInvalidRegistrationToken
+	}
+
+	return client, nil
+}
+
+// hashRegistrationToken returns the hash of the registration access token to store
+func hashRegistrationToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// validateMetadata checks the client metadata and fills the omitted fields with the default values
+// described in RFC 7591, section 2. It returns the client grants for the requested grant types.
+func validateMetadata(m Metadata) (Metadata, []string, error) {
+	redirectURIs, err := validateRedirectURIs(m.RedirectURIs)
+	if err != nil {
+		return m, nil, ErrInvalidRedirect
+	}
+	m.RedirectURIs = redirectURIs
+
+	if m.TokenEndpointAuthMethod == "" {
+		m.TokenEndpointAuthMethod = oauth.DefaultClientAuthMethod
+	}
+	if len(m.GrantTypes) == 0 {
+		m.GrantTypes = []string{oauth2.AuthorizationCode.String()}
+	}
+	// the client without the redirect-based grants registers the empty response types
+	if m.ResponseTypes == nil {
+		m.ResponseTypes = []string{oauth2.Code.String()}
+	}
+
+	grants := make([]string, 0, len(m.GrantTypes))
+	redirectBased, backchannel := false, false
+	for _, gt := range m.GrantTypes {
+		grant, ok := registrationGrantTypes[gt]
+		if !ok {
+			return m, nil, ErrInvalidClientMetadata
+		}
+		// public clients can't authenticate with the client_credentials grant
+		if grant == oauth2.ClientCredentials && m.TokenEndpointAuthMethod == oauth.AuthMethodNone {
+			return m, nil, ErrInvalidClientMetadata
+		}
+		// the backchannel authentication is available only for confidential clients
+		if grant == oauth.CIBAGrantType && m.TokenEndpointAuthMethod == oauth.AuthMethodNone {
+			return m, nil, ErrInvalidClientMetadata
+		}
+		if grant == oauth2.AuthorizationCode || grant == oauth2.Implicit {
+			redirectBased = true
+		}
+		if grant == oauth.CIBAGrantType {
+			backchannel = true
+		}
+		grants = append(grants, string(grant))
+	}
+
+	// the response types must match the grant types, see RFC 7591, section 2.1
+	for _, rt := range m.ResponseTypes {
+		switch oauth2.ResponseType(rt) {
+		case oauth2.Code:
+			if !hasString(grants, oauth2.AuthorizationCode.String()) {
+				return m, nil, ErrInvalidClientMetadata
+			}
+		case oauth2.Token:
+			if !hasString(grants, string(oauth2.Implicit)) {
+				return m, nil, ErrInvalidClientMetadata
+			}
+		default:
+			return m, nil, ErrInvalidClientMetadata
+		}
+	}
+
+	// the redirect-based flows need a registered redirect uri
+	if redirectBased && len(m.RedirectURIs) == 0 {
+		return m, nil, ErrInvalidRedirect
+	}
+
+	if err := validateBackchannel(&m, backchannel); err != nil {
+		return m, nil, err
+	}
+	if err := validateLogout(&m); err != nil {
+		return m, nil, err
+	}
+
+	// the client domain is the client_uri or the origin of the first redirect uri
+	if m.ClientURI != "" {
+		u, err := url.Parse(m.ClientURI)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return m, nil, ErrInvalidClientMetadata
+		}
+	} else {
+		for _, uri := range m.RedirectURIs {
+			if u, _ := url.Parse(uri); u.Scheme == "https" || u.Scheme == "http" {
+				m.ClientURI = u.Scheme + "://" + u.Host
+				break
+			}
+		}
+	}
+
+	return m, grants, nil
+}
+
+// validateScope checks the scope the client can request is registered,
+// the client can request only the default scopes if the scope is empty,
+// so the scopes registered later aren't granted to all clients.
+func (s *service) validateScope(ctx context.Context, scope string) (string, error) {
+	if scope = strings.Join(strings.Fields(scope), " "); scope == "" {
+		defaultScope, err := s.scopes.ClientScope(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get default scopes: %w", err)
+		}
+		return defaultScope, nil
+	}
+
+	if err := s.scopes.Validate(ctx, scope, true); err != nil {
+		if errors.Is(err, oauthErrors.ErrInvalidScope) {
+			return "", ErrInvalidClientMetadata
+		}
+		return "", err
+	}
+
+	return scope, nil
+}
+
+// validateOpenRegistration checks the self-registered client requests only the default scopes
+// and doesn't use the backchannel authentication, see WithOpenRegistration.
+func (s *service) validateOpenRegistration(ctx context.Context, scope string, grants []string) error {
+	if !s.openRegistration {
+		return nil
+	}
+
+	if hasString(grants, string(oauth.CIBAGrantType)) {
+		return ErrInvalidClientMetadata
+	}
+
+	defaultScope, err := s.validateScope(ctx, "")
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Fields(scope) {
+		if !oauth.MatchScope(name, defaultScope) {
+			return ErrInvalidClientMetadata
+		}
+	}
+
+	return nil
+}
+
+// validateBackchannel checks the backchannel token delivery settings of the CIBA client,
+// the ping mode client must register the https notification endpoint.
+// The settings of other clients are reset.
+func validateBackchannel(m *Metadata, backchannel bool) error {
+	if !backchannel {
+		m.BackchannelTokenDeliveryMode = oauth.BackchannelTokenDeliveryModePoll
+		m.BackchannelClientNotificationEndpoint = ""
+		return nil
+	}
+
+	switch m.BackchannelTokenDeliveryMode {
+	case "":
+		m.BackchannelTokenDeliveryMode = oauth.BackchannelTokenDeliveryModePoll
+	case oauth.BackchannelTokenDeliveryModePoll, oauth.BackchannelTokenDeliveryModePing:
+	default:
+		return ErrInvalidClientMetadata
+	}
+
+	if m.BackchannelTokenDeliveryMode != oauth.BackchannelTokenDeliveryModePing {
+		if m.BackchannelClientNotificationEndpoint != "" {
+			return ErrInvalidClientMetadata
+		}
+		return nil
+	}
+
+	u, err := url.Parse(m.BackchannelClientNotificationEndpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.Fragment != "" {
+		return ErrInvalidClientMetadata
+	}
+
+	return nil
+}
+
+// validateLogout checks the logout settings of the client:
+// the post logout redirect uris are validated as the redirect uris,
+// the front-channel logout uri must have the same origin as one of the redirect uris,
+// the back-channel logout uri must be the https uri, since the server posts the logout token to it.
+func validateLogout(m *Metadata) error {
+	postLogoutRedirectURIs, err := validateRedirectURIs(m.PostLogoutRedirectURIs)
+	if err != nil {
+		return ErrInvalidClientMetadata
+	}
+	m.PostLogoutRedirectURIs = postLogoutRedirectURIs
+
+	if m.FrontchannelLogoutURI != "" {
+		u, err := url.Parse(m.FrontchannelLogoutURI)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.Fragment != "" {
+			return ErrInvalidClientMetadata
+		}
+		sameOrigin := false
+		for _, uri := range m.RedirectURIs {
+			if r, _ := url.Parse(uri); r != nil && r.Scheme == u.Scheme && r.Host == u.Host {
+				sameOrigin = true
+				break
+			}
+		}
+		if !sameOrigin {
+			return ErrInvalidClientMetadata
+		}
+	}
+
+	if m.BackchannelLogoutURI != "" {
+		u, err := url.Parse(m.BackchannelLogoutURI)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.Fragment != "" {
+			return ErrInvalidClientMetadata
+		}
+	}
+
+	return nil
+}
+
+// metadataError returns the client registration error for the client authentication validation error
+func metadataError(err error) error {
+	if errors.Is(err, ErrInvalidAuth) || errors.Is(err, ErrInvalidJWKS) || errors.Is(err, ErrInvalidSubjectDN) {
+		return ErrInvalidClientMetadata
+	}
+	return err
+}
+
+// hasString checks if the list contains the value
+func hasString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRedirectURIs checks that the redirect URIs are absolute URIs without a fragment,
+// as required by RFC 6749, section 3.1.2. Private-use URI schemes of native apps
+// are allowed, e.g. com.example.app:/callback. Duplicates are removed.
+func validateRedirectURIs(redirectURIs []string) ([]string, error) {
+	result := make([]string, 0, len(redirectURIs))
+	seen := make(map[string]bool, len(redirectURIs))
+	for _, uri := range redirectURIs {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || u.Opaque != "" || u.Fragment != "" || u.User != nil ||
+			((u.Scheme == "http" || u.Scheme == "https") && u.Host == "") {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRedirect, uri)
+		}
+		if !seen[uri] {
+			seen[uri] = true
+			result = append(result, uri)
+		}
+	}
+	return result, nil
+}
+
+// validateAuthentication checks the token endpoint authentication method is allowed for the client.
+// Public clients can't keep a secret, so they use "none" method,
+// confidential clients use oauth.DefaultClientAuthMethod by default.
+func validateAuthentication(auth Authentication, isPublic bool) (Authentication, error) {
+	if string(auth.JWKS) == "null" {
+		auth.JWKS = nil
+	}
+	if auth.Method == "" {
+		auth.Method = oauth.DefaultClientAuthMethod
+		if isPublic {
+			auth.Method = oauth.AuthMethodNone
+		}
+	}
+
+	switch {
+	case isPublic && auth.Method != oauth.AuthMethodNone,
+		!isPublic && auth.Method == oauth.AuthMethodNone:
+		return auth, ErrInvalidAuth
+	}
+
+	supported := false
+	for _, m := range oauth.ClientAuthMethods {
+		if m == auth.Method {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return auth, ErrInvalidAuth
+	}
+
+	// tls_client_auth client registers the subject DN of its certificate,
+	// e.g. CN=client.example.com,O=Example
+	auth.SubjectDN = strings.TrimSpace(auth.SubjectDN)
+	if (auth.Method == oauth.AuthMethodTLSClientAuth) != (auth.SubjectDN != "") {
+		return auth, ErrInvalidSubjectDN
+	}
+
+	if auth.Method != oauth.AuthMethodPrivateKeyJWT && auth.Method != oauth.AuthMethodSelfSignedTLSClientAuth {
+		if len(auth.JWKS) > 0 || auth.JWKSURI != "" {
+			return auth, ErrInvalidJWKS
+		}
+		return auth, nil
+	}
+
+	// private_key_jwt and self_signed_tls_client_auth clients register their public keys
+	// by value or by reference, the keys are fetched only over TLS
+	if (len(auth.JWKS) > 0) == (auth.JWKSURI != "") {
+		return auth, ErrInvalidJWKS
+	}
+	if auth.JWKSURI != "" {
+		u, err := url.Parse(auth.JWKSURI)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return auth, ErrInvalidJWKS
+		}
+		return auth, nil
+	}
+
+	var set jwk.Set
+	if err := json.Unmarshal(auth.JWKS, &set); err != nil || len(set.Keys) == 0 {
+		return auth, ErrInvalidJWKS
+	}
+	for _, key := range set.Keys {
+		if _, err := key.PublicKey(); err != nil {
+			return auth, fmt.Errorf("%w: %s", ErrInvalidJWKS, err)
+		}
+	}
+
+	return auth, nil
+}
+
+// validateResponseModes checks the response modes are supported by the server
+// and removes the duplicates.
+func validateResponseModes(modes []string) ([]string, error) {
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		if !hasString(oauth.ResponseModes, mode) && !hasString(oauth.JWTResponseModes, mode) {
+			return nil, ErrInvalidModes
+		}
+		if !hasString(result, mode) {
+			result = append(result, mode)
+		}
+	}
+
+	return result, nil
+}
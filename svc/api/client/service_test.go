@@ -0,0 +1,383 @@
+package client_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/api/client"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// clientRepoMock keeps the clients in memory,
+// the queries which aren't used by the registration are left to the embedded nil repository.
+type clientRepoMock struct {
+	*repository.Queries
+	clients map[string]repository.Client
+}
+
+func (m *clientRepoMock) CreateClient(ctx context.Context, arg repository.CreateClientParams) (repository.Client, error) {
+	c := repository.Client{
+		ID:                      arg.ID,
+		Name:                    arg.Name,
+		Secret:                  arg.Secret,
+		Domain:                  arg.Domain,
+		IsPublic:                arg.IsPublic,
+		AllowedGrants:           arg.AllowedGrants,
+		Scope:                   arg.Scope,
+		RedirectUris:            arg.RedirectUris,
+		TokenEndpointAuthMethod: arg.TokenEndpointAuthMethod,
+		EncryptedSecret:         arg.EncryptedSecret,
+		RegistrationAccessToken: arg.RegistrationAccessToken,
+	}
+	m.clients[c.ID] = c
+	return c, nil
+}
+
+func (m *clientRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	c, ok := m.clients[id]
+	if !ok {
+		return repository.Client{}, sql.ErrNoRows
+	}
+	return c, nil
+}
+
+func (m *clientRepoMock) UpdateClientMetadata(ctx context.Context, arg repository.UpdateClientMetadataParams) (repository.Client, error) {
+	c := m.clients[arg.ID]
+	c.Name, c.Domain, c.IsPublic = arg.Name, arg.Domain, arg.IsPublic
+	c.AllowedGrants, c.Scope, c.RedirectUris = arg.AllowedGrants, arg.Scope, arg.RedirectUris
+	c.TokenEndpointAuthMethod, c.Secret, c.EncryptedSecret = arg.TokenEndpointAuthMethod, arg.Secret, arg.EncryptedSecret
+	m.clients[c.ID] = c
+	return c, nil
+}
+
+func (m *clientRepoMock) DeleteClient(ctx context.Context, id string) error {
+	delete(m.clients, id)
+	return nil
+}
+
+type scopeRepoMock struct{}
+
+func (scopeRepoMock) GetScopes(ctx context.Context) ([]repository.Scope, error) {
+	return []repository.Scope{
+		{Name: "openid", IsDefault: true, ResourceOwner: repository.ScopeResourceOwnerUser},
+		{Name: "user:read", IsDefault: true, ResourceOwner: repository.ScopeResourceOwnerUser},
+		{Name: "admin:*", ResourceOwner: repository.ScopeResourceOwnerUser},
+	}, nil
+}
+
+func newRegistrationService(opts ...client.ServiceOption) (client.Service, *clientRepoMock) {
+	repo := &clientRepoMock{clients: map[string]repository.Client{}}
+	scopes := oauth.NewScopeRegistry(scopeRepoMock{})
+	return client.NewService(repo, scopes, "0123456789abcdef0123456789abcdef", opts...), repo
+}
+
+func TestRegister_RegistrationToken(t *testing.T) {
+	srv, repo := newRegistrationService()
+	ctx := context.Background()
+
+	reg, err := srv.Register(ctx, client.Metadata{RedirectURIs: []string{"https://client.example.com/callback"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(reg.RegistrationAccessToken, "reg_") || reg.ClientSecret == "" {
+		t.Fatalf("Register() = %+v, want the client secret and the registration access token", reg)
+	}
+
+	// only the hashes of the secrets are stored
+	stored := repo.clients[reg.ClientID]
+	h := sha256.Sum256([]byte(reg.RegistrationAccessToken))
+	if stored.RegistrationAccessToken != hex.EncodeToString(h[:]) {
+		t.Errorf("stored registration access token = %q, want its sha256 hash", stored.RegistrationAccessToken)
+	}
+	if bcrypt.CompareHashAndPassword(stored.Secret, []byte(reg.ClientSecret)) != nil {
+		t.Errorf("stored client secret isn't the bcrypt hash of the returned secret")
+	}
+
+	got, err := srv.GetRegistration(ctx, reg.ClientID, reg.RegistrationAccessToken)
+	if err != nil {
+		t.Fatalf("GetRegistration() error = %v", err)
+	}
+	if got.ClientSecret != "" || got.RegistrationAccessToken != "" {
+		t.Errorf("GetRegistration() = %+v, want the secrets returned only once", got)
+	}
+
+	tests := []struct {
+		name     string
+		clientID string
+		token    string
+	}{
+		{name: "missing token", clientID: reg.ClientID},
+		{name: "wrong token", clientID: reg.ClientID, token: "reg_wrong"},
+		{name: "token hash instead of token", clientID: reg.ClientID, token: stored.RegistrationAccessToken},
+		{name: "unknown client", clientID: "id_unknown", token: reg.RegistrationAccessToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := srv.GetRegistration(ctx, tt.clientID, tt.token); !errors.Is(err, client.ErrInvalidRegistrationToken) {
+				t.Errorf("GetRegistration() error = %v, want %v", err, client.ErrInvalidRegistrationToken)
+			}
+			if _, err := srv.UpdateRegistration(ctx, tt.clientID, tt.token, client.Metadata{RedirectURIs: []string{"https://evil.example.com/callback"}}); !errors.Is(err, client.ErrInvalidRegistrationToken) {
+				t.Errorf("UpdateRegistration() error = %v, want %v", err, client.ErrInvalidRegistrationToken)
+			}
+			if err := srv.DeleteRegistration(ctx, tt.clientID, tt.token); !errors.Is(err, client.ErrInvalidRegistrationToken) {
+				t.Errorf("DeleteRegistration() error = %v, want %v", err, client.ErrInvalidRegistrationToken)
+			}
+		})
+	}
+	if _, ok := repo.clients[reg.ClientID]; !ok {
+		t.Fatal("client is deleted with the invalid registration access token")
+	}
+	if uris := repo.clients[reg.ClientID].RedirectUris; len(uris) != 1 || uris[0] != "https://client.example.com/callback" {
+		t.Errorf("redirect uris = %v, want the client metadata unchanged", uris)
+	}
+
+	if err := srv.DeleteRegistration(ctx, reg.ClientID, reg.RegistrationAccessToken); err != nil {
+		t.Fatalf("DeleteRegistration() error = %v", err)
+	}
+	if _, ok := repo.clients[reg.ClientID]; ok {
+		t.Error("DeleteRegistration() didn't delete the client")
+	}
+}
+
+func TestRegister_Scope(t *testing.T) {
+	srv, _ := newRegistrationService(client.WithOpenRegistration())
+	ctx := context.Background()
+	redirectURIs := []string{"https://client.example.com/callback"}
+
+	// the privileged scopes aren't granted to the client registered without the scope
+	reg, err := srv.Register(ctx, client.Metadata{RedirectURIs: redirectURIs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Scope != "openid user:read" {
+		t.Errorf("Register() scope = %q, want the default scopes", reg.Scope)
+	}
+
+	if _, err := srv.Register(ctx, client.Metadata{RedirectURIs: redirectURIs, Scope: "openid admin:write"}); !errors.Is(err, client.ErrInvalidClientMetadata) {
+		t.Errorf("Register() error = %v, want %v for the privileged scope", err, client.ErrInvalidClientMetadata)
+	}
+	if _, err := srv.Register(ctx, client.Metadata{RedirectURIs: redirectURIs, Scope: "billing:read"}); !errors.Is(err, client.ErrInvalidClientMetadata) {
+		t.Errorf("Register() error = %v, want %v for the unknown scope", err, client.ErrInvalidClientMetadata)
+	}
+	if _, err := srv.UpdateRegistration(ctx, reg.ClientID, reg.RegistrationAccessToken, client.Metadata{RedirectURIs: redirectURIs, Scope: "admin:write"}); !errors.Is(err, client.ErrInvalidClientMetadata) {
+		t.Errorf("UpdateRegistration() error = %v, want %v for the privileged scope", err, client.ErrInvalidClientMetadata)
+	}
+
+	// the initial access token holder registers the trusted clients
+	trusted, _ := newRegistrationService()
+	if reg, err = trusted.Register(ctx, client.Metadata{RedirectURIs: redirectURIs, Scope: "openid admin:write"}); err != nil || reg.Scope != "openid admin:write" {
+		t.Errorf("Register() = %v, %v; want the requested registered scope", reg, err)
+	}
+}
+
+func TestRegister_OpenRegistrationBackchannel(t *testing.T) {
+	srv, _ := newRegistrationService(client.WithOpenRegistration())
+	ctx := context.Background()
+
+	// the backchannel authentication reveals whether the user exists,
+	// so it isn't available for the self-registered clients
+	m := client.Metadata{GrantTypes: []string{"urn:openid:params:grant-type:ciba"}, ResponseTypes: []string{}}
+	if _, err := srv.Register(ctx, m); !errors.Is(err, client.ErrInvalidClientMetadata) {
+		t.Errorf("Register() error = %v, want %v for the ciba grant", err, client.ErrInvalidClientMetadata)
+	}
+
+	reg, err := srv.Register(ctx, client.Metadata{GrantTypes: []string{"client_credentials"}, ResponseTypes: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.UpdateRegistration(ctx, reg.ClientID, reg.RegistrationAccessToken, m); !errors.Is(err, client.ErrInvalidClientMetadata) {
+		t.Errorf("UpdateRegistration() error = %v, want %v for the ciba grant", err, client.ErrInvalidClientMetadata)
+	}
+}
+
+func TestRegister_Metadata(t *testing.T) {
+	redirectURIs := []string{"https://client.example.com/callback"}
+
+	tests := []struct {
+		name       string
+		metadata   client.Metadata
+		wantErr    error
+		wantGrants []string
+	}{
+		{
+			name:       "default grant and response types",
+			metadata:   client.Metadata{RedirectURIs: redirectURIs},
+			wantGrants: []string{"authorization_code"},
+		},
+		{
+			name:     "public client with client_credentials",
+			metadata: client.Metadata{TokenEndpointAuthMethod: "none", GrantTypes: []string{"client_credentials"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:     "public client with ciba",
+			metadata: client.Metadata{TokenEndpointAuthMethod: "none", GrantTypes: []string{"urn:openid:params:grant-type:ciba"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:     "client_credentials with default code response type",
+			metadata: client.Metadata{GrantTypes: []string{"client_credentials"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:       "confidential client with client_credentials",
+			metadata:   client.Metadata{GrantTypes: []string{"client_credentials"}, ResponseTypes: []string{}},
+			wantGrants: []string{"client_credentials"},
+		},
+		{
+			name:     "token response type without implicit grant",
+			metadata: client.Metadata{RedirectURIs: redirectURIs, ResponseTypes: []string{"code", "token"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:       "token response type with implicit grant",
+			metadata:   client.Metadata{RedirectURIs: redirectURIs, GrantTypes: []string{"authorization_code", "implicit"}, ResponseTypes: []string{"code", "token"}},
+			wantGrants: []string{"authorization_code", "__implicit"},
+		},
+		{
+			name:       "device and ciba grants",
+			metadata:   client.Metadata{GrantTypes: []string{"urn:ietf:params:oauth:grant-type:device_code", "urn:openid:params:grant-type:ciba"}, ResponseTypes: []string{}},
+			wantGrants: []string{"urn:ietf:params:oauth:grant-type:device_code", "urn:openid:params:grant-type:ciba"},
+		},
+		{
+			name:     "unknown response type",
+			metadata: client.Metadata{RedirectURIs: redirectURIs, ResponseTypes: []string{"id_token"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:     "unknown grant type",
+			metadata: client.Metadata{RedirectURIs: redirectURIs, GrantTypes: []string{"password"}},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:       "private_key_jwt with https jwks_uri",
+			metadata:   client.Metadata{RedirectURIs: redirectURIs, TokenEndpointAuthMethod: "private_key_jwt", JWKSURI: "https://client.example.com/jwks.json"},
+			wantGrants: []string{"authorization_code"},
+		},
+		{
+			name:     "private_key_jwt with http jwks_uri",
+			metadata: client.Metadata{RedirectURIs: redirectURIs, TokenEndpointAuthMethod: "private_key_jwt", JWKSURI: "http://client.example.com/jwks.json"},
+			wantErr:  client.ErrInvalidClientMetadata,
+		},
+		{
+			name:     "redirect-based grant without redirect uri",
+			metadata: client.Metadata{},
+			wantErr:  client.ErrInvalidRedirect,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, repo := newRegistrationService()
+			reg, err := srv.Register(context.Background(), tt.metadata)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := repo.clients[reg.ClientID].AllowedGrants; strings.Join(got, " ") != strings.Join(tt.wantGrants, " ") {
+				t.Errorf("allowed grants = %v, want %v", got, tt.wantGrants)
+			}
+		})
+	}
+}
+
+func TestUpdateRegistration_Secret(t *testing.T) {
+	srv, repo := newRegistrationService()
+	ctx := context.Background()
+	redirectURIs := []string{"https://client.example.com/callback"}
+
+	reg, err := srv.Register(ctx, client.Metadata{RedirectURIs: redirectURIs, TokenEndpointAuthMethod: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.ClientSecret != "" {
+		t.Fatalf("Register() returned the secret of the public client")
+	}
+	token := reg.RegistrationAccessToken
+
+	// the public client becomes confidential and gets a new secret
+	updated, err := srv.UpdateRegistration(ctx, reg.ClientID, token, client.Metadata{RedirectURIs: redirectURIs, TokenEndpointAuthMethod: "client_secret_basic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ClientSecret == "" || bcrypt.CompareHashAndPassword(repo.clients[reg.ClientID].Secret, []byte(updated.ClientSecret)) != nil {
+		t.Fatalf("UpdateRegistration() = %+v, want a new client secret", updated)
+	}
+	secretHash := repo.clients[reg.ClientID].Secret
+
+	// the confidential client keeps its secret
+	kept, err := srv.UpdateRegistration(ctx, reg.ClientID, token, client.Metadata{RedirectURIs: redirectURIs, ClientName: "Renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.ClientSecret != "" || string(repo.clients[reg.ClientID].Secret) != string(secretHash) {
+		t.Errorf("UpdateRegistration() regenerated the secret of the confidential client")
+	}
+
+	// the client_secret_jwt client gets a new secret, since it's stored encrypted
+	jwt, err := srv.UpdateRegistration(ctx, reg.ClientID, token, client.Metadata{RedirectURIs: redirectURIs, TokenEndpointAuthMethod: "client_secret_jwt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jwt.ClientSecret == "" || len(repo.clients[reg.ClientID].EncryptedSecret) == 0 {
+		t.Errorf("UpdateRegistration() = %+v, want a new encrypted secret for client_secret_jwt", jwt)
+	}
+}
+
+func (m *clientRepoMock) UpdateClientAllowedGrants(ctx context.Context, arg repository.UpdateClientAllowedGrantsParams) (repository.Client, error) {
+	c := m.clients[arg.ID]
+	c.AllowedGrants = arg.AllowedGrants
+	m.clients[c.ID] = c
+	return c, nil
+}
+
+func TestUpdateImplicitGrant(t *testing.T) {
+	srv, repo := newRegistrationService()
+	ctx := context.Background()
+	repo.clients["id_client"] = repository.Client{ID: "id_client", AllowedGrants: []string{"authorization_code", "refresh_token"}}
+
+	c, err := srv.UpdateImplicitGrant(ctx, "id_client", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Implicit || strings.Join(repo.clients["id_client"].AllowedGrants, " ") != "authorization_code refresh_token __implicit" {
+		t.Errorf("UpdateImplicitGrant() enabled = %v, grants = %v; want the implicit grant enabled", c.Implicit, repo.clients["id_client"].AllowedGrants)
+	}
+
+	c, err = srv.UpdateImplicitGrant(ctx, "id_client", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Implicit || strings.Join(repo.clients["id_client"].AllowedGrants, " ") != "authorization_code refresh_token" {
+		t.Errorf("UpdateImplicitGrant() enabled = %v, grants = %v; want the implicit grant disabled", c.Implicit, repo.clients["id_client"].AllowedGrants)
+	}
+}
+
+func TestCreate_DefaultAuthMethod(t *testing.T) {
+	srv, repo := newRegistrationService(client.WithOpenRegistration())
+	ctx := context.Background()
+	redirectURIs := []string{"https://client.example.com/callback"}
+
+	created, err := srv.Create(ctx, "8b4b4e5e-3c39-4f5c-8c3c-6a1d8b2f6f5e", "Client", "client.example.com", false, redirectURIs, client.Authentication{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := srv.Register(ctx, client.Metadata{RedirectURIs: redirectURIs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{created.ID, reg.ClientID} {
+		if m := repo.clients[id].TokenEndpointAuthMethod; m != oauth.DefaultClientAuthMethod {
+			t.Errorf("client %s auth method = %s, want %s", id, m, oauth.DefaultClientAuthMethod)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
 	"github.com/dmitrymomot/oauth2-server/internal/kitlog"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
 	"github.com/go-chi/chi/v5"
 	jwtkit "github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/transport"
@@ -29,7 +30,7 @@ func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(kitlog.NewLogger(log))),
 		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
-		httptransport.ServerBefore(jwtkit.HTTPToContext()),
+		httptransport.ServerBefore(jwtkit.HTTPToContext(), middleware.ClientCertificateToContext(), middleware.DPoPToContext()),
 	}
 
 	r.Post("/", httptransport.NewServer(
@@ -53,6 +54,48 @@ func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
 		options...,
 	).ServeHTTP)
 
+	r.Put("/{id}/redirect_uris", httptransport.NewServer(
+		e.UpdateRedirectURIs,
+		decodeUpdateRedirectURIsRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}/authentication", httptransport.NewServer(
+		e.UpdateAuthentication,
+		decodeUpdateAuthenticationRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}/pushed_authorization", httptransport.NewServer(
+		e.UpdatePushedAuthorization,
+		decodeUpdatePushedAuthorizationRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}/implicit", httptransport.NewServer(
+		e.UpdateImplicitGrant,
+		decodeUpdateImplicitGrantRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}/response_modes", httptransport.NewServer(
+		e.UpdateResponseModes,
+		decodeUpdateResponseModesRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}/tokens", httptransport.NewServer(
+		e.UpdateTokenLifetimes,
+		decodeUpdateTokenLifetimesRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
 	r.Delete("/{id}", httptransport.NewServer(
 		e.Delete,
 		decodeDeleteRequest,
@@ -63,6 +106,48 @@ func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
 	return r
 }
 
+// MakeRegistrationHTTPHandler returns a handler of the dynamic client registration endpoint
+// and the client configuration endpoint, see RFC 7591 and RFC 7592.
+func MakeRegistrationHTTPHandler(e RegistrationEndpoints, log logger) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(kitlog.NewLogger(log))),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+		httptransport.ServerBefore(jwtkit.HTTPToContext()),
+	}
+
+	r.Post("/", httptransport.NewServer(
+		e.Register,
+		decodeRegisterRequest,
+		encodeRegistrationResponse(http.StatusCreated),
+		options...,
+	).ServeHTTP)
+
+	r.Get("/{id}", httptransport.NewServer(
+		e.GetRegistration,
+		decodeGetByIDRequest,
+		encodeRegistrationResponse(http.StatusOK),
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}", httptransport.NewServer(
+		e.UpdateRegistration,
+		decodeUpdateRegistrationRequest,
+		encodeRegistrationResponse(http.StatusOK),
+		options...,
+	).ServeHTTP)
+
+	r.Delete("/{id}", httptransport.NewServer(
+		e.DeleteRegistration,
+		decodeDeleteRequest,
+		encodeRegistrationResponse(http.StatusNoContent),
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
 // returns http error code by error type
 func codeAndMessageFrom(err error) (int, interface{}) {
 	if resp := NewError(err); resp != nil {
@@ -100,6 +185,108 @@ func decodeGetByIDRequest(_ context.Context, r *http.Request) (interface{}, erro
 	return id, nil
 }
 
+// decodeUpdateRedirectURIsRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateRedirectURIsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateRedirectURIsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeUpdateAuthenticationRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateAuthenticationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateAuthenticationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeUpdatePushedAuthorizationRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdatePushedAuthorizationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdatePushedAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeUpdateImplicitGrantRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateImplicitGrantRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateImplicitGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeUpdateResponseModesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateResponseModesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateResponseModesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeUpdateTokenLifetimesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateTokenLifetimesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateTokenLifetimesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
 // decodeDeleteRequest is a transport/http.DecodeRequestFunc that decodes a
 // JSON-encoded request from the HTTP request body.
 func decodeDeleteRequest(_ context.Context, r *http.Request) (interface{}, error) {
@@ -110,3 +297,50 @@ func decodeDeleteRequest(_ context.Context, r *http.Request) (interface{}, error
 
 	return id, nil
 }
+
+// decodeRegisterRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded client metadata from the HTTP request body.
+func decodeRegisterRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req Metadata
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, ErrInvalidClientMetadata
+	}
+
+	return req, nil
+}
+
+// decodeUpdateRegistrationRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded client metadata from the HTTP request body.
+func decodeUpdateRegistrationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	var req UpdateRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, ErrInvalidClientMetadata
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// encodeRegistrationResponse returns a transport/http.EncodeResponseFunc that encodes
+// the client information response as is with the given status code.
+// The response contains the credentials, so it must not be cached.
+func encodeRegistrationResponse(code int) httptransport.EncodeResponseFunc {
+	return func(_ context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		if response == nil {
+			w.WriteHeader(code)
+			return nil
+		}
+
+		w.Header().Set(httpencoder.ContentTypeHeader, httpencoder.ContentType)
+		w.WriteHeader(code)
+		return json.NewEncoder(w).Encode(response)
+	}
+}
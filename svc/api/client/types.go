@@ -1,31 +1,195 @@
 package client
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
 )
 
 // Client represents an OAuth client.
 type Client struct {
 	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
 	Secret    string `json:"secret,omitempty"`
 	Domain    string `json:"domain"`
 	Public    bool   `json:"is_public"`
-	UserID    string `json:"user_id"`
+	UserID    string `json:"user_id,omitempty"`
 	CreatedAt string `json:"created_at"`
+
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+	TLSClientAuthSubjectDN  string          `json:"tls_client_auth_subject_dn,omitempty"`
+
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
+	Implicit                           bool `json:"implicit"`
+
+	// ResponseModes the client may use, all supported response modes if empty
+	ResponseModes []string `json:"response_modes"`
+
+	TokenLifetimes TokenLifetimes `json:"token_lifetimes"`
+}
+
+// TokenLifetimes represents the client token settings, the lifetimes are in seconds.
+// The server defaults of the grant type are applied if the lifetime is zero.
+type TokenLifetimes struct {
+	AccessTokenTTL     int64  `json:"access_token_ttl"`
+	RefreshTokenTTL    int64  `json:"refresh_token_ttl"`
+	CodeTTL            int64  `json:"code_ttl"`
+	IssueRefreshTokens bool   `json:"issue_refresh_tokens"`
+	RefreshTokenExpiry string `json:"refresh_token_expiry"` // absolute or idle
+}
+
+// Authentication represents the client authentication settings at the token endpoint.
+type Authentication struct {
+	Method    string          // token_endpoint_auth_method
+	JWKS      json.RawMessage // public keys of the private_key_jwt or self_signed_tls_client_auth client
+	JWKSURI   string          // URI of the public keys of the private_key_jwt or self_signed_tls_client_auth client
+	SubjectDN string          // expected subject DN of the tls_client_auth client certificate
 }
 
 // NewClient creates a new client instance.
 // The secret is hashed before being stored in the database.
 // So it can be returned only once after creation.
 func NewClient(source repository.Client, secret string) *Client {
+	// the dynamically registered clients have no owner
+	var userID string
+	if source.UserID.Valid {
+		userID = source.UserID.UUID.String()
+	}
+
 	return &Client{
 		ID:        source.ID,
+		Name:      source.Name,
 		Secret:    secret,
 		Domain:    source.Domain,
 		Public:    source.IsPublic,
-		UserID:    source.UserID.String(),
+		UserID:    userID,
 		CreatedAt: source.CreatedAt.Format(time.RFC3339),
+
+		RedirectURIs: source.RedirectUris,
+
+		TokenEndpointAuthMethod: source.TokenEndpointAuthMethod,
+		JWKS: func() json.RawMessage {
+			if source.Jwks == "" {
+				return nil
+			}
+			return json.RawMessage(source.Jwks)
+		}(),
+		JWKSURI:                source.JwksUri,
+		TLSClientAuthSubjectDN: source.TlsClientAuthSubjectDn,
+
+		RequirePushedAuthorizationRequests: source.RequirePushedAuthorizationRequests,
+		Implicit:                           hasString(source.AllowedGrants, string(oauth2.Implicit)),
+
+		ResponseModes: source.ResponseModes,
+
+		TokenLifetimes: TokenLifetimes{
+			AccessTokenTTL:     source.AccessTokenTtl,
+			RefreshTokenTTL:    source.RefreshTokenTtl,
+			CodeTTL:            source.CodeTtl,
+			IssueRefreshTokens: source.IssueRefreshTokens,
+			RefreshTokenExpiry: string(source.RefreshTokenExpiry),
+		},
 	}
 }
+
+// Metadata represents the client metadata of the dynamic client registration.
+// See: https://www.rfc-editor.org/rfc/rfc7591#section-2
+type Metadata struct {
+	RedirectURIs            []string        `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string        `json:"grant_types,omitempty"`
+	ResponseTypes           []string        `json:"response_types,omitempty"`
+	ClientName              string          `json:"client_name,omitempty"`
+	ClientURI               string          `json:"client_uri,omitempty"`
+	Scope                   string          `json:"scope,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	TLSClientAuthSubjectDN  string          `json:"tls_client_auth_subject_dn,omitempty"`
+
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+
+	// the client initiated backchannel authentication settings,
+	// see: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.4
+	BackchannelTokenDeliveryMode          string `json:"backchannel_token_delivery_mode,omitempty"`
+	BackchannelClientNotificationEndpoint string `json:"backchannel_client_notification_endpoint,omitempty"`
+
+	// the logout settings, see: https://openid.net/specs/openid-connect-rpinitiated-1_0.html#ClientMetadata,
+	// https://openid.net/specs/openid-connect-frontchannel-1_0.html#RPLogout
+	// and https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRegistration
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty"`
+	FrontchannelLogoutURI  string   `json:"frontchannel_logout_uri,omitempty"`
+	BackchannelLogoutURI   string   `json:"backchannel_logout_uri,omitempty"`
+}
+
+// Registration represents the client information response of the dynamic client registration.
+// The client secret and the registration access token are returned only once after registration,
+// since only their hashes are stored.
+// See: https://www.rfc-editor.org/rfc/rfc7591#section-3.2.1
+type Registration struct {
+	ClientID              string `json:"client_id"`
+	ClientSecret          string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt      int64  `json:"client_id_issued_at"`
+	ClientSecretExpiresAt *int64 `json:"client_secret_expires_at,omitempty"`
+
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+
+	Metadata
+}
+
+// NewRegistration creates a new client information response.
+// The secret never expires, so client_secret_expires_at is 0 if the secret is returned.
+func NewRegistration(source repository.Client, secret, registrationToken string) *Registration {
+	r := &Registration{
+		ClientID:                source.ID,
+		ClientSecret:            secret,
+		ClientIDIssuedAt:        source.CreatedAt.Unix(),
+		RegistrationAccessToken: registrationToken,
+		Metadata: Metadata{
+			RedirectURIs:            source.RedirectUris,
+			TokenEndpointAuthMethod: source.TokenEndpointAuthMethod,
+			GrantTypes:              make([]string, 0, len(source.AllowedGrants)),
+			ClientName:              source.Name,
+			ClientURI:               source.Domain,
+			Scope:                   source.Scope,
+			JWKSURI:                 source.JwksUri,
+			TLSClientAuthSubjectDN:  source.TlsClientAuthSubjectDn,
+
+			RequirePushedAuthorizationRequests: source.RequirePushedAuthorizationRequests,
+
+			PostLogoutRedirectURIs: source.PostLogoutRedirectUris,
+			FrontchannelLogoutURI:  source.FrontchannelLogoutUri,
+			BackchannelLogoutURI:   source.BackchannelLogoutUri,
+		},
+	}
+	if secret != "" {
+		r.ClientSecretExpiresAt = new(int64)
+	}
+	if source.Jwks != "" {
+		r.JWKS = json.RawMessage(source.Jwks)
+	}
+
+	for _, gt := range source.AllowedGrants {
+		switch oauth2.GrantType(gt) {
+		case oauth2.AuthorizationCode:
+			r.ResponseTypes = append(r.ResponseTypes, oauth2.Code.String())
+		case oauth2.Implicit:
+			// go-oauth2 uses an internal name for the implicit grant
+			gt = grantTypeImplicit
+			r.ResponseTypes = append(r.ResponseTypes, oauth2.Token.String())
+		case oauth.CIBAGrantType:
+			r.BackchannelTokenDeliveryMode = source.BackchannelTokenDeliveryMode
+			r.BackchannelClientNotificationEndpoint = source.BackchannelClientNotificationEndpoint
+		}
+		r.GrantTypes = append(r.GrantTypes, gt)
+	}
+
+	return r
+}
@@ -0,0 +1,130 @@
+package scope
+
+import (
+	"context"
+
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints collects all of the endpoints that compose a scope registry service. It's
+	// meant to be used as a helper struct, to collect all of the endpoints into a
+	// single parameter.
+	Endpoints struct {
+		Create endpoint.Endpoint
+		Get    endpoint.Endpoint
+		List   endpoint.Endpoint
+		Update endpoint.Endpoint
+		Delete endpoint.Endpoint
+	}
+
+	ScopeResponse struct {
+		Scope  *Scope   `json:"scope,omitempty"`
+		Scopes []*Scope `json:"scopes,omitempty"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes the
+// corresponding method on the provided service. Primarily useful in a server.
+// The scope registry is managed by the server administrator,
+// so all endpoints require the admin token passed as the bearer token.
+func MakeEndpoints(s Service, adminToken string, m ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		Create: MakeCreateEndpoint(s),
+		Get:    MakeGetEndpoint(s),
+		List:   MakeListEndpoint(s),
+		Update: MakeUpdateEndpoint(s),
+		Delete: MakeDeleteEndpoint(s),
+	}
+
+	m = append([]endpoint.Middleware{middleware.GokitAdminTokenMiddleware(adminToken)}, m...)
+	for _, mdw := range m {
+		e.Create = mdw(e.Create)
+		e.Get = mdw(e.Get)
+		e.List = mdw(e.List)
+		e.Update = mdw(e.Update)
+		e.Delete = mdw(e.Delete)
+	}
+
+	return e
+}
+
+// MakeCreateEndpoint returns an endpoint via the passed service.
+func MakeCreateEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(Scope)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		scope, err := s.Create(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return ScopeResponse{Scope: scope}, nil
+	}
+}
+
+// MakeGetEndpoint returns an endpoint via the passed service.
+func MakeGetEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(string)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		scope, err := s.Get(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return ScopeResponse{Scope: scope}, nil
+	}
+}
+
+// MakeListEndpoint returns an endpoint via the passed service.
+func MakeListEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		scopes, err := s.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return ScopeResponse{Scopes: scopes}, nil
+	}
+}
+
+// MakeUpdateEndpoint returns an endpoint via the passed service.
+func MakeUpdateEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(Scope)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		scope, err := s.Update(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return ScopeResponse{Scope: scope}, nil
+	}
+}
+
+// MakeDeleteEndpoint returns an endpoint via the passed service.
+func MakeDeleteEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(string)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if err := s.Delete(ctx, req); err != nil {
+			return nil, err
+		}
+
+		return true, nil
+	}
+}
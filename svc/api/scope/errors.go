@@ -0,0 +1,76 @@
+package scope
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+)
+
+// Predefined errors.
+var (
+	ErrScopeNotFound      = errors.New("scope_not_found")
+	ErrScopeAlreadyExists = errors.New("scope_already_exists")
+	ErrInvalidRequest     = errors.New("invalid_request")
+	ErrInvalidParameter   = errors.New("invalid_parameter")
+	ErrInvalidScopeName   = errors.New("invalid_scope_name")
+	ErrInvalidOwner       = errors.New("invalid_resource_owner")
+	ErrInvalidAdminToken  = middleware.ErrInvalidAdminToken
+)
+
+// Error codes map
+var ErrorCodes = map[error]int{
+	ErrScopeNotFound:      http.StatusNotFound,
+	ErrScopeAlreadyExists: http.StatusConflict,
+	ErrInvalidRequest:     http.StatusBadRequest,
+	ErrInvalidParameter:   http.StatusBadRequest,
+	ErrInvalidScopeName:   http.StatusBadRequest,
+	ErrInvalidOwner:       http.StatusBadRequest,
+	ErrInvalidAdminToken:  http.StatusUnauthorized,
+}
+
+// Error messages
+var ErrorMessages = map[error]string{
+	ErrScopeNotFound:      "Scope not found",
+	ErrScopeAlreadyExists: "Scope is already registered",
+	ErrInvalidRequest:     "Invalid request",
+	ErrInvalidParameter:   "Invalid parameter",
+	ErrInvalidScopeName:   "Scope name must be a non-empty string without spaces and quotes",
+	ErrInvalidOwner:       "Resource owner must be either user or client",
+	ErrInvalidAdminToken:  "Missed or invalid admin token",
+}
+
+// NewError creates a new error
+func NewError(err error) *httpencoder.ErrorResponse {
+	code, ok := ErrorCodes[err]
+	if !ok {
+		if stdErr := findError(err); stdErr != nil {
+			code, ok = ErrorCodes[stdErr]
+		} else {
+			return nil
+		}
+	}
+
+	errStr := err.Error()
+	msg, ok := ErrorMessages[err]
+	if !ok {
+		errStr = http.StatusText(code)
+		msg = err.Error()
+	}
+
+	return &httpencoder.ErrorResponse{
+		Code:    code,
+		Err:     errStr,
+		Message: msg,
+	}
+}
+
+func findError(err error) error {
+	for stdErr := range ErrorCodes {
+		if errors.Is(err, stdErr) {
+			return stdErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package scope
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+)
+
+type (
+	// Service is the scope registry service interface.
+	Service interface {
+		// Create registers a new scope.
+		Create(ctx context.Context, s Scope) (*Scope, error)
+		// Get returns the registered scope by its name.
+		Get(ctx context.Context, name string) (*Scope, error)
+		// List returns all registered scopes.
+		List(ctx context.Context) ([]*Scope, error)
+		// Update replaces the description and the settings of the registered scope.
+		Update(ctx context.Context, s Scope) (*Scope, error)
+		// Delete removes the scope from the registry,
+		// the scope can't be requested anymore, but the issued tokens are still valid.
+		Delete(ctx context.Context, name string) error
+	}
+
+	// Scope represents the registered scope.
+	Scope struct {
+		Name            string `json:"name"`
+		Description     string `json:"description"`
+		RequiresConsent bool   `json:"requires_consent"`
+		IsDefault       bool   `json:"is_default"`
+		ResourceOwner   string `json:"resource_owner"`
+		UpdatedAt       string `json:"updated_at"`
+		CreatedAt       string `json:"created_at"`
+	}
+
+	service struct {
+		repo scopeRepository
+	}
+
+	scopeRepository interface {
+		CreateScope(ctx context.Context, arg repository.CreateScopeParams) (repository.Scope, error)
+		GetScope(ctx context.Context, name string) (repository.Scope, error)
+		GetScopes(ctx context.Context) ([]repository.Scope, error)
+		UpdateScope(ctx context.Context, arg repository.UpdateScopeParams) (repository.Scope, error)
+		DeleteScope(ctx context.Context, name string) error
+	}
+)
+
+// NewScope casts a repository.Scope to a scope.Scope.
+func NewScope(s repository.Scope) *Scope {
+	return &Scope{
+		Name:            s.Name,
+		Description:     s.Description,
+		RequiresConsent: s.RequiresConsent,
+		IsDefault:       s.IsDefault,
+		ResourceOwner:   string(s.ResourceOwner),
+		UpdatedAt:       s.UpdatedAt.Format(time.RFC3339),
+		CreatedAt:       s.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// NewService creates a new scope registry service.
+// It is the concrete implementation of the Service interface.
+func NewService(repo scopeRepository) Service {
+	return &service{repo: repo}
+}
+
+// Create registers a new scope.
+func (s *service) Create(ctx context.Context, sc Scope) (*Scope, error) {
+	owner, err := validateScope(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetScope(ctx, sc.Name); err == nil {
+		return nil, ErrScopeAlreadyExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get scope: %w", err)
+	}
+
+	result, err := s.repo.CreateScope(ctx, repository.CreateScopeParams{
+		Name:            sc.Name,
+		Description:     sc.Description,
+		RequiresConsent: sc.RequiresConsent,
+		IsDefault:       sc.IsDefault,
+		ResourceOwner:   owner,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scope: %w", err)
+	}
+
+	return NewScope(result), nil
+}
+
+// Get returns the registered scope by its name.
+func (s *service) Get(ctx context.Context, name string) (*Scope, error) {
+	result, err := s.repo.GetScope(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScopeNotFound
+		}
+		return nil, fmt.Errorf("failed to get scope: %w", err)
+	}
+
+	return NewScope(result), nil
+}
+
+// List returns all registered scopes.
+func (s *service) List(ctx context.Context) ([]*Scope, error) {
+	items, err := s.repo.GetScopes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scopes: %w", err)
+	}
+
+	result := make([]*Scope, 0, len(items))
+	for _, item := range items {
+		result = append(result, NewScope(item))
+	}
+
+	return result, nil
+}
+
+// Update replaces the description and the settings of the registered scope.
+func (s *service) Update(ctx context.Context, sc Scope) (*Scope, error) {
+	owner, err := validateScope(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.UpdateScope(ctx, repository.UpdateScopeParams{
+		Name:            sc.Name,
+		Description:     sc.Description,
+		RequiresConsent: sc.RequiresConsent,
+		IsDefault:       sc.IsDefault,
+		ResourceOwner:   owner,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScopeNotFound
+		}
+		return nil, fmt.Errorf("failed to update scope: %w", err)
+	}
+
+	return NewScope(result), nil
+}
+
+// Delete removes the scope from the registry.
+func (s *service) Delete(ctx context.Context, name string) error {
+	if _, err := s.Get(ctx, name); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteScope(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete scope: %w", err)
+	}
+
+	return nil
+}
+
+// validateScope checks the scope name and returns the resource owner,
+// the scope protects the user resources by default.
+func validateScope(sc Scope) (repository.ScopeResourceOwner, error) {
+	// the scope token syntax, see: https://www.rfc-editor.org/rfc/rfc6749#section-3.3
+	if sc.Name == "" || strings.ContainsAny(sc.Name, " \"\\") {
+		return "", ErrInvalidScopeName
+	}
+	for _, r := range sc.Name {
+		if r < 0x21 || r > 0x7e {
+			return "", ErrInvalidScopeName
+		}
+	}
+
+	switch owner := repository.ScopeResourceOwner(sc.ResourceOwner); owner {
+	case "":
+		return repository.ScopeResourceOwnerUser, nil
+	case repository.ScopeResourceOwnerUser, repository.ScopeResourceOwnerClient:
+		return owner, nil
+	}
+
+	return "", ErrInvalidOwner
+}
@@ -0,0 +1,131 @@
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/internal/kitlog"
+	"github.com/go-chi/chi/v5"
+	jwtkit "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Println(args ...interface{})
+		Warnf(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// MakeHTTPHandler returns a handler of the scope registry management API.
+func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(kitlog.NewLogger(log))),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+		httptransport.ServerBefore(jwtkit.HTTPToContext()),
+	}
+
+	r.Post("/", httptransport.NewServer(
+		e.Create,
+		decodeCreateRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/", httptransport.NewServer(
+		e.List,
+		decodeListRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/{name}", httptransport.NewServer(
+		e.Get,
+		decodeNameRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{name}", httptransport.NewServer(
+		e.Update,
+		decodeUpdateRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Delete("/{name}", httptransport.NewServer(
+		e.Delete,
+		decodeNameRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	if resp := NewError(err); resp != nil {
+		return resp.Code, resp
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeCreateRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeCreateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req Scope
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+
+	return req, nil
+}
+
+// decodeListRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+// decodeNameRequest is a transport/http.DecodeRequestFunc that decodes
+// the scope name from the URL path, the name is URL encoded, e.g. user%3A%2A.
+func decodeNameRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return scopeNameFromURL(r)
+}
+
+// decodeUpdateRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := scopeNameFromURL(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var req Scope
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+	req.Name = name
+
+	return req, nil
+}
+
+// scopeNameFromURL returns the unescaped scope name from the URL path.
+func scopeNameFromURL(r *http.Request) (string, error) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil || name == "" {
+		return "", ErrInvalidParameter
+	}
+
+	return name, nil
+}
@@ -0,0 +1,59 @@
+package token
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints collects all of the endpoints that compose a token administration service. It's
+	// meant to be used as a helper struct, to collect all of the endpoints into a
+	// single parameter.
+	Endpoints struct {
+		Revoke endpoint.Endpoint
+	}
+
+	// RevokeRequest is the request type for the Revoke endpoint.
+	RevokeRequest struct {
+		UserID   string `json:"user_id"`
+		ClientID string `json:"client_id"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes the
+// corresponding method on the provided service. Primarily useful in a server.
+// The tokens of any user and client are revoked by the server administrator,
+// so all endpoints require the admin token passed as the bearer token.
+func MakeEndpoints(s Service, adminToken string, m ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		Revoke: MakeRevokeEndpoint(s),
+	}
+
+	m = append([]endpoint.Middleware{middleware.GokitAdminTokenMiddleware(adminToken)}, m...)
+	for _, mdw := range m {
+		e.Revoke = mdw(e.Revoke)
+	}
+
+	return e
+}
+
+// MakeRevokeEndpoint returns an endpoint via the passed service.
+func MakeRevokeEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RevokeRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		n, err := s.Revoke(ctx, req.UserID, req.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		return httpencoder.BoolResult(true, fmt.Sprintf("%d tokens have been revoked.", n)), nil
+	}
+}
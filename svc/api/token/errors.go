@@ -0,0 +1,64 @@
+package token
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
+)
+
+// Predefined errors.
+var (
+	ErrInvalidRequest    = errors.New("invalid_request")
+	ErrInvalidParameter  = errors.New("invalid_parameter")
+	ErrInvalidAdminToken = middleware.ErrInvalidAdminToken
+)
+
+// Error codes map
+var ErrorCodes = map[error]int{
+	ErrInvalidRequest:    http.StatusBadRequest,
+	ErrInvalidParameter:  http.StatusBadRequest,
+	ErrInvalidAdminToken: http.StatusUnauthorized,
+}
+
+// Error messages
+var ErrorMessages = map[error]string{
+	ErrInvalidRequest:    "Invalid request",
+	ErrInvalidParameter:  "Either user_id or client_id must be set, user_id must be a valid UUID",
+	ErrInvalidAdminToken: "Missed or invalid admin token",
+}
+
+// NewError creates a new error
+func NewError(err error) *httpencoder.ErrorResponse {
+	code, ok := ErrorCodes[err]
+	if !ok {
+		if stdErr := findError(err); stdErr != nil {
+			code, ok = ErrorCodes[stdErr]
+		} else {
+			return nil
+		}
+	}
+
+	errStr := err.Error()
+	msg, ok := ErrorMessages[err]
+	if !ok {
+		errStr = http.StatusText(code)
+		msg = err.Error()
+	}
+
+	return &httpencoder.ErrorResponse{
+		Code:    code,
+		Err:     errStr,
+		Message: msg,
+	}
+}
+
+func findError(err error) error {
+	for stdErr := range ErrorCodes {
+		if errors.Is(err, stdErr) {
+			return stdErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package token
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Service is the token administration service interface.
+	Service interface {
+		// Revoke revokes the tokens issued on behalf of the user, the tokens issued to the client,
+		// or, if both are set, the tokens issued to the client on behalf of the user.
+		// It returns the number of the revoked tokens.
+		Revoke(ctx context.Context, userID, clientID string) (int64, error)
+	}
+
+	service struct {
+		revoker tokenRevoker
+	}
+
+	// tokenRevoker revokes the tokens in bulk, see oauth.TokenRevocation.
+	tokenRevoker interface {
+		RevokeUserTokens(ctx context.Context, userID uuid.UUID) (int64, error)
+		RevokeClientTokens(ctx context.Context, clientID string) (int64, error)
+		RevokeUserClientTokens(ctx context.Context, userID uuid.UUID, clientID string) (int64, error)
+	}
+)
+
+// NewService creates a new token administration service.
+// It is the concrete implementation of the Service interface.
+func NewService(revoker tokenRevoker) Service {
+	return &service{revoker: revoker}
+}
+
+// Revoke revokes the tokens issued on behalf of the user, the tokens issued to the client,
+// or, if both are set, the tokens issued to the client on behalf of the user.
+// It returns the number of the revoked tokens.
+func (s *service) Revoke(ctx context.Context, userID, clientID string) (int64, error) {
+	if userID == "" {
+		if clientID == "" {
+			return 0, ErrInvalidParameter
+		}
+		return s.revoker.RevokeClientTokens(ctx, clientID)
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid user id: %s", ErrInvalidParameter, err)
+	}
+
+	if clientID == "" {
+		return s.revoker.RevokeUserTokens(ctx, uid)
+	}
+
+	return s.revoker.RevokeUserClientTokens(ctx, uid, clientID)
+}
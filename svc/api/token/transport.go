@@ -0,0 +1,59 @@
+package token
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/internal/kitlog"
+	"github.com/go-chi/chi/v5"
+	jwtkit "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Println(args ...interface{})
+		Warnf(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// MakeHTTPHandler returns a handler of the token administration API.
+func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(kitlog.NewLogger(log))),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+		httptransport.ServerBefore(jwtkit.HTTPToContext()),
+	}
+
+	r.Delete("/", httptransport.NewServer(
+		e.Revoke,
+		decodeRevokeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	if resp := NewError(err); resp != nil {
+		return resp.Code, resp
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeRevokeRequest is a transport/http.DecodeRequestFunc that decodes
+// the user ID and the client ID from the URL query.
+func decodeRevokeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return RevokeRequest{
+		UserID:   r.URL.Query().Get("user_id"),
+		ClientID: r.URL.Query().Get("client_id"),
+	}, nil
+}
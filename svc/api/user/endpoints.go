@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
 	"github.com/dmitrymomot/oauth2-server/internal/validator"
@@ -19,6 +20,7 @@ type (
 		UpdateEmail    endpoint.Endpoint
 		UpdatePassword endpoint.Endpoint
 		Delete         endpoint.Endpoint
+		RevokeTokens   endpoint.Endpoint
 	}
 
 	UserResponse struct {
@@ -35,6 +37,7 @@ func MakeEndpoints(s Service, m ...endpoint.Middleware) Endpoints {
 		UpdateEmail:    MakeUpdateEmailEndpoint(s),
 		UpdatePassword: MakeUpdatePasswordEndpoint(s),
 		Delete:         MakeDeleteEndpoint(s),
+		RevokeTokens:   MakeRevokeTokensEndpoint(s),
 	}
 
 	for _, mdw := range m {
@@ -43,6 +46,7 @@ func MakeEndpoints(s Service, m ...endpoint.Middleware) Endpoints {
 		e.UpdateEmail = mdw(e.UpdateEmail)
 		e.UpdatePassword = mdw(e.UpdatePassword)
 		e.Delete = mdw(e.Delete)
+		e.RevokeTokens = mdw(e.RevokeTokens)
 	}
 
 	return e
@@ -158,3 +162,31 @@ func MakeDeleteEndpoint(s Service) endpoint.Endpoint {
 		return httpencoder.BoolResult(true, "We have sent you an email to confirm the deletion of your account."), nil
 	}
 }
+
+// RevokeTokensRequest is the request type for the RevokeTokens endpoint.
+type RevokeTokensRequest struct {
+	ClientID string `json:"client_id" filter:"trim" label:"Client ID"`
+}
+
+// MakeRevokeTokensEndpoint returns an endpoint via the passed service.
+// The user signs out of the client, or of all clients if the client ID isn't set.
+func MakeRevokeTokensEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokenInfo, ok := middleware.GetTokenInfoFromContext(ctx)
+		if !ok || tokenInfo == nil || tokenInfo.UserID == "" {
+			return nil, ErrForbidden
+		}
+
+		req, ok := request.(RevokeTokensRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		n, err := s.RevokeTokens(ctx, tokenInfo.UserID, req.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		return httpencoder.BoolResult(true, fmt.Sprintf("%d tokens have been revoked.", n)), nil
+	}
+}
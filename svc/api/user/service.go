@@ -23,6 +23,9 @@ type (
 		UpdatePassword(ctx context.Context, id, oldPassword, newPassword string) error
 		// Delete deletes the user with the specified ID.
 		Delete(ctx context.Context, id string) error
+		// RevokeTokens revokes the tokens of the user with the specified ID,
+		// issued to the client if the client ID isn't empty, or to all clients otherwise.
+		RevokeTokens(ctx context.Context, id, clientID string) (int64, error)
 	}
 
 	User struct {
@@ -33,9 +36,10 @@ type (
 	}
 
 	service struct {
-		repo userRepository
-		mail mailer
-		db   *sql.DB
+		repo    userRepository
+		mail    mailer
+		db      *sql.DB
+		revoker tokenRevoker
 	}
 
 	userRepository interface {
@@ -47,6 +51,12 @@ type (
 		CreateUserVerification(ctx context.Context, arg repository.CreateUserVerificationParams) error
 	}
 
+	// tokenRevoker revokes the tokens in bulk, see oauth.TokenRevocation.
+	tokenRevoker interface {
+		RevokeUserTokens(ctx context.Context, userID uuid.UUID) (int64, error)
+		RevokeUserClientTokens(ctx context.Context, userID uuid.UUID, clientID string) (int64, error)
+	}
+
 	mailer interface {
 		SendConfirmationEmail(ctx context.Context, uid uuid.UUID, email, otp string) error
 		SendDestroyProfileEmail(ctx context.Context, uid uuid.UUID, email, otp string) error
@@ -65,8 +75,8 @@ func NewUser(u repository.User) *User {
 
 // NewService creates a new user service.
 // It is the concrete implementation of the Service interface.
-func NewService(repo userRepository, m mailer, db *sql.DB) Service {
-	return &service{repo: repo, mail: m, db: db}
+func NewService(repo userRepository, m mailer, db *sql.DB, revoker tokenRevoker) Service {
+	return &service{repo: repo, mail: m, db: db, revoker: revoker}
 }
 
 // GetByID returns the user with the specified the user ID.
@@ -226,3 +236,19 @@ func (s *service) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// RevokeTokens revokes the tokens of the user with the specified ID,
+// issued to the client if the client ID isn't empty, or to all clients otherwise.
+// It returns the number of the revoked tokens.
+func (s *service) RevokeTokens(ctx context.Context, id, clientID string) (int64, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	if clientID == "" {
+		return s.revoker.RevokeUserTokens(ctx, uid)
+	}
+
+	return s.revoker.RevokeUserClientTokens(ctx, uid, clientID)
+}
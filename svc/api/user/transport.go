@@ -8,6 +8,7 @@ import (
 
 	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
 	"github.com/dmitrymomot/oauth2-server/internal/kitlog"
+	"github.com/dmitrymomot/oauth2-server/lib/middleware"
 	"github.com/go-chi/chi/v5"
 	jwtkit "github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/transport"
@@ -29,7 +30,7 @@ func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(kitlog.NewLogger(log))),
 		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
-		httptransport.ServerBefore(jwtkit.HTTPToContext()),
+		httptransport.ServerBefore(jwtkit.HTTPToContext(), middleware.ClientCertificateToContext(), middleware.DPoPToContext()),
 	}
 
 	r.Route("/profile", func(r chi.Router) {
@@ -60,6 +61,13 @@ func MakeHTTPHandler(e Endpoints, log logger) http.Handler {
 			httpencoder.EncodeResponse,
 			options...,
 		).ServeHTTP)
+
+		r.Delete("/tokens", httptransport.NewServer(
+			e.RevokeTokens,
+			decodeRevokeTokensRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
 	})
 
 	r.Get("/{id}", httptransport.NewServer(
@@ -120,3 +128,8 @@ func decodeUpdatePasswordRequest(_ context.Context, r *http.Request) (interface{
 func decodeDeleteRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	return nil, nil
 }
+
+// DecodeRevokeTokensRequest ...
+func decodeRevokeTokensRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return RevokeTokensRequest{ClientID: r.URL.Query().Get("client_id")}, nil
+}
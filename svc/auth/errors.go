@@ -12,4 +12,10 @@ var (
 	ErrVerificationCodeExpired    = errors.New("Verification code expired")
 	ErrUserNotVerified            = errors.New("User not verified")
 	ErrUserAlreadyVerified        = errors.New("User already verified")
+	ErrInvalidUserCode            = errors.New("Invalid code. Please check the code displayed on your device and try again.")
+	ErrUserCodeExpired            = errors.New("Code expired. Please request a new code on your device.")
+	ErrTooManyUserCodeAttempts    = errors.New("Too many invalid codes. Please wait a few minutes and try again.")
+	ErrInvalidBackchannelRequest  = errors.New("Invalid or already processed sign-in request.")
+	ErrBackchannelRequestExpired  = errors.New("Sign-in request expired. Please ask to start a new one.")
+	ErrInvalidCSRFToken           = errors.New("The form has expired. Please try again.")
 )
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
 	"github.com/dmitrymomot/random"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -32,14 +33,44 @@ type (
 		DestroyProfileRequest(ctx context.Context, email string) error
 		// DestroyProfile destroys a user profile.
 		DestroyProfile(ctx context.Context, email, otp string) error
+		// GetDeviceRequest returns the device authorization request to approve by the user.
+		GetDeviceRequest(ctx context.Context, uid uuid.UUID, userCode string) (*DeviceRequest, error)
+		// AuthorizeDevice approves or denies the device authorization request by user code.
+		AuthorizeDevice(ctx context.Context, uid uuid.UUID, userCode string, approve bool) error
+		// GetBackchannelRequest returns the backchannel authentication request to approve by the user.
+		GetBackchannelRequest(ctx context.Context, uid uuid.UUID, approvalCode string) (*BackchannelRequest, error)
+		// AuthorizeBackchannel approves or denies the backchannel authentication request by approval code.
+		AuthorizeBackchannel(ctx context.Context, uid uuid.UUID, approvalCode string, approve bool) error
+	}
+
+	// DeviceRequest is the device authorization request displayed to the user.
+	DeviceRequest struct {
+		UserCode   string
+		ClientName string
+		Scopes     []oauth.ScopeDescription
+		// Consented is true if the user has already approved the requested scopes for the client
+		Consented bool
+	}
+
+	// BackchannelRequest is the backchannel authentication request displayed to the user.
+	BackchannelRequest struct {
+		ApprovalCode   string
+		ClientName     string
+		Scope          string
+		BindingMessage string
 	}
 
 	service struct {
-		repo authRepository
-		db   *sql.DB
-		mail mailer
+		repo     authRepository
+		db       *sql.DB
+		mail     mailer
+		notifier backchannelNotifier
+		consent  consentManager
 	}
 
+	// ServiceOption is a function that configures the auth service.
+	ServiceOption func(*service)
+
 	authRepository interface {
 		WithTx(tx *sql.Tx) *repository.Queries
 		GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error)
@@ -53,6 +84,27 @@ type (
 		GetUserVerificationByEmail(ctx context.Context, arg repository.GetUserVerificationByEmailParams) (repository.UserVerification, error)
 		DeleteUserVerificationsByEmail(ctx context.Context, arg repository.DeleteUserVerificationsByEmailParams) error
 		DeleteUserVerificationsByUserID(ctx context.Context, arg repository.DeleteUserVerificationsByUserIDParams) error
+
+		GetDeviceCodeByUserCode(ctx context.Context, userCode string) (repository.DeviceCode, error)
+		UpdateDeviceCodeStatus(ctx context.Context, arg repository.UpdateDeviceCodeStatusParams) (int64, error)
+
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+		GetBackchannelAuthRequestByApprovalCode(ctx context.Context, approvalCode string) (repository.BackchannelAuthRequest, error)
+		UpdateBackchannelAuthRequestStatus(ctx context.Context, arg repository.UpdateBackchannelAuthRequestStatusParams) (int64, error)
+	}
+
+	// backchannelNotifier notifies the ping mode client the backchannel authentication request
+	// is approved or denied, it's implemented by oauth.BackchannelAuthGrant
+	backchannelNotifier interface {
+		NotifyClient(ctx context.Context, req repository.BackchannelAuthRequest) error
+	}
+
+	// consentManager remembers the scopes approved by the user for the client,
+	// it's implemented by oauth.ConsentManager
+	consentManager interface {
+		Required(ctx context.Context, uid uuid.UUID, clientID, scope string) (bool, error)
+		Grant(ctx context.Context, uid uuid.UUID, clientID, scope string) error
+		Scopes(ctx context.Context, clientID, scope string) ([]oauth.ScopeDescription, error)
 	}
 
 	mailer interface {
@@ -62,13 +114,34 @@ type (
 	}
 )
 
+// WithBackchannelNotifier sets the notifier of the ping mode CIBA clients.
+func WithBackchannelNotifier(n backchannelNotifier) ServiceOption {
+	return func(s *service) {
+		s.notifier = n
+	}
+}
+
+// WithConsentManager sets the consent manager, so the scopes approved with the device authorization
+// are remembered and aren't asked again in the authorization code flow.
+func WithConsentManager(c consentManager) ServiceOption {
+	return func(s *service) {
+		s.consent = c
+	}
+}
+
 // NewService creates a new auth service.
-func NewService(repo authRepository, db *sql.DB, m mailer) Service {
-	return &service{
+func NewService(repo authRepository, db *sql.DB, m mailer, opts ...ServiceOption) Service {
+	s := &service{
 		repo: repo,
 		db:   db,
 		mail: m,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Login authenticates a user and returns a user ID.
@@ -489,3 +562,203 @@ func (s *service) DestroyProfile(ctx context.Context, email, otp string) error {
 
 	return nil
 }
+
+// GetDeviceRequest returns the device authorization request to approve by the user,
+// so the user can check the client and the scopes before the device gets access.
+func (s *service) GetDeviceRequest(ctx context.Context, uid uuid.UUID, userCode string) (*DeviceRequest, error) {
+	dc, err := s.deviceCode(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.GetClientByID(ctx, dc.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	clientName := client.Name
+	if clientName == "" {
+		clientName = client.Domain
+	}
+
+	req := &DeviceRequest{
+		UserCode:   oauth.FormatUserCode(dc.UserCode),
+		ClientName: clientName,
+	}
+	if s.consent == nil {
+		for _, name := range strings.Fields(dc.Scope) {
+			req.Scopes = append(req.Scopes, oauth.ScopeDescription{Name: name, Description: name})
+		}
+		return req, nil
+	}
+
+	if req.Scopes, err = s.consent.Scopes(ctx, dc.ClientID, dc.Scope); err != nil {
+		return nil, err
+	}
+	required, err := s.consent.Required(ctx, uid, dc.ClientID, dc.Scope)
+	if err != nil {
+		return nil, err
+	}
+	req.Consented = !required
+
+	return req, nil
+}
+
+// AuthorizeDevice approves or denies the device authorization request by user code.
+// The approved scopes are remembered for the client.
+func (s *service) AuthorizeDevice(ctx context.Context, uid uuid.UUID, userCode string, approve bool) error {
+	dc, err := s.deviceCode(ctx, userCode)
+	if err != nil {
+		return err
+	}
+
+	status := repository.DeviceCodeStatusDenied
+	if approve {
+		status = repository.DeviceCodeStatusApproved
+	}
+
+	// only pending requests can be updated, so the code can't be used twice
+	updated, err := s.repo.UpdateDeviceCodeStatus(ctx, repository.UpdateDeviceCodeStatusParams{
+		Status:   status,
+		UserID:   uuid.NullUUID{UUID: uid, Valid: true},
+		UserCode: dc.UserCode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update device code status: %w", err)
+	}
+	if updated == 0 {
+		return ErrInvalidUserCode
+	}
+
+	if approve && s.consent != nil {
+		if err := s.consent.Grant(ctx, uid, dc.ClientID, dc.Scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deviceCode returns the pending device authorization request by user code
+func (s *service) deviceCode(ctx context.Context, userCode string) (repository.DeviceCode, error) {
+	userCode = normalizeUserCode(userCode)
+	if userCode == "" {
+		return repository.DeviceCode{}, ErrInvalidUserCode
+	}
+
+	dc, err := s.repo.GetDeviceCodeByUserCode(ctx, userCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.DeviceCode{}, ErrInvalidUserCode
+		}
+		return repository.DeviceCode{}, fmt.Errorf("failed to get device code by user code: %w", err)
+	}
+
+	if dc.Status != repository.DeviceCodeStatusPending {
+		return repository.DeviceCode{}, ErrInvalidUserCode
+	}
+	if dc.ExpiresAt.Before(time.Now()) {
+		return repository.DeviceCode{}, ErrUserCodeExpired
+	}
+
+	return dc, nil
+}
+
+// GetBackchannelRequest returns the backchannel authentication request to approve by the user.
+// The request started for another user is reported as invalid.
+func (s *service) GetBackchannelRequest(ctx context.Context, uid uuid.UUID, approvalCode string) (*BackchannelRequest, error) {
+	br, err := s.backchannelRequest(ctx, uid, approvalCode)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.GetClientByID(ctx, br.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	clientName := client.Name
+	if clientName == "" {
+		clientName = client.Domain
+	}
+
+	return &BackchannelRequest{
+		ApprovalCode:   br.ApprovalCode,
+		ClientName:     clientName,
+		Scope:          br.Scope,
+		BindingMessage: br.BindingMessage,
+	}, nil
+}
+
+// AuthorizeBackchannel approves or denies the backchannel authentication request by approval code.
+// The ping mode client is notified to request the token.
+func (s *service) AuthorizeBackchannel(ctx context.Context, uid uuid.UUID, approvalCode string, approve bool) error {
+	br, err := s.backchannelRequest(ctx, uid, approvalCode)
+	if err != nil {
+		return err
+	}
+
+	status := repository.BackchannelAuthStatusDenied
+	if approve {
+		status = repository.BackchannelAuthStatusApproved
+	}
+
+	// only pending requests can be updated, so the code can't be used twice
+	updated, err := s.repo.UpdateBackchannelAuthRequestStatus(ctx, repository.UpdateBackchannelAuthRequestStatusParams{
+		Status:       status,
+		ApprovalCode: br.ApprovalCode,
+		UserID:       uid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update backchannel auth request status: %w", err)
+	}
+	if updated == 0 {
+		return ErrInvalidBackchannelRequest
+	}
+
+	if s.notifier != nil {
+		if err := s.notifier.NotifyClient(ctx, br); err != nil {
+			return fmt.Errorf("failed to notify client: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// backchannelRequest returns the pending backchannel authentication request of the user by approval code
+func (s *service) backchannelRequest(ctx context.Context, uid uuid.UUID, approvalCode string) (repository.BackchannelAuthRequest, error) {
+	if approvalCode == "" {
+		return repository.BackchannelAuthRequest{}, ErrInvalidBackchannelRequest
+	}
+
+	br, err := s.repo.GetBackchannelAuthRequestByApprovalCode(ctx, approvalCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.BackchannelAuthRequest{}, ErrInvalidBackchannelRequest
+		}
+		return repository.BackchannelAuthRequest{}, fmt.Errorf("failed to get backchannel auth request by approval code: %w", err)
+	}
+
+	if br.UserID != uid || br.Status != repository.BackchannelAuthStatusPending {
+		return repository.BackchannelAuthRequest{}, ErrInvalidBackchannelRequest
+	}
+	if br.ExpiresAt.Before(time.Now()) {
+		return repository.BackchannelAuthRequest{}, ErrBackchannelRequestExpired
+	}
+
+	return br, nil
+}
+
+// normalizeUserCode removes separators from the user code entered by the user
+// and converts it to upper case, e.g. "bcdf-ghjk" -> "BCDFGHJK".
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, code)
+}
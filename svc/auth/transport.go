@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/dmitrymomot/oauth2-server/internal/binder"
 	"github.com/dmitrymomot/oauth2-server/internal/session"
@@ -12,10 +13,21 @@ import (
 	"github.com/dmitrymomot/oauth2-server/internal/validator"
 	"github.com/foolin/goview"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type httpMiddleware func(http.Handler) http.Handler
 
+// csrfTokenParam is the form field of the CSRF token
+const csrfTokenParam = "csrf_token"
+
+// Failed device user code lookups limit per session,
+// the user code is short, so it must not be guessed by trying the codes one by one
+const (
+	maxUserCodeFailures   = 5
+	userCodeFailureWindow = 15 * time.Minute
+)
+
 // MakeHTTPHandler returns a handler that makes a set of endpoints available on
 // predefined paths.
 func MakeHTTPHandler(srv Service, oauth2AuthURI string, notAuthMdw httpMiddleware) http.Handler {
@@ -42,6 +54,9 @@ func MakeHTTPHandler(srv Service, oauth2AuthURI string, notAuthMdw httpMiddlewar
 		rv.HandleFunc("/verify", httpAccountDestroyVerificationHandler(srv))
 	})
 
+	r.HandleFunc("/device", httpDeviceHandler(srv))
+	r.HandleFunc("/backchannel", httpBackchannelHandler(srv))
+
 	return r
 }
 
@@ -440,3 +455,212 @@ func httpAccountDestroyVerificationHandler(srv Service) http.HandlerFunc {
 		goview.Render(w, http.StatusOK, "destroy_account_success", data)
 	}
 }
+
+// === Device Authorization ===
+
+// Device handler actions, the user code is looked up without the action
+const (
+	deviceActionApprove = "approve"
+	deviceActionDeny    = "deny"
+)
+
+// httpDeviceHandlerRequest is the request payload for the device handler.
+type httpDeviceHandlerRequest struct {
+	UserCode string `json:"user_code" validate:"required" filter:"trim" label:"Code"`
+	Action   string `json:"action" validate:"-" label:"Action"`
+}
+
+// httpDeviceHandler handles the user code entered by the logged in user.
+// The device authorization request is shown to the user on the second step,
+// so the user approves or denies it after checking the client and the scopes.
+func httpDeviceHandler(srv Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		uid, ok := session.GetLoggedInUserID(r, w)
+		if !ok {
+			session.StoreReturnURI(r, w, r.URL.String())
+			http.Redirect(w, r, "/auth/login", http.StatusFound)
+			return
+		}
+
+		data := map[string]interface{}{
+			"page_title": "Connect a device",
+		}
+
+		payload := httpDeviceHandlerRequest{}
+		if err := binder.Bind(r, &payload); err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "device", data)
+			return
+		}
+		data["form"] = payload
+
+		// the token is single-use, so it's verified before a new one is issued for the form
+		validCSRF := r.Method == http.MethodPost && session.VerifyCSRFToken(r, w, r.PostFormValue(csrfTokenParam))
+		csrfToken, err := session.StoreCSRFToken(r, w)
+		if err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "device", data)
+			return
+		}
+		data["csrf_token"] = csrfToken
+
+		if r.Method == http.MethodPost {
+			if !validCSRF {
+				data["errors"] = []string{ErrInvalidCSRFToken.Error()}
+				goview.Render(w, http.StatusOK, "device", data)
+				return
+			}
+			if v := validator.ValidateStruct(&payload); len(v) > 0 {
+				data["validation"] = v
+				goview.Render(w, http.StatusOK, "device", data)
+				return
+			}
+
+			userID, err := uuid.Parse(uid)
+			if err != nil {
+				data["errors"] = []string{err.Error()}
+				goview.Render(w, http.StatusOK, "device", data)
+				return
+			}
+
+			since := time.Now().Add(-userCodeFailureWindow)
+			if session.CountUserCodeFailures(r, w, since) >= maxUserCodeFailures {
+				data["errors"] = []string{ErrTooManyUserCodeAttempts.Error()}
+				goview.Render(w, http.StatusOK, "device", data)
+				return
+			}
+
+			if payload.Action != deviceActionApprove && payload.Action != deviceActionDeny {
+				req, err := srv.GetDeviceRequest(r.Context(), userID, payload.UserCode)
+				if err != nil {
+					renderUserCodeError(w, r, data, err, since)
+					return
+				}
+				data["request"] = req
+				goview.Render(w, http.StatusOK, "device", data)
+				return
+			}
+
+			approved := payload.Action == deviceActionApprove
+			if err := srv.AuthorizeDevice(r.Context(), userID, payload.UserCode, approved); err != nil {
+				renderUserCodeError(w, r, data, err, since)
+				return
+			}
+
+			data["page_title"] = "Device authorization"
+			data["approved"] = approved
+			goview.Render(w, http.StatusOK, "device_success", data)
+			return
+		}
+
+		goview.Render(w, http.StatusOK, "device", data)
+	}
+}
+
+// renderUserCodeError renders the user code form with the error,
+// the unknown user code is counted as the failed lookup within the session.
+func renderUserCodeError(w http.ResponseWriter, r *http.Request, data map[string]interface{}, err error, since time.Time) {
+	if errors.Is(err, ErrInvalidUserCode) {
+		if sErr := session.AddUserCodeFailure(r, w, since); sErr != nil {
+			err = sErr
+		}
+	}
+
+	data["validation"] = url.Values{
+		"user_code": []string{err.Error()},
+	}
+	goview.Render(w, http.StatusOK, "device", data)
+}
+
+// === Backchannel Authentication ===
+
+// httpBackchannelHandlerRequest is the request payload for the backchannel handler.
+type httpBackchannelHandlerRequest struct {
+	Code   string `json:"code" validate:"required" filter:"trim" label:"Code"`
+	Action string `json:"action" validate:"-" label:"Action"`
+}
+
+// httpBackchannelHandler shows the backchannel authentication request to the logged in user
+// who opened the approval link and approves or denies it.
+func httpBackchannelHandler(srv Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		uid, ok := session.GetLoggedInUserID(r, w)
+		if !ok {
+			session.StoreReturnURI(r, w, r.URL.String())
+			http.Redirect(w, r, "/auth/login", http.StatusFound)
+			return
+		}
+
+		data := map[string]interface{}{
+			"page_title": "Approve sign-in",
+		}
+
+		payload := httpBackchannelHandlerRequest{}
+		if err := binder.Bind(r, &payload); err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "backchannel", data)
+			return
+		}
+
+		if v := validator.ValidateStruct(&payload); len(v) > 0 {
+			data["errors"] = []string{ErrInvalidBackchannelRequest.Error()}
+			goview.Render(w, http.StatusOK, "backchannel", data)
+			return
+		}
+
+		userID, err := uuid.Parse(uid)
+		if err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "backchannel", data)
+			return
+		}
+
+		// the token is single-use, so it's verified before a new one is issued for the form
+		validCSRF := r.Method == http.MethodPost && session.VerifyCSRFToken(r, w, r.PostFormValue(csrfTokenParam))
+		csrfToken, err := session.StoreCSRFToken(r, w)
+		if err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "backchannel", data)
+			return
+		}
+		data["csrf_token"] = csrfToken
+
+		if r.Method == http.MethodPost && validCSRF {
+			approved := payload.Action == "approve"
+			if err := srv.AuthorizeBackchannel(r.Context(), userID, payload.Code, approved); err != nil {
+				data["errors"] = []string{err.Error()}
+				goview.Render(w, http.StatusOK, "backchannel", data)
+				return
+			}
+
+			data["page_title"] = "Sign-in request"
+			data["approved"] = approved
+			goview.Render(w, http.StatusOK, "backchannel_success", data)
+			return
+		}
+		if r.Method == http.MethodPost {
+			// the request is shown again to be approved with the new token
+			data["errors"] = []string{ErrInvalidCSRFToken.Error()}
+		}
+
+		req, err := srv.GetBackchannelRequest(r.Context(), userID, payload.Code)
+		if err != nil {
+			data["errors"] = []string{err.Error()}
+			goview.Render(w, http.StatusOK, "backchannel", data)
+			return
+		}
+		data["request"] = req
+
+		goview.Render(w, http.StatusOK, "backchannel", data)
+	}
+}
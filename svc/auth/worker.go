@@ -12,6 +12,18 @@ import (
 const (
 	CleanUpExpiredVerificationRequestsTask = "clean_up_expired_verification_requests"
 	CleanUpExpiredTokensTask               = "clean_up_expired_tokens"
+	CleanUpExpiredDeviceCodesTask          = "clean_up_expired_device_codes"
+	CleanUpExpiredPushedRequestsTask       = "clean_up_expired_pushed_authorization_requests"
+	CleanUpExpiredBackchannelRequestsTask  = "clean_up_expired_backchannel_auth_requests"
+)
+
+// Queues used by the worker scheduler
+const (
+	expiredVerificationRequestsQueue = "auth-exp-ver-reqs"
+	expiredTokensQueue               = "auth-exp-tokens"
+	expiredDeviceCodesQueue          = "auth-exp-device-codes"
+	expiredPushedRequestsQueue       = "auth-exp-par"
+	expiredBackchannelRequestsQueue  = "auth-exp-ciba"
 )
 
 type (
@@ -24,6 +36,9 @@ type (
 	workerRepository interface {
 		CleanUpExpiredUserVerifications(ctx context.Context) error
 		DeleteExpiredTokens(ctx context.Context) error
+		DeleteExpiredDeviceCodes(ctx context.Context) error
+		DeleteExpiredPushedAuthorizationRequests(ctx context.Context) error
+		DeleteExpiredBackchannelAuthRequests(ctx context.Context) error
 	}
 
 	logger interface {
@@ -39,21 +54,50 @@ func NewWorker(repo workerRepository, log logger) *Worker {
 // Schedule schedules tasks for the worker.
 func (w *Worker) Schedule(s *asynq.Scheduler) {
 	s.Register("@every 1h", asynq.NewTask(CleanUpExpiredVerificationRequestsTask, nil),
-		asynq.Queue("auth-exp-ver-reqs"),
+		asynq.Queue(expiredVerificationRequestsQueue),
 		asynq.Unique(time.Hour),
 		asynq.MaxRetry(0),
 	)
 	s.Register("@every 1h", asynq.NewTask(CleanUpExpiredTokensTask, nil),
-		asynq.Queue("auth-exp-tokens"),
+		asynq.Queue(expiredTokensQueue),
 		asynq.Unique(time.Hour),
 		asynq.MaxRetry(0),
 	)
+	s.Register("@every 10m", asynq.NewTask(CleanUpExpiredDeviceCodesTask, nil),
+		asynq.Queue(expiredDeviceCodesQueue),
+		asynq.Unique(10*time.Minute),
+		asynq.MaxRetry(0),
+	)
+	s.Register("@every 10m", asynq.NewTask(CleanUpExpiredPushedRequestsTask, nil),
+		asynq.Queue(expiredPushedRequestsQueue),
+		asynq.Unique(10*time.Minute),
+		asynq.MaxRetry(0),
+	)
+	s.Register("@every 10m", asynq.NewTask(CleanUpExpiredBackchannelRequestsTask, nil),
+		asynq.Queue(expiredBackchannelRequestsQueue),
+		asynq.Unique(10*time.Minute),
+		asynq.MaxRetry(0),
+	)
+}
+
+// Queues returns the queues the scheduled tasks are enqueued to.
+func (w *Worker) Queues() []string {
+	return []string{
+		expiredVerificationRequestsQueue,
+		expiredTokensQueue,
+		expiredDeviceCodesQueue,
+		expiredPushedRequestsQueue,
+		expiredBackchannelRequestsQueue,
+	}
 }
 
 // Register registers task handlers for email delivery.
 func (w *Worker) Register(mux *asynq.ServeMux) {
 	mux.HandleFunc(CleanUpExpiredVerificationRequestsTask, w.CleanUpExpiredVerificationRequests)
 	mux.HandleFunc(CleanUpExpiredTokensTask, w.CleanUpExpiredTokens)
+	mux.HandleFunc(CleanUpExpiredDeviceCodesTask, w.CleanUpExpiredDeviceCodes)
+	mux.HandleFunc(CleanUpExpiredPushedRequestsTask, w.CleanUpExpiredPushedRequests)
+	mux.HandleFunc(CleanUpExpiredBackchannelRequestsTask, w.CleanUpExpiredBackchannelRequests)
 }
 
 // CleanUpExpiredVerificationRequests cleans up expired verification requests.
@@ -77,3 +121,36 @@ func (w *Worker) CleanUpExpiredTokens(ctx context.Context, t *asynq.Task) error
 
 	return nil
 }
+
+// CleanUpExpiredDeviceCodes cleans up expired device authorization requests.
+func (w *Worker) CleanUpExpiredDeviceCodes(ctx context.Context, t *asynq.Task) error {
+	if err := w.repo.DeleteExpiredDeviceCodes(ctx); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			w.log.Errorf("failed to clean up expired device codes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CleanUpExpiredPushedRequests cleans up expired pushed authorization requests.
+func (w *Worker) CleanUpExpiredPushedRequests(ctx context.Context, t *asynq.Task) error {
+	if err := w.repo.DeleteExpiredPushedAuthorizationRequests(ctx); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			w.log.Errorf("failed to clean up expired pushed authorization requests: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CleanUpExpiredBackchannelRequests cleans up expired backchannel authentication requests.
+func (w *Worker) CleanUpExpiredBackchannelRequests(ctx context.Context, t *asynq.Task) error {
+	if err := w.repo.DeleteExpiredBackchannelAuthRequests(ctx); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			w.log.Errorf("failed to clean up expired backchannel authentication requests: %w", err)
+		}
+	}
+
+	return nil
+}
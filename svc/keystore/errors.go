@@ -0,0 +1,11 @@
+package keystore
+
+import "errors"
+
+// Predefined errors
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidKeyData       = errors.New("invalid key data")
+	ErrNoActiveKey          = errors.New("no active signing key")
+	ErrKeyNotFound          = errors.New("signing key not found")
+)
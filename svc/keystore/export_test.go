@@ -0,0 +1,14 @@
+package keystore
+
+import "context"
+
+// Repository is the repository the store changes the keys with.
+type Repository = keystoreRepository
+
+// WithTxRunner replaces the database transaction of the keys changes,
+// so the store can be tested without the database.
+func WithTxRunner(run func(ctx context.Context, fn func(repo Repository) error) error) StoreOption {
+	return func(s *Store) {
+		s.inTx = run
+	}
+}
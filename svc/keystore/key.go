@@ -0,0 +1,129 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// rsaKeySize is the size of generated RSA keys
+const rsaKeySize = 2048
+
+// Key represents a signing key pair.
+type Key struct {
+	ID          string                      `json:"id"`
+	Algorithm   string                      `json:"algorithm"`
+	Status      repository.SigningKeyStatus `json:"status"`
+	ActivatedAt *time.Time                  `json:"activated_at,omitempty"`
+	RetiredAt   *time.Time                  `json:"retired_at,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+
+	privateKey crypto.Signer
+}
+
+// newKey creates a new key instance from a repository signing key.
+// The private key is decrypted with the given secret.
+func newKey(source repository.SigningKey, secret []byte) (*Key, error) {
+	der, err := utils.Decrypt(source.PrivateKey, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key %s: %w", source.ID, err)
+	}
+
+	pk, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", source.ID, err)
+	}
+
+	signer, ok := pk.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, pk)
+	}
+
+	k := &Key{
+		ID:         source.ID,
+		Algorithm:  source.Algorithm,
+		Status:     source.Status,
+		CreatedAt:  source.CreatedAt,
+		privateKey: signer,
+	}
+
+	if source.ActivatedAt.Valid {
+		k.ActivatedAt = &source.ActivatedAt.Time
+	}
+
+	if source.RetiredAt.Valid {
+		k.RetiredAt = &source.RetiredAt.Time
+	}
+
+	return k, nil
+}
+
+// PublicKey returns the public part of the key pair.
+func (k *Key) PublicKey() crypto.PublicKey {
+	return k.privateKey.Public()
+}
+
+// SigningMethod returns the JWT signing method of the key.
+func (k *Key) SigningMethod() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// JWK returns the public JSON Web Key.
+func (k *Key) JWK() (jwk.Key, error) {
+	return jwk.NewKey(k.ID, k.Algorithm, k.PublicKey())
+}
+
+// Sign returns a signed JWT with the kid header.
+func (k *Key) Sign(claims jwt.Claims) (string, error) {
+	return k.SignWithType(claims, "")
+}
+
+// SignWithType returns a signed JWT with the kid header and the given typ header.
+// The default typ header "JWT" is used if typ is empty.
+func (k *Key) SignWithType(claims jwt.Claims, typ string) (string, error) {
+	token := jwt.NewWithClaims(k.SigningMethod(), claims)
+	token.Header["kid"] = k.ID
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+
+	signed, err := token.SignedString(k.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// generateKey generates a new private key for the given algorithm.
+func generateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case AlgES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		return pk, err
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+}
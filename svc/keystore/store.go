@@ -0,0 +1,363 @@
+package keystore
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/random"
+)
+
+// Default settings
+const (
+	defaultCacheTTL  = time.Minute
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+type (
+	// Store manages the signing keys.
+	// There is always one active key which is used to sign tokens,
+	// one next key which is published in the JWKS in advance, so resource servers
+	// can cache it before it becomes active, and a number of retired keys
+	// which are still published until all tokens signed by them are expired.
+	Store struct {
+		repo   keystoreRepository
+		db     *sql.DB
+		secret []byte
+
+		algorithm string
+		cacheTTL  time.Duration
+		retention time.Duration
+
+		mu       sync.RWMutex
+		keys     []*Key
+		loadedAt time.Time
+
+		// inTx runs the keys changes in a database transaction
+		inTx func(ctx context.Context, fn func(repo keystoreRepository) error) error
+	}
+
+	// StoreOption is a function that configures the Store.
+	StoreOption func(*Store)
+
+	keystoreRepository interface {
+		WithTx(tx *sql.Tx) *repository.Queries
+		CreateSigningKey(ctx context.Context, arg repository.CreateSigningKeyParams) (repository.SigningKey, error)
+		GetSigningKeys(ctx context.Context) ([]repository.SigningKey, error)
+		RetireActiveSigningKeys(ctx context.Context) error
+		ActivateNextSigningKeys(ctx context.Context) (int64, error)
+		DeleteRetiredSigningKeys(ctx context.Context, retiredBefore time.Time) error
+		DeleteNextSigningKeys(ctx context.Context) error
+		LockSigningKeys(ctx context.Context) error
+	}
+)
+
+// WithAlgorithm sets the algorithm for the new keys.
+// Supported algorithms: RS256, ES256, EdDSA. Default: RS256.
+func WithAlgorithm(alg string) StoreOption {
+	return func(s *Store) {
+		if alg != "" {
+			s.algorithm = alg
+		}
+	}
+}
+
+// WithCacheTTL sets the lifetime of the in-memory keys cache.
+// Keys are reloaded from the database after the cache is expired,
+// so all instances pick up the rotated keys.
+func WithCacheTTL(ttl time.Duration) StoreOption {
+	return func(s *Store) {
+		if ttl > 0 {
+			s.cacheTTL = ttl
+		}
+	}
+}
+
+// WithRetention sets how long the retired keys are published.
+// It must be greater than the max lifetime of the signed tokens.
+func WithRetention(d time.Duration) StoreOption {
+	return func(s *Store) {
+		if d > 0 {
+			s.retention = d
+		}
+	}
+}
+
+// NewStore creates a new key store instance.
+// The secret is used to encrypt the private keys at rest.
+func NewStore(repo keystoreRepository, db *sql.DB, secret string, opts ...StoreOption) (*Store, error) {
+	s := &Store{
+		repo:      repo,
+		db:        db,
+		secret:    []byte(secret),
+		algorithm: AlgRS256,
+		cacheTTL:  defaultCacheTTL,
+		retention: defaultRetention,
+	}
+	s.inTx = s.tx
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	switch s.algorithm {
+	case AlgRS256, AlgES256, AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, s.algorithm)
+	}
+
+	return s, nil
+}
+
+// Algorithm returns the algorithm which is used for the new keys.
+func (s *Store) Algorithm() string {
+	return s.algorithm
+}
+
+// Init makes sure there are active and next keys.
+// The next key of another algorithm is replaced, so the algorithm change
+// takes effect on the next rotation, the active key is never replaced to keep the issued tokens valid.
+// It must be called once on the application start, the instances initialize the keys one at a time.
+func (s *Store) Init(ctx context.Context) error {
+	if err := s.locked(ctx, func(repo keystoreRepository) error {
+		items, err := repo.GetSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get signing keys: %w", err)
+		}
+
+		var hasActive, hasNext bool
+		for _, k := range items {
+			switch k.Status {
+			case repository.SigningKeyStatusActive:
+				hasActive = true
+			case repository.SigningKeyStatusNext:
+				hasNext = k.Algorithm == s.algorithm
+			}
+		}
+
+		if !hasActive {
+			if err := s.createKey(ctx, repo, repository.SigningKeyStatusActive); err != nil {
+				return err
+			}
+		}
+		if !hasNext {
+			// the next key is not used to sign tokens yet, so it's safe to delete it
+			if err := repo.DeleteNextSigningKeys(ctx); err != nil {
+				return fmt.Errorf("failed to delete next signing keys: %w", err)
+			}
+			if err := s.createKey(ctx, repo, repository.SigningKeyStatusNext); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	_, err := s.load(ctx)
+	return err
+}
+
+// Rotate retires the active key, activates the next one and generates a new next key.
+// Retired keys older than the retention period are deleted.
+func (s *Store) Rotate(ctx context.Context) error {
+	if err := s.locked(ctx, func(repo keystoreRepository) error {
+		if err := repo.RetireActiveSigningKeys(ctx); err != nil {
+			return fmt.Errorf("failed to retire active signing keys: %w", err)
+		}
+
+		activated, err := repo.ActivateNextSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to activate next signing key: %w", err)
+		}
+		if activated == 0 {
+			// there was no next key, so activate a fresh one right away
+			if err := s.createKey(ctx, repo, repository.SigningKeyStatusActive); err != nil {
+				return err
+			}
+		}
+
+		if err := s.createKey(ctx, repo, repository.SigningKeyStatusNext); err != nil {
+			return err
+		}
+
+		if err := repo.DeleteRetiredSigningKeys(ctx, time.Now().Add(-s.retention)); err != nil {
+			return fmt.Errorf("failed to delete retired signing keys: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	_, err := s.load(ctx)
+	return err
+}
+
+// locked runs fn in a transaction holding the signing keys lock,
+// so the concurrent instances don't create duplicate active or next keys.
+func (s *Store) locked(ctx context.Context, fn func(repo keystoreRepository) error) error {
+	return s.inTx(ctx, func(repo keystoreRepository) error {
+		if err := repo.LockSigningKeys(ctx); err != nil {
+			return fmt.Errorf("failed to lock signing keys: %w", err)
+		}
+		return fn(repo)
+	})
+}
+
+// tx runs fn in a database transaction.
+func (s *Store) tx(ctx context.Context, fn func(repo keystoreRepository) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.repo.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SigningKey returns the active key.
+func (s *Store) SigningKey(ctx context.Context) (*Key, error) {
+	keys, err := s.cachedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		if k.Status == repository.SigningKeyStatusActive {
+			return k, nil
+		}
+	}
+
+	return nil, ErrNoActiveKey
+}
+
+// PublicKey returns the public key with the given key id.
+// Active, next and not deleted retired keys can be used to verify tokens.
+func (s *Store) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	keys, err := s.cachedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		if k.ID == kid {
+			return k.PublicKey(), nil
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// JWKS returns the public JSON Web Key Set.
+func (s *Store) JWKS(ctx context.Context) (jwk.Set, error) {
+	keys, err := s.cachedKeys(ctx)
+	if err != nil {
+		return jwk.Set{}, err
+	}
+
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(keys))}
+	for _, k := range keys {
+		key, err := k.JWK()
+		if err != nil {
+			return jwk.Set{}, err
+		}
+		set.Keys = append(set.Keys, key)
+	}
+
+	return set, nil
+}
+
+// cachedKeys returns the keys from the cache or reloads them from the database.
+func (s *Store) cachedKeys(ctx context.Context) ([]*Key, error) {
+	s.mu.RLock()
+	keys, loadedAt := s.keys, s.loadedAt
+	s.mu.RUnlock()
+
+	if keys != nil && time.Since(loadedAt) < s.cacheTTL {
+		return keys, nil
+	}
+
+	return s.load(ctx)
+}
+
+// load loads the keys from the database and updates the cache.
+func (s *Store) load(ctx context.Context) ([]*Key, error) {
+	items, err := s.repo.GetSigningKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	keys := make([]*Key, 0, len(items))
+	for _, item := range items {
+		k, err := newKey(item, s.secret)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return keys, nil
+}
+
+// createKey generates and stores a new key with the given status.
+func (s *Store) createKey(ctx context.Context, repo keystoreRepository, status repository.SigningKeyStatus) error {
+	pk, err := generateKey(s.algorithm)
+	if err != nil {
+		return err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(pk.Public())
+	if err != nil {
+		return fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	encrypted, err := utils.Encrypt(privateDER, s.secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+
+	var activatedAt sql.NullTime
+	if status == repository.SigningKeyStatusActive {
+		activatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	if _, err := repo.CreateSigningKey(ctx, repository.CreateSigningKeyParams{
+		ID:          random.String(16, random.Alphanumeric),
+		Algorithm:   s.algorithm,
+		PrivateKey:  encrypted,
+		PublicKey:   publicDER,
+		Status:      status,
+		ActivatedAt: activatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package keystore_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/keystore"
+)
+
+const testSecret = "test-secret"
+
+// keysRepoMock stores the signing keys in memory,
+// the advisory lock is held until the end of the transaction
+type keysRepoMock struct {
+	mu   sync.Mutex
+	keys []repository.SigningKey
+	lock chan struct{}
+	// readDelay widens the window between reading and creating the keys
+	readDelay time.Duration
+}
+
+func newKeysRepo() *keysRepoMock {
+	return &keysRepoMock{lock: make(chan struct{}, 1)}
+}
+
+// runTx runs fn on the repository and releases the lock like the transaction commit does
+func (m *keysRepoMock) runTx(ctx context.Context, fn func(repo keystore.Repository) error) error {
+	defer func() {
+		select {
+		case <-m.lock:
+		default:
+		}
+	}()
+	return fn(m)
+}
+
+func (m *keysRepoMock) WithTx(tx *sql.Tx) *repository.Queries {
+	return nil
+}
+
+func (m *keysRepoMock) LockSigningKeys(ctx context.Context) error {
+	m.lock <- struct{}{}
+	return nil
+}
+
+func (m *keysRepoMock) CreateSigningKey(ctx context.Context, arg repository.CreateSigningKeyParams) (repository.SigningKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := repository.SigningKey{
+		ID:          arg.ID,
+		Algorithm:   arg.Algorithm,
+		PrivateKey:  arg.PrivateKey,
+		PublicKey:   arg.PublicKey,
+		Status:      arg.Status,
+		ActivatedAt: arg.ActivatedAt,
+		CreatedAt:   time.Now(),
+	}
+	m.keys = append(m.keys, k)
+	return k, nil
+}
+
+func (m *keysRepoMock) GetSigningKeys(ctx context.Context) ([]repository.SigningKey, error) {
+	m.mu.Lock()
+	// the newest keys first
+	keys := make([]repository.SigningKey, 0, len(m.keys))
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		keys = append(keys, m.keys[i])
+	}
+	m.mu.Unlock()
+
+	time.Sleep(m.readDelay)
+	return keys, nil
+}
+
+func (m *keysRepoMock) RetireActiveSigningKeys(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, k := range m.keys {
+		if k.Status == repository.SigningKeyStatusActive {
+			m.keys[i].Status = repository.SigningKeyStatusRetired
+			m.keys[i].RetiredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (m *keysRepoMock) ActivateNextSigningKeys(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var activated int64
+	for i, k := range m.keys {
+		if k.Status == repository.SigningKeyStatusNext {
+			m.keys[i].Status = repository.SigningKeyStatusActive
+			m.keys[i].ActivatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			activated++
+		}
+	}
+	return activated, nil
+}
+
+func (m *keysRepoMock) DeleteRetiredSigningKeys(ctx context.Context, retiredBefore time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := m.keys[:0]
+	for _, k := range m.keys {
+		if k.Status != repository.SigningKeyStatusRetired || !k.RetiredAt.Time.Before(retiredBefore) {
+			keys = append(keys, k)
+		}
+	}
+	m.keys = keys
+	return nil
+}
+
+func (m *keysRepoMock) DeleteNextSigningKeys(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := m.keys[:0]
+	for _, k := range m.keys {
+		if k.Status != repository.SigningKeyStatusNext {
+			keys = append(keys, k)
+		}
+	}
+	m.keys = keys
+	return nil
+}
+
+// byStatus returns the stored keys with the given status
+func (m *keysRepoMock) byStatus(status repository.SigningKeyStatus) []repository.SigningKey {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []repository.SigningKey
+	for _, k := range m.keys {
+		if k.Status == status {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func newTestStore(t *testing.T, repo *keysRepoMock, alg string, opts ...keystore.StoreOption) *keystore.Store {
+	t.Helper()
+
+	opts = append(opts, keystore.WithAlgorithm(alg), keystore.WithTxRunner(repo.runTx))
+	s, err := keystore.NewStore(repo, nil, testSecret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_Init(t *testing.T) {
+	ctx := context.Background()
+	repo := newKeysRepo()
+
+	s := newTestStore(t, repo, keystore.AlgES256)
+	if err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	active, next := repo.byStatus(repository.SigningKeyStatusActive), repo.byStatus(repository.SigningKeyStatusNext)
+	if len(active) != 1 || len(next) != 1 {
+		t.Fatalf("Init() created %d active and %d next keys, want 1 and 1", len(active), len(next))
+	}
+
+	key, err := s.SigningKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != active[0].ID || key.Algorithm != keystore.AlgES256 {
+		t.Errorf("SigningKey() = %s %s, want the active key %s", key.ID, key.Algorithm, active[0].ID)
+	}
+
+	// the next key is published in advance
+	set, err := s.JWKS(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Errorf("JWKS() has %d keys, want 2", len(set.Keys))
+	}
+
+	// the keys are created only once
+	if err := newTestStore(t, repo, keystore.AlgES256).Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.byStatus(repository.SigningKeyStatusNext); len(repo.keys) != 2 || got[0].ID != next[0].ID {
+		t.Errorf("Init() changed the existing keys: %d keys", len(repo.keys))
+	}
+}
+
+func TestStore_Init_Concurrent(t *testing.T) {
+	repo := newKeysRepo()
+	repo.readDelay = 10 * time.Millisecond
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		s := newTestStore(t, repo, keystore.AlgES256)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Init(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	active, next := repo.byStatus(repository.SigningKeyStatusActive), repo.byStatus(repository.SigningKeyStatusNext)
+	if len(active) != 1 || len(next) != 1 {
+		t.Errorf("concurrent Init() created %d active and %d next keys, want 1 and 1", len(active), len(next))
+	}
+}
+
+func TestStore_Init_AlgorithmChange(t *testing.T) {
+	ctx := context.Background()
+	repo := newKeysRepo()
+
+	if err := newTestStore(t, repo, keystore.AlgES256).Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	active, next := repo.byStatus(repository.SigningKeyStatusActive)[0], repo.byStatus(repository.SigningKeyStatusNext)[0]
+
+	s := newTestStore(t, repo, keystore.AlgEdDSA)
+	if err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the active key still signs tokens until the rotation
+	key, err := s.SigningKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != active.ID || key.Algorithm != keystore.AlgES256 {
+		t.Errorf("SigningKey() = %s %s, want the active key %s", key.ID, key.Algorithm, active.ID)
+	}
+
+	newNext := repo.byStatus(repository.SigningKeyStatusNext)
+	if len(newNext) != 1 || newNext[0].ID == next.ID || newNext[0].Algorithm != keystore.AlgEdDSA {
+		t.Fatalf("Init() next keys = %+v, want one new EdDSA key", newNext)
+	}
+
+	if err := s.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if key, err = s.SigningKey(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != newNext[0].ID || key.Algorithm != keystore.AlgEdDSA {
+		t.Errorf("SigningKey() after rotation = %s %s, want %s EdDSA", key.ID, key.Algorithm, newNext[0].ID)
+	}
+}
+
+func TestStore_Rotate(t *testing.T) {
+	ctx := context.Background()
+	repo := newKeysRepo()
+
+	s := newTestStore(t, repo, keystore.AlgES256)
+	if err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	active, next := repo.byStatus(repository.SigningKeyStatusActive)[0], repo.byStatus(repository.SigningKeyStatusNext)[0]
+
+	if err := s.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	retired := repo.byStatus(repository.SigningKeyStatusRetired)
+	if len(retired) != 1 || retired[0].ID != active.ID || !retired[0].RetiredAt.Valid {
+		t.Errorf("Rotate() retired keys = %+v, want %s", retired, active.ID)
+	}
+
+	key, err := s.SigningKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != next.ID {
+		t.Errorf("SigningKey() = %s, want the former next key %s", key.ID, next.ID)
+	}
+
+	newNext := repo.byStatus(repository.SigningKeyStatusNext)
+	if len(newNext) != 1 || newNext[0].ID == next.ID {
+		t.Errorf("Rotate() next keys = %+v, want one new key", newNext)
+	}
+
+	// the tokens signed by the retired key can still be verified
+	if _, err := s.PublicKey(ctx, active.ID); err != nil {
+		t.Errorf("PublicKey() of the retired key: %v", err)
+	}
+}
+
+func TestStore_Rotate_WithoutNextKey(t *testing.T) {
+	ctx := context.Background()
+	repo := newKeysRepo()
+
+	s := newTestStore(t, repo, keystore.AlgES256)
+	if err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	active := repo.byStatus(repository.SigningKeyStatusActive)[0]
+	if err := repo.DeleteNextSigningKeys(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := s.SigningKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID == active.ID {
+		t.Errorf("SigningKey() = %s, want a fresh key", key.ID)
+	}
+	if len(repo.byStatus(repository.SigningKeyStatusActive)) != 1 || len(repo.byStatus(repository.SigningKeyStatusNext)) != 1 {
+		t.Errorf("Rotate() must leave one active and one next key")
+	}
+}
+
+func TestStore_Rotate_Retention(t *testing.T) {
+	ctx := context.Background()
+	repo := newKeysRepo()
+
+	s := newTestStore(t, repo, keystore.AlgES256, keystore.WithRetention(time.Hour))
+	if err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Rotate(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first retired key is beyond the retention period
+	retired := repo.byStatus(repository.SigningKeyStatusRetired)
+	if len(retired) != 2 {
+		t.Fatalf("Rotate() left %d retired keys, want 2", len(retired))
+	}
+	expired, kept := retired[0], retired[1]
+	repo.mu.Lock()
+	for i, k := range repo.keys {
+		if k.ID == expired.ID {
+			repo.keys[i].RetiredAt = sql.NullTime{Time: time.Now().Add(-2 * time.Hour), Valid: true}
+		}
+	}
+	repo.mu.Unlock()
+
+	if err := s.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.PublicKey(ctx, expired.ID); err != keystore.ErrKeyNotFound {
+		t.Errorf("PublicKey() of the expired retired key error = %v, want %v", err, keystore.ErrKeyNotFound)
+	}
+	if _, err := s.PublicKey(ctx, kept.ID); err != nil {
+		t.Errorf("PublicKey() of the retired key within the retention period: %v", err)
+	}
+	if got := len(repo.byStatus(repository.SigningKeyStatusRetired)); got != 2 {
+		t.Errorf("Rotate() left %d retired keys, want 2", got)
+	}
+}
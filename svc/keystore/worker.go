@@ -0,0 +1,64 @@
+package keystore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	RotateSigningKeysTask = "rotate_signing_keys"
+)
+
+type (
+	// Worker is a task handler for the signing keys rotation.
+	Worker struct {
+		store    keyRotator
+		log      logger
+		queue    string
+		interval time.Duration
+	}
+
+	keyRotator interface {
+		Rotate(ctx context.Context) error
+	}
+
+	logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// NewWorker creates a new key rotation task handler.
+// The keys are rotated every interval, the task is enqueued to the given queue.
+func NewWorker(store keyRotator, log logger, queue string, interval time.Duration) *Worker {
+	return &Worker{store: store, log: log, queue: queue, interval: interval}
+}
+
+// Schedule schedules tasks for the worker.
+func (w *Worker) Schedule(s *asynq.Scheduler) {
+	s.Register(fmt.Sprintf("@every %s", w.interval), asynq.NewTask(RotateSigningKeysTask, nil),
+		asynq.Queue(w.queue),
+		asynq.Unique(w.interval),
+		asynq.MaxRetry(3),
+	)
+}
+
+// Register registers task handlers for the keys rotation.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(RotateSigningKeysTask, w.RotateSigningKeys)
+}
+
+// RotateSigningKeys rotates the signing keys.
+func (w *Worker) RotateSigningKeys(ctx context.Context, t *asynq.Task) error {
+	if err := w.store.Rotate(ctx); err != nil {
+		w.log.Errorf("failed to rotate signing keys: %v", err)
+		return err
+	}
+
+	w.log.Infof("signing keys rotated")
+
+	return nil
+}
@@ -149,3 +149,21 @@ func (e *Enqueuer) SendDestroyProfileEmail(ctx context.Context, uid uuid.UUID, e
 
 	return e.enqueueTask(ctx, asynq.NewTask(SendDestroyProfileEmailTask, payload))
 }
+
+// SendBackchannelAuthEmail sends the link to approve the sign-in request
+// of the client to user.
+// This method returns a task to be added to the queue.
+func (e *Enqueuer) SendBackchannelAuthEmail(ctx context.Context, uid uuid.UUID, email, clientName, bindingMessage, approvalURL string) error {
+	payload, err := json.Marshal(BackchannelAuthEmailPayload{
+		UserID:         uid.String(),
+		Email:          email,
+		ClientName:     clientName,
+		BindingMessage: bindingMessage,
+		ApprovalURL:    approvalURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	return e.enqueueTask(ctx, asynq.NewTask(SendBackchannelAuthEmailTask, payload))
+}
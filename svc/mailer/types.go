@@ -5,6 +5,7 @@ const (
 	SendConfirmationEmailTask     = "send_confirmation_email"
 	SendPasswordRecoveryEmailTask = "send_password_recovery_email"
 	SendDestroyProfileEmailTask   = "send_destroy_profile_email"
+	SendBackchannelAuthEmailTask  = "send_backchannel_auth_email"
 )
 
 type (
@@ -17,4 +18,14 @@ type (
 		Email  string `json:"email,omitempty"`
 		OTP    string `json:"otp,omitempty"`
 	}
+
+	// Payload for sending the sign-in approval link of the backchannel
+	// authentication request started by the client on behalf of the user.
+	BackchannelAuthEmailPayload struct {
+		UserID         string `json:"user_id,omitempty"`
+		Email          string `json:"email,omitempty"`
+		ClientName     string `json:"client_name,omitempty"`
+		BindingMessage string `json:"binding_message,omitempty"`
+		ApprovalURL    string `json:"approval_url,omitempty"`
+	}
 )
@@ -22,6 +22,7 @@ type (
 		SendVerificationCode(ctx context.Context, uid, email, otp string) error
 		SendResetPasswordCode(ctx context.Context, uid, email, otp string) error
 		SendDestroyProfileCode(ctx context.Context, uid, email, otp string) error
+		SendBackchannelAuthLink(ctx context.Context, uid, email, clientName, bindingMessage, approvalURL string) error
 	}
 )
 
@@ -37,6 +38,7 @@ func (w *Worker) Register(mux *asynq.ServeMux) {
 	mux.HandleFunc(SendConfirmationEmailTask, w.TaskSendConfirmationEmail)
 	mux.HandleFunc(SendPasswordRecoveryEmailTask, w.TaskSendPasswordResetEmail)
 	mux.HandleFunc(SendDestroyProfileEmailTask, w.TaskSendDestroyProfileEmail)
+	mux.HandleFunc(SendBackchannelAuthEmailTask, w.TaskSendBackchannelAuthEmail)
 }
 
 // TaskSendConfirmationEmail sends confirmation email to user
@@ -81,3 +83,18 @@ func (w *Worker) TaskSendDestroyProfileEmail(ctx context.Context, t *asynq.Task)
 
 	return nil
 }
+
+// TaskSendBackchannelAuthEmail sends the link to approve the sign-in request
+// started by the client on behalf of the user.
+func (w *Worker) TaskSendBackchannelAuthEmail(ctx context.Context, t *asynq.Task) error {
+	var p BackchannelAuthEmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if err := w.mail.SendBackchannelAuthLink(ctx, p.UserID, p.Email, p.ClientName, p.BindingMessage, p.ApprovalURL); err != nil {
+		return errors.Wrap(err, "failed to send email with sign-in approval link")
+	}
+
+	return nil
+}
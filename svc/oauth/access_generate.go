@@ -0,0 +1,167 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/dmitrymomot/oauth2-server/svc/keystore"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AccessTokenJWTType is the typ header of the JWT access tokens,
+// see: https://www.rfc-editor.org/rfc/rfc9068#section-2.1
+const AccessTokenJWTType = "at+jwt"
+
+// AMRPassword is the amr claim value of the password authentication,
+// see: https://www.rfc-editor.org/rfc/rfc8176#section-2
+const AMRPassword = "pwd"
+
+type (
+	// JWTAccessGenerate generates JWT access tokens signed with the active key
+	// from the key store. Implements the oauth2.AccessGenerate interface.
+	// The tokens follow the JWT profile for the OAuth 2.0 access tokens,
+	// see: https://www.rfc-editor.org/rfc/rfc9068
+	JWTAccessGenerate struct {
+		keys     signingKeyProvider
+		issuer   string
+		audience string
+	}
+
+	accessGenerateOption func(a *JWTAccessGenerate)
+
+	signingKeyProvider interface {
+		SigningKey(ctx context.Context) (*keystore.Key, error)
+	}
+
+	// accessTokenClaims represents the access token claims
+	accessTokenClaims struct {
+		jwt.RegisteredClaims
+		ClientID             string                `json:"client_id,omitempty"`
+		Scope                string                `json:"scope,omitempty"`
+		AuthTime             int64                 `json:"auth_time,omitempty"`
+		ACR                  string                `json:"acr,omitempty"`
+		AMR                  []string              `json:"amr,omitempty"`
+		Act                  *ActorClaim           `json:"act,omitempty"`
+		Cnf                  *ConfirmationClaim    `json:"cnf,omitempty"`
+		AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+	}
+
+	// ConfirmationClaim binds the access token to the TLS client certificate
+	// or to the DPoP proof key,
+	// see: https://www.rfc-editor.org/rfc/rfc8705#section-3.1
+	// and https://www.rfc-editor.org/rfc/rfc9449#section-6.1
+	ConfirmationClaim struct {
+		X5tS256 string `json:"x5t#S256,omitempty"`
+		JKT     string `json:"jkt,omitempty"`
+	}
+)
+
+// WithAccessTokenIssuer sets the iss claim of the access tokens.
+func WithAccessTokenIssuer(issuer string) accessGenerateOption {
+	return func(a *JWTAccessGenerate) {
+		a.issuer = issuer
+	}
+}
+
+// WithAccessTokenAudience sets the default aud claim of the access tokens,
+// e.g. the identifier of the API served with the authorization server.
+// The tokens are issued for the client if it isn't set.
+// The tokens requested for the resource servers are aimed at them instead.
+func WithAccessTokenAudience(audience string) accessGenerateOption {
+	return func(a *JWTAccessGenerate) {
+		a.audience = audience
+	}
+}
+
+// NewJWTAccessGenerate creates a new JWT access token generator instance.
+func NewJWTAccessGenerate(keys signingKeyProvider, opts ...accessGenerateOption) *JWTAccessGenerate {
+	a := &JWTAccessGenerate{keys: keys}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Token generates the access token and the refresh token if isGenRefresh is true.
+func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic, isGenRefresh bool) (string, string, error) {
+	key, err := a.keys.SigningKey(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	createdAt := data.TokenInfo.GetAccessCreateAt()
+	claims := accessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    a.issuer,
+			Audience:  jwt.ClaimStrings{data.Client.GetID()},
+			Subject:   data.UserID,
+			ExpiresAt: jwt.NewNumericDate(createdAt.Add(data.TokenInfo.GetAccessExpiresIn())),
+			IssuedAt:  jwt.NewNumericDate(createdAt),
+			ID:        uuid.NewString(),
+		},
+		ClientID: data.Client.GetID(),
+		Scope:    data.TokenInfo.GetScope(),
+	}
+	if a.audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.audience}
+	}
+	// the token issued to the client on its own behalf has the client as the subject
+	if claims.Subject == "" {
+		claims.Subject = data.Client.GetID()
+	}
+	// the refreshed token keeps the user authentication of the original authorization
+	if t, ok := data.TokenInfo.(*Token); ok {
+		if t.AuthTime != nil {
+			claims.AuthTime = t.AuthTime.Unix()
+		}
+		claims.ACR, claims.AMR = t.ACR, t.AMR
+	}
+	// the token is aimed at the requested resources and the exchanged token
+	// at the requested audience on behalf of the actor,
+	// the token requested over mutual TLS or with the DPoP proof is bound
+	// to the client certificate or the proof key
+	if meta, ok := TokenMetaFromContext(ctx); ok {
+		if len(meta.Audience) > 0 {
+			claims.Audience = meta.Audience
+		}
+		claims.Act = meta.Act
+		claims.AuthorizationDetails = meta.AuthorizationDetails
+		if !meta.AuthTime.IsZero() {
+			claims.AuthTime = meta.AuthTime.Unix()
+		}
+		if meta.ACR != "" || len(meta.AMR) > 0 {
+			claims.ACR, claims.AMR = meta.ACR, meta.AMR
+		}
+		if meta.CertThumbprint != "" || meta.DPoPJKT != "" {
+			claims.Cnf = &ConfirmationClaim{X5tS256: meta.CertThumbprint, JKT: meta.DPoPJKT}
+		}
+	}
+
+	access, err := key.SignWithType(claims, AccessTokenJWTType)
+	if err != nil {
+		return "", "", err
+	}
+
+	var refresh string
+	if isGenRefresh {
+		t := uuid.NewSHA1(uuid.Must(uuid.NewRandom()), []byte(access)).String()
+		refresh = base64.URLEncoding.EncodeToString([]byte(t))
+		refresh = strings.ToUpper(strings.TrimRight(refresh, "="))
+	}
+
+	return access, refresh, nil
+}
+
+// claimsFromToken returns the claims of the access token issued by this server.
+// The token has been already found in the storage, so the signature isn't verified.
+func claimsFromToken(access string) *accessTokenClaims {
+	claims := &accessTokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(access, claims); err != nil {
+		return nil
+	}
+	return claims
+}
@@ -0,0 +1,182 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+// authorizationDetailsParam is the request parameter and the token claim
+// of the rich authorization requests, see: https://www.rfc-editor.org/rfc/rfc9396
+const authorizationDetailsParam = "authorization_details"
+
+type (
+	// AuthorizationDetail is an entry of the authorization_details parameter.
+	// The type field is required, the other fields are defined by the type,
+	// see: https://www.rfc-editor.org/rfc/rfc9396#section-2
+	AuthorizationDetail map[string]interface{}
+
+	// AuthorizationDetailsValidator validates the authorization details of a type,
+	// e.g. the payment initiation: the amount, the currency and the creditor account.
+	AuthorizationDetailsValidator interface {
+		// Type returns the authorization details type.
+		Type() string
+		// Validate checks the authorization detail requested by the client.
+		Validate(ctx context.Context, clientID string, detail AuthorizationDetail) error
+	}
+)
+
+// Type returns the authorization details type
+func (d AuthorizationDetail) Type() string {
+	t, _ := d["type"].(string)
+	return t
+}
+
+// RegisterAuthorizationDetailsType enables the authorization details of the validator type.
+// The authorization details of unknown types are rejected.
+func (s *Server) RegisterAuthorizationDetailsType(v AuthorizationDetailsValidator) {
+	if s.detailsTypes == nil {
+		s.detailsTypes = make(map[string]AuthorizationDetailsValidator)
+	}
+	s.detailsTypes[v.Type()] = v
+}
+
+// AuthorizationDetailsTypes returns the sorted list of the supported authorization details types.
+func (s *Server) AuthorizationDetailsTypes() []string {
+	types := make([]string, 0, len(s.detailsTypes))
+	for t := range s.detailsTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// validateAuthorizationDetails parses the authorization_details parameter
+// and validates each detail with the validator of its type.
+func (s *Server) validateAuthorizationDetails(ctx context.Context, clientID, param string) ([]AuthorizationDetail, error) {
+	if param == "" {
+		return nil, nil
+	}
+
+	details, err := parseAuthorizationDetails(param)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range details {
+		v, ok := s.detailsTypes[d.Type()]
+		if !ok {
+			return nil, ErrInvalidAuthorizationDetails
+		}
+		if err := v.Validate(ctx, clientID, d); err != nil {
+			return nil, ErrInvalidAuthorizationDetails
+		}
+	}
+
+	return details, nil
+}
+
+// resolveTokenAuthorizationDetails passes the authorization details of the token request
+// of the authenticated client to the token generator. The ones requested with the authorization code
+// or the refresh token are checked by the token store against the granted ones, see grantAuthorizationDetails.
+func (s *Server) resolveTokenAuthorizationDetails(r *http.Request, gt oauth2.GrantType, clientID string) error {
+	param := r.FormValue(authorizationDetailsParam)
+	if param == "" {
+		return nil
+	}
+
+	var (
+		details []AuthorizationDetail
+		err     error
+	)
+	switch gt {
+	case oauth2.AuthorizationCode, oauth2.Refreshing:
+		details, err = parseAuthorizationDetails(param)
+	default:
+		details, err = s.validateAuthorizationDetails(r.Context(), clientID, param)
+	}
+	if err != nil {
+		return err
+	}
+
+	meta, ok := TokenMetaFromContext(r.Context())
+	if !ok {
+		meta = &TokenMeta{}
+		*r = *r.WithContext(WithTokenMeta(r.Context(), meta))
+	}
+	meta.AuthorizationDetails = details
+	meta.GrantedAuthorizationDetails = details
+
+	return nil
+}
+
+// grantAuthorizationDetails restricts the authorization details of the token issued
+// for the authorization code or the refresh token to the granted ones.
+// The token request may narrow them to some of the granted details, all of them are used otherwise.
+func grantAuthorizationDetails(ctx context.Context, granted string) error {
+	meta, ok := TokenMetaFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var details []AuthorizationDetail
+	if granted != "" {
+		var err error
+		if details, err = parseAuthorizationDetails(granted); err != nil {
+			return fmt.Errorf("failed to parse granted authorization details: %w", err)
+		}
+	}
+
+	for _, requested := range meta.AuthorizationDetails {
+		if !containsAuthorizationDetail(details, requested) {
+			return ErrInvalidAuthorizationDetails
+		}
+	}
+	if len(meta.AuthorizationDetails) == 0 {
+		meta.AuthorizationDetails = details
+	}
+	meta.GrantedAuthorizationDetails = details
+
+	return nil
+}
+
+// parseAuthorizationDetails parses the JSON array of the authorization details
+func parseAuthorizationDetails(param string) ([]AuthorizationDetail, error) {
+	var details []AuthorizationDetail
+	if err := json.Unmarshal([]byte(param), &details); err != nil || len(details) == 0 {
+		return nil, ErrInvalidAuthorizationDetails
+	}
+	for _, d := range details {
+		if d.Type() == "" {
+			return nil, ErrInvalidAuthorizationDetails
+		}
+	}
+	return details, nil
+}
+
+// encodeAuthorizationDetails returns the authorization details as the JSON array
+// to persist with the token, the empty string is returned if there are no details.
+func encodeAuthorizationDetails(details []AuthorizationDetail) (string, error) {
+	if len(details) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(details)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode authorization details: %w", err)
+	}
+	return string(b), nil
+}
+
+// containsAuthorizationDetail checks the detail is one of the list
+func containsAuthorizationDetail(details []AuthorizationDetail, detail AuthorizationDetail) bool {
+	for _, d := range details {
+		if reflect.DeepEqual(d, detail) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+func TestStoreRefreshTokenAuthorizationDetails(t *testing.T) {
+	payment := oauth.AuthorizationDetail{
+		"type":                  "payment_initiation",
+		"instructedAmount":      map[string]interface{}{"currency": "EUR", "amount": "100.00"},
+		"creditorAccount":       map[string]interface{}{"iban": "DE02100100109307118603"},
+		"remittanceInformation": "Invoice 123",
+	}
+	account := oauth.AuthorizationDetail{
+		"type":    "account_information",
+		"actions": []interface{}{"list_accounts", "read_balances"},
+	}
+	granted := []oauth.AuthorizationDetail{payment, account}
+
+	store := oauth.NewStore(&tokenRepoMock{})
+	ctx := oauth.WithTokenMeta(context.Background(), &oauth.TokenMeta{GrantedAuthorizationDetails: granted})
+	if err := store.Create(ctx, &models.Token{ClientID: "client", Access: "access-1", Refresh: "refresh-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		requested   []oauth.AuthorizationDetail
+		wantDetails []oauth.AuthorizationDetail
+		wantErr     error
+	}{
+		{name: "all granted details", wantDetails: granted},
+		{name: "one of granted details", requested: []oauth.AuthorizationDetail{account}, wantDetails: []oauth.AuthorizationDetail{account}},
+		{name: "not granted detail", requested: []oauth.AuthorizationDetail{{"type": "payment_initiation"}}, wantErr: oauth.ErrInvalidAuthorizationDetails},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &oauth.TokenMeta{AuthorizationDetails: tt.requested}
+			ti, err := store.GetByRefresh(oauth.WithTokenMeta(context.Background(), meta), "refresh-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByRefresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(meta.AuthorizationDetails, tt.wantDetails) {
+				t.Errorf("authorization details = %v, want %v", meta.AuthorizationDetails, tt.wantDetails)
+			}
+			if got := ti.(*oauth.Token).AuthorizationDetails; !reflect.DeepEqual(got, granted) {
+				t.Errorf("token authorization details = %v, want %v", got, granted)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/random"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
+)
+
+// CIBAGrantType is the grant type of the client initiated backchannel authentication.
+// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html
+const CIBAGrantType oauth2.GrantType = "urn:openid:params:grant-type:ciba"
+
+// Backchannel token delivery modes, the push mode isn't supported,
+// see: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.5
+const (
+	BackchannelTokenDeliveryModePoll = "poll"
+	BackchannelTokenDeliveryModePing = "ping"
+)
+
+// Backchannel authentication defaults
+const (
+	defaultBackchannelAuthTTL      = 5 * time.Minute
+	defaultBackchannelPollInterval = 5 * time.Second
+
+	// the binding message is displayed on the consumption and the authentication devices,
+	// so it must be short
+	maxBindingMessageLength = 64
+)
+
+// BackchannelTokenDeliveryModes is the list of supported backchannel token delivery modes.
+var BackchannelTokenDeliveryModes = []string{
+	BackchannelTokenDeliveryModePoll,
+	BackchannelTokenDeliveryModePing,
+}
+
+type (
+	// BackchannelAuthGrant implements the client initiated backchannel authentication grant.
+	// The client starts the authentication of the user identified by the login hint
+	// on the backchannel authentication endpoint, the user is asked to approve the request
+	// on another device by the notifier. The client polls the token endpoint
+	// or waits for the ping callback in the ping mode.
+	BackchannelAuthGrant struct {
+		repo        backchannelRepository
+		tokens      tokenGenerator
+		notifier    BackchannelNotifier
+		approvalURI string
+		ttl         time.Duration
+		interval    time.Duration
+		httpClient  *http.Client
+		log         logger
+	}
+
+	backchannelAuthGrantOption func(g *BackchannelAuthGrant)
+
+	backchannelRepository interface {
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+		GetUserByEmail(ctx context.Context, email string) (repository.User, error)
+
+		CreateBackchannelAuthRequest(ctx context.Context, arg repository.CreateBackchannelAuthRequestParams) (repository.BackchannelAuthRequest, error)
+		GetBackchannelAuthRequest(ctx context.Context, authReqID string) (repository.BackchannelAuthRequest, error)
+		UpdateBackchannelAuthRequestPolling(ctx context.Context, arg repository.UpdateBackchannelAuthRequestPollingParams) error
+		DeleteBackchannelAuthRequest(ctx context.Context, authReqID string) (int64, error)
+	}
+
+	// BackchannelNotifier asks the user to approve the authentication request of the client,
+	// e.g. with an email or a push notification to the user device.
+	BackchannelNotifier interface {
+		NotifyUser(ctx context.Context, req BackchannelUserNotification) error
+	}
+
+	// BackchannelUserNotification is the authentication request the user is asked to approve.
+	// The user approves or denies it on the page of the approval URI.
+	BackchannelUserNotification struct {
+		UserID         uuid.UUID
+		Email          string
+		ClientID       string
+		ClientName     string
+		Scope          string
+		BindingMessage string
+		ApprovalURI    string
+		ExpiresAt      time.Time
+	}
+
+	// BackchannelAuthRequest represents the parameters of the backchannel authentication request.
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.7.1
+	BackchannelAuthRequest struct {
+		Scope                   string
+		LoginHint               string
+		BindingMessage          string
+		ClientNotificationToken string
+		RequestedExpiry         time.Duration
+	}
+
+	// BackchannelAuthResponse represents the backchannel authentication response.
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.7.3
+	BackchannelAuthResponse struct {
+		AuthReqID string `json:"auth_req_id"`
+		ExpiresIn int64  `json:"expires_in"`
+		Interval  int64  `json:"interval,omitempty"`
+	}
+
+	// backchannelAuthorizer starts the backchannel authentication
+	backchannelAuthorizer interface {
+		Authorize(ctx context.Context, client oauth2.ClientInfo, req BackchannelAuthRequest) (*BackchannelAuthResponse, error)
+	}
+
+	// backchannelMailer sends the approval link to the user,
+	// it's implemented by mailer.Enqueuer
+	backchannelMailer interface {
+		SendBackchannelAuthEmail(ctx context.Context, uid uuid.UUID, email, clientName, bindingMessage, approvalURL string) error
+	}
+
+	// MailNotifier sends the approval link of the backchannel authentication request
+	// to the user email.
+	MailNotifier struct {
+		mail backchannelMailer
+	}
+)
+
+// WithBackchannelAuthTTL sets the maximal lifetime of the backchannel authentication request,
+// the client may request a shorter one with the requested_expiry parameter.
+func WithBackchannelAuthTTL(ttl time.Duration) backchannelAuthGrantOption {
+	return func(g *BackchannelAuthGrant) {
+		g.ttl = ttl
+	}
+}
+
+// WithBackchannelPollInterval sets the minimal interval between token requests
+func WithBackchannelPollInterval(interval time.Duration) backchannelAuthGrantOption {
+	return func(g *BackchannelAuthGrant) {
+		g.interval = interval
+	}
+}
+
+// WithBackchannelHTTPClient sets the HTTP client of the ping callbacks
+func WithBackchannelHTTPClient(c *http.Client) backchannelAuthGrantOption {
+	return func(g *BackchannelAuthGrant) {
+		if c != nil {
+			g.httpClient = c
+		}
+	}
+}
+
+// WithBackchannelLogger sets the logger to report the failed ping callbacks.
+func WithBackchannelLogger(log logger) backchannelAuthGrantOption {
+	return func(g *BackchannelAuthGrant) {
+		g.log = log
+	}
+}
+
+// NewBackchannelAuthGrant creates a new backchannel authentication grant handler.
+// The approvalURI is the page where the user approves or denies the request,
+// the approval code is passed in the code query parameter.
+func NewBackchannelAuthGrant(repo backchannelRepository, tokens tokenGenerator, notifier BackchannelNotifier, approvalURI string, opts ...backchannelAuthGrantOption) *BackchannelAuthGrant {
+	g := &BackchannelAuthGrant{
+		repo:        repo,
+		tokens:      tokens,
+		notifier:    notifier,
+		approvalURI: approvalURI,
+		ttl:         defaultBackchannelAuthTTL,
+		interval:    defaultBackchannelPollInterval,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// GrantType returns the CIBA grant type.
+func (g *BackchannelAuthGrant) GrantType() oauth2.GrantType {
+	return CIBAGrantType
+}
+
+// Authorize starts the authentication of the user identified by the login hint
+// and notifies the user to approve the request.
+func (g *BackchannelAuthGrant) Authorize(ctx context.Context, client oauth2.ClientInfo, req BackchannelAuthRequest) (*BackchannelAuthResponse, error) {
+	c, err := g.repo.GetClientByID(ctx, client.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client by id: %w", err)
+	}
+	if c.BackchannelTokenDeliveryMode == BackchannelTokenDeliveryModePing && req.ClientNotificationToken == "" {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+
+	user, err := g.repo.GetUserByEmail(ctx, req.LoginHint)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownUserID
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	ttl := g.ttl
+	if req.RequestedExpiry > 0 && req.RequestedExpiry < ttl {
+		ttl = req.RequestedExpiry
+	}
+
+	br, err := g.repo.CreateBackchannelAuthRequest(ctx, repository.CreateBackchannelAuthRequestParams{
+		AuthReqID:               random.String(40),
+		ApprovalCode:            random.String(40),
+		ClientID:                c.ID,
+		UserID:                  user.ID,
+		Scope:                   req.Scope,
+		BindingMessage:          req.BindingMessage,
+		ClientNotificationToken: req.ClientNotificationToken,
+		PollInterval:            int64(g.interval.Seconds()),
+		ExpiresAt:               time.Now().Add(ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backchannel auth request: %w", err)
+	}
+
+	clientName := c.Name
+	if clientName == "" {
+		clientName = c.Domain
+	}
+	if err := g.notifier.NotifyUser(ctx, BackchannelUserNotification{
+		UserID:         user.ID,
+		Email:          user.Email,
+		ClientID:       c.ID,
+		ClientName:     clientName,
+		Scope:          br.Scope,
+		BindingMessage: br.BindingMessage,
+		ApprovalURI:    g.approvalURI + "?" + url.Values{"code": {br.ApprovalCode}}.Encode(),
+		ExpiresAt:      br.ExpiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to notify user: %w", err)
+	}
+
+	return &BackchannelAuthResponse{
+		AuthReqID: br.AuthReqID,
+		ExpiresIn: int64(ttl.Seconds()),
+		Interval:  br.PollInterval,
+	}, nil
+}
+
+// Token checks the backchannel authentication request state and issues the token
+// once the user approved the request.
+func (g *BackchannelAuthGrant) Token(ctx context.Context, client oauth2.ClientInfo, tgr *oauth2.TokenGenerateRequest, r *http.Request) (oauth2.TokenInfo, error) {
+	authReqID := r.FormValue("auth_req_id")
+	if authReqID == "" {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+
+	br, err := g.repo.GetBackchannelAuthRequest(ctx, authReqID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthErrors.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("failed to get backchannel auth request: %w", err)
+	}
+	if br.ClientID != client.GetID() {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	if err := poll(ctx, pollRequest{
+		name:         "backchannel auth request",
+		approved:     br.Status == repository.BackchannelAuthStatusApproved,
+		denied:       br.Status == repository.BackchannelAuthStatusDenied,
+		expiresAt:    br.ExpiresAt,
+		pollInterval: br.PollInterval,
+		lastPolledAt: br.LastPolledAt,
+		delete: func(ctx context.Context) (int64, error) {
+			return g.repo.DeleteBackchannelAuthRequest(ctx, br.AuthReqID)
+		},
+		updatePolling: func(ctx context.Context, interval int64) error {
+			return g.repo.UpdateBackchannelAuthRequestPolling(ctx, repository.UpdateBackchannelAuthRequestPollingParams{
+				PollInterval: interval,
+				AuthReqID:    br.AuthReqID,
+			})
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	tgr.UserID = br.UserID.String()
+	tgr.Scope = br.Scope
+	return issueToken(ctx, g.tokens, tgr)
+}
+
+// NotifyClient sends the ping callback to the client notification endpoint
+// once the user approved or denied the request, so the ping mode client requests the token.
+// The poll mode clients aren't notified.
+// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.10.2
+func (g *BackchannelAuthGrant) NotifyClient(ctx context.Context, br repository.BackchannelAuthRequest) error {
+	c, err := g.repo.GetClientByID(ctx, br.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client by id: %w", err)
+	}
+	if c.BackchannelTokenDeliveryMode != BackchannelTokenDeliveryModePing || c.BackchannelClientNotificationEndpoint == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"auth_req_id": br.AuthReqID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ping callback: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BackchannelClientNotificationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create ping callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+br.ClientNotificationToken)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return g.pingError(c.ID, fmt.Errorf("failed to send ping callback: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return g.pingError(c.ID, fmt.Errorf("ping callback failed with status %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// pingError reports the failed ping callback, the client can still poll the token endpoint,
+// so the error is returned only if there is no logger.
+func (g *BackchannelAuthGrant) pingError(clientID string, err error) error {
+	if g.log == nil {
+		return err
+	}
+	g.log.Warnf("backchannel ping callback: client_id=%s: %v", clientID, err)
+	return nil
+}
+
+// NewMailNotifier creates the notifier which emails the approval link to the user.
+func NewMailNotifier(mail backchannelMailer) *MailNotifier {
+	return &MailNotifier{mail: mail}
+}
+
+// NotifyUser sends the approval link of the backchannel authentication request to the user email.
+func (n *MailNotifier) NotifyUser(ctx context.Context, req BackchannelUserNotification) error {
+	return n.mail.SendBackchannelAuthEmail(ctx, req.UserID, req.Email, req.ClientName, req.BindingMessage, req.ApprovalURI)
+}
+
+// HandleBackchannelAuthenticationRequest handles the backchannel authentication request
+// of the confidential client. The user is identified by the email in the login_hint parameter.
+// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.7
+func (s *Server) HandleBackchannelAuthenticationRequest(w http.ResponseWriter, r *http.Request) error {
+	ba, ok := s.grants[CIBAGrantType].(backchannelAuthorizer)
+	if !ok {
+		return s.tokenError(w, oauthErrors.ErrUnsupportedGrantType)
+	}
+
+	client, tgr, err := s.authenticateClient(r, CIBAGrantType)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+	if c, ok := client.(interface{ IsPublic() bool }); ok && c.IsPublic() {
+		return s.tokenError(w, oauthErrors.ErrUnauthorizedClient)
+	}
+
+	// the backchannel authentication is the OpenID Connect flow
+	if !MatchScope(ScopeOpenID, tgr.Scope) {
+		return s.tokenError(w, oauthErrors.ErrInvalidScope)
+	}
+	if fn := s.ClientScopeHandler; fn != nil {
+		allowed, err := fn(tgr)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		if !allowed {
+			return s.tokenError(w, oauthErrors.ErrInvalidScope)
+		}
+	}
+
+	// only the login hint is supported to identify the user
+	req := BackchannelAuthRequest{
+		Scope:                   tgr.Scope,
+		LoginHint:               r.PostForm.Get("login_hint"),
+		BindingMessage:          r.PostForm.Get("binding_message"),
+		ClientNotificationToken: r.PostForm.Get("client_notification_token"),
+	}
+	if req.LoginHint == "" {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(req.BindingMessage) > maxBindingMessageLength {
+		return s.tokenError(w, ErrInvalidBindingMessage)
+	}
+	if expiry := r.PostForm.Get("requested_expiry"); expiry != "" {
+		seconds, err := strconv.ParseInt(expiry, 10, 64)
+		if err != nil || seconds <= 0 {
+			return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+		}
+		req.RequestedExpiry = time.Duration(seconds) * time.Second
+	}
+
+	resp, err := ba.Authorize(r.Context(), client, req)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	return s.token(w, resp, http.StatusOK)
+}
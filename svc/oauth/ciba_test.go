@@ -0,0 +1,239 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/google/uuid"
+)
+
+type backchannelRepoMock struct {
+	clients  map[string]repository.Client
+	users    map[string]repository.User
+	requests map[string]repository.BackchannelAuthRequest
+	// redeemed simulates a concurrent token request which redeems the request right after it's read
+	redeemed bool
+}
+
+func (m *backchannelRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	if c, ok := m.clients[id]; ok {
+		return c, nil
+	}
+	return repository.Client{}, sql.ErrNoRows
+}
+
+func (m *backchannelRepoMock) GetUserByEmail(ctx context.Context, email string) (repository.User, error) {
+	if u, ok := m.users[email]; ok {
+		return u, nil
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
+func (m *backchannelRepoMock) CreateBackchannelAuthRequest(ctx context.Context, arg repository.CreateBackchannelAuthRequestParams) (repository.BackchannelAuthRequest, error) {
+	br := repository.BackchannelAuthRequest{
+		AuthReqID:               arg.AuthReqID,
+		ApprovalCode:            arg.ApprovalCode,
+		ClientID:                arg.ClientID,
+		UserID:                  arg.UserID,
+		Scope:                   arg.Scope,
+		BindingMessage:          arg.BindingMessage,
+		ClientNotificationToken: arg.ClientNotificationToken,
+		Status:                  repository.BackchannelAuthStatusPending,
+		PollInterval:            arg.PollInterval,
+		ExpiresAt:               arg.ExpiresAt,
+	}
+	m.requests[br.AuthReqID] = br
+	return br, nil
+}
+
+func (m *backchannelRepoMock) GetBackchannelAuthRequest(ctx context.Context, authReqID string) (repository.BackchannelAuthRequest, error) {
+	br, ok := m.requests[authReqID]
+	if !ok {
+		return repository.BackchannelAuthRequest{}, sql.ErrNoRows
+	}
+	if m.redeemed {
+		delete(m.requests, authReqID)
+	}
+	return br, nil
+}
+
+func (m *backchannelRepoMock) UpdateBackchannelAuthRequestPolling(ctx context.Context, arg repository.UpdateBackchannelAuthRequestPollingParams) error {
+	br := m.requests[arg.AuthReqID]
+	br.PollInterval = arg.PollInterval
+	br.LastPolledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	m.requests[arg.AuthReqID] = br
+	return nil
+}
+
+func (m *backchannelRepoMock) DeleteBackchannelAuthRequest(ctx context.Context, authReqID string) (int64, error) {
+	if _, ok := m.requests[authReqID]; !ok {
+		return 0, nil
+	}
+	delete(m.requests, authReqID)
+	return 1, nil
+}
+
+type backchannelNotifierMock struct {
+	notifications []oauth.BackchannelUserNotification
+}
+
+func (m *backchannelNotifierMock) NotifyUser(ctx context.Context, req oauth.BackchannelUserNotification) error {
+	m.notifications = append(m.notifications, req)
+	return nil
+}
+
+func newBackchannelRepo(userID uuid.UUID) *backchannelRepoMock {
+	return &backchannelRepoMock{
+		clients: map[string]repository.Client{
+			"poll-client": {ID: "poll-client", Name: "Call center", BackchannelTokenDeliveryMode: oauth.BackchannelTokenDeliveryModePoll},
+			"ping-client": {ID: "ping-client", Name: "Call center", BackchannelTokenDeliveryMode: oauth.BackchannelTokenDeliveryModePing},
+		},
+		users: map[string]repository.User{
+			"user@example.com": {ID: userID, Email: "user@example.com"},
+		},
+		requests: map[string]repository.BackchannelAuthRequest{},
+	}
+}
+
+func TestBackchannelAuthGrant_Authorize(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		clientID string
+		req      oauth.BackchannelAuthRequest
+		wantErr  error
+		wantTTL  int64
+	}{
+		{name: "poll client", clientID: "poll-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "user@example.com", BindingMessage: "W4SCT"}, wantTTL: 300},
+		{name: "requested expiry", clientID: "poll-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "user@example.com", RequestedExpiry: time.Minute}, wantTTL: 60},
+		{name: "requested expiry above the limit", clientID: "poll-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "user@example.com", RequestedExpiry: time.Hour}, wantTTL: 300},
+		{name: "ping client", clientID: "ping-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "user@example.com", ClientNotificationToken: "token"}, wantTTL: 300},
+		{name: "ping client without notification token", clientID: "ping-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "user@example.com"}, wantErr: oauth2Errors.ErrInvalidRequest},
+		{name: "unknown user", clientID: "poll-client", req: oauth.BackchannelAuthRequest{Scope: "openid", LoginHint: "unknown@example.com"}, wantErr: oauth.ErrUnknownUserID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newBackchannelRepo(userID)
+			notifier := &backchannelNotifierMock{}
+			g := oauth.NewBackchannelAuthGrant(repo, tokenGeneratorMock{}, notifier, "https://example.com/auth/backchannel")
+
+			resp, err := g.Authorize(context.Background(), &models.Client{ID: tt.clientID}, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(notifier.notifications) != 0 {
+					t.Errorf("Authorize() notified the user of the rejected request")
+				}
+				return
+			}
+
+			if resp.ExpiresIn != tt.wantTTL || resp.Interval != 5 {
+				t.Errorf("Authorize() expires_in = %d, interval = %d, want %d, 5", resp.ExpiresIn, resp.Interval, tt.wantTTL)
+			}
+			if len(notifier.notifications) != 1 {
+				t.Fatalf("Authorize() sent %d notifications, want 1", len(notifier.notifications))
+			}
+
+			n := notifier.notifications[0]
+			br := repo.requests[resp.AuthReqID]
+			if n.UserID != userID || n.ClientName != "Call center" || n.BindingMessage != tt.req.BindingMessage {
+				t.Errorf("Authorize() notification = %+v", n)
+			}
+			if n.ApprovalURI != "https://example.com/auth/backchannel?code="+url.QueryEscape(br.ApprovalCode) {
+				t.Errorf("Authorize() approval uri = %s", n.ApprovalURI)
+			}
+		})
+	}
+}
+
+func TestBackchannelAuthGrant_Token(t *testing.T) {
+	userID := uuid.New()
+	pending := repository.BackchannelAuthRequest{
+		AuthReqID:    "req",
+		ClientID:     "poll-client",
+		UserID:       userID,
+		Scope:        "openid email",
+		Status:       repository.BackchannelAuthStatusPending,
+		PollInterval: 5,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	with := func(fn func(br *repository.BackchannelAuthRequest)) repository.BackchannelAuthRequest {
+		br := pending
+		fn(&br)
+		return br
+	}
+
+	tests := []struct {
+		name         string
+		clientID     string
+		request      repository.BackchannelAuthRequest
+		redeemed     bool
+		wantErr      error
+		wantLeft     bool
+		wantInterval int64
+	}{
+		{name: "pending", clientID: "poll-client", request: pending, wantErr: oauth.ErrAuthorizationPending, wantLeft: true, wantInterval: 5},
+		{name: "polling too fast", clientID: "poll-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.LastPolledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}), wantErr: oauth.ErrSlowDown, wantLeft: true, wantInterval: 10},
+		{name: "approved", clientID: "poll-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.Status = repository.BackchannelAuthStatusApproved
+		})},
+		{name: "already redeemed", clientID: "poll-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.Status = repository.BackchannelAuthStatusApproved
+		}), redeemed: true, wantErr: oauth2Errors.ErrInvalidGrant},
+		{name: "denied", clientID: "poll-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.Status = repository.BackchannelAuthStatusDenied
+		}), wantErr: oauth2Errors.ErrAccessDenied},
+		{name: "expired", clientID: "poll-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.Status = repository.BackchannelAuthStatusApproved
+			br.ExpiresAt = time.Now().Add(-time.Second)
+		}), wantErr: oauth.ErrExpiredToken},
+		{name: "another client", clientID: "ping-client", request: with(func(br *repository.BackchannelAuthRequest) {
+			br.Status = repository.BackchannelAuthStatusApproved
+		}), wantErr: oauth2Errors.ErrInvalidGrant, wantLeft: true, wantInterval: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newBackchannelRepo(userID)
+			repo.requests[tt.request.AuthReqID] = tt.request
+			repo.redeemed = tt.redeemed
+			g := oauth.NewBackchannelAuthGrant(repo, tokenGeneratorMock{}, &backchannelNotifierMock{}, "https://example.com/auth/backchannel")
+
+			r := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(url.Values{
+				"grant_type":  {string(oauth.CIBAGrantType)},
+				"auth_req_id": {tt.request.AuthReqID},
+			}.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			ti, err := g.Token(context.Background(), &models.Client{ID: tt.clientID}, &oauth2.TokenGenerateRequest{ClientID: tt.clientID}, r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Token() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (ti.GetUserID() != userID.String() || ti.GetScope() != "openid email") {
+				t.Errorf("Token() user = %s, scope = %s", ti.GetUserID(), ti.GetScope())
+			}
+
+			br, left := repo.requests[tt.request.AuthReqID]
+			if left != tt.wantLeft {
+				t.Fatalf("Token() request left = %v, want %v", left, tt.wantLeft)
+			}
+			if left && br.PollInterval != tt.wantInterval {
+				t.Errorf("Token() poll interval = %d, want %d", br.PollInterval, tt.wantInterval)
+			}
+		})
+	}
+}
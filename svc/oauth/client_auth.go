@@ -0,0 +1,455 @@
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Token endpoint authentication methods,
+// see: https://www.rfc-editor.org/rfc/rfc7591#section-2
+const (
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretJWT   = "client_secret_jwt"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+
+	// Mutual-TLS client authentication methods,
+	// see: https://www.rfc-editor.org/rfc/rfc8705#section-2
+	AuthMethodTLSClientAuth           = "tls_client_auth"
+	AuthMethodSelfSignedTLSClientAuth = "self_signed_tls_client_auth"
+)
+
+// DefaultClientAuthMethod is the token endpoint authentication method
+// of the confidential client which omits it on registration, see: https://www.rfc-editor.org/rfc/rfc7591#section-2
+const DefaultClientAuthMethod = AuthMethodClientSecretBasic
+
+// ClientAssertionType is the client assertion type of the JWT client authentication.
+// See: https://www.rfc-editor.org/rfc/rfc7523#section-2.2
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientAuthMethods is the list of supported token endpoint authentication methods.
+var ClientAuthMethods = []string{
+	AuthMethodClientSecretBasic,
+	AuthMethodClientSecretPost,
+	AuthMethodClientSecretJWT,
+	AuthMethodPrivateKeyJWT,
+	AuthMethodTLSClientAuth,
+	AuthMethodSelfSignedTLSClientAuth,
+	AuthMethodNone,
+}
+
+// ClientAssertionSigningAlgs is the list of supported client assertion signing algorithms.
+var ClientAssertionSigningAlgs = append(append([]string{}, privateKeyJWTAlgs...), clientSecretJWTAlgs...)
+
+// maxRemoteSets is the number of the cached client key sets fetched from the jwks_uri
+const maxRemoteSets = 1000
+
+var (
+	privateKeyJWTAlgs   = []string{"RS256", "PS256", "ES256", "EdDSA"}
+	clientSecretJWTAlgs = []string{"HS256", "HS384", "HS512"}
+)
+
+type (
+	// ClientAuthenticator authenticates the client with the method registered for the client:
+	// client secret in the basic auth header or in the form, the JWT client assertion
+	// signed with the client secret or with the client private key,
+	// or the TLS client certificate.
+	// Implements the server.ClientInfoHandler.
+	ClientAuthenticator struct {
+		repo      clientAuthRepository
+		replay    replayCache
+		clientCAs *x509.CertPool
+		secret    []byte
+		audience  []string
+		jwksHTTP  *http.Client
+
+		mu         sync.Mutex
+		remoteSets map[string]*remoteSetEntry
+	}
+
+	// remoteSetEntry is the cached key set of the client jwks_uri
+	remoteSetEntry struct {
+		uri    string
+		set    *jwk.RemoteSet
+		usedAt time.Time
+	}
+
+	// ClientAuthenticatorOption is a function that configures a ClientAuthenticator.
+	ClientAuthenticatorOption func(a *ClientAuthenticator)
+
+	clientAuthRepository interface {
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+	}
+
+	// replayCache remembers the used JWT IDs until they expire
+	replayCache interface {
+		Use(ctx context.Context, key string, exp time.Time) (bool, error)
+	}
+)
+
+// WithReplayCache sets the storage of the used client assertion IDs.
+// The client assertions are rejected without it, since the replay can't be detected.
+func WithReplayCache(c replayCache) ClientAuthenticatorOption {
+	return func(a *ClientAuthenticator) {
+		a.replay = c
+	}
+}
+
+// WithClientCAs sets the certificate authorities which issue the client certificates
+// of the tls_client_auth clients. The tls_client_auth clients are rejected without it.
+func WithClientCAs(pool *x509.CertPool) ClientAuthenticatorOption {
+	return func(a *ClientAuthenticator) {
+		a.clientCAs = pool
+	}
+}
+
+// WithJWKSHTTPClient sets the HTTP client which fetches the client key sets from the jwks_uri.
+func WithJWKSHTTPClient(httpClient *http.Client) ClientAuthenticatorOption {
+	return func(a *ClientAuthenticator) {
+		a.jwksHTTP = httpClient
+	}
+}
+
+// NewClientAuthenticator creates a new client authenticator instance.
+// The secret is used to decrypt the client secrets of the client_secret_jwt clients.
+// The audience is the list of accepted client assertion audiences,
+// e.g. the token endpoint URL and the issuer identifier.
+func NewClientAuthenticator(repo clientAuthRepository, secret string, audience []string, opts ...ClientAuthenticatorOption) *ClientAuthenticator {
+	a := &ClientAuthenticator{
+		repo:       repo,
+		secret:     []byte(secret),
+		audience:   audience,
+		remoteSets: make(map[string]*remoteSetEntry),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// ClientInfoHandler returns the client credentials from the request
+// and checks the client uses the registered authentication method.
+// The client authenticated with the client assertion or the TLS client certificate
+// is marked in the request context, see ClientAuth, and has the empty secret.
+func (a *ClientAuthenticator) ClientInfoHandler(r *http.Request) (string, string, error) {
+	method, clientID, secret, err := clientCredentialsFromRequest(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	client, err := a.repo.GetClientByID(r.Context(), clientID)
+	if err != nil {
+		return "", "", oauthErrors.ErrInvalidClient
+	}
+
+	registered := client.TokenEndpointAuthMethod
+	if registered == "" {
+		registered = DefaultClientAuthMethod
+	}
+
+	switch {
+	case isAssertionAuthMethod(method):
+		if !isAssertionAuthMethod(registered) {
+			return "", "", oauthErrors.ErrInvalidClient
+		}
+		if err := a.verifyAssertion(r.Context(), client, secret); err != nil {
+			return "", "", err
+		}
+		return a.authenticated(r.Context(), client.ID, registered)
+	case isTLSAuthMethod(registered):
+		// the client_id parameter identifies the client, the certificate authenticates it
+		if method != AuthMethodNone {
+			return "", "", oauthErrors.ErrInvalidClient
+		}
+		if err := a.verifyCertificate(r.Context(), client, r.TLS); err != nil {
+			return "", "", err
+		}
+		return a.authenticated(r.Context(), client.ID, registered)
+	case method != registered:
+		return "", "", oauthErrors.ErrInvalidClient
+	case method == AuthMethodNone && !client.IsPublic:
+		return "", "", oauthErrors.ErrInvalidClient
+	}
+
+	return client.ID, secret, nil
+}
+
+// authenticated marks the client authenticated without the secret in the request context
+func (a *ClientAuthenticator) authenticated(ctx context.Context, clientID, method string) (string, string, error) {
+	auth, ok := ClientAuthFromContext(ctx)
+	if !ok {
+		return "", "", oauthErrors.ErrInvalidClient
+	}
+	auth.ClientID, auth.Method = clientID, method
+
+	return clientID, "", nil
+}
+
+// verifyAssertion verifies the client assertion as described in RFC 7523, section 3.
+func (a *ClientAuthenticator) verifyAssertion(ctx context.Context, client repository.Client, assertion string) error {
+	var methods []string
+	var keyFunc jwt.Keyfunc
+	switch client.TokenEndpointAuthMethod {
+	case AuthMethodClientSecretJWT:
+		secret, err := utils.Decrypt(client.EncryptedSecret, a.secret)
+		if err != nil {
+			return oauthErrors.ErrInvalidClient
+		}
+		methods = clientSecretJWTAlgs
+		keyFunc = func(*jwt.Token) (interface{}, error) {
+			return secret, nil
+		}
+	case AuthMethodPrivateKeyJWT:
+		methods = privateKeyJWTAlgs
+		keyFunc = func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			return a.publicKey(ctx, client, kid)
+		}
+	default:
+		return oauthErrors.ErrInvalidClient
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	if _, err := jwt.ParseWithClaims(assertion, claims, keyFunc,
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(client.ID),
+		jwt.WithSubject(client.ID),
+	); err != nil {
+		return oauthErrors.ErrInvalidClient
+	}
+	if claims.ExpiresAt == nil || claims.ID == "" || !hasAudience(claims.Audience, a.audience) {
+		return oauthErrors.ErrInvalidClient
+	}
+
+	if a.replay == nil {
+		return oauthErrors.ErrInvalidClient
+	}
+	ok, err := a.replay.Use(ctx, client.ID+":"+claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return fmt.Errorf("failed to check client assertion replay: %w", err)
+	}
+	if !ok {
+		return oauthErrors.ErrInvalidClient
+	}
+
+	return nil
+}
+
+// verifyCertificate verifies the TLS client certificate as described in RFC 8705, section 2:
+// the tls_client_auth certificate is issued by the trusted CA for the registered subject DN,
+// the self_signed_tls_client_auth certificate key is registered in the client key set.
+func (a *ClientAuthenticator) verifyCertificate(ctx context.Context, client repository.Client, state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return oauthErrors.ErrInvalidClient
+	}
+	cert := state.PeerCertificates[0]
+
+	switch client.TokenEndpointAuthMethod {
+	case AuthMethodTLSClientAuth:
+		if a.clientCAs == nil || client.TlsClientAuthSubjectDn == "" {
+			return oauthErrors.ErrInvalidClient
+		}
+		intermediates := x509.NewCertPool()
+		for _, c := range state.PeerCertificates[1:] {
+			intermediates.AddCert(c)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{
+			Roots:         a.clientCAs,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}); err != nil {
+			return oauthErrors.ErrInvalidClient
+		}
+		if cert.Subject.String() != client.TlsClientAuthSubjectDn {
+			return oauthErrors.ErrInvalidClient
+		}
+	case AuthMethodSelfSignedTLSClientAuth:
+		ok, err := a.hasCertificateKey(ctx, client, cert)
+		if err != nil {
+			return fmt.Errorf("failed to get client keys: %w", err)
+		}
+		if !ok {
+			return oauthErrors.ErrInvalidClient
+		}
+	default:
+		return oauthErrors.ErrInvalidClient
+	}
+
+	return nil
+}
+
+// hasCertificateKey checks the certificate public key is in the client key set
+func (a *ClientAuthenticator) hasCertificateKey(ctx context.Context, client repository.Client, cert *x509.Certificate) (bool, error) {
+	certKey, err := jwk.NewKey("", "", cert.PublicKey)
+	if err != nil {
+		return false, nil
+	}
+	thumbprint, err := certKey.Thumbprint()
+	if err != nil {
+		return false, nil
+	}
+
+	var set jwk.Set
+	switch {
+	case client.Jwks != "":
+		if err := json.Unmarshal([]byte(client.Jwks), &set); err != nil {
+			return false, fmt.Errorf("failed to parse jwks: %w", err)
+		}
+	case client.JwksUri != "":
+		if set, err = a.remoteSet(client.ID, client.JwksUri).Keys(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	for _, k := range set.Keys {
+		if t, err := k.Thumbprint(); err == nil && t == thumbprint {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// publicKey returns the client public key from the registered JWKS or JWKS URI.
+func (a *ClientAuthenticator) publicKey(ctx context.Context, client repository.Client, kid string) (interface{}, error) {
+	if client.Jwks != "" {
+		return publicKeyFromJWKS(client.Jwks, kid)
+	}
+
+	if client.JwksUri != "" {
+		return a.remoteSet(client.ID, client.JwksUri).PublicKey(ctx, kid)
+	}
+
+	return nil, jwk.ErrKeyNotFound
+}
+
+// publicKeyFromJWKS returns the public key from the JSON encoded key set.
+// The key id may be omitted if the key set contains only one key.
+func publicKeyFromJWKS(jwks, kid string) (interface{}, error) {
+	var set jwk.Set
+	if err := json.Unmarshal([]byte(jwks), &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+	key, ok := set.Lookup(kid)
+	if !ok && kid == "" && len(set.Keys) == 1 {
+		key, ok = set.Keys[0], true
+	}
+	if !ok {
+		return nil, jwk.ErrKeyNotFound
+	}
+	return key.PublicKey()
+}
+
+// remoteSet returns the cached key set of the client jwks_uri.
+// The key sets are cached per client, so the client can't evict the keys of another client,
+// the key set is replaced if the client changes the jwks_uri
+// and the least recently used key set is evicted if the cache is full.
+func (a *ClientAuthenticator) remoteSet(clientID, uri string) *jwk.RemoteSet {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e, ok := a.remoteSets[clientID]
+	if !ok || e.uri != uri {
+		if !ok && len(a.remoteSets) >= maxRemoteSets {
+			a.evictRemoteSet()
+		}
+		e = &remoteSetEntry{uri: uri, set: jwk.NewRemoteSet(uri, jwk.WithHTTPClient(a.jwksHTTP))}
+		a.remoteSets[clientID] = e
+	}
+	e.usedAt = time.Now()
+
+	return e.set
+}
+
+// evictRemoteSet removes the least recently used key set from the cache.
+func (a *ClientAuthenticator) evictRemoteSet() {
+	var oldest string
+	for id, e := range a.remoteSets {
+		if oldest == "" || e.usedAt.Before(a.remoteSets[oldest].usedAt) {
+			oldest = id
+		}
+	}
+	delete(a.remoteSets, oldest)
+}
+
+// clientCredentialsFromRequest detects the client authentication method used in the request.
+// The client assertion is returned as the secret of the JWT authentication methods.
+func clientCredentialsFromRequest(r *http.Request) (method, clientID, secret string, err error) {
+	if assertionType := r.PostFormValue("client_assertion_type"); assertionType != "" {
+		assertion := r.PostFormValue("client_assertion")
+		if assertionType != ClientAssertionType || assertion == "" {
+			return "", "", "", oauthErrors.ErrInvalidRequest
+		}
+
+		// the client_id parameter is optional, the client is identified by the assertion subject
+		clientID = r.PostFormValue("client_id")
+		claims := &jwt.RegisteredClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(assertion, claims); err != nil {
+			return "", "", "", oauthErrors.ErrInvalidClient
+		}
+		if clientID == "" {
+			clientID = claims.Subject
+		} else if clientID != claims.Subject {
+			return "", "", "", oauthErrors.ErrInvalidClient
+		}
+
+		return AuthMethodClientSecretJWT, clientID, assertion, nil
+	}
+
+	if username, password, ok := r.BasicAuth(); ok {
+		// the credentials are form-urlencoded, see: https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
+		if clientID, err = url.QueryUnescape(username); err != nil {
+			return "", "", "", oauthErrors.ErrInvalidClient
+		}
+		if secret, err = url.QueryUnescape(password); err != nil {
+			return "", "", "", oauthErrors.ErrInvalidClient
+		}
+		return AuthMethodClientSecretBasic, clientID, secret, nil
+	}
+
+	clientID = r.FormValue("client_id")
+	if clientID == "" {
+		return "", "", "", oauthErrors.ErrInvalidClient
+	}
+	if secret = r.FormValue("client_secret"); secret != "" {
+		return AuthMethodClientSecretPost, clientID, secret, nil
+	}
+
+	return AuthMethodNone, clientID, "", nil
+}
+
+// isAssertionAuthMethod checks if the client is authenticated with the client assertion
+func isAssertionAuthMethod(method string) bool {
+	return method == AuthMethodClientSecretJWT || method == AuthMethodPrivateKeyJWT
+}
+
+// isTLSAuthMethod checks if the client is authenticated with the TLS client certificate
+func isTLSAuthMethod(method string) bool {
+	return method == AuthMethodTLSClientAuth || method == AuthMethodSelfSignedTLSClientAuth
+}
+
+// hasAudience checks if the token audience contains one of the accepted values
+func hasAudience(aud jwt.ClaimStrings, accepted []string) bool {
+	for _, a := range aud {
+		if contains(accepted, a) {
+			return true
+		}
+	}
+	return false
+}
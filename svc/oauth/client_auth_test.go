@@ -0,0 +1,375 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/testutil"
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testTokenEndpoint = "https://auth.example.com/oauth/token"
+	testEncryptionKey = "encryption-key"
+)
+
+type clientAuthRepoMock struct {
+	clients map[string]repository.Client
+}
+
+func (m *clientAuthRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	c, ok := m.clients[id]
+	if !ok {
+		return repository.Client{}, sql.ErrNoRows
+	}
+	return c, nil
+}
+
+type replayCacheMock map[string]bool
+
+func (m replayCacheMock) Use(ctx context.Context, key string, exp time.Time) (bool, error) {
+	if m[key] {
+		return false, nil
+	}
+	m[key] = true
+	return true, nil
+}
+
+func TestClientAuthenticator(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.NewKey("key-1", "ES256", pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+
+	encryptedSecret, err := utils.Encrypt([]byte("shared-secret"), []byte(testEncryptionKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &clientAuthRepoMock{clients: map[string]repository.Client{
+		"private-key-client": {ID: "private-key-client", TokenEndpointAuthMethod: oauth.AuthMethodPrivateKeyJWT, Jwks: string(jwks)},
+		"secret-jwt-client":  {ID: "secret-jwt-client", TokenEndpointAuthMethod: oauth.AuthMethodClientSecretJWT, EncryptedSecret: encryptedSecret},
+		"basic-client":       {ID: "basic-client", TokenEndpointAuthMethod: oauth.AuthMethodClientSecretBasic},
+		"post-client":        {ID: "post-client", TokenEndpointAuthMethod: oauth.AuthMethodClientSecretPost},
+	}}
+	a := oauth.NewClientAuthenticator(repo, testEncryptionKey, []string{testTokenEndpoint}, oauth.WithReplayCache(replayCacheMock{}))
+
+	sign := func(method jwt.SigningMethod, key interface{}, clientID, aud, jti string) string {
+		token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+			Issuer:    clientID,
+			Subject:   clientID,
+			Audience:  jwt.ClaimStrings{aud},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        jti,
+		})
+		token.Header["kid"] = "key-1"
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	assertion := func(s string) url.Values {
+		return url.Values{"client_assertion_type": {oauth.ClientAssertionType}, "client_assertion": {s}}
+	}
+
+	privateKeyAssertion := sign(jwt.SigningMethodES256, pk, "private-key-client", testTokenEndpoint, "jti-1")
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		basic      []string
+		wantClient string
+		wantSecret string
+		wantErr    error
+	}{
+		{
+			name:       "private_key_jwt",
+			form:       assertion(privateKeyAssertion),
+			wantClient: "private-key-client",
+		},
+		{
+			name:    "replayed assertion",
+			form:    assertion(privateKeyAssertion),
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+		{
+			name:    "wrong audience",
+			form:    assertion(sign(jwt.SigningMethodES256, pk, "private-key-client", "https://other.example.com", "jti-2")),
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+		{
+			name:       "client_secret_jwt",
+			form:       assertion(sign(jwt.SigningMethodHS256, []byte("shared-secret"), "secret-jwt-client", testTokenEndpoint, "jti-3")),
+			wantClient: "secret-jwt-client",
+		},
+		{
+			name:    "client_secret_jwt signed with another secret",
+			form:    assertion(sign(jwt.SigningMethodHS256, []byte("another-secret"), "secret-jwt-client", testTokenEndpoint, "jti-4")),
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+		{
+			name:    "private_key_jwt client uses the secret",
+			form:    url.Values{"client_id": {"private-key-client"}, "client_secret": {"secret"}},
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+		{
+			name:       "client_secret_basic",
+			basic:      []string{"basic-client", "secret"},
+			wantClient: "basic-client",
+			wantSecret: "secret",
+		},
+		{
+			name:    "client_secret_basic client uses the form",
+			form:    url.Values{"client_id": {"basic-client"}, "client_secret": {"secret"}},
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+		{
+			name:       "client_secret_post",
+			form:       url.Values{"client_id": {"post-client"}, "client_secret": {"secret"}},
+			wantClient: "post-client",
+			wantSecret: "secret",
+		},
+		{
+			name:    "confidential client without secret",
+			form:    url.Values{"client_id": {"post-client"}},
+			wantErr: oauth2Errors.ErrInvalidClient,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.form == nil {
+				tt.form = url.Values{}
+			}
+			r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basic != nil {
+				r.SetBasicAuth(tt.basic[0], tt.basic[1])
+			}
+			auth := &oauth.ClientAuth{}
+			r = r.WithContext(oauth.WithClientAuth(r.Context(), auth))
+
+			clientID, secret, err := a.ClientInfoHandler(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClientInfoHandler() error = %v, want %v", err, tt.wantErr)
+			}
+			if clientID != tt.wantClient || secret != tt.wantSecret {
+				t.Errorf("ClientInfoHandler() = %q, %q; want %q, %q", clientID, secret, tt.wantClient, tt.wantSecret)
+			}
+			if tt.wantErr == nil && tt.form.Get("client_assertion") != "" && auth.ClientID != tt.wantClient {
+				t.Errorf("ClientInfoHandler() authenticated client = %q, want %q", auth.ClientID, tt.wantClient)
+			}
+		})
+	}
+}
+
+func TestClientAuthenticatorJWKSURI(t *testing.T) {
+	newKey := func() (*ecdsa.PrivateKey, []byte) {
+		pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := jwk.NewKey("key-1", "ES256", pk.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		jwks, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+		return pk, jwks
+	}
+	oldKey, oldJWKS := newKey()
+	newPK, newJWKS := newKey()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/new" {
+			w.Write(newJWKS)
+			return
+		}
+		w.Write(oldJWKS)
+	}))
+	defer srv.Close()
+
+	repo := &clientAuthRepoMock{clients: map[string]repository.Client{
+		"client": {ID: "client", TokenEndpointAuthMethod: oauth.AuthMethodPrivateKeyJWT, JwksUri: srv.URL + "/old"},
+		"other":  {ID: "other", TokenEndpointAuthMethod: oauth.AuthMethodPrivateKeyJWT, JwksUri: srv.URL + "/old"},
+	}}
+	a := oauth.NewClientAuthenticator(repo, testEncryptionKey, []string{testTokenEndpoint},
+		oauth.WithReplayCache(replayCacheMock{}),
+		oauth.WithJWKSHTTPClient(srv.Client()),
+	)
+
+	authenticate := func(key *ecdsa.PrivateKey, clientID string) error {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+			Issuer:    clientID,
+			Subject:   clientID,
+			Audience:  jwt.ClaimStrings{testTokenEndpoint},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        clientID + time.Now().String(),
+		})
+		token.Header["kid"] = "key-1"
+		assertion, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(url.Values{
+			"client_assertion_type": {oauth.ClientAssertionType},
+			"client_assertion":      {assertion},
+		}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r = r.WithContext(oauth.WithClientAuth(r.Context(), &oauth.ClientAuth{}))
+		_, _, err = a.ClientInfoHandler(r)
+		return err
+	}
+
+	if err := authenticate(oldKey, "client"); err != nil {
+		t.Fatalf("ClientInfoHandler() error = %v, want the key from the jwks_uri accepted", err)
+	}
+	if err := authenticate(oldKey, "other"); err != nil {
+		t.Fatalf("ClientInfoHandler() error = %v, want the key from the jwks_uri accepted", err)
+	}
+
+	// the client moves its keys to another jwks_uri, the cached key set of the old uri isn't used
+	c := repo.clients["client"]
+	c.JwksUri = srv.URL + "/new"
+	repo.clients["client"] = c
+
+	if err := authenticate(newPK, "client"); err != nil {
+		t.Errorf("ClientInfoHandler() error = %v, want the key from the new jwks_uri accepted", err)
+	}
+	if err := authenticate(oldKey, "client"); err == nil {
+		t.Errorf("ClientInfoHandler() accepted the key from the old jwks_uri")
+	}
+	if err := authenticate(newPK, "other"); err == nil {
+		t.Errorf("ClientInfoHandler() accepted the key of another client")
+	}
+}
+
+func TestClientAuthenticatorTLS(t *testing.T) {
+	ca, caKey := testutil.NewCertificate(t, "Test CA", nil, nil)
+	otherCA, otherCAKey := testutil.NewCertificate(t, "Other CA", nil, nil)
+	clientCert, clientKey := testutil.NewCertificate(t, "client.example.com", ca, caKey)
+	rogueCert, rogueKey := testutil.NewCertificate(t, "client.example.com", otherCA, otherCAKey)
+	selfSigned, selfSignedKey := testutil.NewCertificate(t, "self-signed", nil, nil)
+
+	key, err := jwk.NewKey("", "", selfSigned.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	repo := &clientAuthRepoMock{clients: map[string]repository.Client{
+		"pki-client":         {ID: "pki-client", TokenEndpointAuthMethod: oauth.AuthMethodTLSClientAuth, TlsClientAuthSubjectDn: "CN=client.example.com"},
+		"self-signed-client": {ID: "self-signed-client", TokenEndpointAuthMethod: oauth.AuthMethodSelfSignedTLSClientAuth, Jwks: string(jwks)},
+	}}
+	a := oauth.NewClientAuthenticator(repo, testEncryptionKey, []string{testTokenEndpoint}, oauth.WithClientCAs(clientCAs))
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := &oauth.ClientAuth{}
+		r = r.WithContext(oauth.WithClientAuth(r.Context(), auth))
+		if _, _, err := a.ClientInfoHandler(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(auth.ClientID + " " + auth.Method))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		clientID string
+		cert     *x509.Certificate
+		key      *ecdsa.PrivateKey
+		want     string
+	}{
+		{
+			name:     "tls_client_auth",
+			clientID: "pki-client",
+			cert:     clientCert,
+			key:      clientKey,
+			want:     "pki-client " + oauth.AuthMethodTLSClientAuth,
+		},
+		{
+			name:     "tls_client_auth certificate of untrusted CA",
+			clientID: "pki-client",
+			cert:     rogueCert,
+			key:      rogueKey,
+		},
+		{
+			name:     "tls_client_auth certificate of another subject",
+			clientID: "pki-client",
+			cert:     selfSigned,
+			key:      selfSignedKey,
+		},
+		{
+			name:     "tls_client_auth without certificate",
+			clientID: "pki-client",
+		},
+		{
+			name:     "self_signed_tls_client_auth",
+			clientID: "self-signed-client",
+			cert:     selfSigned,
+			key:      selfSignedKey,
+			want:     "self-signed-client " + oauth.AuthMethodSelfSignedTLSClientAuth,
+		},
+		{
+			name:     "self_signed_tls_client_auth unregistered certificate",
+			clientID: "self-signed-client",
+			cert:     clientCert,
+			key:      clientKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// new connection for each certificate
+			tr := srv.Client().Transport.(*http.Transport).Clone()
+			if tt.cert != nil {
+				tr.TLSClientConfig.Certificates = []tls.Certificate{
+					{Certificate: [][]byte{tt.cert.Raw}, PrivateKey: tt.key},
+				}
+			}
+			c := &http.Client{Transport: tr}
+
+			resp, err := c.PostForm(srv.URL, url.Values{"client_id": {tt.clientID}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if tt.want == "" && resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("ClientInfoHandler() status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+			}
+			if tt.want != "" && string(body) != tt.want {
+				t.Errorf("ClientInfoHandler() authenticated client = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
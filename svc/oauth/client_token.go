@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+type (
+	// ClientTokenConfig is the token lifetimes of the client.
+	// The zero values fall back to the token config of the grant type.
+	ClientTokenConfig struct {
+		AccessTokenTTL  time.Duration
+		RefreshTokenTTL time.Duration
+		CodeTTL         time.Duration
+		// the refresh tokens are not issued to the client for the new authorizations,
+		// the refresh tokens issued before are rotated until they expire or are revoked
+		RefreshTokensDisabled bool
+		// the refresh token expires after it hasn't been used for the refresh token lifetime,
+		// otherwise it expires after the lifetime since the authorization
+		IdleRefreshExpiry bool
+	}
+
+	// clientTokenConfigProvider is implemented by the client which has its own token lifetimes, see Client.
+	clientTokenConfigProvider interface {
+		TokenConfig() ClientTokenConfig
+	}
+
+	// clientAccessGenerate applies the client token config to the access and refresh tokens
+	// before they are generated, since go-oauth2 applies the same config of the grant type to all clients.
+	clientAccessGenerate struct {
+		oauth2.AccessGenerate
+	}
+
+	// clientAuthorizeGenerate applies the client authorization code lifetime.
+	clientAuthorizeGenerate struct {
+		oauth2.AuthorizeGenerate
+	}
+)
+
+// Token applies the client token config to the token info and generates the tokens.
+func (g clientAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic, isGenRefresh bool) (string, string, error) {
+	c, ok := data.Client.(clientTokenConfigProvider)
+	if !ok {
+		return g.AccessGenerate.Token(ctx, data, isGenRefresh)
+	}
+	cfg := c.TokenConfig()
+	ti := data.TokenInfo
+
+	if cfg.AccessTokenTTL > 0 {
+		ti.SetAccessExpiresIn(cfg.AccessTokenTTL)
+	}
+	if meta, ok := TokenMetaFromContext(ctx); ok && !meta.AccessNotAfter.IsZero() {
+		if notAfter := meta.AccessNotAfter.Sub(ti.GetAccessCreateAt()); ti.GetAccessExpiresIn() == 0 || ti.GetAccessExpiresIn() > notAfter {
+			ti.SetAccessExpiresIn(notAfter)
+		}
+	}
+
+	// the refreshed token info already has the rotated refresh token,
+	// its lifetime is prolonged only if the refresh token expires when idle
+	refreshing := ti.GetRefresh() != ""
+	switch {
+	case !isGenRefresh:
+	case refreshing && cfg.IdleRefreshExpiry:
+		ti.SetRefreshCreateAt(data.CreateAt)
+		if cfg.RefreshTokenTTL > 0 {
+			ti.SetRefreshExpiresIn(cfg.RefreshTokenTTL)
+		}
+	case refreshing:
+	case cfg.RefreshTokensDisabled:
+		isGenRefresh = false
+		ti.SetRefreshCreateAt(time.Time{})
+		ti.SetRefreshExpiresIn(0)
+	case cfg.RefreshTokenTTL > 0:
+		ti.SetRefreshExpiresIn(cfg.RefreshTokenTTL)
+	}
+
+	return g.AccessGenerate.Token(ctx, data, isGenRefresh)
+}
+
+// Token applies the client authorization code lifetime and generates the code.
+func (g clientAuthorizeGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic) (string, error) {
+	if c, ok := data.Client.(clientTokenConfigProvider); ok {
+		if ttl := c.TokenConfig().CodeTTL; ttl > 0 {
+			data.TokenInfo.SetCodeExpiresIn(ttl)
+		}
+	}
+
+	return g.AuthorizeGenerate.Token(ctx, data)
+}
@@ -0,0 +1,129 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func newClientTokenManager(t *testing.T) oauth2.Manager {
+	tokens, err := store.NewMemoryTokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clients := clientTokenStoreMock{clients: map[string]*oauth.Client{
+		"default": {ID: "default", Public: true},
+		"kiosk": {
+			ID: "kiosk", Public: true,
+			AccessTokenTTL: 5 * time.Minute, RefreshTokenTTL: time.Hour, CodeTTL: time.Minute,
+			IdleRefreshExpiry: true,
+		},
+		"job": {ID: "job", Public: true, AccessTokenTTL: 10 * time.Minute, RefreshTokensDisabled: true},
+	}}
+
+	_, manager := oauth.NewOauth2Server(
+		generates.NewAccessGenerate(),
+		generates.NewAuthorizeGenerate(),
+		tokens, clients,
+		oauth.NewHandler(nil),
+	)
+	return manager
+}
+
+func TestClientTokenConfig_AccessToken(t *testing.T) {
+	manager := newClientTokenManager(t)
+
+	tests := []struct {
+		name        string
+		clientID    string
+		wantAccess  time.Duration
+		wantRefresh time.Duration
+		wantNoRefr  bool
+	}{
+		{name: "grant type defaults", clientID: "default", wantAccess: 2 * time.Hour, wantRefresh: 7 * 24 * time.Hour},
+		{name: "client lifetimes", clientID: "kiosk", wantAccess: 5 * time.Minute, wantRefresh: time.Hour},
+		{name: "refresh tokens disabled", clientID: "job", wantAccess: 10 * time.Minute, wantNoRefr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti, err := manager.GenerateAccessToken(context.Background(), oauth2.PasswordCredentials, &oauth2.TokenGenerateRequest{
+				ClientID: tt.clientID,
+				UserID:   "user-1",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ti.GetAccessExpiresIn() != tt.wantAccess {
+				t.Errorf("access expires in = %s, want %s", ti.GetAccessExpiresIn(), tt.wantAccess)
+			}
+			if tt.wantNoRefr {
+				if ti.GetRefresh() != "" || ti.GetRefreshExpiresIn() != 0 {
+					t.Errorf("refresh token = %q expires in %s, want none", ti.GetRefresh(), ti.GetRefreshExpiresIn())
+				}
+				return
+			}
+			if ti.GetRefresh() == "" || ti.GetRefreshExpiresIn() != tt.wantRefresh {
+				t.Errorf("refresh token = %q expires in %s, want %s", ti.GetRefresh(), ti.GetRefreshExpiresIn(), tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func TestClientTokenConfig_RefreshExpiry(t *testing.T) {
+	manager := newClientTokenManager(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		clientID string
+		idle     bool
+	}{{clientID: "default"}, {clientID: "kiosk", idle: true}} {
+		t.Run(tt.clientID, func(t *testing.T) {
+			ti, err := manager.GenerateAccessToken(ctx, oauth2.PasswordCredentials, &oauth2.TokenGenerateRequest{
+				ClientID: tt.clientID,
+				UserID:   "user-1",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			issuedAt := ti.GetRefreshCreateAt()
+
+			time.Sleep(10 * time.Millisecond)
+			refreshed, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{
+				ClientID: tt.clientID,
+				Refresh:  ti.GetRefresh(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the idle refresh token expiry is counted from the last refresh
+			if prolonged := refreshed.GetRefreshCreateAt().After(issuedAt); prolonged != tt.idle {
+				t.Errorf("refresh token expiry prolonged = %v, want %v", prolonged, tt.idle)
+			}
+		})
+	}
+}
+
+func TestClientTokenConfig_Code(t *testing.T) {
+	manager := newClientTokenManager(t)
+
+	for clientID, want := range map[string]time.Duration{"default": 10 * time.Minute, "kiosk": time.Minute} {
+		ti, err := manager.GenerateAuthToken(context.Background(), oauth2.Code, &oauth2.TokenGenerateRequest{
+			ClientID: clientID,
+			UserID:   "user-1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ti.GetCodeExpiresIn() != want {
+			t.Errorf("%s: code expires in = %s, want %s", clientID, ti.GetCodeExpiresIn(), want)
+		}
+	}
+}
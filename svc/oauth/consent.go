@@ -0,0 +1,303 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dmitrymomot/oauth2-server/internal/session"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/foolin/goview"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/google/uuid"
+)
+
+// Consent form parameters
+const (
+	consentDecisionParam  = "consent"
+	consentCSRFTokenParam = "csrf_token"
+	consentApprove        = "approve"
+)
+
+type (
+	// ConsentManager remembers the scopes approved by the user for the client,
+	// so the consent page is shown only for a new scope set or on prompt=consent.
+	ConsentManager struct {
+		repo   consentRepository
+		scopes consentScopeRegistry
+	}
+
+	consentManagerOption func(m *ConsentManager)
+
+	consentScopeRegistry interface {
+		DefaultScope(ctx context.Context, clientScope string, withUser bool) (string, error)
+		ConsentScope(ctx context.Context, scope string) (string, error)
+		Describe(ctx context.Context, scope string) ([]ScopeDescription, error)
+	}
+
+	consentRepository interface {
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+		CreateUserConsent(ctx context.Context, arg repository.CreateUserConsentParams) error
+		GetUserConsents(ctx context.Context, arg repository.GetUserConsentsParams) ([]repository.UserConsent, error)
+	}
+
+	consentManager interface {
+		Required(ctx context.Context, uid uuid.UUID, clientID, scope string) (bool, error)
+		Grant(ctx context.Context, uid uuid.UUID, clientID, scope string) error
+		ClientName(ctx context.Context, clientID string) (string, error)
+		Scopes(ctx context.Context, clientID, scope string) ([]ScopeDescription, error)
+	}
+)
+
+// WithConsentScopeRegistry sets the registry of the scope descriptions displayed on the consent page.
+// The scopes which don't require the consent are approved without asking the user.
+func WithConsentScopeRegistry(r consentScopeRegistry) consentManagerOption {
+	return func(m *ConsentManager) {
+		m.scopes = r
+	}
+}
+
+// NewConsentManager creates a new consent manager instance.
+func NewConsentManager(repo consentRepository, opts ...consentManagerOption) *ConsentManager {
+	m := &ConsentManager{repo: repo}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Required returns true if the user hasn't approved the scope for the client yet.
+// The scope is approved if it's covered by one of the remembered scope sets.
+func (m *ConsentManager) Required(ctx context.Context, uid uuid.UUID, clientID, scope string) (bool, error) {
+	scope, err := m.requestedScope(ctx, clientID, scope)
+	if err != nil {
+		return false, err
+	}
+	if m.scopes != nil {
+		if scope, err = m.scopes.ConsentScope(ctx, scope); err != nil {
+			return false, fmt.Errorf("failed to get consent scope: %w", err)
+		}
+		// all requested scopes are granted without the consent
+		if scope == "" {
+			return false, nil
+		}
+	}
+
+	consents, err := m.repo.GetUserConsents(ctx, repository.GetUserConsentsParams{
+		UserID:   uid,
+		ClientID: clientID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get user consents: %w", err)
+	}
+
+	scope = normalizeScope(scope)
+	for _, c := range consents {
+		if scope == "" || c.Scope == scope || MatchScopesStrict(scope, c.Scope) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Grant remembers the scope set approved by the user for the client.
+func (m *ConsentManager) Grant(ctx context.Context, uid uuid.UUID, clientID, scope string) error {
+	scope, err := m.requestedScope(ctx, clientID, scope)
+	if err != nil {
+		return err
+	}
+
+	if err := m.repo.CreateUserConsent(ctx, repository.CreateUserConsentParams{
+		UserID:   uid,
+		ClientID: clientID,
+		Scope:    normalizeScope(scope),
+	}); err != nil {
+		return fmt.Errorf("failed to create user consent: %w", err)
+	}
+
+	return nil
+}
+
+// ClientName returns the client name displayed on the consent page.
+// The client domain is used if the client has no name.
+func (m *ConsentManager) ClientName(ctx context.Context, clientID string) (string, error) {
+	client, err := m.repo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	if client.Name != "" {
+		return client.Name, nil
+	}
+	return client.Domain, nil
+}
+
+// Scopes returns the requested scopes displayed on the consent page.
+func (m *ConsentManager) Scopes(ctx context.Context, clientID, scope string) ([]ScopeDescription, error) {
+	scope, err := m.requestedScope(ctx, clientID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.scopes == nil {
+		result := make([]ScopeDescription, 0)
+		for _, s := range strings.Fields(scope) {
+			result = append(result, ScopeDescription{Name: s, Description: s})
+		}
+		return result, nil
+	}
+
+	result, err := m.scopes.Describe(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe scopes: %w", err)
+	}
+	return result, nil
+}
+
+// requestedScope returns the scope granted by the authorization request,
+// the default scopes of the client are granted if the request has no scope.
+func (m *ConsentManager) requestedScope(ctx context.Context, clientID, scope string) (string, error) {
+	if scope != "" || m.scopes == nil {
+		return scope, nil
+	}
+
+	client, err := m.repo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get client by id: %w", err)
+	}
+
+	scope, err = m.scopes.DefaultScope(ctx, client.Scope, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to get default scope: %w", err)
+	}
+	return scope, nil
+}
+
+// handleConsent asks the logged in user to approve the authorization request.
+// It returns true if the request can be passed to the authorization server:
+// the user has approved the scope now or earlier, or the request is invalid,
+// so the error is handled by the authorization server.
+func handleConsent(w http.ResponseWriter, r *http.Request, srv oauth2Server, c consentManager) (bool, error) {
+	req, err := srv.ValidationAuthorizeRequest(r)
+	if err != nil {
+		return true, nil
+	}
+
+	userID, _ := session.GetLoggedInUserID(r, w)
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return false, ErrUnauthorized
+	}
+
+	// the user has submitted the consent form
+	if decision := r.PostFormValue(consentDecisionParam); r.Method == http.MethodPost && decision != "" {
+		if !session.VerifyCSRFToken(r, w, r.PostFormValue(consentCSRFTokenParam)) {
+			return false, ErrInvalidRequest
+		}
+
+		if decision != consentApprove {
+			return false, redirectAuthorizeError(w, r, srv, req, errors.ErrAccessDenied)
+		}
+
+		if err := c.Grant(r.Context(), uid, req.ClientID, req.Scope); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	// the authorization details describe a single transaction,
+	// so they are approved by the user on each request
+	details, _ := parseAuthorizationDetails(r.FormValue(authorizationDetailsParam))
+
+	prompt := strings.Fields(r.FormValue("prompt"))
+	required := hasPrompt(prompt, "consent") || len(details) > 0
+	if !required {
+		if required, err = c.Required(r.Context(), uid, req.ClientID, req.Scope); err != nil {
+			return false, err
+		}
+	}
+	if !required {
+		return true, nil
+	}
+
+	if hasPrompt(prompt, "none") {
+		return false, redirectAuthorizeError(w, r, srv, req, ErrConsentRequired)
+	}
+
+	clientName, err := c.ClientName(r.Context(), req.ClientID)
+	if err != nil {
+		return false, err
+	}
+
+	scopes, err := c.Scopes(r.Context(), req.ClientID, req.Scope)
+	if err != nil {
+		return false, err
+	}
+
+	csrfToken, err := session.StoreCSRFToken(r, w)
+	if err != nil {
+		return false, err
+	}
+
+	// authorization request parameters are resubmitted with the consent form
+	params := make(map[string][]string, len(r.Form))
+	for key, values := range r.Form {
+		if key != consentDecisionParam && key != consentCSRFTokenParam {
+			params[key] = values
+		}
+	}
+
+	return false, goview.Render(w, http.StatusOK, "consent", map[string]interface{}{
+		"page_title":  "Authorize " + clientName,
+		"client_name": clientName,
+		"scopes":      scopes,
+		"csrf_token":  csrfToken,
+		"params":      params,
+		"action":      r.URL.Path,
+
+		"authorization_details": consentAuthorizationDetails(details),
+	})
+}
+
+// consentAuthorizationDetails returns the authorization details displayed on the consent page:
+// the type and the type specific fields of each detail
+func consentAuthorizationDetails(details []AuthorizationDetail) []map[string]string {
+	result := make([]map[string]string, 0, len(details))
+	for _, d := range details {
+		fields := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			if k != "type" {
+				fields[k] = v
+			}
+		}
+		b, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			continue
+		}
+		result = append(result, map[string]string{"type": d.Type(), "fields": string(b)})
+	}
+	return result
+}
+
+// redirectAuthorizeError redirects the user back to the client with the error
+// in the requested response mode
+func redirectAuthorizeError(w http.ResponseWriter, r *http.Request, srv oauth2Server, req *server.AuthorizeRequest, err error) error {
+	data, _, _ := srv.GetErrorData(err)
+	return srv.RedirectAuthorizeResponse(w, r, req, data)
+}
+
+// hasPrompt checks if the prompt parameter contains the value
+func hasPrompt(prompt []string, value string) bool {
+	for _, p := range prompt {
+		if p == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/google/uuid"
+)
+
+type consentRepoMock struct {
+	consents []repository.UserConsent
+}
+
+func (m *consentRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	return repository.Client{ID: id, Domain: "https://example.com", Scope: "user:* client:*"}, nil
+}
+
+func (m *consentRepoMock) CreateUserConsent(ctx context.Context, arg repository.CreateUserConsentParams) error {
+	m.consents = append(m.consents, repository.UserConsent{
+		UserID:   arg.UserID,
+		ClientID: arg.ClientID,
+		Scope:    arg.Scope,
+	})
+	return nil
+}
+
+func (m *consentRepoMock) GetUserConsents(ctx context.Context, arg repository.GetUserConsentsParams) ([]repository.UserConsent, error) {
+	var result []repository.UserConsent
+	for _, c := range m.consents {
+		if c.UserID == arg.UserID && c.ClientID == arg.ClientID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func TestConsentManager(t *testing.T) {
+	ctx := context.Background()
+	uid := uuid.New()
+	m := oauth.NewConsentManager(&consentRepoMock{})
+
+	if required, err := m.Required(ctx, uid, "client", "user:read"); err != nil || !required {
+		t.Fatalf("Required() = %v, %v; want true before the consent is granted", required, err)
+	}
+
+	if err := m.Grant(ctx, uid, "client", "openid user:read user:read client:read"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		uid      uuid.UUID
+		clientID string
+		scope    string
+		want     bool
+	}{
+		{name: "same scope set", uid: uid, clientID: "client", scope: "client:read openid user:read", want: false},
+		{name: "subset", uid: uid, clientID: "client", scope: "user:read", want: false},
+		{name: "empty scope", uid: uid, clientID: "client", scope: "", want: false},
+		{name: "new scope", uid: uid, clientID: "client", scope: "user:read user:write", want: true},
+		{name: "another client", uid: uid, clientID: "another", scope: "user:read", want: true},
+		{name: "another user", uid: uuid.New(), clientID: "client", scope: "user:read", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Required(ctx, tt.uid, tt.clientID, tt.scope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Required() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
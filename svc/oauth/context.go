@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// tokenMetaKey is a context key for the token metadata.
+	tokenMetaKey struct{}
+
+	// clientAuthKey is a context key for the client authentication result.
+	clientAuthKey struct{}
+
+	// TokenMeta holds the OpenID Connect request data which is not a part
+	// of the oauth2.TokenInfo interface, but must be persisted with the token:
+	// nonce from the authorization request, the time, the context class
+	// and the methods of the user authentication.
+	// It's passed through the request context, because go-oauth2 manager
+	// creates token info instances on its own.
+	// Audience is the aud claim of the access token: the requested resources or
+	// the audience of the exchanged token, Act is used only to generate the exchanged token.
+	// Resources are the resource indicators granted by the authorization,
+	// they are persisted with the token, so the refresh token can be used for any of them.
+	// AuthorizationDetails of the access token and the GrantedAuthorizationDetails
+	// persisted with the token follow the same rules.
+	// CertThumbprint binds the access token to the TLS client certificate of the token request,
+	// DPoPJKT binds it to the key of the DPoP proof.
+	// SessionID is the sid of the browser session the user is authenticated in,
+	// the tokens are revoked and the clients are notified when the user logs out.
+	// AccessNotAfter caps the access token lifetime set for the client,
+	// e.g. the exchanged token doesn't outlive the subject token.
+	TokenMeta struct {
+		Nonce     string
+		AuthTime  time.Time
+		ACR       string
+		AMR       []string
+		SessionID string
+
+		Audience  []string
+		Resources []string
+		Act       *ActorClaim
+
+		AccessNotAfter time.Time
+
+		AuthorizationDetails        []AuthorizationDetail
+		GrantedAuthorizationDetails []AuthorizationDetail
+
+		CertThumbprint string
+		DPoPJKT        string
+	}
+
+	// ClientAuth holds the client authenticated with the client assertion
+	// or the TLS client certificate.
+	// go-oauth2 verifies the client secret on its own, so the client store
+	// uses it to accept the client which has no secret in the request.
+	ClientAuth struct {
+		ClientID string
+		Method   string
+	}
+)
+
+// WithTokenMeta returns a copy of the context with the token metadata.
+func WithTokenMeta(ctx context.Context, meta *TokenMeta) context.Context {
+	return context.WithValue(ctx, tokenMetaKey{}, meta)
+}
+
+// TokenMetaFromContext returns the token metadata from the context.
+func TokenMetaFromContext(ctx context.Context) (*TokenMeta, bool) {
+	meta, ok := ctx.Value(tokenMetaKey{}).(*TokenMeta)
+	return meta, ok && meta != nil
+}
+
+// WithClientAuth returns a copy of the context with the client authentication holder.
+func WithClientAuth(ctx context.Context, auth *ClientAuth) context.Context {
+	return context.WithValue(ctx, clientAuthKey{}, auth)
+}
+
+// ClientAuthFromContext returns the client authentication holder from the context.
+func ClientAuthFromContext(ctx context.Context) (*ClientAuth, bool) {
+	auth, ok := ctx.Value(clientAuthKey{}).(*ClientAuth)
+	return auth, ok && auth != nil
+}
@@ -0,0 +1,215 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/random"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// DeviceCodeGrantType is the grant type of the device authorization grant.
+// See: https://www.rfc-editor.org/rfc/rfc8628
+const DeviceCodeGrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Device authorization defaults
+const (
+	defaultDeviceCodeTTL      = 10 * time.Minute
+	defaultDevicePollInterval = 5 * time.Second
+
+	// user code charset without vowels to avoid forming words,
+	// see: https://www.rfc-editor.org/rfc/rfc8628#section-6.1
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+)
+
+type (
+	// DeviceGrant implements the device authorization grant.
+	// The device requests the codes on the device authorization endpoint,
+	// then polls the token endpoint until the user approves or denies the request
+	// on the verification page.
+	DeviceGrant struct {
+		repo            deviceRepository
+		tokens          tokenGenerator
+		verificationURI string
+		ttl             time.Duration
+		interval        time.Duration
+	}
+
+	deviceGrantOption func(g *DeviceGrant)
+
+	deviceRepository interface {
+		CreateDeviceCode(ctx context.Context, arg repository.CreateDeviceCodeParams) (repository.DeviceCode, error)
+		GetDeviceCode(ctx context.Context, deviceCode string) (repository.DeviceCode, error)
+		UpdateDeviceCodePolling(ctx context.Context, arg repository.UpdateDeviceCodePollingParams) error
+		DeleteDeviceCode(ctx context.Context, deviceCode string) (int64, error)
+	}
+
+	// DeviceAuthorizationResponse represents the device authorization response.
+	// See: https://www.rfc-editor.org/rfc/rfc8628#section-3.2
+	DeviceAuthorizationResponse struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+
+	// deviceAuthorizer issues device and user codes
+	deviceAuthorizer interface {
+		Authorize(ctx context.Context, client oauth2.ClientInfo, scope string) (*DeviceAuthorizationResponse, error)
+	}
+)
+
+// WithDeviceCodeTTL sets the lifetime of the device and user codes
+func WithDeviceCodeTTL(ttl time.Duration) deviceGrantOption {
+	return func(g *DeviceGrant) {
+		g.ttl = ttl
+	}
+}
+
+// WithDevicePollInterval sets the minimal interval between token requests
+func WithDevicePollInterval(interval time.Duration) deviceGrantOption {
+	return func(g *DeviceGrant) {
+		g.interval = interval
+	}
+}
+
+// NewDeviceGrant creates a new device authorization grant handler.
+// The verificationURI is the page where the user enters the user code.
+func NewDeviceGrant(repo deviceRepository, tokens tokenGenerator, verificationURI string, opts ...deviceGrantOption) *DeviceGrant {
+	g := &DeviceGrant{
+		repo:            repo,
+		tokens:          tokens,
+		verificationURI: verificationURI,
+		ttl:             defaultDeviceCodeTTL,
+		interval:        defaultDevicePollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// GrantType returns the device code grant type.
+func (g *DeviceGrant) GrantType() oauth2.GrantType {
+	return DeviceCodeGrantType
+}
+
+// Authorize issues a new pair of device and user codes for the client.
+func (g *DeviceGrant) Authorize(ctx context.Context, client oauth2.ClientInfo, scope string) (*DeviceAuthorizationResponse, error) {
+	dc, err := g.repo.CreateDeviceCode(ctx, repository.CreateDeviceCodeParams{
+		DeviceCode:   random.String(40),
+		UserCode:     random.String(userCodeLength, userCodeCharset),
+		ClientID:     client.GetID(),
+		Scope:        scope,
+		PollInterval: int64(g.interval.Seconds()),
+		ExpiresAt:    time.Now().Add(g.ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create device code: %w", err)
+	}
+
+	userCode := FormatUserCode(dc.UserCode)
+
+	return &DeviceAuthorizationResponse{
+		DeviceCode:              dc.DeviceCode,
+		UserCode:                userCode,
+		VerificationURI:         g.verificationURI,
+		VerificationURIComplete: g.verificationURI + "?" + url.Values{"user_code": {userCode}}.Encode(),
+		ExpiresIn:               int64(g.ttl.Seconds()),
+		Interval:                dc.PollInterval,
+	}, nil
+}
+
+// Token checks the device code state and issues the token once the user approved the request.
+func (g *DeviceGrant) Token(ctx context.Context, client oauth2.ClientInfo, tgr *oauth2.TokenGenerateRequest, r *http.Request) (oauth2.TokenInfo, error) {
+	code := r.FormValue("device_code")
+	if code == "" {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+
+	dc, err := g.repo.GetDeviceCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthErrors.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("failed to get device code: %w", err)
+	}
+	if dc.ClientID != client.GetID() {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	if err := poll(ctx, pollRequest{
+		name:         "device code",
+		approved:     dc.Status == repository.DeviceCodeStatusApproved,
+		denied:       dc.Status == repository.DeviceCodeStatusDenied,
+		expiresAt:    dc.ExpiresAt,
+		pollInterval: dc.PollInterval,
+		lastPolledAt: dc.LastPolledAt,
+		delete: func(ctx context.Context) (int64, error) {
+			return g.repo.DeleteDeviceCode(ctx, dc.DeviceCode)
+		},
+		updatePolling: func(ctx context.Context, interval int64) error {
+			return g.repo.UpdateDeviceCodePolling(ctx, repository.UpdateDeviceCodePollingParams{
+				PollInterval: interval,
+				DeviceCode:   dc.DeviceCode,
+			})
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	tgr.UserID = dc.UserID.UUID.String()
+	tgr.Scope = dc.Scope
+	return issueToken(ctx, g.tokens, tgr)
+}
+
+// FormatUserCode formats the user code to be displayed, e.g. BCDF-GHJK.
+func FormatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// HandleDeviceAuthorizationRequest handles the device authorization request.
+// See: https://www.rfc-editor.org/rfc/rfc8628#section-3.1
+func (s *Server) HandleDeviceAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	da, ok := s.grants[DeviceCodeGrantType].(deviceAuthorizer)
+	if !ok {
+		return s.tokenError(w, oauthErrors.ErrUnsupportedGrantType)
+	}
+
+	client, tgr, err := s.authenticateClient(r, DeviceCodeGrantType)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	if fn := s.ClientScopeHandler; fn != nil {
+		allowed, err := fn(tgr)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		if !allowed {
+			return s.tokenError(w, oauthErrors.ErrInvalidScope)
+		}
+	}
+
+	resp, err := da.Authorize(r.Context(), client, tgr.Scope)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	return s.token(w, resp, http.StatusOK)
+}
@@ -0,0 +1,184 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/google/uuid"
+)
+
+// tokenGeneratorMock issues the token info without storing it
+type tokenGeneratorMock struct{}
+
+func (tokenGeneratorMock) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+	return &models.Token{ClientID: tgr.ClientID, UserID: tgr.UserID, Scope: tgr.Scope}, nil
+}
+
+type deviceRepoMock struct {
+	codes map[string]repository.DeviceCode
+	// redeemed simulates a concurrent token request which redeems the code right after it's read
+	redeemed bool
+}
+
+func (m *deviceRepoMock) CreateDeviceCode(ctx context.Context, arg repository.CreateDeviceCodeParams) (repository.DeviceCode, error) {
+	dc := repository.DeviceCode{
+		DeviceCode:   arg.DeviceCode,
+		UserCode:     arg.UserCode,
+		ClientID:     arg.ClientID,
+		Scope:        arg.Scope,
+		Status:       repository.DeviceCodeStatusPending,
+		PollInterval: arg.PollInterval,
+		ExpiresAt:    arg.ExpiresAt,
+	}
+	m.codes[dc.DeviceCode] = dc
+	return dc, nil
+}
+
+func (m *deviceRepoMock) GetDeviceCode(ctx context.Context, deviceCode string) (repository.DeviceCode, error) {
+	dc, ok := m.codes[deviceCode]
+	if !ok {
+		return repository.DeviceCode{}, sql.ErrNoRows
+	}
+	if m.redeemed {
+		delete(m.codes, deviceCode)
+	}
+	return dc, nil
+}
+
+func (m *deviceRepoMock) UpdateDeviceCodePolling(ctx context.Context, arg repository.UpdateDeviceCodePollingParams) error {
+	dc := m.codes[arg.DeviceCode]
+	dc.PollInterval = arg.PollInterval
+	dc.LastPolledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	m.codes[arg.DeviceCode] = dc
+	return nil
+}
+
+func (m *deviceRepoMock) DeleteDeviceCode(ctx context.Context, deviceCode string) (int64, error) {
+	if _, ok := m.codes[deviceCode]; !ok {
+		return 0, nil
+	}
+	delete(m.codes, deviceCode)
+	return 1, nil
+}
+
+func TestDeviceGrant_Authorize(t *testing.T) {
+	repo := &deviceRepoMock{codes: map[string]repository.DeviceCode{}}
+	g := oauth.NewDeviceGrant(repo, tokenGeneratorMock{}, "https://example.com/auth/device")
+
+	resp, err := g.Authorize(context.Background(), &models.Client{ID: "tv"}, "openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dc, ok := repo.codes[resp.DeviceCode]
+	if !ok {
+		t.Fatalf("Authorize() device code is not stored")
+	}
+	if dc.ClientID != "tv" || dc.Scope != "openid" {
+		t.Errorf("Authorize() client = %s, scope = %s", dc.ClientID, dc.Scope)
+	}
+	if resp.UserCode != oauth.FormatUserCode(dc.UserCode) {
+		t.Errorf("Authorize() user code = %s, want %s", resp.UserCode, oauth.FormatUserCode(dc.UserCode))
+	}
+	if resp.VerificationURIComplete != "https://example.com/auth/device?user_code="+url.QueryEscape(resp.UserCode) {
+		t.Errorf("Authorize() verification uri complete = %s", resp.VerificationURIComplete)
+	}
+	if resp.ExpiresIn != 600 || resp.Interval != 5 {
+		t.Errorf("Authorize() expires_in = %d, interval = %d, want 600, 5", resp.ExpiresIn, resp.Interval)
+	}
+}
+
+func TestDeviceGrant_Token(t *testing.T) {
+	userID := uuid.New()
+	pending := repository.DeviceCode{
+		DeviceCode:   "device-code",
+		UserCode:     "BCDFGHJK",
+		ClientID:     "tv",
+		Scope:        "openid profile",
+		Status:       repository.DeviceCodeStatusPending,
+		PollInterval: 5,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	with := func(fn func(dc *repository.DeviceCode)) repository.DeviceCode {
+		dc := pending
+		fn(&dc)
+		return dc
+	}
+	approve := func(dc *repository.DeviceCode) {
+		dc.Status = repository.DeviceCodeStatusApproved
+		dc.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+
+	tests := []struct {
+		name         string
+		clientID     string
+		code         repository.DeviceCode
+		redeemed     bool
+		wantErr      error
+		wantLeft     bool
+		wantInterval int64
+	}{
+		{name: "pending", clientID: "tv", code: pending, wantErr: oauth.ErrAuthorizationPending, wantLeft: true, wantInterval: 5},
+		{name: "polling too fast", clientID: "tv", code: with(func(dc *repository.DeviceCode) {
+			dc.LastPolledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}), wantErr: oauth.ErrSlowDown, wantLeft: true, wantInterval: 10},
+		{name: "polling after the interval", clientID: "tv", code: with(func(dc *repository.DeviceCode) {
+			dc.LastPolledAt = sql.NullTime{Time: time.Now().Add(-10 * time.Second), Valid: true}
+		}), wantErr: oauth.ErrAuthorizationPending, wantLeft: true, wantInterval: 5},
+		{name: "approved", clientID: "tv", code: with(approve)},
+		{name: "already redeemed", clientID: "tv", code: with(approve), redeemed: true, wantErr: oauth2Errors.ErrInvalidGrant},
+		{name: "denied", clientID: "tv", code: with(func(dc *repository.DeviceCode) {
+			dc.Status = repository.DeviceCodeStatusDenied
+		}), wantErr: oauth2Errors.ErrAccessDenied},
+		{name: "expired", clientID: "tv", code: with(func(dc *repository.DeviceCode) {
+			approve(dc)
+			dc.ExpiresAt = time.Now().Add(-time.Second)
+		}), wantErr: oauth.ErrExpiredToken},
+		{name: "another client", clientID: "console", code: with(approve), wantErr: oauth2Errors.ErrInvalidGrant, wantLeft: true, wantInterval: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &deviceRepoMock{
+				codes:    map[string]repository.DeviceCode{tt.code.DeviceCode: tt.code},
+				redeemed: tt.redeemed,
+			}
+			g := oauth.NewDeviceGrant(repo, tokenGeneratorMock{}, "https://example.com/auth/device")
+
+			r := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(url.Values{
+				"grant_type":  {string(oauth.DeviceCodeGrantType)},
+				"device_code": {tt.code.DeviceCode},
+			}.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			ti, err := g.Token(context.Background(), &models.Client{ID: tt.clientID}, &oauth2.TokenGenerateRequest{ClientID: tt.clientID}, r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Token() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (ti.GetUserID() != userID.String() || ti.GetScope() != "openid profile") {
+				t.Errorf("Token() user = %s, scope = %s", ti.GetUserID(), ti.GetScope())
+			}
+			if tt.wantErr != nil && ti != nil {
+				t.Errorf("Token() issued the token with error %v", err)
+			}
+
+			dc, left := repo.codes[tt.code.DeviceCode]
+			if left != tt.wantLeft {
+				t.Fatalf("Token() device code left = %v, want %v", left, tt.wantLeft)
+			}
+			if left && dc.PollInterval != tt.wantInterval {
+				t.Errorf("Token() poll interval = %d, want %d", dc.PollInterval, tt.wantInterval)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/server"
+)
+
+// Well-known paths
+const (
+	WellKnownPath                   = "/.well-known"
+	OpenIDConfigurationPath         = "/openid-configuration"
+	AuthorizationServerMetadataPath = "/oauth-authorization-server"
+	JWKSPath                        = "/jwks.json"
+)
+
+type (
+	// ServerMetadata represents the OAuth 2.0 Authorization Server Metadata document.
+	// See: https://www.rfc-editor.org/rfc/rfc8414#section-2
+	ServerMetadata struct {
+		Issuer                                     string   `json:"issuer"`
+		AuthorizationEndpoint                      string   `json:"authorization_endpoint"`
+		TokenEndpoint                              string   `json:"token_endpoint"`
+		JWKSURI                                    string   `json:"jwks_uri"`
+		ScopesSupported                            []string `json:"scopes_supported"`
+		ResponseTypesSupported                     []string `json:"response_types_supported"`
+		ResponseModesSupported                     []string `json:"response_modes_supported"`
+		GrantTypesSupported                        []string `json:"grant_types_supported"`
+		TokenEndpointAuthMethodsSupported          []string `json:"token_endpoint_auth_methods_supported"`
+		TokenEndpointAuthSigningAlgValuesSupported []string `json:"token_endpoint_auth_signing_alg_values_supported"`
+		RevocationEndpoint                         string   `json:"revocation_endpoint"`
+		RevocationEndpointAuthMethodsSupported     []string `json:"revocation_endpoint_auth_methods_supported"`
+		IntrospectionEndpoint                      string   `json:"introspection_endpoint"`
+		IntrospectionEndpointAuthMethodsSupported  []string `json:"introspection_endpoint_auth_methods_supported"`
+		IntrospectionSigningAlgValuesSupported     []string `json:"introspection_signing_alg_values_supported,omitempty"`
+		CodeChallengeMethodsSupported              []string `json:"code_challenge_methods_supported"`
+		DeviceAuthorizationEndpoint                string   `json:"device_authorization_endpoint,omitempty"`
+		TLSClientCertificateBoundAccessTokens      bool     `json:"tls_client_certificate_bound_access_tokens"`
+		DPoPSigningAlgValuesSupported              []string `json:"dpop_signing_alg_values_supported,omitempty"`
+		PushedAuthorizationRequestEndpoint         string   `json:"pushed_authorization_request_endpoint"`
+		RequirePushedAuthorizationRequests         bool     `json:"require_pushed_authorization_requests"`
+		RequestParameterSupported                  bool     `json:"request_parameter_supported"`
+		RequestURIParameterSupported               bool     `json:"request_uri_parameter_supported"`
+		RequestObjectSigningAlgValuesSupported     []string `json:"request_object_signing_alg_values_supported,omitempty"`
+		AuthorizationSigningAlgValuesSupported     []string `json:"authorization_signing_alg_values_supported,omitempty"`
+		RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty"`
+		AuthorizationDetailsTypesSupported         []string `json:"authorization_details_types_supported,omitempty"`
+		BackchannelAuthenticationEndpoint          string   `json:"backchannel_authentication_endpoint,omitempty"`
+		BackchannelTokenDeliveryModesSupported     []string `json:"backchannel_token_delivery_modes_supported,omitempty"`
+		BackchannelUserCodeParameterSupported      bool     `json:"backchannel_user_code_parameter_supported"`
+
+		baseURL string
+	}
+
+	// OpenIDConfiguration represents the OpenID Provider metadata document.
+	// See: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+	OpenIDConfiguration struct {
+		ServerMetadata
+		UserInfoEndpoint                 string   `json:"userinfo_endpoint"`
+		SubjectTypesSupported            []string `json:"subject_types_supported"`
+		IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+		ClaimsSupported                  []string `json:"claims_supported"`
+
+		// the RP-initiated, front-channel and back-channel logout,
+		// see: https://openid.net/specs/openid-connect-rpinitiated-1_0.html#OPMetadata
+		EndSessionEndpoint                 string `json:"end_session_endpoint"`
+		FrontchannelLogoutSupported        bool   `json:"frontchannel_logout_supported"`
+		FrontchannelLogoutSessionSupported bool   `json:"frontchannel_logout_session_supported"`
+		BackchannelLogoutSupported         bool   `json:"backchannel_logout_supported"`
+		BackchannelLogoutSessionSupported  bool   `json:"backchannel_logout_session_supported"`
+	}
+
+	jwksProvider interface {
+		JWKS(ctx context.Context) (jwk.Set, error)
+	}
+
+	// scopeNamesProvider returns the scopes which can be granted, see ScopeRegistry.
+	scopeNamesProvider interface {
+		Names(ctx context.Context) ([]string, error)
+	}
+)
+
+// NewServerMetadata returns the authorization server metadata built from the server configuration.
+// The baseURL is the URL the oauth handler is mounted on, e.g. https://example.com/oauth.
+// The supported scopes are filled by the discovery handler on each request, see MakeDiscoveryHTTPHandler.
+// The certificate-bound access tokens aren't advertised, since the tokens are bound
+// only if the server requests the client certificates on the TLS listener.
+func NewServerMetadata(issuer, baseURL string, cfg *server.Config) ServerMetadata {
+	issuer = strings.TrimSuffix(issuer, "/")
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	meta := ServerMetadata{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             baseURL + AuthorizePath,
+		TokenEndpoint:                     baseURL + TokenPath,
+		JWKSURI:                           issuer + WellKnownPath + JWKSPath,
+		ScopesSupported:                   oidcScopes,
+		ResponseTypesSupported:            make([]string, 0, len(cfg.AllowedResponseTypes)),
+		ResponseModesSupported:            make([]string, 0, len(ResponseModes)+len(JWTResponseModes)),
+		GrantTypesSupported:               make([]string, 0, len(cfg.AllowedGrantTypes)),
+		TokenEndpointAuthMethodsSupported: ClientAuthMethods,
+		TokenEndpointAuthSigningAlgValuesSupported: ClientAssertionSigningAlgs,
+		RevocationEndpoint:                         baseURL + RevokePath,
+		RevocationEndpointAuthMethodsSupported:     ClientAuthMethods,
+		IntrospectionEndpoint:                      baseURL + IntrospectPath,
+		IntrospectionEndpointAuthMethodsSupported:  make([]string, 0, len(ClientAuthMethods)),
+		CodeChallengeMethodsSupported:              make([]string, 0, len(cfg.AllowedCodeChallengeMethods)),
+		DPoPSigningAlgValuesSupported:              dpop.SigningAlgs,
+		PushedAuthorizationRequestEndpoint:         baseURL + PushedAuthorizationRequestPath,
+		RequestParameterSupported:                  true,
+		RequestObjectSigningAlgValuesSupported:     RequestObjectSigningAlgs,
+		baseURL:                                    baseURL,
+	}
+
+	for _, rt := range cfg.AllowedResponseTypes {
+		meta.ResponseTypesSupported = append(meta.ResponseTypesSupported, rt.String())
+	}
+
+	// the response modes are available for all response types,
+	// except the query modes for the implicit tokens
+	meta.ResponseModesSupported = append(meta.ResponseModesSupported, ResponseModes...)
+	meta.ResponseModesSupported = append(meta.ResponseModesSupported, JWTResponseModes...)
+
+	for _, gt := range cfg.AllowedGrantTypes {
+		if gt == oauth2.Implicit {
+			// go-oauth2 uses an internal name for the implicit grant
+			meta.GrantTypesSupported = append(meta.GrantTypesSupported, "implicit")
+			continue
+		}
+		if gt == DeviceCodeGrantType {
+			meta.DeviceAuthorizationEndpoint = baseURL + DeviceAuthorizationPath
+		}
+		if gt == CIBAGrantType {
+			meta.BackchannelAuthenticationEndpoint = baseURL + BackchannelAuthenticationPath
+			meta.BackchannelTokenDeliveryModesSupported = BackchannelTokenDeliveryModes
+		}
+		// go-oauth2 returns the empty name for the extension grant types
+		meta.GrantTypesSupported = append(meta.GrantTypesSupported, string(gt))
+	}
+
+	// the public clients can't introspect the tokens
+	for _, m := range ClientAuthMethods {
+		if m != AuthMethodNone {
+			meta.IntrospectionEndpointAuthMethodsSupported = append(meta.IntrospectionEndpointAuthMethodsSupported, m)
+		}
+	}
+
+	for _, m := range cfg.AllowedCodeChallengeMethods {
+		meta.CodeChallengeMethodsSupported = append(meta.CodeChallengeMethodsSupported, m.String())
+	}
+
+	return meta
+}
+
+// NewOpenIDConfiguration returns the OpenID Provider metadata
+// which extends the authorization server metadata.
+// The authorization and introspection responses are signed with the same key as the ID tokens.
+func NewOpenIDConfiguration(meta ServerMetadata, signingAlg string) OpenIDConfiguration {
+	meta.AuthorizationSigningAlgValuesSupported = []string{signingAlg}
+	meta.IntrospectionSigningAlgValuesSupported = []string{signingAlg}
+	return OpenIDConfiguration{
+		ServerMetadata:                   meta,
+		UserInfoEndpoint:                 meta.baseURL + UserInfoPath,
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{signingAlg},
+		ClaimsSupported:                  []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "at_hash", "sid", "email", "email_verified"},
+
+		EndSessionEndpoint:                 meta.baseURL + LogoutPath,
+		FrontchannelLogoutSupported:        true,
+		FrontchannelLogoutSessionSupported: true,
+		BackchannelLogoutSupported:         true,
+		BackchannelLogoutSessionSupported:  true,
+	}
+}
+
+// MakeDiscoveryHTTPHandler returns a handler that serves the discovery documents
+// and the public signing keys. It should be mounted on WellKnownPath.
+// The supported scopes are read from the scope registry on each request,
+// so the documents follow the registry changes, the OpenID Connect scopes are used if it's nil.
+func MakeDiscoveryHTTPHandler(cfg OpenIDConfiguration, keys jwksProvider, scopes scopeNamesProvider, log logger) http.Handler {
+	r := chi.NewRouter()
+	errEncoder := httpencoder.EncodeError(log, codeAndMessageFrom)
+
+	// withScopes returns the copy of the configuration with the registered scopes
+	withScopes := func(ctx context.Context) (OpenIDConfiguration, error) {
+		if scopes == nil {
+			return cfg, nil
+		}
+		names, err := scopes.Names(ctx)
+		if err != nil {
+			return cfg, err
+		}
+		c := cfg
+		c.ScopesSupported = names
+		return c, nil
+	}
+
+	r.Get(OpenIDConfigurationPath, func(w http.ResponseWriter, r *http.Request) {
+		c, err := withScopes(r.Context())
+		if err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+		httpencoder.EncodeResponseAsIs(r.Context(), w, c)
+	})
+
+	r.Get(AuthorizationServerMetadataPath, func(w http.ResponseWriter, r *http.Request) {
+		c, err := withScopes(r.Context())
+		if err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+		httpencoder.EncodeResponseAsIs(r.Context(), w, c.ServerMetadata)
+	})
+
+	r.Get(JWKSPath, func(w http.ResponseWriter, r *http.Request) {
+		set, err := keys.JWKS(r.Context())
+		if err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		httpencoder.EncodeResponseAsIs(r.Context(), w, set)
+	})
+
+	return r
+}
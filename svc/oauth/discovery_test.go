@@ -0,0 +1,100 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNewServerMetadata(t *testing.T) {
+	cfg := server.NewConfig()
+	cfg.AllowedGrantTypes = []oauth2.GrantType{oauth2.AuthorizationCode, oauth2.Implicit}
+
+	meta := oauth.NewServerMetadata("https://example.com/", "https://example.com/oauth/", cfg)
+
+	if meta.Issuer != "https://example.com" {
+		t.Errorf("Issuer = %s, want https://example.com", meta.Issuer)
+	}
+	if meta.TokenEndpoint != "https://example.com/oauth"+oauth.TokenPath {
+		t.Errorf("TokenEndpoint = %s", meta.TokenEndpoint)
+	}
+	if meta.JWKSURI != "https://example.com/.well-known/jwks.json" {
+		t.Errorf("JWKSURI = %s", meta.JWKSURI)
+	}
+	if want := []string{"authorization_code", "implicit"}; !reflect.DeepEqual(meta.GrantTypesSupported, want) {
+		t.Errorf("GrantTypesSupported = %v, want %v", meta.GrantTypesSupported, want)
+	}
+	if want := []string{"code", "token"}; !reflect.DeepEqual(meta.ResponseTypesSupported, want) {
+		t.Errorf("ResponseTypesSupported = %v, want %v", meta.ResponseTypesSupported, want)
+	}
+	if want := []string{"plain", "S256"}; !reflect.DeepEqual(meta.CodeChallengeMethodsSupported, want) {
+		t.Errorf("CodeChallengeMethodsSupported = %v, want %v", meta.CodeChallengeMethodsSupported, want)
+	}
+	if meta.DeviceAuthorizationEndpoint != "" {
+		t.Errorf("DeviceAuthorizationEndpoint = %s, want empty", meta.DeviceAuthorizationEndpoint)
+	}
+	if meta.TLSClientCertificateBoundAccessTokens {
+		t.Errorf("TLSClientCertificateBoundAccessTokens = true, want false without the TLS listener")
+	}
+
+	cfg.AllowedGrantTypes = append(cfg.AllowedGrantTypes, oauth.DeviceCodeGrantType, oauth.CIBAGrantType)
+	meta = oauth.NewServerMetadata("https://example.com", "https://example.com/oauth", cfg)
+	if meta.DeviceAuthorizationEndpoint != "https://example.com/oauth"+oauth.DeviceAuthorizationPath {
+		t.Errorf("DeviceAuthorizationEndpoint = %s", meta.DeviceAuthorizationEndpoint)
+	}
+	if want := []string{"authorization_code", "implicit", string(oauth.DeviceCodeGrantType), string(oauth.CIBAGrantType)}; !reflect.DeepEqual(meta.GrantTypesSupported, want) {
+		t.Errorf("GrantTypesSupported = %v, want %v", meta.GrantTypesSupported, want)
+	}
+
+	oidc := oauth.NewOpenIDConfiguration(meta, "RS256")
+	if oidc.UserInfoEndpoint != "https://example.com/oauth"+oauth.UserInfoPath {
+		t.Errorf("UserInfoEndpoint = %s", oidc.UserInfoEndpoint)
+	}
+}
+
+type scopeNamesMock struct {
+	names []string
+}
+
+func (m *scopeNamesMock) Names(ctx context.Context) ([]string, error) {
+	return m.names, nil
+}
+
+func TestMakeDiscoveryHTTPHandler_Scopes(t *testing.T) {
+	scopes := &scopeNamesMock{names: []string{"openid", "user:read"}}
+	meta := oauth.NewServerMetadata("https://example.com", "https://example.com/oauth", server.NewConfig())
+	h := oauth.MakeDiscoveryHTTPHandler(oauth.NewOpenIDConfiguration(meta, "RS256"), nil, scopes, logrus.New())
+
+	scopesSupported := func(path string) []string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var doc struct {
+			ScopesSupported []string `json:"scopes_supported"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.ScopesSupported
+	}
+
+	for _, path := range []string{oauth.OpenIDConfigurationPath, oauth.AuthorizationServerMetadataPath} {
+		if got := scopesSupported(path); !reflect.DeepEqual(got, scopes.names) {
+			t.Errorf("%s scopes_supported = %v, want %v", path, got, scopes.names)
+		}
+	}
+
+	// the registry changes are served without restart
+	scopes.names = append(scopes.names, "billing:read")
+	if got := scopesSupported(oauth.OpenIDConfigurationPath); !reflect.DeepEqual(got, scopes.names) {
+		t.Errorf("scopes_supported = %v, want %v", got, scopes.names)
+	}
+}
@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+)
+
+type (
+	// DPoPVerifier verifies the DPoP proofs of the token requests.
+	// The proof must contain the fresh server nonce, so the client can't
+	// pre-generate the proofs for later use.
+	// See: https://www.rfc-editor.org/rfc/rfc9449
+	DPoPVerifier struct {
+		tokenEndpoint string
+		nonces        *dpop.Nonces
+		replay        replayCache
+	}
+)
+
+// NewDPoPVerifier creates a new DPoP proof verifier.
+// The tokenEndpoint is the token endpoint URL the proofs are issued for.
+// The proof IDs are stored in the replay cache until the proofs expire.
+func NewDPoPVerifier(tokenEndpoint string, nonces *dpop.Nonces, replay replayCache) *DPoPVerifier {
+	return &DPoPVerifier{
+		tokenEndpoint: tokenEndpoint,
+		nonces:        nonces,
+		replay:        replay,
+	}
+}
+
+// Verify verifies the DPoP proof of the token request and returns the thumbprint
+// of the proof key. The empty thumbprint is returned if the request has no proof.
+func (v *DPoPVerifier) Verify(r *http.Request) (string, error) {
+	proofs := r.Header.Values(dpop.HeaderName)
+	switch len(proofs) {
+	case 0:
+		return "", nil
+	case 1:
+	default:
+		return "", ErrInvalidDPoPProof
+	}
+
+	proof, err := dpop.Parse(proofs[0], r.Method, v.tokenEndpoint, "", dpop.DefaultMaxAge)
+	if err != nil {
+		return "", ErrInvalidDPoPProof
+	}
+	if proof.Nonce == "" || !v.nonces.Valid(proof.Nonce) {
+		return "", ErrUseDPoPNonce
+	}
+
+	ok, err := v.replay.Use(r.Context(), proof.Thumbprint+":"+proof.ID, proof.IssuedAt.Add(dpop.DefaultMaxAge))
+	if err != nil {
+		return "", fmt.Errorf("failed to check dpop proof replay: %w", err)
+	}
+	if !ok {
+		return "", ErrInvalidDPoPProof
+	}
+
+	return proof.Thumbprint, nil
+}
+
+// VerifyAccess verifies the DPoP proof of the request to the protected resource
+// served by the authorization server, e.g. the userinfo endpoint: the proof is issued
+// for the resource uri and the access token, and is signed with the key jkt the token is bound to.
+// The server nonce isn't required, as on the resource servers.
+func (v *DPoPVerifier) VerifyAccess(r *http.Request, uri, token, jkt string) error {
+	proofs := r.Header.Values(dpop.HeaderName)
+	if len(proofs) != 1 {
+		return ErrInvalidDPoPProof
+	}
+
+	proof, err := dpop.Parse(proofs[0], r.Method, uri, token, dpop.DefaultMaxAge)
+	if err != nil || subtle.ConstantTimeCompare([]byte(proof.Thumbprint), []byte(jkt)) != 1 {
+		return ErrInvalidDPoPProof
+	}
+
+	ok, err := v.replay.Use(r.Context(), proof.Thumbprint+":"+proof.ID, proof.IssuedAt.Add(dpop.DefaultMaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to check dpop proof replay: %w", err)
+	}
+	if !ok {
+		return ErrInvalidDPoPProof
+	}
+
+	return nil
+}
+
+// Nonce returns a new server nonce for the DPoP-Nonce header.
+func (v *DPoPVerifier) Nonce() string {
+	return v.nonces.New()
+}
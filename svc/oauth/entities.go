@@ -12,12 +12,35 @@ import (
 // Client represents an OAuth client implements the oauth2.ClientInfo interface.
 type Client struct {
 	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
 	Secret     string    `json:"secret,omitempty"`
 	secretHash []byte    `json:"-"` // hash of the secret
 	Domain     string    `json:"domain"`
 	Public     bool      `json:"is_public"`
 	UserID     uuid.UUID `json:"user_id"`
 	CreatedAt  time.Time `json:"created_at"`
+
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
+	JWKS                    string `json:"-"`
+	JWKSURI                 string `json:"jwks_uri,omitempty"`
+	encryptedSecret         []byte `json:"-"` // secret encrypted at rest for client_secret_jwt
+
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+
+	// response modes the client may use, all supported modes if empty
+	ResponseModes []string `json:"response_modes,omitempty"`
+
+	// token lifetimes of the client, the server defaults are applied if zero
+	AccessTokenTTL        time.Duration `json:"access_token_ttl,omitempty"`
+	RefreshTokenTTL       time.Duration `json:"refresh_token_ttl,omitempty"`
+	CodeTTL               time.Duration `json:"code_ttl,omitempty"`
+	RefreshTokensDisabled bool          `json:"refresh_tokens_disabled,omitempty"`
+	IdleRefreshExpiry     bool          `json:"idle_refresh_expiry,omitempty"`
+
+	// the client has been authenticated with the client assertion or the TLS client certificate
+	authVerified bool
 }
 
 // NewClient creates a new client instance.
@@ -26,12 +49,30 @@ type Client struct {
 func NewClient(source repository.Client, secret string) *Client {
 	return &Client{
 		ID:         source.ID,
+		Name:       source.Name,
 		Secret:     secret,
 		secretHash: source.Secret,
 		Domain:     source.Domain,
 		Public:     source.IsPublic,
-		UserID:     source.UserID,
+		UserID:     source.UserID.UUID,
 		CreatedAt:  source.CreatedAt,
+
+		RedirectURIs: source.RedirectUris,
+
+		TokenEndpointAuthMethod: source.TokenEndpointAuthMethod,
+		JWKS:                    source.Jwks,
+		JWKSURI:                 source.JwksUri,
+		encryptedSecret:         source.EncryptedSecret,
+
+		RequirePushedAuthorizationRequests: source.RequirePushedAuthorizationRequests,
+
+		ResponseModes: source.ResponseModes,
+
+		AccessTokenTTL:        time.Duration(source.AccessTokenTtl) * time.Second,
+		RefreshTokenTTL:       time.Duration(source.RefreshTokenTtl) * time.Second,
+		CodeTTL:               time.Duration(source.CodeTtl) * time.Second,
+		RefreshTokensDisabled: !source.IssueRefreshTokens,
+		IdleRefreshExpiry:     source.RefreshTokenExpiry == repository.RefreshTokenExpiryIdle,
 	}
 }
 
@@ -50,18 +91,56 @@ func (c *Client) GetDomain() string {
 	return c.Domain
 }
 
+// GetRedirectURIs returns the registered client redirect URIs.
+func (c *Client) GetRedirectURIs() []string {
+	return c.RedirectURIs
+}
+
+// RequiresPushedAuthorization returns true if the client must push
+// the authorization request parameters to the PAR endpoint.
+func (c *Client) RequiresPushedAuthorization() bool {
+	return c.RequirePushedAuthorizationRequests
+}
+
+// GetResponseModes returns the response modes the client may use,
+// all supported response modes are allowed if empty.
+func (c *Client) GetResponseModes() []string {
+	return c.ResponseModes
+}
+
+// TokenConfig returns the token lifetimes of the client.
+func (c *Client) TokenConfig() ClientTokenConfig {
+	return ClientTokenConfig{
+		AccessTokenTTL:        c.AccessTokenTTL,
+		RefreshTokenTTL:       c.RefreshTokenTTL,
+		CodeTTL:               c.CodeTTL,
+		RefreshTokensDisabled: c.RefreshTokensDisabled,
+		IdleRefreshExpiry:     c.IdleRefreshExpiry,
+	}
+}
+
 // IsPublic returns true if the client is public.
 func (c *Client) IsPublic() bool {
 	return c.Public
 }
 
 // GetUserID returns the client user ID.
+// The dynamically registered clients have no owner.
 func (c *Client) GetUserID() string {
+	if c.UserID == uuid.Nil {
+		return ""
+	}
 	return c.UserID.String()
 }
 
 // VerifyPassword verifies the client secret.
+// Public clients can't keep the secret, so they may omit it.
+// Clients authenticated with the client assertion or the TLS client certificate
+// have no secret in the request.
 func (c *Client) VerifyPassword(secret string) bool {
+	if c.authVerified || (c.Public && secret == "") {
+		return true
+	}
 	if bcrypt.CompareHashAndPassword(c.secretHash, []byte(secret)) == nil {
 		return true
 	}
@@ -70,23 +149,34 @@ func (c *Client) VerifyPassword(secret string) bool {
 
 // Token represents an OAuth token implements the oauth2.TokenInfo interface.
 type Token struct {
-	ID                  uuid.UUID  `json:"id"`
-	ClientID            string     `json:"client_id"`
-	UserID              *uuid.UUID `json:"user_id,omitempty"`
-	RedirectURI         string     `json:"redirect_uri,omitempty"`
-	Scope               string     `json:"scope,omitempty"`
-	Code                string     `json:"code,omitempty"`
-	CodeCreatedAt       *time.Time `json:"code_created_at,omitempty"`
-	CodeExpiresIn       int64      `json:"code_expires_in,omitempty"`
-	CodeChallenge       string     `json:"code_challenge,omitempty"`
-	CodeChallengeMethod string     `json:"code_challenge_method,omitempty"`
-	Access              string     `json:"access,omitempty"`
-	AccessCreatedAt     *time.Time `json:"access_created_at,omitempty"`
-	AccessExpiresIn     int64      `json:"access_expires_in,omitempty"`
-	Refresh             string     `json:"refresh,omitempty"`
-	RefreshCreatedAt    *time.Time `json:"refresh_created_at,omitempty"`
-	RefreshExpiresIn    int64      `json:"refresh_expires_in,omitempty"`
-	CreatedAt           time.Time  `json:"created_at"`
+	ID                   uuid.UUID             `json:"id"`
+	ClientID             string                `json:"client_id"`
+	UserID               *uuid.UUID            `json:"user_id,omitempty"`
+	RedirectURI          string                `json:"redirect_uri,omitempty"`
+	Scope                string                `json:"scope,omitempty"`
+	Code                 string                `json:"code,omitempty"`
+	CodeCreatedAt        *time.Time            `json:"code_created_at,omitempty"`
+	CodeExpiresIn        int64                 `json:"code_expires_in,omitempty"`
+	CodeChallenge        string                `json:"code_challenge,omitempty"`
+	CodeChallengeMethod  string                `json:"code_challenge_method,omitempty"`
+	Access               string                `json:"access,omitempty"`
+	AccessCreatedAt      *time.Time            `json:"access_created_at,omitempty"`
+	AccessExpiresIn      int64                 `json:"access_expires_in,omitempty"`
+	Refresh              string                `json:"refresh,omitempty"`
+	RefreshCreatedAt     *time.Time            `json:"refresh_created_at,omitempty"`
+	RefreshExpiresIn     int64                 `json:"refresh_expires_in,omitempty"`
+	Nonce                string                `json:"nonce,omitempty"`
+	AuthTime             *time.Time            `json:"auth_time,omitempty"`
+	ACR                  string                `json:"acr,omitempty"`
+	AMR                  []string              `json:"amr,omitempty"`
+	SID                  string                `json:"sid,omitempty"`
+	FamilyID             uuid.UUID             `json:"family_id"`
+	ParentID             *uuid.UUID            `json:"parent_id,omitempty"`
+	RotatedAt            *time.Time            `json:"rotated_at,omitempty"`
+	DPoPJKT              string                `json:"dpop_jkt,omitempty"`
+	Resources            []string              `json:"resources,omitempty"`
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+	CreatedAt            time.Time             `json:"created_at"`
 }
 
 // NewToken creates a new token instance from a repository token.
@@ -104,6 +194,13 @@ func NewToken(source repository.Token) *Token {
 		AccessExpiresIn:     source.AccessExpiresIn,
 		Refresh:             source.Refresh,
 		RefreshExpiresIn:    source.RefreshExpiresIn,
+		Nonce:               source.Nonce,
+		ACR:                 source.Acr,
+		AMR:                 source.Amr,
+		SID:                 source.Sid,
+		FamilyID:            source.FamilyID,
+		DPoPJKT:             source.DpopJkt,
+		Resources:           source.Resources,
 		CreatedAt:           source.CreatedAt,
 	}
 
@@ -123,6 +220,22 @@ func NewToken(source repository.Token) *Token {
 		t.RefreshCreatedAt = &source.RefreshCreatedAt.Time
 	}
 
+	if source.AuthTime.Valid {
+		t.AuthTime = &source.AuthTime.Time
+	}
+
+	if source.ParentID.Valid {
+		t.ParentID = &source.ParentID.UUID
+	}
+
+	if source.AuthorizationDetails != "" {
+		t.AuthorizationDetails, _ = parseAuthorizationDetails(source.AuthorizationDetails)
+	}
+
+	if source.RotatedAt.Valid {
+		t.RotatedAt = &source.RotatedAt.Time
+	}
+
 	return t
 }
 
@@ -139,6 +252,9 @@ func (t *Token) SetClientID(id string) {
 }
 
 func (t *Token) GetUserID() string {
+	if t.UserID == nil {
+		return ""
+	}
 	return t.UserID.String()
 }
 
@@ -16,6 +16,44 @@ var (
 	ErrMethodNotAllowed   = errors.New("method_not_allowed")
 	ErrInvalidAccessToken = errors.New("invalid_access_token")
 	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInsufficientScope  = errors.New("insufficient_scope")
+
+	// device authorization grant errors,
+	// see: https://www.rfc-editor.org/rfc/rfc8628#section-3.5
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrExpiredToken         = errors.New("expired_token")
+
+	// the user must approve the request, but prompt=none is requested,
+	// see: https://openid.net/specs/openid-connect-core-1_0.html#AuthError
+	ErrConsentRequired = errors.New("consent_required")
+
+	// the requested audience of the exchanged token or the requested resource isn't allowed,
+	// see: https://www.rfc-editor.org/rfc/rfc8693#section-2.2.2
+	// and https://www.rfc-editor.org/rfc/rfc8707#section-2
+	ErrInvalidTarget = errors.New("invalid_target")
+
+	// DPoP errors,
+	// see: https://www.rfc-editor.org/rfc/rfc9449#section-5
+	ErrInvalidDPoPProof = errors.New("invalid_dpop_proof")
+	ErrUseDPoPNonce     = errors.New("use_dpop_nonce")
+
+	// the request_uri of the pushed authorization request is unknown, expired
+	// or issued for another client, see: https://www.rfc-editor.org/rfc/rfc9101#section-7
+	ErrInvalidRequestURI = errors.New("invalid_request_uri")
+
+	// the request object is invalid or isn't signed by the client,
+	// see: https://www.rfc-editor.org/rfc/rfc9101#section-6.3
+	ErrInvalidRequestObject = errors.New("invalid_request_object")
+
+	// the authorization details are malformed, of an unknown type
+	// or rejected by the type validator, see: https://www.rfc-editor.org/rfc/rfc9396#section-5
+	ErrInvalidAuthorizationDetails = errors.New("invalid_authorization_details")
+
+	// the backchannel authentication request errors,
+	// see: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.13
+	ErrUnknownUserID         = errors.New("unknown_user_id")
+	ErrInvalidBindingMessage = errors.New("invalid_binding_message")
 )
 
 // Error codes map
@@ -25,6 +63,21 @@ var ErrorCodes = map[error]int{
 	ErrMethodNotAllowed:   http.StatusMethodNotAllowed,
 	ErrInvalidAccessToken: http.StatusUnauthorized,
 	ErrUnauthorized:       http.StatusUnauthorized,
+	ErrInsufficientScope:  http.StatusForbidden,
+
+	ErrAuthorizationPending: http.StatusBadRequest,
+	ErrSlowDown:             http.StatusBadRequest,
+	ErrExpiredToken:         http.StatusBadRequest,
+	ErrConsentRequired:      http.StatusBadRequest,
+	ErrInvalidTarget:        http.StatusBadRequest,
+	ErrInvalidDPoPProof:     http.StatusBadRequest,
+	ErrUseDPoPNonce:         http.StatusBadRequest,
+	ErrInvalidRequestURI:    http.StatusBadRequest,
+	ErrInvalidRequestObject: http.StatusBadRequest,
+
+	ErrInvalidAuthorizationDetails: http.StatusBadRequest,
+	ErrUnknownUserID:               http.StatusBadRequest,
+	ErrInvalidBindingMessage:       http.StatusBadRequest,
 
 	oauthErrors.ErrInvalidRedirectURI:   http.StatusBadRequest,
 	oauthErrors.ErrInvalidAuthorizeCode: http.StatusBadRequest,
@@ -44,6 +97,21 @@ var ErrorMessages = map[error]string{
 	ErrMethodNotAllowed:   "Method not allowed",
 	ErrInvalidAccessToken: "Missed or invalid access token",
 	ErrUnauthorized:       "Unauthorized",
+	ErrInsufficientScope:  "Access token does not have the required scope",
+
+	ErrAuthorizationPending: "The authorization request is still pending",
+	ErrSlowDown:             "The device is polling too frequently, slow down",
+	ErrExpiredToken:         "The device code has expired",
+	ErrConsentRequired:      "The user consent is required",
+	ErrInvalidTarget:        "The requested audience is not allowed",
+	ErrInvalidDPoPProof:     "The DPoP proof is invalid",
+	ErrUseDPoPNonce:         "The DPoP proof must contain the server nonce",
+	ErrInvalidRequestURI:    "The request_uri is invalid or expired",
+	ErrInvalidRequestObject: "The request object is invalid",
+
+	ErrInvalidAuthorizationDetails: "The authorization details are invalid",
+	ErrUnknownUserID:               "The user identified by the login hint is unknown",
+	ErrInvalidBindingMessage:       "The binding message is invalid or too long",
 
 	oauthErrors.ErrInvalidRedirectURI:   "Invalid redirect uri",
 	oauthErrors.ErrInvalidAuthorizeCode: "Invalid authorize code",
@@ -56,6 +124,14 @@ var ErrorMessages = map[error]string{
 	oauthErrors.ErrInvalidCodeChallenge: "Invalid code challenge",
 }
 
+func init() {
+	// go-oauth2 server renders only the errors it knows as OAuth 2.0 error responses
+	for _, err := range []error{ErrAuthorizationPending, ErrSlowDown, ErrExpiredToken, ErrConsentRequired, ErrInvalidTarget, ErrInvalidDPoPProof, ErrUseDPoPNonce, ErrInvalidRequestURI, ErrInvalidRequestObject, ErrInvalidAuthorizationDetails, ErrUnknownUserID, ErrInvalidBindingMessage} {
+		oauthErrors.Descriptions[err] = ErrorMessages[err]
+		oauthErrors.StatusCodes[err] = ErrorCodes[err]
+	}
+}
+
 // NewError creates a new error
 func NewError(err error) *httpencoder.ErrorResponse {
 	stdErr := findError(err)
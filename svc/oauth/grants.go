@@ -0,0 +1,214 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+type (
+	// GrantHandler handles token requests for a custom grant type.
+	// The client is already authenticated and allowed to use the grant type.
+	GrantHandler interface {
+		// GrantType returns the grant_type parameter value.
+		GrantType() oauth2.GrantType
+		// Token validates the grant and issues the token.
+		Token(ctx context.Context, client oauth2.ClientInfo, tgr *oauth2.TokenGenerateRequest, r *http.Request) (oauth2.TokenInfo, error)
+	}
+
+	// tokenResponseExtender is implemented by the grant handlers
+	// which add the grant specific fields to the token response
+	tokenResponseExtender interface {
+		ExtendTokenResponse(ti oauth2.TokenInfo, data map[string]interface{})
+	}
+
+	// tokenGenerator issues tokens for the custom grant types
+	tokenGenerator interface {
+		GenerateAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error)
+	}
+)
+
+// RegisterGrant adds a custom grant type handler to the server
+// and allows the grant type in the server config.
+func (s *Server) RegisterGrant(h GrantHandler) {
+	if s.grants == nil {
+		s.grants = make(map[oauth2.GrantType]GrantHandler)
+	}
+	s.grants[h.GrantType()] = h
+	s.Config.AllowedGrantTypes = append(s.Config.AllowedGrantTypes, h.GrantType())
+}
+
+// SetDPoPVerifier enables the DPoP proofs on the token endpoint:
+// the tokens requested with the proof are bound to the proof key.
+func (s *Server) SetDPoPVerifier(v *DPoPVerifier) {
+	s.dpop = v
+}
+
+// HandleTokenRequest handles token requests of custom grant types
+// and passes all other requests to the go-oauth2 server.
+// The token response of the request with the DPoP proof has the DPoP token type.
+func (s *Server) HandleTokenRequest(w http.ResponseWriter, r *http.Request) error {
+	jkt, err := s.verifyDPoP(w, r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	gt := oauth2.GrantType(r.FormValue("grant_type"))
+	h, ok := s.grants[gt]
+
+	if err := s.resolveTokenResources(r, gt); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	var ti oauth2.TokenInfo
+	if ok {
+		client, tgr, err := s.authenticateClient(r, gt)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		if err := s.resolveTokenAuthorizationDetails(r, gt, client.GetID()); err != nil {
+			return s.tokenError(w, err)
+		}
+		if ti, err = h.Token(r.Context(), client, tgr, r); err != nil {
+			return s.tokenError(w, err)
+		}
+	} else {
+		gt, tgr, err := s.ValidationTokenRequest(r)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		if err := s.resolveTokenAuthorizationDetails(r, gt, tgr.ClientID); err != nil {
+			return s.tokenError(w, err)
+		}
+		if ti, err = s.GetAccessToken(r.Context(), gt, tgr); err != nil {
+			return s.tokenError(w, err)
+		}
+	}
+
+	data := s.GetTokenData(ti)
+	// the authorization details granted to the access token are returned to the client
+	if meta, ok := TokenMetaFromContext(r.Context()); ok && len(meta.AuthorizationDetails) > 0 {
+		data[authorizationDetailsParam] = meta.AuthorizationDetails
+	}
+	if e, ok := h.(tokenResponseExtender); ok {
+		e.ExtendTokenResponse(ti, data)
+	}
+	if jkt != "" {
+		data["token_type"] = dpop.TokenType
+	}
+
+	return s.token(w, data, http.StatusOK)
+}
+
+// verifyDPoP verifies the DPoP proof of the token request and passes
+// the proof key thumbprint to the token generator, see TokenMeta.
+// The client gets a new server nonce in each response to the request with the proof.
+func (s *Server) verifyDPoP(w http.ResponseWriter, r *http.Request) (string, error) {
+	if s.dpop == nil || r.Header.Get(dpop.HeaderName) == "" {
+		return "", nil
+	}
+	w.Header().Set(dpop.NonceHeaderName, s.dpop.Nonce())
+
+	jkt, err := s.dpop.Verify(r)
+	if err != nil {
+		return "", err
+	}
+
+	if meta, ok := TokenMetaFromContext(r.Context()); ok {
+		meta.DPoPJKT = jkt
+	} else {
+		*r = *r.WithContext(WithTokenMeta(r.Context(), &TokenMeta{DPoPJKT: jkt}))
+	}
+
+	return jkt, nil
+}
+
+// authenticateClient authenticates the client of the token request
+// and checks the client is allowed to use the grant type.
+func (s *Server) authenticateClient(r *http.Request, gt oauth2.GrantType) (oauth2.ClientInfo, *oauth2.TokenGenerateRequest, error) {
+	client, clientSecret, err := s.verifyClient(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientID := client.GetID()
+
+	if fn := s.ClientAuthorizedHandler; fn != nil {
+		allowed, err := fn(clientID, gt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !allowed {
+			return nil, nil, errors.ErrUnauthorizedClient
+		}
+	}
+
+	return client, &oauth2.TokenGenerateRequest{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        r.FormValue("scope"),
+		Request:      r,
+	}, nil
+}
+
+// authenticateConfidentialClient authenticates the client of the introspection request,
+// the public clients are rejected.
+func (s *Server) authenticateConfidentialClient(r *http.Request) (oauth2.ClientInfo, error) {
+	client, _, err := s.verifyClient(r)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := client.(interface{ IsPublic() bool }); ok && c.IsPublic() {
+		return nil, errors.ErrInvalidClient
+	}
+	return client, nil
+}
+
+// verifyClient verifies the client credentials of the POST request
+// with the client authentication method, see ClientAuthenticator.
+func (s *Server) verifyClient(r *http.Request) (oauth2.ClientInfo, string, error) {
+	if r.Method != http.MethodPost {
+		return nil, "", errors.ErrInvalidRequest
+	}
+
+	clientID, clientSecret, err := s.ClientInfoHandler(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := s.manager.GetClient(r.Context(), clientID)
+	if err != nil {
+		return nil, "", errors.ErrInvalidClient
+	}
+	if v, ok := client.(oauth2.ClientPasswordVerifier); ok && !v.VerifyPassword(clientSecret) {
+		return nil, "", errors.ErrInvalidClient
+	}
+
+	return client, clientSecret, nil
+}
+
+func (s *Server) tokenError(w http.ResponseWriter, err error) error {
+	data, statusCode, header := s.GetErrorData(err)
+	for key := range header {
+		w.Header().Set(key, header.Get(key))
+	}
+	return s.token(w, data, statusCode)
+}
+
+func (s *Server) token(w http.ResponseWriter, data interface{}, statusCode int) error {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(data)
+}
+
+// issueToken issues an access token for the custom grant type.
+// go-oauth2 keeps the token config of the grant types private, so custom grants
+// reuse the password grant config: access token with a refresh token.
+func issueToken(ctx context.Context, m tokenGenerator, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+	return m.GenerateAccessToken(ctx, oauth2.PasswordCredentials, tgr)
+}
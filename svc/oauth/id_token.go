@@ -0,0 +1,120 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/golang-jwt/jwt/v5"
+
+	_ "crypto/sha256" // register hash functions
+	_ "crypto/sha512"
+)
+
+// Default ID token lifetime
+const defaultIDTokenTTL = time.Hour
+
+type (
+	// IDTokenGenerator generates signed OpenID Connect ID tokens.
+	IDTokenGenerator struct {
+		issuer string
+		keys   signingKeyProvider
+		ttl    time.Duration
+	}
+
+	// IDTokenClaims represents the ID token claims set.
+	IDTokenClaims struct {
+		jwt.RegisteredClaims
+		Nonce           string `json:"nonce,omitempty"`
+		AuthTime        int64  `json:"auth_time,omitempty"`
+		SessionID       string `json:"sid,omitempty"`
+		AccessTokenHash string `json:"at_hash,omitempty"`
+		Email           string `json:"email,omitempty"`
+		EmailVerified   *bool  `json:"email_verified,omitempty"`
+	}
+)
+
+// NewIDTokenGenerator creates a new ID token generator instance.
+// ID tokens are signed with the active key from the key store.
+func NewIDTokenGenerator(issuer string, keys signingKeyProvider) *IDTokenGenerator {
+	return &IDTokenGenerator{
+		issuer: issuer,
+		keys:   keys,
+		ttl:    defaultIDTokenTTL,
+	}
+}
+
+// Issuer returns the issuer identifier.
+func (g *IDTokenGenerator) Issuer() string {
+	return g.issuer
+}
+
+// Generate returns a signed ID token for the given token info and user.
+// The nonce and the authentication time are taken from the authorization request,
+// the sid identifies the browser session for the front- and back-channel logout.
+func (g *IDTokenGenerator) Generate(ctx context.Context, ti oauth2.TokenInfo, user repository.User, nonce string, authTime *time.Time, sid string) (string, error) {
+	key, err := g.keys.SigningKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	now := time.Now()
+	claims := IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    g.issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{ti.GetClientID()},
+			ExpiresAt: jwt.NewNumericDate(now.Add(g.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce:     nonce,
+		SessionID: sid,
+	}
+
+	if authTime != nil && !authTime.IsZero() {
+		claims.AuthTime = authTime.Unix()
+	}
+
+	if access := ti.GetAccess(); access != "" {
+		hash, err := accessTokenHash(access, key.Algorithm)
+		if err != nil {
+			return "", err
+		}
+		claims.AccessTokenHash = hash
+	}
+
+	if MatchScope(ScopeEmail, ti.GetScope()) {
+		verified := user.VerifiedAt.Valid
+		claims.Email = user.Email
+		claims.EmailVerified = &verified
+	}
+
+	return key.Sign(claims)
+}
+
+// accessTokenHash returns the base64url encoding of the left-most half
+// of the hash of the access token, where the hash algorithm is the one
+// used in the alg header of the ID token.
+func accessTokenHash(access, alg string) (string, error) {
+	var h crypto.Hash
+	switch alg {
+	case "HS256", "RS256", "ES256", "PS256":
+		h = crypto.SHA256
+	case "HS384", "RS384", "ES384", "PS384":
+		h = crypto.SHA384
+	case "HS512", "RS512", "ES512", "PS512", "EdDSA":
+		h = crypto.SHA512
+	default:
+		return "", fmt.Errorf("unsupported id token signing algorithm: %s", alg)
+	}
+
+	hasher := h.New()
+	hasher.Write([]byte(access))
+	sum := hasher.Sum(nil)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
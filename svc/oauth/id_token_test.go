@@ -0,0 +1,255 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/utils"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/keystore"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	idTokenIssuer    = "https://auth.example.com"
+	signingKeySecret = "signing-key-secret"
+)
+
+// signingKeyRepoMock serves the stored signing keys to the key store
+type signingKeyRepoMock struct {
+	*repository.Queries
+	keys []repository.SigningKey
+}
+
+func (m *signingKeyRepoMock) GetSigningKeys(ctx context.Context) ([]repository.SigningKey, error) {
+	return m.keys, nil
+}
+
+// newSigningKeys returns the key store with the active key of the given algorithm
+func newSigningKeys(t *testing.T, alg string) *keystore.Store {
+	t.Helper()
+
+	var (
+		pk  crypto.Signer
+		err error
+	)
+	switch alg {
+	case keystore.AlgRS256:
+		pk, err = rsa.GenerateKey(rand.Reader, 2048)
+	case keystore.AlgES256:
+		pk, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case keystore.AlgEdDSA:
+		_, pk, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := utils.Encrypt(der, []byte(signingKeySecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := keystore.NewStore(&signingKeyRepoMock{keys: []repository.SigningKey{{
+		ID:          "key-" + alg,
+		Algorithm:   alg,
+		PrivateKey:  encrypted,
+		Status:      repository.SigningKeyStatusActive,
+		ActivatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		CreatedAt:   time.Now(),
+	}}}, nil, signingKeySecret, keystore.WithAlgorithm(alg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+type idTokenRepoMock struct {
+	users  map[uuid.UUID]repository.User
+	tokens map[string]repository.Token
+}
+
+func (m *idTokenRepoMock) GetUserByEmail(ctx context.Context, email string) (repository.User, error) {
+	return repository.User{}, sql.ErrNoRows
+}
+
+func (m *idTokenRepoMock) GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error) {
+	if u, ok := m.users[id]; ok {
+		return u, nil
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
+func (m *idTokenRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	return repository.Client{}, sql.ErrNoRows
+}
+
+func (m *idTokenRepoMock) GetTokenByAccess(ctx context.Context, access string) (repository.Token, error) {
+	if t, ok := m.tokens[access]; ok {
+		return t, nil
+	}
+	return repository.Token{}, sql.ErrNoRows
+}
+
+type loggerMock struct {
+	errors []string
+}
+
+func (m *loggerMock) Warnf(format string, args ...interface{}) {}
+
+func (m *loggerMock) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+// parseIDToken verifies the ID token signature and returns its claims and header
+func parseIDToken(t *testing.T, keys *keystore.Store, idToken string) (*oauth.IDTokenClaims, map[string]interface{}) {
+	t.Helper()
+
+	claims := &oauth.IDTokenClaims{}
+	token, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.PublicKey(context.Background(), kid)
+	}, jwt.WithIssuer(idTokenIssuer))
+	if err != nil {
+		t.Fatalf("invalid id token: %v", err)
+	}
+	return claims, token.Header
+}
+
+func TestIDToken_Claims(t *testing.T) {
+	userID := uuid.New()
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	repo := &idTokenRepoMock{
+		users: map[uuid.UUID]repository.User{userID: {ID: userID, Email: "user@example.com"}},
+		tokens: map[string]repository.Token{"access": {
+			Access:   "access",
+			Nonce:    "n-0S6_WzA2Mj",
+			Sid:      "session",
+			AuthTime: sql.NullTime{Time: authTime, Valid: true},
+		}},
+	}
+
+	tests := []struct {
+		alg  string
+		hash crypto.Hash
+	}{
+		{alg: keystore.AlgRS256, hash: crypto.SHA256},
+		{alg: keystore.AlgES256, hash: crypto.SHA256},
+		{alg: keystore.AlgEdDSA, hash: crypto.SHA512},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			keys := newSigningKeys(t, tt.alg)
+			h := oauth.NewHandler(repo, oauth.WithIDTokenGenerator(oauth.NewIDTokenGenerator(idTokenIssuer, keys)))
+
+			fields := h.ExtensionFieldsHandler(&models.Token{ClientID: "web", UserID: userID.String(), Scope: "openid", Access: "access"})
+			idToken, _ := fields["id_token"].(string)
+			if idToken == "" {
+				t.Fatalf("ExtensionFieldsHandler() id_token is not issued")
+			}
+
+			claims, header := parseIDToken(t, keys, idToken)
+			if header["alg"] != tt.alg || header["kid"] != "key-"+tt.alg {
+				t.Errorf("id token header = %v", header)
+			}
+			if claims.Subject != userID.String() || len(claims.Audience) != 1 || claims.Audience[0] != "web" {
+				t.Errorf("id token sub = %s, aud = %v", claims.Subject, claims.Audience)
+			}
+			if claims.Nonce != "n-0S6_WzA2Mj" || claims.SessionID != "session" || claims.AuthTime != authTime.Unix() {
+				t.Errorf("id token nonce = %s, sid = %s, auth_time = %d", claims.Nonce, claims.SessionID, claims.AuthTime)
+			}
+
+			var sum []byte
+			switch tt.hash {
+			case crypto.SHA256:
+				s := sha256.Sum256([]byte("access"))
+				sum = s[:]
+			case crypto.SHA512:
+				s := sha512.Sum512([]byte("access"))
+				sum = s[:]
+			}
+			if want := base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]); claims.AccessTokenHash != want {
+				t.Errorf("id token at_hash = %s, want %s", claims.AccessTokenHash, want)
+			}
+		})
+	}
+}
+
+func TestIDToken_Scope(t *testing.T) {
+	userID := uuid.New()
+	repo := &idTokenRepoMock{
+		users: map[uuid.UUID]repository.User{userID: {
+			ID:         userID,
+			Email:      "user@example.com",
+			VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}},
+		tokens: map[string]repository.Token{"access": {Access: "access"}},
+	}
+	keys := newSigningKeys(t, keystore.AlgES256)
+
+	tests := []struct {
+		name        string
+		token       *models.Token
+		wantIDToken bool
+		wantEmail   bool
+		wantLogged  bool
+	}{
+		{name: "without openid scope", token: &models.Token{ClientID: "web", UserID: userID.String(), Scope: "email", Access: "access"}},
+		{name: "openid scope", token: &models.Token{ClientID: "web", UserID: userID.String(), Scope: "openid", Access: "access"}, wantIDToken: true},
+		{name: "email scope", token: &models.Token{ClientID: "web", UserID: userID.String(), Scope: "openid email", Access: "access"}, wantIDToken: true, wantEmail: true},
+		{name: "unknown user", token: &models.Token{ClientID: "web", UserID: uuid.NewString(), Scope: "openid", Access: "access"}, wantLogged: true},
+		{name: "token is not stored", token: &models.Token{ClientID: "web", UserID: userID.String(), Scope: "openid", Access: "unknown"}, wantLogged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &loggerMock{}
+			h := oauth.NewHandler(repo,
+				oauth.WithIDTokenGenerator(oauth.NewIDTokenGenerator(idTokenIssuer, keys)),
+				oauth.WithHandlerLogger(log),
+			)
+
+			fields := h.ExtensionFieldsHandler(tt.token)
+			idToken, ok := fields["id_token"].(string)
+			if ok != tt.wantIDToken {
+				t.Fatalf("ExtensionFieldsHandler() id_token issued = %v, want %v", ok, tt.wantIDToken)
+			}
+			if logged := len(log.errors) > 0; logged != tt.wantLogged {
+				t.Errorf("ExtensionFieldsHandler() logged errors = %v, want logged %v", log.errors, tt.wantLogged)
+			}
+			if !ok {
+				return
+			}
+
+			claims, _ := parseIDToken(t, keys, idToken)
+			if tt.wantEmail && (claims.Email != "user@example.com" || claims.EmailVerified == nil || !*claims.EmailVerified) {
+				t.Errorf("id token email = %s, email_verified = %v", claims.Email, claims.EmailVerified)
+			}
+			if !tt.wantEmail && (claims.Email != "" || claims.EmailVerified != nil) {
+				t.Errorf("id token has the email claims without the email scope")
+			}
+			if claims.Nonce != "" || claims.AuthTime != 0 {
+				t.Errorf("id token nonce = %s, auth_time = %d, want none", claims.Nonce, claims.AuthTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IntrospectionJWTType is the typ header of the signed introspection response,
+// the client requests it with the application/token-introspection+jwt Accept header.
+// See: https://www.rfc-editor.org/rfc/rfc9701
+const IntrospectionJWTType = "token-introspection+jwt"
+
+// Token type hints of the introspection request,
+// see: https://www.rfc-editor.org/rfc/rfc7662#section-2.1
+const (
+	tokenTypeHintAccessToken  = "access_token"
+	tokenTypeHintRefreshToken = "refresh_token"
+)
+
+type (
+	// TokenIntrospection returns the state of the token to the authenticated caller.
+	// The client the token is issued to can introspect it, as well as the resource servers
+	// the token is aimed at: the resource server introspects with the client linked to it.
+	// The token of another audience is reported as inactive.
+	// The tokens are looked up in the repository, not in the token store:
+	// the introspection doesn't revoke the reused refresh token nor check the DPoP proof.
+	// See: https://www.rfc-editor.org/rfc/rfc7662#section-4
+	TokenIntrospection struct {
+		repo     introspectionRepository
+		issuer   string
+		keys     signingKeyProvider
+		audience string
+	}
+
+	introspectionOption func(i *TokenIntrospection)
+
+	introspectionRepository interface {
+		GetTokenByAccess(ctx context.Context, access string) (repository.Token, error)
+		GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error)
+		GetResourceServersByClientID(ctx context.Context, clientID string) ([]repository.ResourceServer, error)
+	}
+)
+
+// WithIntrospectionAudience sets the audience of the tokens issued without the resource indicators,
+// it must be the same as the default aud claim of the access tokens, see WithAccessTokenAudience.
+func WithIntrospectionAudience(audience string) introspectionOption {
+	return func(i *TokenIntrospection) {
+		i.audience = audience
+	}
+}
+
+// NewTokenIntrospection creates a new token introspection instance.
+// The signed responses are issued by the issuer and signed with the active key from the key store.
+func NewTokenIntrospection(repo introspectionRepository, issuer string, keys signingKeyProvider, opts ...introspectionOption) *TokenIntrospection {
+	i := &TokenIntrospection{
+		repo:   repo,
+		issuer: issuer,
+		keys:   keys,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Introspect returns the introspection response of the token for the caller.
+// The unknown, expired or rotated token and the token the caller may not see are inactive.
+func (i *TokenIntrospection) Introspect(ctx context.Context, callerID, token, tokenTypeHint string) (IntrospectResponse, error) {
+	ti, tokenType, err := i.load(ctx, token, tokenTypeHint)
+	if err != nil || ti == nil {
+		return IntrospectResponse{}, err
+	}
+	// the rotated refresh token is kept only to detect the reuse
+	if tokenType == tokenTypeHintRefreshToken && ti.RotatedAt != nil {
+		return IntrospectResponse{}, nil
+	}
+
+	createdAt, expiresIn := ti.GetAccessCreateAt(), ti.GetAccessExpiresIn()
+	if tokenType == tokenTypeHintRefreshToken {
+		createdAt, expiresIn = ti.GetRefreshCreateAt(), ti.GetRefreshExpiresIn()
+	}
+	expiresAt := createdAt.Add(expiresIn)
+	if expiresIn > 0 && !expiresAt.After(time.Now()) {
+		return IntrospectResponse{}, nil
+	}
+
+	audience, ok, err := i.callerAudience(ctx, callerID, ti)
+	if err != nil || !ok {
+		return IntrospectResponse{}, err
+	}
+
+	resp := IntrospectResponse{
+		Active:    true,
+		Scope:     ti.GetScope(),
+		ClientID:  ti.GetClientID(),
+		UserID:    ti.GetUserID(),
+		TokenType: tokenType,
+		IssuedAt:  createdAt.Unix(),
+		NotBefore: createdAt.Unix(),
+		Subject:   ti.GetUserID(),
+		Audience:  audience,
+		Issuer:    i.issuer,
+	}
+	if expiresIn > 0 {
+		resp.ExpiresAt = expiresAt.Unix()
+	}
+	// the resource server checks the certificate binding of the access token
+	if claims := claimsFromToken(ti.GetAccess()); tokenType == tokenTypeHintAccessToken && claims != nil {
+		resp.Cnf, resp.TokenID = claims.Cnf, claims.ID
+	}
+	if tokenType == tokenTypeHintRefreshToken && ti.DPoPJKT != "" {
+		resp.Cnf = &ConfirmationClaim{JKT: ti.DPoPJKT}
+	}
+	resp.AuthorizationDetails = ti.AuthorizationDetails
+
+	return resp, nil
+}
+
+// Sign returns the introspection response as the JWT aimed at the caller,
+// see: https://www.rfc-editor.org/rfc/rfc9701#section-5
+func (i *TokenIntrospection) Sign(ctx context.Context, callerID string, resp IntrospectResponse) (string, error) {
+	key, err := i.keys.SigningKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	return key.SignWithType(jwt.MapClaims{
+		"iss":                 i.issuer,
+		"aud":                 callerID,
+		"iat":                 time.Now().Unix(),
+		"token_introspection": resp,
+	}, IntrospectionJWTType)
+}
+
+// load looks up the token starting with the type of the hint,
+// the hint doesn't limit the lookup, see: https://www.rfc-editor.org/rfc/rfc7662#section-2.1
+func (i *TokenIntrospection) load(ctx context.Context, token, tokenTypeHint string) (*Token, string, error) {
+	tokenTypes := []string{tokenTypeHintAccessToken, tokenTypeHintRefreshToken}
+	if tokenTypeHint == tokenTypeHintRefreshToken {
+		tokenTypes = []string{tokenTypeHintRefreshToken, tokenTypeHintAccessToken}
+	}
+
+	for _, tokenType := range tokenTypes {
+		var (
+			t   repository.Token
+			err error
+		)
+		if tokenType == tokenTypeHintAccessToken {
+			t, err = i.repo.GetTokenByAccess(ctx, token)
+		} else {
+			t, err = i.repo.GetTokenByRefresh(ctx, token)
+		}
+		if err == nil {
+			return NewToken(t), tokenType, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("failed to get token: %w", err)
+		}
+	}
+
+	return nil, "", nil
+}
+
+// callerAudience returns the token audience reported to the caller:
+// the client sees the token audience, the resource server sees its own identifier.
+// It returns false if the caller may not see the token.
+func (i *TokenIntrospection) callerAudience(ctx context.Context, callerID string, ti *Token) (string, bool, error) {
+	audience := []string{ti.GetClientID()}
+	if len(ti.Resources) > 0 {
+		audience = ti.Resources
+	} else if i.audience != "" {
+		audience = []string{i.audience}
+	}
+
+	if ti.GetClientID() == callerID {
+		return audience[0], true, nil
+	}
+
+	servers, err := i.repo.GetResourceServersByClientID(ctx, callerID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get resource servers by client id: %w", err)
+	}
+	for _, rs := range servers {
+		if contains(audience, rs.Identifier) {
+			return rs.Identifier, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// SetTokenIntrospection enables the token introspection endpoint.
+func (s *Server) SetTokenIntrospection(i *TokenIntrospection) {
+	s.introspection = i
+}
+
+// HandleIntrospectionRequest handles the token introspection request of the confidential client.
+// The client authenticates the same way as on the token endpoint.
+// The response is signed if the client accepts the JWT response.
+func (s *Server) HandleIntrospectionRequest(w http.ResponseWriter, r *http.Request) error {
+	if s.introspection == nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	if err := r.ParseForm(); err != nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+
+	client, err := s.authenticateConfidentialClient(r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.introspection.Introspect(r.Context(), client.GetID(), token, r.PostForm.Get("token_type_hint"))
+	if err != nil {
+		return err
+	}
+
+	if !acceptsIntrospectionJWT(r) {
+		return s.token(w, resp, http.StatusOK)
+	}
+
+	signed, err := s.introspection.Sign(r.Context(), client.GetID(), resp)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/"+IntrospectionJWTType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte(signed))
+	return err
+}
+
+// acceptsIntrospectionJWT checks the client requests the signed introspection response
+func acceptsIntrospectionJWT(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == "application/"+IntrospectionJWTType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+)
+
+type introspectionRepoMock struct {
+	tokens  []repository.Token
+	servers map[string][]repository.ResourceServer
+}
+
+func (m *introspectionRepoMock) find(match func(t repository.Token) bool) (repository.Token, error) {
+	for _, t := range m.tokens {
+		if match(t) {
+			return t, nil
+		}
+	}
+	return repository.Token{}, sql.ErrNoRows
+}
+
+func (m *introspectionRepoMock) GetTokenByAccess(ctx context.Context, access string) (repository.Token, error) {
+	return m.find(func(t repository.Token) bool { return t.Access == access })
+}
+
+func (m *introspectionRepoMock) GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error) {
+	return m.find(func(t repository.Token) bool { return t.Refresh == refresh })
+}
+
+func (m *introspectionRepoMock) GetResourceServersByClientID(ctx context.Context, clientID string) ([]repository.ResourceServer, error) {
+	return m.servers[clientID], nil
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	expired := sql.NullTime{Time: time.Now().Add(-2 * time.Hour), Valid: true}
+	repo := &introspectionRepoMock{
+		servers: map[string][]repository.ResourceServer{
+			"orders-api":  {{Identifier: "https://api.example.com", ClientID: "orders-api"}},
+			"billing-api": {{Identifier: "https://billing.example.com", ClientID: "billing-api"}},
+		},
+		tokens: []repository.Token{
+			{ClientID: "client", Access: "access-1", AccessCreatedAt: now, AccessExpiresIn: 3600, Refresh: "refresh-1", RefreshCreatedAt: now, Resources: []string{"https://api.example.com"}},
+			{ClientID: "client", Access: "access-2", AccessCreatedAt: now},
+			{ClientID: "client", Access: "expired", AccessCreatedAt: expired, AccessExpiresIn: 3600},
+			{ClientID: "client", Refresh: "rotated", RefreshCreatedAt: now, RotatedAt: now},
+			{ClientID: "public", Access: "access-dpop", AccessCreatedAt: now, Refresh: "refresh-dpop", RefreshCreatedAt: now, DpopJkt: "thumbprint"},
+		},
+	}
+	ti := oauth.NewTokenIntrospection(
+		repo,
+		"https://auth.example.com",
+		nil,
+		oauth.WithIntrospectionAudience("https://default.example.com"),
+	)
+
+	tests := []struct {
+		name         string
+		callerID     string
+		token        string
+		hint         string
+		wantActive   bool
+		wantAudience string
+		wantType     string
+	}{
+		{name: "token client", callerID: "client", token: "access-1", wantActive: true, wantAudience: "https://api.example.com", wantType: "access_token"},
+		{name: "refresh token", callerID: "client", token: "refresh-1", hint: "refresh_token", wantActive: true, wantAudience: "https://api.example.com", wantType: "refresh_token"},
+		{name: "resource server of the audience", callerID: "orders-api", token: "access-1", wantActive: true, wantAudience: "https://api.example.com", wantType: "access_token"},
+		{name: "resource server of another audience", callerID: "billing-api", token: "access-1"},
+		{name: "another client", callerID: "another-client", token: "access-1"},
+		{name: "default audience", callerID: "client", token: "access-2", wantActive: true, wantAudience: "https://default.example.com", wantType: "access_token"},
+		{name: "expired token", callerID: "client", token: "expired"},
+		{name: "unknown token", callerID: "client", token: "unknown"},
+		{name: "rotated refresh token", callerID: "client", token: "rotated", hint: "refresh_token"},
+		{name: "dpop-bound refresh token without proof", callerID: "public", token: "refresh-dpop", hint: "refresh_token", wantActive: true, wantAudience: "https://default.example.com", wantType: "refresh_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ti.Introspect(context.Background(), tt.callerID, tt.token, tt.hint)
+			if err != nil {
+				t.Fatalf("Introspect() error = %v", err)
+			}
+			if resp.Active != tt.wantActive || resp.Audience != tt.wantAudience || resp.TokenType != tt.wantType {
+				t.Errorf("Introspect() = %+v, want active %v, aud %q, token type %q", resp, tt.wantActive, tt.wantAudience, tt.wantType)
+			}
+		})
+	}
+
+	// the introspection has no side effects on the tokens
+	if len(repo.tokens) != 5 {
+		t.Errorf("Introspect() left %d tokens, want 5", len(repo.tokens))
+	}
+	if resp, _ := ti.Introspect(context.Background(), "public", "refresh-dpop", ""); resp.Cnf == nil || resp.Cnf.JKT != "thumbprint" {
+		t.Errorf("Introspect() cnf = %+v, want the DPoP key thumbprint", resp.Cnf)
+	}
+}
@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Default JWT secured authorization response lifetime
+const defaultResponseTTL = 10 * time.Minute
+
+// ResponseSigner signs the authorization responses returned
+// in the jwt response modes with the active key from the key store.
+// See: https://openid.net/specs/oauth-v2-jarm.html
+type ResponseSigner struct {
+	issuer string
+	keys   signingKeyProvider
+	ttl    time.Duration
+}
+
+// NewResponseSigner creates a new authorization response signer instance.
+func NewResponseSigner(issuer string, keys signingKeyProvider) *ResponseSigner {
+	return &ResponseSigner{
+		issuer: issuer,
+		keys:   keys,
+		ttl:    defaultResponseTTL,
+	}
+}
+
+// Sign returns the JWT containing the authorization response parameters
+// aimed at the client.
+func (s *ResponseSigner) Sign(ctx context.Context, clientID string, params map[string]interface{}) (string, error) {
+	key, err := s.keys.SigningKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	claims := make(jwt.MapClaims, len(params)+3)
+	for k, v := range params {
+		claims[k] = v
+	}
+	claims["iss"] = s.issuer
+	claims["aud"] = clientID
+	claims["exp"] = time.Now().Add(s.ttl).Unix()
+
+	return key.Sign(claims)
+}
+
+// SetResponseSigner enables the jwt response modes of the authorization endpoint.
+func (s *Server) SetResponseSigner(signer *ResponseSigner) {
+	s.jarm = signer
+}
@@ -0,0 +1,170 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// JWTBearerGrantType is the grant type of the JWT bearer authorization grant.
+// See: https://www.rfc-editor.org/rfc/rfc7523#section-2.1
+const JWTBearerGrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// Subject types of the trusted issuer: the assertion subject is the local user id or email.
+const (
+	SubjectTypeID    = "id"
+	SubjectTypeEmail = "email"
+)
+
+type (
+	// JWTBearerGrant implements the JWT bearer authorization grant.
+	// The client passes the JWT issued by a trusted issuer for the user,
+	// the assertion subject is mapped to the local user according to the issuer subject type.
+	JWTBearerGrant struct {
+		repo     jwtBearerRepository
+		tokens   tokenGenerator
+		audience []string
+		replay   replayCache
+	}
+
+	// JWTBearerGrantOption is a function that configures a JWTBearerGrant.
+	JWTBearerGrantOption func(g *JWTBearerGrant)
+
+	jwtBearerRepository interface {
+		GetTrustedIssuer(ctx context.Context, issuer string) (repository.TrustedIssuer, error)
+		GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error)
+		GetUserByEmail(ctx context.Context, email string) (repository.User, error)
+	}
+)
+
+// WithJWTBearerReplayCache sets the storage of the used assertion IDs.
+// The assertions are rejected without it, since the replay can't be detected.
+func WithJWTBearerReplayCache(c replayCache) JWTBearerGrantOption {
+	return func(g *JWTBearerGrant) {
+		g.replay = c
+	}
+}
+
+// NewJWTBearerGrant creates a new JWT bearer grant handler.
+// The audience is the list of accepted assertion audiences,
+// e.g. the token endpoint URL and the issuer identifier.
+func NewJWTBearerGrant(repo jwtBearerRepository, tokens tokenGenerator, audience []string, opts ...JWTBearerGrantOption) *JWTBearerGrant {
+	g := &JWTBearerGrant{
+		repo:     repo,
+		tokens:   tokens,
+		audience: audience,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// GrantType returns the JWT bearer grant type.
+func (g *JWTBearerGrant) GrantType() oauth2.GrantType {
+	return JWTBearerGrantType
+}
+
+// Token verifies the assertion with the trusted issuer keys
+// and issues the token to the user identified by the assertion subject.
+// See: https://www.rfc-editor.org/rfc/rfc7523#section-3
+func (g *JWTBearerGrant) Token(ctx context.Context, client oauth2.ClientInfo, tgr *oauth2.TokenGenerateRequest, r *http.Request) (oauth2.TokenInfo, error) {
+	assertion := r.FormValue("assertion")
+	if assertion == "" {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+
+	// the issuer is read before the signature is verified to find its keys
+	unverified := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, unverified); err != nil || unverified.Issuer == "" {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	issuer, err := g.repo.GetTrustedIssuer(ctx, unverified.Issuer)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthErrors.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("failed to get trusted issuer: %w", err)
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	if _, err := jwt.ParseWithClaims(assertion, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return publicKeyFromJWKS(issuer.Jwks, kid)
+	},
+		jwt.WithValidMethods(privateKeyJWTAlgs),
+		jwt.WithIssuer(issuer.Issuer),
+	); err != nil {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+	if claims.ExpiresAt == nil || claims.Subject == "" || claims.ID == "" || !hasAudience(claims.Audience, g.audience) {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	if g.replay == nil {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+	ok, err := g.replay.Use(ctx, issuer.Issuer+":"+claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check assertion replay: %w", err)
+	}
+	if !ok {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	user, err := g.user(ctx, issuer.SubjectType, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := tgr.Scope
+	if scope == "" {
+		scope = issuer.Scope
+	}
+	if scope != "" && !MatchScopesStrict(scope, issuer.Scope) {
+		return nil, oauthErrors.ErrInvalidScope
+	}
+
+	tgr.UserID = user.ID.String()
+	tgr.Scope = scope
+
+	return issueToken(ctx, g.tokens, tgr)
+}
+
+// user returns the local user identified by the assertion subject
+func (g *JWTBearerGrant) user(ctx context.Context, subjectType, subject string) (repository.User, error) {
+	var user repository.User
+	var err error
+	switch subjectType {
+	case SubjectTypeID:
+		id, perr := uuid.Parse(subject)
+		if perr != nil {
+			return repository.User{}, oauthErrors.ErrInvalidGrant
+		}
+		user, err = g.repo.GetUserByID(ctx, id)
+	case SubjectTypeEmail:
+		user, err = g.repo.GetUserByEmail(ctx, strings.TrimSpace(strings.ToLower(subject)))
+	default:
+		return repository.User{}, fmt.Errorf("unknown subject type of the trusted issuer: %s", subjectType)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.User{}, oauthErrors.ErrInvalidGrant
+		}
+		return repository.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,183 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type jwtBearerRepoMock struct {
+	issuers map[string]repository.TrustedIssuer
+	users   []repository.User
+}
+
+func (m *jwtBearerRepoMock) GetTrustedIssuer(ctx context.Context, issuer string) (repository.TrustedIssuer, error) {
+	i, ok := m.issuers[issuer]
+	if !ok {
+		return repository.TrustedIssuer{}, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (m *jwtBearerRepoMock) GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error) {
+	for _, u := range m.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
+func (m *jwtBearerRepoMock) GetUserByEmail(ctx context.Context, email string) (repository.User, error) {
+	for _, u := range m.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
+func TestJWTBearerGrant(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.NewKey("partner-key", "ES256", pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := repository.User{ID: uuid.New(), Email: "user@example.com"}
+	repo := &jwtBearerRepoMock{
+		issuers: map[string]repository.TrustedIssuer{
+			"https://partner.example.com": {Issuer: "https://partner.example.com", Jwks: string(jwks), SubjectType: oauth.SubjectTypeEmail, Scope: "user:read"},
+			"https://idp.example.com":     {Issuer: "https://idp.example.com", Jwks: string(jwks), SubjectType: oauth.SubjectTypeID, Scope: "user:read"},
+		},
+		users: []repository.User{user},
+	}
+	grant := oauth.NewJWTBearerGrant(repo, &tokenExchangerMock{}, []string{testTokenEndpoint}, oauth.WithJWTBearerReplayCache(replayCacheMock{}))
+
+	sign := func(key *ecdsa.PrivateKey, claims jwt.RegisteredClaims) string {
+		if claims.ExpiresAt == nil {
+			claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+		}
+		if claims.Audience == nil {
+			claims.Audience = jwt.ClaimStrings{testTokenEndpoint}
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "partner-key"
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	validAssertion := sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: "User@example.com", ID: "1"})
+
+	tests := []struct {
+		name      string
+		assertion string
+		scope     string
+		wantErr   error
+	}{
+		{
+			name:      "subject is mapped by email",
+			assertion: validAssertion,
+		},
+		{
+			name:      "replayed assertion",
+			assertion: validAssertion,
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "subject is mapped by id",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://idp.example.com", Subject: user.ID.String(), ID: "1"}),
+		},
+		{
+			name:      "untrusted issuer",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://evil.example.com", Subject: user.Email, ID: "2"}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "signed with unknown key",
+			assertion: sign(otherKey, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email, ID: "3"}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "wrong audience",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email, ID: "4", Audience: jwt.ClaimStrings{"https://other.example.com"}}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "expired assertion",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email, ID: "5", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "unknown user",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: "unknown@example.com", ID: "6"}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "assertion without jti",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email}),
+			wantErr:   oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:      "scope is not allowed for the issuer",
+			assertion: sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email, ID: "7"}),
+			scope:     "user:write",
+			wantErr:   oauth2Errors.ErrInvalidScope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"grant_type": {string(oauth.JWTBearerGrantType)}, "assertion": {tt.assertion}}
+			r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			ti, err := grant.Token(r.Context(), &oauth.Client{ID: "partner"}, &oauth2.TokenGenerateRequest{ClientID: "partner", Scope: tt.scope}, r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Token() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (ti.GetUserID() != user.ID.String() || ti.GetScope() != "user:read") {
+				t.Errorf("Token() user = %s, scope = %s; want %s, user:read", ti.GetUserID(), ti.GetScope(), user.ID)
+			}
+		})
+	}
+
+	t.Run("without replay cache", func(t *testing.T) {
+		grant := oauth.NewJWTBearerGrant(repo, &tokenExchangerMock{}, []string{testTokenEndpoint})
+		form := url.Values{"grant_type": {string(oauth.JWTBearerGrantType)}, "assertion": {sign(pk, jwt.RegisteredClaims{Issuer: "https://partner.example.com", Subject: user.Email, ID: "8"})}}
+		r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if _, err := grant.Token(r.Context(), &oauth.Client{ID: "partner"}, &oauth2.TokenGenerateRequest{ClientID: "partner"}, r); !errors.Is(err, oauth2Errors.ErrInvalidGrant) {
+			t.Errorf("Token() error = %v, want %v", err, oauth2Errors.ErrInvalidGrant)
+		}
+	})
+}
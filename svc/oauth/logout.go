@@ -0,0 +1,457 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/session"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/keystore"
+	"github.com/foolin/goview"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// LogoutTokenJWTType is the typ header of the back-channel logout tokens,
+// see: https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+const LogoutTokenJWTType = "logout+jwt"
+
+// BackchannelLogoutEvent is the event the logout token is issued for.
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// Logout form parameters
+const (
+	logoutConfirmParam   = "confirm"
+	logoutCSRFTokenParam = "csrf_token"
+)
+
+// Default logout token lifetime, the token is delivered right after the logout
+const defaultLogoutTokenTTL = 2 * time.Minute
+
+type (
+	// Logout implements the OpenID Connect RP-initiated logout.
+	// The clients the user has signed in to within the browser session are notified
+	// with the front-channel logout iframes and the back-channel logout tokens,
+	// the tokens issued within the session are revoked.
+	// See: https://openid.net/specs/openid-connect-rpinitiated-1_0.html
+	Logout struct {
+		repo     logoutRepository
+		issuer   string
+		keys     publicKeyProvider
+		notifier BackchannelLogoutNotifier
+		log      logger
+	}
+
+	logoutOption func(l *Logout)
+
+	logoutRepository interface {
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+		DeleteTokensBySID(ctx context.Context, sid string) (int64, error)
+	}
+
+	publicKeyProvider interface {
+		PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+	}
+
+	// BackchannelLogoutNotifier delivers the logout token to the client
+	// back-channel logout uri, e.g. directly or with the background task.
+	BackchannelLogoutNotifier interface {
+		NotifyBackchannelLogout(ctx context.Context, n BackchannelLogoutNotification) error
+	}
+
+	// BackchannelLogoutNotification is the logout of the user the client is notified about.
+	BackchannelLogoutNotification struct {
+		ClientID  string `json:"client_id"`
+		UserID    string `json:"user_id"`
+		SessionID string `json:"sid,omitempty"`
+	}
+
+	// LogoutRequest represents the parameters of the RP-initiated logout request.
+	// Subject is the user the id_token_hint is issued for.
+	// See: https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+	LogoutRequest struct {
+		IDTokenHint           string
+		ClientID              string
+		PostLogoutRedirectURI string
+		State                 string
+		Subject               string
+	}
+
+	// BackchannelLogout signs the logout tokens and delivers them
+	// to the client back-channel logout uri.
+	// See: https://openid.net/specs/openid-connect-backchannel-1_0.html
+	BackchannelLogout struct {
+		repo       backchannelLogoutRepository
+		issuer     string
+		keys       signingKeyProvider
+		ttl        time.Duration
+		httpClient *http.Client
+	}
+
+	backchannelLogoutOption func(b *BackchannelLogout)
+
+	backchannelLogoutRepository interface {
+		GetClientByID(ctx context.Context, id string) (repository.Client, error)
+	}
+
+	// LogoutTokenClaims represents the logout token claims set.
+	LogoutTokenClaims struct {
+		jwt.RegisteredClaims
+		SessionID string              `json:"sid,omitempty"`
+		Events    map[string]struct{} `json:"events"`
+	}
+)
+
+// WithLogoutLogger sets the logger of the failed client notifications.
+func WithLogoutLogger(log logger) logoutOption {
+	return func(l *Logout) {
+		l.log = log
+	}
+}
+
+// NewLogout creates a new RP-initiated logout instance.
+// The id_token_hint is verified with the server keys,
+// the back-channel logout clients are notified with the notifier,
+// e.g. BackchannelLogout or LogoutEnqueuer to retry the failed deliveries in the background.
+func NewLogout(repo logoutRepository, issuer string, keys publicKeyProvider, notifier BackchannelLogoutNotifier, opts ...logoutOption) *Logout {
+	l := &Logout{
+		repo:     repo,
+		issuer:   issuer,
+		keys:     keys,
+		notifier: notifier,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// VerifyRequest validates the logout request and returns it with the client and the subject
+// resolved from the id_token_hint. The expired ID token is accepted as the hint.
+// The post_logout_redirect_uri must exactly match one of the uris registered by the client.
+func (l *Logout) VerifyRequest(ctx context.Context, req LogoutRequest) (LogoutRequest, error) {
+	if req.IDTokenHint != "" {
+		claims := &IDTokenClaims{}
+		if _, err := jwt.ParseWithClaims(req.IDTokenHint, claims, func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			return l.keys.PublicKey(ctx, kid)
+		},
+			jwt.WithValidMethods([]string{keystore.AlgRS256, keystore.AlgES256, keystore.AlgEdDSA}),
+			jwt.WithoutClaimsValidation(),
+		); err != nil {
+			return req, oauthErrors.ErrInvalidRequest
+		}
+		if claims.Issuer != l.issuer || claims.Subject == "" || len(claims.Audience) == 0 {
+			return req, oauthErrors.ErrInvalidRequest
+		}
+
+		// the client_id must be the audience of the ID token if both are passed
+		if req.ClientID == "" {
+			req.ClientID = claims.Audience[0]
+		} else if !contains(claims.Audience, req.ClientID) {
+			return req, oauthErrors.ErrInvalidRequest
+		}
+		req.Subject = claims.Subject
+	}
+
+	if req.PostLogoutRedirectURI == "" {
+		return req, nil
+	}
+	if req.ClientID == "" {
+		return req, oauthErrors.ErrInvalidRequest
+	}
+
+	client, err := l.repo.GetClientByID(ctx, req.ClientID)
+	if err != nil {
+		return req, oauthErrors.ErrInvalidClient
+	}
+	if !contains(client.PostLogoutRedirectUris, req.PostLogoutRedirectURI) {
+		return req, oauthErrors.ErrInvalidRedirectURI
+	}
+
+	return req, nil
+}
+
+// Logout revokes the tokens issued within the browser session and notifies
+// the clients the user has signed in to. It returns the front-channel logout uris
+// to render in the iframes, the back-channel logout clients are notified with the notifier.
+// The failed notifications are logged, since the user is logged out anyway.
+func (l *Logout) Logout(ctx context.Context, userID, sid string, clientIDs []string) ([]string, error) {
+	if sid != "" {
+		if _, err := l.repo.DeleteTokensBySID(ctx, sid); err != nil {
+			return nil, fmt.Errorf("failed to delete tokens by sid: %w", err)
+		}
+	}
+
+	frontchannelURIs := make([]string, 0, len(clientIDs))
+	for _, clientID := range clientIDs {
+		client, err := l.repo.GetClientByID(ctx, clientID)
+		if err != nil {
+			l.logError(clientID, fmt.Errorf("failed to get client by id: %w", err))
+			continue
+		}
+
+		if client.FrontchannelLogoutUri != "" {
+			uri, err := frontchannelLogoutURI(client.FrontchannelLogoutUri, l.issuer, sid)
+			if err != nil {
+				l.logError(clientID, err)
+			} else {
+				frontchannelURIs = append(frontchannelURIs, uri)
+			}
+		}
+
+		if client.BackchannelLogoutUri != "" && l.notifier != nil {
+			if err := l.notifier.NotifyBackchannelLogout(ctx, BackchannelLogoutNotification{
+				ClientID:  client.ID,
+				UserID:    userID,
+				SessionID: sid,
+			}); err != nil {
+				l.logError(clientID, err)
+			}
+		}
+	}
+
+	return frontchannelURIs, nil
+}
+
+// logError reports the failed notification of the client
+func (l *Logout) logError(clientID string, err error) {
+	if l.log != nil {
+		l.log.Warnf("logout notification: client_id=%s: %v", clientID, err)
+	}
+}
+
+// RedirectURI returns the post logout redirect uri with the state,
+// the empty string is returned if the uri isn't requested.
+func (req LogoutRequest) RedirectURI() string {
+	if req.PostLogoutRedirectURI == "" {
+		return ""
+	}
+
+	u, err := url.Parse(req.PostLogoutRedirectURI)
+	if err != nil {
+		return ""
+	}
+	if req.State != "" {
+		q := u.Query()
+		q.Set("state", req.State)
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String()
+}
+
+// frontchannelLogoutURI returns the client front-channel logout uri with the issuer and the session ID,
+// see: https://openid.net/specs/openid-connect-frontchannel-1_0.html#RPLogout
+func frontchannelLogoutURI(logoutURI, issuer, sid string) (string, error) {
+	u, err := url.Parse(logoutURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse front-channel logout uri: %w", err)
+	}
+	if sid != "" {
+		q := u.Query()
+		q.Set("iss", issuer)
+		q.Set("sid", sid)
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// WithBackchannelLogoutTTL sets the lifetime of the logout tokens.
+func WithBackchannelLogoutTTL(ttl time.Duration) backchannelLogoutOption {
+	return func(b *BackchannelLogout) {
+		b.ttl = ttl
+	}
+}
+
+// WithBackchannelLogoutHTTPClient sets the http client used to deliver the logout tokens.
+func WithBackchannelLogoutHTTPClient(c *http.Client) backchannelLogoutOption {
+	return func(b *BackchannelLogout) {
+		if c != nil {
+			b.httpClient = c
+		}
+	}
+}
+
+// NewBackchannelLogout creates a new back-channel logout instance.
+// The logout tokens are signed with the active key from the key store.
+func NewBackchannelLogout(repo backchannelLogoutRepository, issuer string, keys signingKeyProvider, opts ...backchannelLogoutOption) *BackchannelLogout {
+	b := &BackchannelLogout{
+		repo:       repo,
+		issuer:     issuer,
+		keys:       keys,
+		ttl:        defaultLogoutTokenTTL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// LogoutToken returns the signed logout token of the user session for the client.
+// See: https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+func (b *BackchannelLogout) LogoutToken(ctx context.Context, n BackchannelLogoutNotification) (string, error) {
+	key, err := b.keys.SigningKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	now := time.Now()
+	return key.SignWithType(LogoutTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    b.issuer,
+			Subject:   n.UserID,
+			Audience:  jwt.ClaimStrings{n.ClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(b.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+		SessionID: n.SessionID,
+		Events:    map[string]struct{}{BackchannelLogoutEvent: {}},
+	}, LogoutTokenJWTType)
+}
+
+// NotifyBackchannelLogout posts the logout token to the client back-channel logout uri.
+// The error is returned if the client doesn't confirm the logout, so the delivery can be retried.
+func (b *BackchannelLogout) NotifyBackchannelLogout(ctx context.Context, n BackchannelLogoutNotification) error {
+	client, err := b.repo.GetClientByID(ctx, n.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client by id: %w", err)
+	}
+	if client.BackchannelLogoutUri == "" {
+		return nil
+	}
+
+	token, err := b.LogoutToken(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.BackchannelLogoutUri,
+		strings.NewReader(url.Values{"logout_token": {token}}.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create back-channel logout request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send back-channel logout request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("back-channel logout request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SetLogout enables the RP-initiated logout endpoint.
+func (s *Server) SetLogout(l *Logout) {
+	s.logout = l
+}
+
+// HandleLogoutRequest handles the RP-initiated logout request.
+// The user is logged out at once if the id_token_hint is issued for the logged in user,
+// otherwise the logout must be confirmed, so a third party can't log the user out.
+// The clients are notified and the user is redirected to the post logout redirect uri.
+func (s *Server) HandleLogoutRequest(w http.ResponseWriter, r *http.Request) error {
+	if s.logout == nil {
+		return ErrInvalidRequest
+	}
+	if err := r.ParseForm(); err != nil {
+		return ErrInvalidRequest
+	}
+
+	req, err := s.logout.VerifyRequest(r.Context(), LogoutRequest{
+		IDTokenHint:           r.Form.Get("id_token_hint"),
+		ClientID:              r.Form.Get("client_id"),
+		PostLogoutRedirectURI: r.Form.Get("post_logout_redirect_uri"),
+		State:                 r.Form.Get("state"),
+	})
+	if err != nil {
+		return err
+	}
+
+	userID, ok := session.GetLoggedInUserID(r, w)
+	if !ok {
+		return renderLogout(w, r, req, nil)
+	}
+
+	confirmed := req.Subject == userID
+	if !confirmed && r.Method == http.MethodPost && r.PostForm.Get(logoutConfirmParam) != "" {
+		if !session.VerifyCSRFToken(r, w, r.PostForm.Get(logoutCSRFTokenParam)) {
+			return ErrInvalidRequest
+		}
+		confirmed = true
+	}
+	if !confirmed {
+		return renderLogoutConfirmation(w, r)
+	}
+
+	sid, _ := session.GetSessionID(r, w)
+	clientIDs := session.GetSignedInClients(r, w)
+	if err := session.Logout(r, w); err != nil {
+		return err
+	}
+
+	frontchannelURIs, err := s.logout.Logout(r.Context(), userID, sid, clientIDs)
+	if err != nil {
+		return err
+	}
+
+	return renderLogout(w, r, req, frontchannelURIs)
+}
+
+// renderLogoutConfirmation asks the user to confirm the logout,
+// the logout request parameters are resubmitted with the form
+func renderLogoutConfirmation(w http.ResponseWriter, r *http.Request) error {
+	csrfToken, err := session.StoreCSRFToken(r, w)
+	if err != nil {
+		return err
+	}
+
+	params := make(map[string][]string, len(r.Form))
+	for key, values := range r.Form {
+		if key != logoutConfirmParam && key != logoutCSRFTokenParam {
+			params[key] = values
+		}
+	}
+
+	return goview.Render(w, http.StatusOK, "logout", map[string]interface{}{
+		"page_title": "Sign out",
+		"confirm":    true,
+		"csrf_token": csrfToken,
+		"params":     params,
+		"action":     r.URL.Path,
+	})
+}
+
+// renderLogout renders the page which loads the front-channel logout uris in the iframes
+// and redirects the user to the post logout redirect uri.
+// The user is redirected at once if there is no front-channel logout client.
+func renderLogout(w http.ResponseWriter, r *http.Request, req LogoutRequest, frontchannelURIs []string) error {
+	redirectURI := req.RedirectURI()
+	if redirectURI != "" && len(frontchannelURIs) == 0 {
+		http.Redirect(w, r, redirectURI, http.StatusFound)
+		return nil
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	return goview.Render(w, http.StatusOK, "logout", map[string]interface{}{
+		"page_title":        "Signed out",
+		"frontchannel_uris": frontchannelURIs,
+		"redirect_uri":      redirectURI,
+	})
+}
@@ -0,0 +1,164 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const logoutIssuer = "https://auth.example.com"
+
+type logoutRepoMock struct {
+	clients     map[string]repository.Client
+	revokedSIDs []string
+}
+
+func (m *logoutRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	if c, ok := m.clients[id]; ok {
+		return c, nil
+	}
+	return repository.Client{}, sql.ErrNoRows
+}
+
+func (m *logoutRepoMock) DeleteTokensBySID(ctx context.Context, sid string) (int64, error) {
+	m.revokedSIDs = append(m.revokedSIDs, sid)
+	return 1, nil
+}
+
+type logoutKeysMock struct {
+	keys map[string]crypto.PublicKey
+}
+
+func (m logoutKeysMock) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if k, ok := m.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, errors.New("key not found")
+}
+
+type logoutNotifierMock struct {
+	notifications []oauth.BackchannelLogoutNotification
+}
+
+func (m *logoutNotifierMock) NotifyBackchannelLogout(ctx context.Context, n oauth.BackchannelLogoutNotification) error {
+	m.notifications = append(m.notifications, n)
+	return nil
+}
+
+func newLogoutRepo() *logoutRepoMock {
+	return &logoutRepoMock{
+		clients: map[string]repository.Client{
+			"web": {
+				ID:                     "web",
+				PostLogoutRedirectUris: []string{"https://web.example.com/logged-out"},
+				FrontchannelLogoutUri:  "https://web.example.com/logout?lang=en",
+			},
+			"api": {
+				ID:                   "api",
+				BackchannelLogoutUri: "https://api.example.com/logout",
+			},
+		},
+	}
+}
+
+func TestLogout_VerifyRequest(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idToken := func(key *ecdsa.PrivateKey, iss, aud string, exp time.Time) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+			Issuer:    iss,
+			Subject:   "user-1",
+			Audience:  jwt.ClaimStrings{aud},
+			ExpiresAt: jwt.NewNumericDate(exp),
+		})
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	valid := idToken(pk, logoutIssuer, "web", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		req         oauth.LogoutRequest
+		wantErr     error
+		wantClient  string
+		wantSubject string
+	}{
+		{name: "no hint", req: oauth.LogoutRequest{}},
+		{name: "valid hint", req: oauth.LogoutRequest{IDTokenHint: valid}, wantClient: "web", wantSubject: "user-1"},
+		{name: "expired hint", req: oauth.LogoutRequest{IDTokenHint: idToken(pk, logoutIssuer, "web", time.Now().Add(-time.Hour))}, wantClient: "web", wantSubject: "user-1"},
+		{name: "hint signed with another key", req: oauth.LogoutRequest{IDTokenHint: idToken(otherKey, logoutIssuer, "web", time.Now().Add(time.Hour))}, wantErr: oauth2Errors.ErrInvalidRequest},
+		{name: "hint of another issuer", req: oauth.LogoutRequest{IDTokenHint: idToken(pk, "https://evil.example.com", "web", time.Now().Add(time.Hour))}, wantErr: oauth2Errors.ErrInvalidRequest},
+		{name: "client isn't the hint audience", req: oauth.LogoutRequest{IDTokenHint: valid, ClientID: "api"}, wantErr: oauth2Errors.ErrInvalidRequest},
+		{name: "registered redirect uri", req: oauth.LogoutRequest{IDTokenHint: valid, PostLogoutRedirectURI: "https://web.example.com/logged-out"}, wantClient: "web", wantSubject: "user-1"},
+		{name: "redirect uri with client id", req: oauth.LogoutRequest{ClientID: "web", PostLogoutRedirectURI: "https://web.example.com/logged-out"}, wantClient: "web"},
+		{name: "unregistered redirect uri", req: oauth.LogoutRequest{IDTokenHint: valid, PostLogoutRedirectURI: "https://evil.example.com"}, wantErr: oauth2Errors.ErrInvalidRedirectURI},
+		{name: "redirect uri without client", req: oauth.LogoutRequest{PostLogoutRedirectURI: "https://web.example.com/logged-out"}, wantErr: oauth2Errors.ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := oauth.NewLogout(newLogoutRepo(), logoutIssuer, logoutKeysMock{keys: map[string]crypto.PublicKey{"key-1": pk.Public()}}, &logoutNotifierMock{})
+
+			req, err := l.VerifyRequest(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (req.ClientID != tt.wantClient || req.Subject != tt.wantSubject) {
+				t.Errorf("VerifyRequest() client = %s, subject = %s, want %s, %s", req.ClientID, req.Subject, tt.wantClient, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestLogout_Logout(t *testing.T) {
+	repo := newLogoutRepo()
+	notifier := &logoutNotifierMock{}
+	l := oauth.NewLogout(repo, logoutIssuer, logoutKeysMock{}, notifier)
+
+	uris, err := l.Logout(context.Background(), "user-1", "sid-1", []string{"web", "api", "unknown"})
+	if err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	want := "https://web.example.com/logout?iss=https%3A%2F%2Fauth.example.com&lang=en&sid=sid-1"
+	if len(uris) != 1 || uris[0] != want {
+		t.Errorf("Logout() front-channel uris = %v, want [%s]", uris, want)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0] != (oauth.BackchannelLogoutNotification{ClientID: "api", UserID: "user-1", SessionID: "sid-1"}) {
+		t.Errorf("Logout() back-channel notifications = %+v", notifier.notifications)
+	}
+	if len(repo.revokedSIDs) != 1 || repo.revokedSIDs[0] != "sid-1" {
+		t.Errorf("Logout() revoked sessions = %v, want [sid-1]", repo.revokedSIDs)
+	}
+}
+
+func TestLogoutRequest_RedirectURI(t *testing.T) {
+	req := oauth.LogoutRequest{PostLogoutRedirectURI: "https://web.example.com/logged-out?from=op", State: "xyz"}
+	if got := req.RedirectURI(); got != "https://web.example.com/logged-out?from=op&state=xyz" {
+		t.Errorf("RedirectURI() = %s", got)
+	}
+	if got := (oauth.LogoutRequest{State: "xyz"}).RedirectURI(); got != "" {
+		t.Errorf("RedirectURI() without uri = %s, want empty", got)
+	}
+}
@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// BackchannelLogoutTask is the task which delivers the logout token to the client.
+const BackchannelLogoutTask = "oauth_backchannel_logout"
+
+// The client must respond to the back-channel logout request in time,
+// see: https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRequest
+const backchannelLogoutTaskTimeout = 30 * time.Second
+
+type (
+	// LogoutEnqueuer enqueues the back-channel logout notifications,
+	// so the failed deliveries are retried by the LogoutWorker.
+	// Implements the BackchannelLogoutNotifier interface.
+	LogoutEnqueuer struct {
+		client   *asynq.Client
+		queue    string
+		maxRetry int
+	}
+
+	// LogoutWorker is a task handler for the back-channel logout notifications.
+	LogoutWorker struct {
+		notifier BackchannelLogoutNotifier
+		queue    string
+	}
+)
+
+// NewLogoutEnqueuer creates a new back-channel logout enqueuer.
+// The tasks are enqueued to the given queue and retried up to maxRetry times.
+func NewLogoutEnqueuer(client *asynq.Client, queue string, maxRetry int) *LogoutEnqueuer {
+	return &LogoutEnqueuer{client: client, queue: queue, maxRetry: maxRetry}
+}
+
+// NotifyBackchannelLogout enqueues the back-channel logout notification of the client.
+func (e *LogoutEnqueuer) NotifyBackchannelLogout(ctx context.Context, n BackchannelLogoutNotification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if _, err := e.client.EnqueueContext(
+		ctx,
+		asynq.NewTask(BackchannelLogoutTask, payload),
+		asynq.Queue(e.queue),
+		asynq.MaxRetry(e.maxRetry),
+		asynq.Timeout(backchannelLogoutTaskTimeout),
+	); err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	return nil
+}
+
+// NewLogoutWorker creates a new back-channel logout task handler.
+// The logout tokens are delivered with the notifier, e.g. BackchannelLogout.
+func NewLogoutWorker(notifier BackchannelLogoutNotifier, queue string) *LogoutWorker {
+	return &LogoutWorker{notifier: notifier, queue: queue}
+}
+
+// Queues returns the queues the tasks are enqueued to.
+func (w *LogoutWorker) Queues() []string {
+	return []string{w.queue}
+}
+
+// Register registers task handlers for the back-channel logout.
+func (w *LogoutWorker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(BackchannelLogoutTask, w.NotifyBackchannelLogout)
+}
+
+// NotifyBackchannelLogout delivers the logout token to the client,
+// the task is retried if the client doesn't confirm the logout.
+func (w *LogoutWorker) NotifyBackchannelLogout(ctx context.Context, t *asynq.Task) error {
+	var n BackchannelLogoutNotification
+	if err := json.Unmarshal(t.Payload(), &n); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if err := w.notifier.NotifyBackchannelLogout(ctx, n); err != nil {
+		return fmt.Errorf("failed to notify client %s: %w", n.ClientID, err)
+	}
+
+	return nil
+}
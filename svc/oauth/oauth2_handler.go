@@ -2,12 +2,15 @@ package oauth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/dmitrymomot/oauth2-server/internal/session"
 	"github.com/dmitrymomot/oauth2-server/repository"
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -30,41 +33,52 @@ type (
 	}
 
 	handler struct {
-		repo handlerRepository
-
-		// default scope for auth grant types
-		passwordScope string // password grant type
-		clientScope   string // client_credentials grant type
-		codeScope     string // authorization_code grant type
+		repo       handlerRepository
+		scopes     scopeRegistry
+		idTokenGen idTokenGenerator
+		log        logger
 	}
 
 	handlerOption func(h *handler)
 
 	handlerRepository interface {
 		GetUserByEmail(ctx context.Context, email string) (repository.User, error)
+		GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error)
 		GetClientByID(ctx context.Context, id string) (repository.Client, error)
 		GetTokenByAccess(ctx context.Context, access string) (repository.Token, error)
 	}
+
+	scopeRegistry interface {
+		Validate(ctx context.Context, scope string, withUser bool) error
+		DefaultScope(ctx context.Context, clientScope string, withUser bool) (string, error)
+	}
+
+	idTokenGenerator interface {
+		Generate(ctx context.Context, ti oauth2.TokenInfo, user repository.User, nonce string, authTime *time.Time, sid string) (string, error)
+	}
 )
 
-// WithPasswordScope sets the default scope for password grant type
-func WithPasswordScope(scope string) handlerOption {
+// WithScopeRegistry sets the registry the requested scopes are validated against,
+// see ScopeRegistry. If it's not set, only the client scope is checked.
+func WithScopeRegistry(r scopeRegistry) handlerOption {
 	return func(h *handler) {
-		h.passwordScope = scope
+		h.scopes = r
 	}
 }
 
-// WithClientScope sets the default scope for client_credentials grant type
-func WithClientScope(scope string) handlerOption {
+// WithIDTokenGenerator sets the OpenID Connect ID token generator.
+// If it's not set, the id_token is not issued even if the openid scope is requested.
+func WithIDTokenGenerator(g idTokenGenerator) handlerOption {
 	return func(h *handler) {
-		h.clientScope = scope
+		h.idTokenGen = g
 	}
 }
 
-// WithCodeScope sets the default scope for authorization_code grant type
-func WithCodeScope(scope string) handlerOption {
+// WithHandlerLogger sets the logger to report the errors
+// which don't fail the token request, e.g. the failed ID token generation.
+func WithHandlerLogger(log logger) handlerOption {
 	return func(h *handler) {
-		h.codeScope = scope
+		h.log = log
 	}
 }
 
@@ -110,11 +124,28 @@ func (h *handler) ClientScopeHandler(tgr *oauth2.TokenGenerateRequest) (allowed
 		return false, err
 	}
 
-	if MatchScopesStrict(tgr.Scope, client.Scope) {
-		return true, nil
+	// the user resources can't be accessed with the client_credentials grant,
+	// the device and backchannel authorization requests are approved by the user later
+	withUser := !isClientCredentialsRequest(tgr)
+
+	// the default scopes are granted if the client omits the scope
+	if tgr.Scope == "" && h.scopes != nil {
+		if tgr.Scope, err = h.scopes.DefaultScope(ctx, client.Scope, withUser); err != nil {
+			return false, err
+		}
 	}
 
-	return false, errors.ErrInvalidScope
+	if scope := withoutOIDCScopes(tgr.Scope); scope != "" && !MatchScopesStrict(scope, client.Scope) {
+		return false, errors.ErrInvalidScope
+	}
+
+	if h.scopes != nil {
+		if err := h.scopes.Validate(ctx, tgr.Scope, withUser); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
 
 // AuthorizeScopeHandler check the scope of the authorization request
@@ -124,8 +155,10 @@ func (h *handler) AuthorizeScopeHandler(w http.ResponseWriter, r *http.Request)
 		return "", nil
 	}
 
-	if !MatchScopesStrict(scopes, h.codeScope) {
-		return "", errors.ErrInvalidScope
+	if h.scopes != nil {
+		if err := h.scopes.Validate(r.Context(), scopes, true); err != nil {
+			return "", err
+		}
 	}
 
 	return scopes, nil
@@ -176,11 +209,66 @@ func (h *handler) ExtensionFieldsHandler(ti oauth2.TokenInfo) (fieldsValue map[s
 
 	if uid := ti.GetUserID(); uid != "" {
 		result["user_id"] = uid
+
+		idToken, err := h.idToken(ti)
+		if err != nil && h.log != nil {
+			h.log.Errorf("id token: client_id=%s, user_id=%s: %v", ti.GetClientID(), uid, err)
+		}
+		if idToken != "" {
+			result["id_token"] = idToken
+		}
 	}
 
 	return result
 }
 
+// idToken returns a signed ID token if the openid scope was granted,
+// otherwise returns an empty string.
+func (h *handler) idToken(ti oauth2.TokenInfo) (string, error) {
+	if h.idTokenGen == nil || !MatchScope(ScopeOpenID, ti.GetScope()) {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uid, err := uuid.Parse(ti.GetUserID())
+	if err != nil {
+		return "", fmt.Errorf("invalid user id: %w", err)
+	}
+
+	user, err := h.repo.GetUserByID(ctx, uid)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user by id: %w", err)
+	}
+
+	// nonce, auth_time and sid are stored with the token,
+	// the ID token isn't issued without them, since the client checks the nonce
+	token, err := h.repo.GetTokenByAccess(ctx, ti.GetAccess())
+	if err != nil {
+		return "", fmt.Errorf("failed to get token by access: %w", err)
+	}
+
+	var authTime *time.Time
+	if token.AuthTime.Valid {
+		authTime = &token.AuthTime.Time
+	}
+
+	idToken, err := h.idTokenGen.Generate(ctx, ti, user, token.Nonce, authTime, token.Sid)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate id token: %w", err)
+	}
+
+	return idToken, nil
+}
+
+// isClientCredentialsRequest returns true if the token is requested with the client_credentials grant,
+// so it's issued without the user authorization.
+func isClientCredentialsRequest(tgr *oauth2.TokenGenerateRequest) bool {
+	return tgr.UserID == "" && tgr.Request != nil &&
+		tgr.Request.FormValue("grant_type") == oauth2.ClientCredentials.String()
+}
+
 // ResponseErrorHandler response error handing
 func (h *handler) ResponseErrorHandler(re *errors.Response) {
 	// do nothing
@@ -0,0 +1,292 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/random"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// Pushed authorization request defaults
+const (
+	// RequestURIPrefix is the prefix of the request_uri issued for the pushed authorization request,
+	// see: https://www.rfc-editor.org/rfc/rfc9126#section-2.2
+	RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+	defaultPushedRequestTTL = 60 * time.Second
+)
+
+// client authentication parameters aren't a part of the pushed authorization request
+var clientAuthParams = []string{"client_secret", "client_assertion", "client_assertion_type"}
+
+type (
+	// PushedAuthorizations stores the authorization request parameters pushed by the clients
+	// to the PAR endpoint, so the authorization request contains only the client_id and request_uri.
+	// See: https://www.rfc-editor.org/rfc/rfc9126
+	PushedAuthorizations struct {
+		repo     pushedAuthorizationRepository
+		ttl      time.Duration
+		required bool
+	}
+
+	pushedAuthorizationsOption func(p *PushedAuthorizations)
+
+	pushedAuthorizationRepository interface {
+		CreatePushedAuthorizationRequest(ctx context.Context, arg repository.CreatePushedAuthorizationRequestParams) (repository.PushedAuthorizationRequest, error)
+		GetPushedAuthorizationRequest(ctx context.Context, requestUri string) (repository.PushedAuthorizationRequest, error)
+		DeletePushedAuthorizationRequest(ctx context.Context, requestUri string) error
+	}
+
+	// pushedAuthorizationProvider is implemented by the clients which may be required
+	// to use the pushed authorization requests.
+	pushedAuthorizationProvider interface {
+		RequiresPushedAuthorization() bool
+	}
+
+	// PushedAuthorizationResponse represents the pushed authorization response.
+	// See: https://www.rfc-editor.org/rfc/rfc9126#section-2.2
+	PushedAuthorizationResponse struct {
+		RequestURI string `json:"request_uri"`
+		ExpiresIn  int64  `json:"expires_in"`
+	}
+)
+
+// WithPushedRequestTTL sets the lifetime of the request_uri
+func WithPushedRequestTTL(ttl time.Duration) pushedAuthorizationsOption {
+	return func(p *PushedAuthorizations) {
+		p.ttl = ttl
+	}
+}
+
+// WithPushedAuthorizationRequired makes the pushed authorization requests
+// mandatory for all clients
+func WithPushedAuthorizationRequired(required bool) pushedAuthorizationsOption {
+	return func(p *PushedAuthorizations) {
+		p.required = required
+	}
+}
+
+// NewPushedAuthorizations creates a new pushed authorization requests storage.
+func NewPushedAuthorizations(repo pushedAuthorizationRepository, opts ...pushedAuthorizationsOption) *PushedAuthorizations {
+	p := &PushedAuthorizations{
+		repo: repo,
+		ttl:  defaultPushedRequestTTL,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Required returns true if the pushed authorization requests are mandatory for all clients.
+func (p *PushedAuthorizations) Required() bool {
+	return p.required
+}
+
+// Push stores the authorization request parameters of the client
+// and returns the request_uri to use in the authorization request.
+func (p *PushedAuthorizations) Push(ctx context.Context, clientID string, params url.Values) (*PushedAuthorizationResponse, error) {
+	req, err := p.repo.CreatePushedAuthorizationRequest(ctx, repository.CreatePushedAuthorizationRequestParams{
+		RequestUri: RequestURIPrefix + random.String(40),
+		ClientID:   clientID,
+		Params:     params.Encode(),
+		ExpiresAt:  time.Now().Add(p.ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pushed authorization request: %w", err)
+	}
+
+	return &PushedAuthorizationResponse{
+		RequestURI: req.RequestUri,
+		ExpiresIn:  int64(p.ttl.Seconds()),
+	}, nil
+}
+
+// Resolve returns the authorization request parameters pushed by the client.
+func (p *PushedAuthorizations) Resolve(ctx context.Context, clientID, requestURI string) (url.Values, error) {
+	req, err := p.repo.GetPushedAuthorizationRequest(ctx, requestURI)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get pushed authorization request: %w", err)
+		}
+		return nil, ErrInvalidRequestURI
+	}
+
+	if req.ClientID != clientID || time.Now().After(req.ExpiresAt) {
+		return nil, ErrInvalidRequestURI
+	}
+
+	params, err := url.ParseQuery(req.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pushed authorization request: %w", err)
+	}
+
+	return params, nil
+}
+
+// Remove deletes the pushed authorization request, so the request_uri can be used only once.
+func (p *PushedAuthorizations) Remove(ctx context.Context, requestURI string) error {
+	if err := p.repo.DeletePushedAuthorizationRequest(ctx, requestURI); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to delete pushed authorization request: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetPushedAuthorizations enables the PAR endpoint and the request_uri
+// parameter of the authorization request.
+func (s *Server) SetPushedAuthorizations(p *PushedAuthorizations) {
+	s.par = p
+}
+
+// HandlePushedAuthorizationRequest handles the pushed authorization request:
+// the client is authenticated and the parameters are validated the same way
+// as on the authorization endpoint.
+// See: https://www.rfc-editor.org/rfc/rfc9126#section-2.1
+func (s *Server) HandlePushedAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	if s.par == nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	if err := r.ParseForm(); err != nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	// only the request body parameters are pushed, the request_uri can't be pushed
+	r.Form = r.PostForm
+	if r.Form.Get("request_uri") != "" {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+
+	client, _, err := s.authenticateClient(r, authorizeGrantType(r.Form.Get("response_type")))
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+	if id := r.Form.Get("client_id"); id != "" && id != client.GetID() {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	r.Form.Set("client_id", client.GetID())
+
+	// the parameters of the signed request object replace the body parameters
+	if request := r.Form.Get("request"); request != "" {
+		if s.requestObjects == nil {
+			return s.tokenError(w, ErrInvalidRequestObject)
+		}
+		params, err := s.requestObjects.Verify(r.Context(), client.GetID(), request)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		params.Set("client_id", client.GetID())
+		r.Form = params
+	}
+
+	if _, err := s.ValidationAuthorizeRequest(r); err != nil {
+		return s.tokenError(w, err)
+	}
+	if fn := s.AuthorizeScopeHandler; fn != nil {
+		if _, err := fn(w, r); err != nil {
+			return s.tokenError(w, err)
+		}
+	}
+
+	params := make(url.Values, len(r.Form))
+	for key, values := range r.Form {
+		params[key] = values
+	}
+	for _, key := range clientAuthParams {
+		params.Del(key)
+	}
+
+	resp, err := s.par.Push(r.Context(), client.GetID(), params)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	return s.token(w, resp, http.StatusCreated)
+}
+
+// ResolveAuthorizeRequest replaces the authorization request parameters
+// with the pushed ones if the request contains the request_uri,
+// or with the claims of the request object if the request contains the request parameter.
+// The request without the request_uri is rejected if the pushed authorization requests
+// are required for all clients or for the requesting client.
+func (s *Server) ResolveAuthorizeRequest(r *http.Request) error {
+	clientID, requestURI, request := r.FormValue("client_id"), r.FormValue("request_uri"), r.FormValue("request")
+	if requestURI != "" && request != "" {
+		return oauthErrors.ErrInvalidRequest
+	}
+
+	var (
+		params url.Values
+		err    error
+	)
+	switch {
+	case requestURI != "":
+		// only the request_uri issued by the PAR endpoint is accepted
+		if s.par == nil {
+			return ErrInvalidRequestURI
+		}
+		if params, err = s.par.Resolve(r.Context(), clientID, requestURI); err != nil {
+			return err
+		}
+		// the request_uri is kept, so the consent form resubmits it instead of the parameters
+		params.Set("request_uri", requestURI)
+	default:
+		if err := s.checkPushedAuthorizationRequired(r.Context(), clientID); err != nil {
+			return err
+		}
+		if request == "" {
+			return nil
+		}
+		if s.requestObjects == nil {
+			return ErrInvalidRequestObject
+		}
+		if params, err = s.requestObjects.Verify(r.Context(), clientID, request); err != nil {
+			return err
+		}
+		// the request object is kept, so the consent form resubmits it instead of the parameters
+		params.Set("request", request)
+	}
+
+	params.Set("client_id", clientID)
+	r.Form = params
+
+	return nil
+}
+
+// checkPushedAuthorizationRequired returns an error if the pushed authorization requests
+// are required for all clients or for the requesting client.
+func (s *Server) checkPushedAuthorizationRequired(ctx context.Context, clientID string) error {
+	if s.par == nil {
+		return nil
+	}
+	if s.par.Required() {
+		return oauthErrors.ErrInvalidRequest
+	}
+	client, err := s.manager.GetClient(ctx, clientID)
+	if err != nil {
+		// the invalid client is reported by the authorization request validation
+		return nil
+	}
+	if c, ok := client.(pushedAuthorizationProvider); ok && c.RequiresPushedAuthorization() {
+		return oauthErrors.ErrInvalidRequest
+	}
+	return nil
+}
+
+// authorizeGrantType returns the grant type of the authorization request response type
+func authorizeGrantType(responseType string) oauth2.GrantType {
+	if oauth2.ResponseType(responseType) == oauth2.Token {
+		return oauth2.Implicit
+	}
+	return oauth2.AuthorizationCode
+}
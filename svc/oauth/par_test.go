@@ -0,0 +1,99 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+)
+
+type parRepoMock struct {
+	requests map[string]repository.PushedAuthorizationRequest
+}
+
+func (m *parRepoMock) CreatePushedAuthorizationRequest(ctx context.Context, arg repository.CreatePushedAuthorizationRequestParams) (repository.PushedAuthorizationRequest, error) {
+	req := repository.PushedAuthorizationRequest{
+		RequestUri: arg.RequestUri,
+		ClientID:   arg.ClientID,
+		Params:     arg.Params,
+		ExpiresAt:  arg.ExpiresAt,
+		CreatedAt:  time.Now(),
+	}
+	m.requests[req.RequestUri] = req
+	return req, nil
+}
+
+func (m *parRepoMock) GetPushedAuthorizationRequest(ctx context.Context, requestUri string) (repository.PushedAuthorizationRequest, error) {
+	req, ok := m.requests[requestUri]
+	if !ok {
+		return repository.PushedAuthorizationRequest{}, sql.ErrNoRows
+	}
+	return req, nil
+}
+
+func (m *parRepoMock) DeletePushedAuthorizationRequest(ctx context.Context, requestUri string) error {
+	delete(m.requests, requestUri)
+	return nil
+}
+
+func TestPushedAuthorizations(t *testing.T) {
+	ctx := context.Background()
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {"client"},
+		"redirect_uri":  {"https://client.example.com/callback"},
+		"scope":         {"openid user:read"},
+	}
+
+	p := oauth.NewPushedAuthorizations(&parRepoMock{requests: map[string]repository.PushedAuthorizationRequest{}})
+	resp, err := p.Push(ctx, "client", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.RequestURI, oauth.RequestURIPrefix) || resp.ExpiresIn <= 0 {
+		t.Fatalf("Push() = %+v, want request_uri with prefix %s", resp, oauth.RequestURIPrefix)
+	}
+
+	expired := oauth.NewPushedAuthorizations(&parRepoMock{requests: map[string]repository.PushedAuthorizationRequest{}}, oauth.WithPushedRequestTTL(-time.Second))
+	expiredResp, err := expired.Push(ctx, "client", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		p          *oauth.PushedAuthorizations
+		clientID   string
+		requestURI string
+		wantErr    error
+	}{
+		{name: "pushed request", p: p, clientID: "client", requestURI: resp.RequestURI},
+		{name: "another client", p: p, clientID: "another", requestURI: resp.RequestURI, wantErr: oauth.ErrInvalidRequestURI},
+		{name: "unknown request_uri", p: p, clientID: "client", requestURI: oauth.RequestURIPrefix + "unknown", wantErr: oauth.ErrInvalidRequestURI},
+		{name: "expired request_uri", p: expired, clientID: "client", requestURI: expiredResp.RequestURI, wantErr: oauth.ErrInvalidRequestURI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.Resolve(ctx, tt.clientID, tt.requestURI)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Encode() != params.Encode() {
+				t.Errorf("Resolve() = %v, want %v", got, params)
+			}
+		})
+	}
+
+	if err := p.Remove(ctx, resp.RequestURI); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Resolve(ctx, "client", resp.RequestURI); !errors.Is(err, oauth.ErrInvalidRequestURI) {
+		t.Errorf("Resolve() after Remove() error = %v, want %v", err, oauth.ErrInvalidRequestURI)
+	}
+}
@@ -0,0 +1,72 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// pollIntervalStep is the value the poll interval is increased by on each slow_down error,
+// see: https://www.rfc-editor.org/rfc/rfc8628#section-3.5
+const pollIntervalStep = 5 * time.Second
+
+// pollRequest is the request the client polls the token endpoint for until the user approves it:
+// the device authorization request or the backchannel authentication request.
+type pollRequest struct {
+	name         string
+	approved     bool
+	denied       bool
+	expiresAt    time.Time
+	pollInterval int64
+	lastPolledAt sql.NullTime
+
+	// delete deletes the request and returns the number of the deleted requests
+	delete func(ctx context.Context) (int64, error)
+	// updatePolling stores the poll time and the poll interval
+	updatePolling func(ctx context.Context, interval int64) error
+}
+
+// poll handles the token request of the polling client.
+// It returns nil if the request is approved, the request is single-use,
+// so it's deleted before the token is issued: only the request which deleted it gets the token.
+// The expired and denied requests are deleted, the pending request returns authorization_pending
+// or slow_down with the increased interval if the client polls too frequently.
+func poll(ctx context.Context, req pollRequest) error {
+	switch {
+	case req.expiresAt.Before(time.Now()):
+		return deletePollRequest(ctx, req, ErrExpiredToken)
+	case req.denied:
+		return deletePollRequest(ctx, req, oauthErrors.ErrAccessDenied)
+	case req.approved:
+		deleted, err := req.delete(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", req.name, err)
+		}
+		if deleted != 1 {
+			return oauthErrors.ErrInvalidGrant
+		}
+		return nil
+	}
+
+	pollErr, interval := ErrAuthorizationPending, req.pollInterval
+	if req.lastPolledAt.Valid && time.Since(req.lastPolledAt.Time) < time.Duration(req.pollInterval)*time.Second {
+		pollErr, interval = ErrSlowDown, req.pollInterval+int64(pollIntervalStep.Seconds())
+	}
+
+	if err := req.updatePolling(ctx, interval); err != nil {
+		return fmt.Errorf("failed to update %s polling: %w", req.name, err)
+	}
+
+	return pollErr
+}
+
+// deletePollRequest deletes the request and returns the given error
+func deletePollRequest(ctx context.Context, req pollRequest, err error) error {
+	if _, dErr := req.delete(ctx); dErr != nil {
+		return fmt.Errorf("failed to delete %s: %w", req.name, dErr)
+	}
+	return err
+}
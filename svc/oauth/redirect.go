@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"net"
+	"net/url"
+)
+
+// redirectURIsProvider is implemented by the clients with the registered redirect URIs.
+type redirectURIsProvider interface {
+	GetRedirectURIs() []string
+}
+
+// MatchRedirectURI checks if the redirect URI exactly matches one of the registered URIs.
+// Loopback redirect URIs of native clients match with any port, see RFC 8252, section 7.3.
+func MatchRedirectURI(registered []string, redirectURI string) bool {
+	for _, uri := range registered {
+		if uri == redirectURI || matchLoopbackURI(uri, redirectURI) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLoopbackURI compares the loopback redirect URIs ignoring the port,
+// since native apps listen on an ephemeral port chosen at the request time.
+func matchLoopbackURI(registered, redirectURI string) bool {
+	a, err := url.Parse(registered)
+	if err != nil || !isLoopbackURI(a) {
+		return false
+	}
+	b, err := url.Parse(redirectURI)
+	if err != nil || !isLoopbackURI(b) {
+		return false
+	}
+
+	return a.Hostname() == b.Hostname() &&
+		a.EscapedPath() == b.EscapedPath() &&
+		a.RawQuery == b.RawQuery &&
+		b.User == nil && b.Fragment == ""
+}
+
+// isLoopbackURI checks if the URI is the http loopback IP literal redirect URI.
+// The "localhost" hostname is not considered as loopback, as RFC 8252 recommends.
+func isLoopbackURI(u *url.URL) bool {
+	if u.Scheme != "http" {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
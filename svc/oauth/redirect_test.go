@@ -0,0 +1,42 @@
+package oauth_test
+
+import (
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+)
+
+func TestMatchRedirectURI(t *testing.T) {
+	registered := []string{
+		"https://example.com/callback",
+		"com.example.app:/oauth2redirect",
+		"http://127.0.0.1/callback",
+		"http://[::1]:8080/callback",
+	}
+
+	tests := []struct {
+		name        string
+		redirectURI string
+		want        bool
+	}{
+		{name: "exact match", redirectURI: "https://example.com/callback", want: true},
+		{name: "private-use scheme", redirectURI: "com.example.app:/oauth2redirect", want: true},
+		{name: "another path", redirectURI: "https://example.com/callback/other", want: false},
+		{name: "query added", redirectURI: "https://example.com/callback?next=/", want: false},
+		{name: "subdomain", redirectURI: "https://evil.example.com/callback", want: false},
+		{name: "another port", redirectURI: "https://example.com:8443/callback", want: false},
+		{name: "loopback any port", redirectURI: "http://127.0.0.1:51004/callback", want: true},
+		{name: "loopback ipv6 any port", redirectURI: "http://[::1]:51004/callback", want: true},
+		{name: "loopback another path", redirectURI: "http://127.0.0.1:51004/other", want: false},
+		{name: "loopback https", redirectURI: "https://127.0.0.1:51004/callback", want: false},
+		{name: "localhost", redirectURI: "http://localhost:51004/callback", want: false},
+		{name: "empty", redirectURI: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oauth.MatchRedirectURI(registered, tt.redirectURI); got != tt.want {
+				t.Errorf("MatchRedirectURI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisReplayCache remembers the used JWT IDs in Redis until the token expires,
+// so the same client assertion can't be used twice.
+type RedisReplayCache struct {
+	redis  *redis.Client
+	prefix string
+}
+
+// NewRedisReplayCache creates a new replay cache instance.
+// The prefix is prepended to the keys, e.g. "jti:".
+func NewRedisReplayCache(client *redis.Client, prefix string) *RedisReplayCache {
+	return &RedisReplayCache{
+		redis:  client,
+		prefix: prefix,
+	}
+}
+
+// Use marks the key as used until the expiration time.
+// It returns false if the key has been already used or is expired.
+func (c *RedisReplayCache) Use(ctx context.Context, key string, exp time.Time) (bool, error) {
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		return false, nil
+	}
+
+	ok, err := c.redis.SetNX(ctx, c.prefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to store jti: %w", err)
+	}
+
+	return ok, nil
+}
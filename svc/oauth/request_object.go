@@ -0,0 +1,126 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequestObjectSigningAlgs is the list of supported request object signing algorithms.
+var RequestObjectSigningAlgs = privateKeyJWTAlgs
+
+// request object claims which aren't the authorization request parameters
+var requestObjectClaims = map[string]bool{
+	"iss": true,
+	"aud": true,
+	"exp": true,
+	"nbf": true,
+	"iat": true,
+	"jti": true,
+	"sub": true,
+}
+
+type (
+	// RequestObjectVerifier verifies the authorization request objects
+	// signed by the client with its registered keys.
+	// See: https://www.rfc-editor.org/rfc/rfc9101
+	RequestObjectVerifier struct {
+		repo     clientAuthRepository
+		keys     clientKeySource
+		audience string
+	}
+
+	// clientKeySource returns the registered public key of the client,
+	// implemented by the ClientAuthenticator which caches the remote key sets.
+	clientKeySource interface {
+		publicKey(ctx context.Context, client repository.Client, kid string) (interface{}, error)
+	}
+)
+
+// NewRequestObjectVerifier creates a new request object verifier.
+// The audience is the issuer identifier the request objects are aimed at.
+func NewRequestObjectVerifier(repo clientAuthRepository, keys clientKeySource, audience string) *RequestObjectVerifier {
+	return &RequestObjectVerifier{
+		repo:     repo,
+		keys:     keys,
+		audience: audience,
+	}
+}
+
+// Verify verifies the request object of the client as described in RFC 9101, section 6
+// and returns the authorization request parameters from its claims.
+// The parameters outside of the request object must be ignored.
+func (v *RequestObjectVerifier) Verify(ctx context.Context, clientID, request string) (url.Values, error) {
+	client, err := v.repo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return nil, ErrInvalidRequestObject
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(request, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.publicKey(ctx, client, kid)
+	},
+		jwt.WithValidMethods(RequestObjectSigningAlgs),
+		jwt.WithIssuer(client.ID),
+		jwt.WithAudience(v.audience),
+	); err != nil {
+		return nil, ErrInvalidRequestObject
+	}
+	if exp, err := claims.GetExpirationTime(); err != nil || exp == nil {
+		return nil, ErrInvalidRequestObject
+	}
+	if id, _ := claims["client_id"].(string); id != client.ID {
+		return nil, ErrInvalidRequestObject
+	}
+	// the request object can't refer to another request object
+	if _, ok := claims["request"]; ok {
+		return nil, ErrInvalidRequestObject
+	}
+	if _, ok := claims["request_uri"]; ok {
+		return nil, ErrInvalidRequestObject
+	}
+
+	params := make(url.Values, len(claims))
+	for key, value := range claims {
+		if requestObjectClaims[key] {
+			continue
+		}
+		s, err := requestObjectParam(value)
+		if err != nil {
+			return nil, ErrInvalidRequestObject
+		}
+		params.Set(key, s)
+	}
+
+	return params, nil
+}
+
+// requestObjectParam returns the authorization request parameter value of the claim:
+// the JSON objects, e.g. the claims parameter, are passed JSON encoded.
+func requestObjectParam(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode request object claim: %w", err)
+		}
+		return string(b), nil
+	}
+}
+
+// SetRequestObjectVerifier enables the request parameter of the authorization request.
+func (s *Server) SetRequestObjectVerifier(v *RequestObjectVerifier) {
+	s.requestObjects = v
+}
@@ -0,0 +1,105 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "https://auth.example.com"
+
+func TestRequestObjectVerifier(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.NewKey("key-1", "ES256", pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(jwk.Set{Keys: []jwk.Key{key}})
+
+	repo := &clientAuthRepoMock{clients: map[string]repository.Client{
+		"client": {ID: "client", TokenEndpointAuthMethod: oauth.AuthMethodPrivateKeyJWT, Jwks: string(jwks)},
+	}}
+	a := oauth.NewClientAuthenticator(repo, testEncryptionKey, []string{testTokenEndpoint})
+	v := oauth.NewRequestObjectVerifier(repo, a, testIssuer)
+
+	sign := func(signer *ecdsa.PrivateKey, modify func(claims jwt.MapClaims)) string {
+		claims := jwt.MapClaims{
+			"iss":           "client",
+			"aud":           testIssuer,
+			"exp":           time.Now().Add(time.Minute).Unix(),
+			"client_id":     "client",
+			"response_type": "code",
+			"redirect_uri":  "https://client.example.com/callback",
+			"scope":         "openid",
+			"max_age":       3600,
+			"claims":        map[string]interface{}{"userinfo": map[string]interface{}{"email": nil}},
+		}
+		if modify != nil {
+			modify(claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "key-1"
+		s, err := token.SignedString(signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		clientID string
+		request  string
+		wantErr  error
+	}{
+		{name: "valid request object", clientID: "client", request: sign(pk, nil)},
+		{name: "unknown client", clientID: "unknown", request: sign(pk, nil), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "wrong key", clientID: "client", request: sign(otherKey, nil), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "wrong issuer", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { c["iss"] = "another" }), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "wrong audience", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { c["aud"] = "https://another.example.com" }), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "client_id mismatch", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { c["client_id"] = "another" }), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "expired", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "no expiration", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { delete(c, "exp") }), wantErr: oauth.ErrInvalidRequestObject},
+		{name: "nested request_uri", clientID: "client", request: sign(pk, func(c jwt.MapClaims) { c["request_uri"] = "https://client.example.com/request" }), wantErr: oauth.ErrInvalidRequestObject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := v.Verify(context.Background(), tt.clientID, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := params.Get("redirect_uri"); got != "https://client.example.com/callback" {
+				t.Errorf("redirect_uri = %s", got)
+			}
+			if got := params.Get("max_age"); got != "3600" {
+				t.Errorf("max_age = %s, want 3600", got)
+			}
+			if got := params.Get("claims"); got != `{"userinfo":{"email":null}}` {
+				t.Errorf("claims = %s", got)
+			}
+			if params.Has("iss") || params.Has("aud") || params.Has("exp") {
+				t.Errorf("Verify() = %v, want no JWT claims", params)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+)
+
+type (
+	// ResourceIndicators validates the resource parameters of the authorization
+	// and token requests against the registry of the resource servers.
+	// The access token is issued with the aud claim set to the requested resources
+	// and the scope must be allowed by them.
+	// See: https://www.rfc-editor.org/rfc/rfc8707
+	ResourceIndicators struct {
+		repo resourceServerRepository
+	}
+
+	resourceServerRepository interface {
+		GetResourceServer(ctx context.Context, identifier string) (repository.ResourceServer, error)
+	}
+)
+
+// NewResourceIndicators creates a new resource indicators validator.
+func NewResourceIndicators(repo resourceServerRepository) *ResourceIndicators {
+	return &ResourceIndicators{repo: repo}
+}
+
+// Validate checks the resources are registered resource servers
+// and the scope is allowed by them. The OpenID Connect scopes are always allowed.
+func (ri *ResourceIndicators) Validate(ctx context.Context, resources []string, scope string) error {
+	allowed := ""
+	for _, resource := range resources {
+		if err := validateResourceURI(resource); err != nil {
+			return err
+		}
+
+		rs, err := ri.repo.GetResourceServer(ctx, resource)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidTarget
+			}
+			return fmt.Errorf("failed to get resource server: %w", err)
+		}
+		allowed += " " + rs.Scope
+	}
+
+	if scope = withoutOIDCScopes(scope); scope != "" && !MatchScopesStrict(scope, normalizeScope(allowed)) {
+		return oauthErrors.ErrInvalidScope
+	}
+
+	return nil
+}
+
+// validateResourceURI checks the resource indicator is an absolute URI without a fragment,
+// see: https://www.rfc-editor.org/rfc/rfc8707#section-2
+func validateResourceURI(resource string) error {
+	u, err := url.Parse(resource)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return ErrInvalidTarget
+	}
+	return nil
+}
+
+// grantResources restricts the audience of the token issued for the authorization code
+// or the refresh token to the resources granted by the authorization.
+// The token request may narrow the audience to some of them, all granted resources are used otherwise.
+func grantResources(ctx context.Context, granted []string) error {
+	meta, ok := TokenMetaFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, aud := range meta.Audience {
+		if !contains(granted, aud) {
+			return ErrInvalidTarget
+		}
+	}
+	if len(meta.Audience) == 0 {
+		meta.Audience = granted
+	}
+	meta.Resources = granted
+
+	return nil
+}
+
+// SetResourceIndicators enables the resource parameter of the authorization and token requests.
+func (s *Server) SetResourceIndicators(ri *ResourceIndicators) {
+	s.resources = ri
+}
+
+// validateAuthorizeResources validates the resources of the authorization request,
+// they are stored with the authorization code or used as the implicit token audience.
+func (s *Server) validateAuthorizeResources(r *http.Request, scope string) error {
+	resources := r.Form["resource"]
+	if len(resources) == 0 {
+		return nil
+	}
+	if s.resources == nil {
+		return ErrInvalidTarget
+	}
+
+	return s.resources.Validate(r.Context(), resources, scope)
+}
+
+// resolveTokenResources passes the resources of the token request to the token generator.
+// The resources requested with the authorization code or the refresh token
+// are checked by the token store against the granted ones, see grantResources.
+// The token exchange grant checks the requested audience against the client policy.
+func (s *Server) resolveTokenResources(r *http.Request, gt oauth2.GrantType) error {
+	resources := r.Form["resource"]
+	if len(resources) == 0 || gt == TokenExchangeGrantType {
+		return nil
+	}
+
+	switch gt {
+	case oauth2.AuthorizationCode, oauth2.Refreshing:
+		for _, resource := range resources {
+			if err := validateResourceURI(resource); err != nil {
+				return err
+			}
+		}
+	default:
+		if s.resources == nil {
+			return ErrInvalidTarget
+		}
+		if err := s.resources.Validate(r.Context(), resources, r.FormValue("scope")); err != nil {
+			return err
+		}
+	}
+
+	meta, ok := TokenMetaFromContext(r.Context())
+	if !ok {
+		meta = &TokenMeta{}
+		*r = *r.WithContext(WithTokenMeta(r.Context(), meta))
+	}
+	meta.Audience = resources
+	meta.Resources = resources
+
+	return nil
+}
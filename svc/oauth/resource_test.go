@@ -0,0 +1,89 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+type resourceServerRepoMock struct {
+	servers map[string]repository.ResourceServer
+}
+
+func (m *resourceServerRepoMock) GetResourceServer(ctx context.Context, identifier string) (repository.ResourceServer, error) {
+	rs, ok := m.servers[identifier]
+	if !ok {
+		return repository.ResourceServer{}, sql.ErrNoRows
+	}
+	return rs, nil
+}
+
+func TestResourceIndicators(t *testing.T) {
+	ri := oauth.NewResourceIndicators(&resourceServerRepoMock{servers: map[string]repository.ResourceServer{
+		"https://api.example.com":     {Identifier: "https://api.example.com", Scope: "orders:read orders:write"},
+		"https://billing.example.com": {Identifier: "https://billing.example.com", Scope: "invoices:*"},
+	}})
+
+	tests := []struct {
+		name      string
+		resources []string
+		scope     string
+		wantErr   error
+	}{
+		{name: "registered resource", resources: []string{"https://api.example.com"}, scope: "openid orders:read"},
+		{name: "several resources", resources: []string{"https://api.example.com", "https://billing.example.com"}, scope: "orders:read invoices:read"},
+		{name: "no scope", resources: []string{"https://api.example.com"}},
+		{name: "unknown resource", resources: []string{"https://unknown.example.com"}, wantErr: oauth.ErrInvalidTarget},
+		{name: "relative uri", resources: []string{"/api"}, wantErr: oauth.ErrInvalidTarget},
+		{name: "uri with fragment", resources: []string{"https://api.example.com#orders"}, wantErr: oauth.ErrInvalidTarget},
+		{name: "scope of another resource", resources: []string{"https://api.example.com"}, scope: "invoices:read", wantErr: oauth2Errors.ErrInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ri.Validate(context.Background(), tt.resources, tt.scope); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoreRefreshTokenResources(t *testing.T) {
+	granted := []string{"https://api.example.com", "https://billing.example.com"}
+	store := oauth.NewStore(&tokenRepoMock{})
+	if err := store.Create(oauth.WithTokenMeta(context.Background(), &oauth.TokenMeta{Resources: granted}), &models.Token{ClientID: "client", Access: "access-1", Refresh: "refresh-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		requested    []string
+		wantAudience []string
+		wantErr      error
+	}{
+		{name: "all granted resources", wantAudience: granted},
+		{name: "one of granted resources", requested: []string{"https://billing.example.com"}, wantAudience: []string{"https://billing.example.com"}},
+		{name: "not granted resource", requested: []string{"https://another.example.com"}, wantErr: oauth.ErrInvalidTarget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &oauth.TokenMeta{Audience: tt.requested}
+			_, err := store.GetByRefresh(oauth.WithTokenMeta(context.Background(), meta), "refresh-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByRefresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(meta.Audience, tt.wantAudience) || !reflect.DeepEqual(meta.Resources, granted) {
+				t.Errorf("audience = %v, resources = %v; want %v, %v", meta.Audience, meta.Resources, tt.wantAudience, granted)
+			}
+		})
+	}
+}
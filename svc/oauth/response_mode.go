@@ -0,0 +1,197 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/foolin/goview"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/server"
+)
+
+// Authorization response modes, the default one is query for the code
+// and fragment for the implicit token,
+// see: https://openid.net/specs/oauth-v2-multiple-response-types-1_0.html#ResponseModes
+// and https://openid.net/specs/oauth-v2-form-post-response-mode-1_0.html
+const (
+	ResponseModeQuery    = "query"
+	ResponseModeFragment = "fragment"
+	ResponseModeFormPost = "form_post"
+)
+
+// ResponseModes is the list of supported plain response modes.
+var ResponseModes = []string{
+	ResponseModeQuery,
+	ResponseModeFragment,
+	ResponseModeFormPost,
+}
+
+// JWT secured authorization response modes,
+// see: https://openid.net/specs/oauth-v2-jarm.html#section-2.3
+const (
+	ResponseModeJWT         = "jwt"
+	ResponseModeQueryJWT    = "query.jwt"
+	ResponseModeFragmentJWT = "fragment.jwt"
+	ResponseModeFormPostJWT = "form_post.jwt"
+)
+
+// JWTResponseModes is the list of supported jwt response modes.
+var JWTResponseModes = []string{
+	ResponseModeJWT,
+	ResponseModeQueryJWT,
+	ResponseModeFragmentJWT,
+	ResponseModeFormPostJWT,
+}
+
+// responseModesProvider is implemented by the clients restricted to some response modes.
+type responseModesProvider interface {
+	GetResponseModes() []string
+}
+
+// validateResponseMode checks that the requested response mode can be used:
+// the mode is supported and allowed for the client, the signer is set for the jwt response modes
+// and the implicit tokens aren't returned in the query.
+func (s *Server) validateResponseMode(r *http.Request, client oauth2.ClientInfo, rt oauth2.ResponseType) error {
+	mode := r.FormValue("response_mode")
+	switch {
+	case mode == "":
+		return nil
+	case isJWTResponseMode(mode):
+		if s.jarm == nil {
+			return errors.ErrInvalidRequest
+		}
+	case !isResponseMode(mode):
+		return errors.ErrInvalidRequest
+	}
+
+	if c, ok := client.(responseModesProvider); ok && len(c.GetResponseModes()) > 0 && !contains(c.GetResponseModes(), mode) {
+		return errors.ErrUnauthorizedClient
+	}
+
+	if rt == oauth2.Token && (mode == ResponseModeQuery || mode == ResponseModeQueryJWT) {
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+// RedirectAuthorizeResponse returns the authorization response to the client
+// in the requested response mode. The jwt response modes contain the signed response,
+// the default response mode of the response type is used if the mode is omitted.
+func (s *Server) RedirectAuthorizeResponse(w http.ResponseWriter, r *http.Request, req *server.AuthorizeRequest, data map[string]interface{}) error {
+	mode := r.FormValue("response_mode")
+	if !isJWTResponseMode(mode) || s.jarm == nil {
+		return s.redirectPlainResponse(w, r, req, mode, data)
+	}
+
+	params := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		params[k] = v
+	}
+	if req.State != "" {
+		params["state"] = req.State
+	}
+
+	response, err := s.jarm.Sign(r.Context(), req.ClientID, params)
+	if err != nil {
+		return err
+	}
+
+	if mode == ResponseModeJWT {
+		mode = ResponseModeQueryJWT
+		if req.ResponseType == oauth2.Token {
+			mode = ResponseModeFragmentJWT
+		}
+	}
+
+	if mode == ResponseModeFormPostJWT {
+		return renderFormPost(w, req.RedirectURI, url.Values{"response": {response}})
+	}
+
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return err
+	}
+	if mode == ResponseModeFragmentJWT {
+		u.Fragment = "response=" + response
+	} else {
+		q := u.Query()
+		q.Set("response", response)
+		u.RawQuery = q.Encode()
+	}
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+	return nil
+}
+
+// redirectPlainResponse returns the authorization response parameters to the client
+// in the query, in the fragment or with the auto-submitted form.
+func (s *Server) redirectPlainResponse(w http.ResponseWriter, r *http.Request, req *server.AuthorizeRequest, mode string, data map[string]interface{}) error {
+	// go-oauth2 uses the default response mode of the response type
+	if mode == "" || !isResponseMode(mode) ||
+		(mode == ResponseModeQuery && req.ResponseType == oauth2.Code) ||
+		(mode == ResponseModeFragment && req.ResponseType == oauth2.Token) {
+		uri, err := s.GetRedirectURI(req, data)
+		if err != nil {
+			return err
+		}
+
+		http.Redirect(w, r, uri, http.StatusFound)
+		return nil
+	}
+
+	params := make(url.Values, len(data)+1)
+	for k, v := range data {
+		params.Set(k, fmt.Sprint(v))
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	if mode == ResponseModeFormPost {
+		return renderFormPost(w, req.RedirectURI, params)
+	}
+
+	// the code in the fragment, the tokens are never returned in the query
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return err
+	}
+	u.Fragment = params.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+	return nil
+}
+
+// renderFormPost renders the page which auto-submits the response parameters
+// to the client redirect uri, see: https://openid.net/specs/oauth-v2-form-post-response-mode-1_0.html
+func renderFormPost(w http.ResponseWriter, redirectURI string, params url.Values) error {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	return goview.Render(w, http.StatusOK, "form_post.tpl", map[string]interface{}{
+		"redirect_uri": redirectURI,
+		"params":       params,
+	})
+}
+
+// isResponseMode returns true if the response mode is one of the plain response modes
+func isResponseMode(mode string) bool {
+	for _, m := range ResponseModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// isJWTResponseMode returns true if the response mode is one of the jwt response modes
+func isJWTResponseMode(mode string) bool {
+	for _, m := range JWTResponseModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
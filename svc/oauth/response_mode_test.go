@@ -0,0 +1,158 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestRedirectAuthorizeResponse(t *testing.T) {
+	srv := &oauth.Server{Server: server.NewDefaultServer(manage.NewDefaultManager())}
+
+	tests := []struct {
+		name         string
+		responseType oauth2.ResponseType
+		responseMode string
+		data         map[string]interface{}
+		wantQuery    url.Values
+		wantFragment url.Values
+	}{
+		{
+			name:         "code in the default query",
+			responseType: oauth2.Code,
+			data:         map[string]interface{}{"code": "abc"},
+			wantQuery:    url.Values{"code": {"abc"}, "state": {"xyz"}},
+		},
+		{
+			name:         "code in the query",
+			responseType: oauth2.Code,
+			responseMode: oauth.ResponseModeQuery,
+			data:         map[string]interface{}{"code": "abc"},
+			wantQuery:    url.Values{"code": {"abc"}, "state": {"xyz"}},
+		},
+		{
+			name:         "code in the fragment",
+			responseType: oauth2.Code,
+			responseMode: oauth.ResponseModeFragment,
+			data:         map[string]interface{}{"code": "abc"},
+			wantQuery:    url.Values{},
+			wantFragment: url.Values{"code": {"abc"}, "state": {"xyz"}},
+		},
+		{
+			name:         "token in the default fragment",
+			responseType: oauth2.Token,
+			data:         map[string]interface{}{"access_token": "abc", "expires_in": 3600},
+			wantQuery:    url.Values{},
+			wantFragment: url.Values{"access_token": {"abc"}, "expires_in": {"3600"}, "state": {"xyz"}},
+		},
+		{
+			name:         "error in the fragment",
+			responseType: oauth2.Code,
+			responseMode: oauth.ResponseModeFragment,
+			data:         map[string]interface{}{"error": "access_denied"},
+			wantQuery:    url.Values{},
+			wantFragment: url.Values{"error": {"access_denied"}, "state": {"xyz"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+url.Values{"response_mode": {tt.responseMode}}.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			req := &server.AuthorizeRequest{
+				ResponseType: tt.responseType,
+				RedirectURI:  "https://client.example.com/callback",
+				State:        "xyz",
+			}
+			if err := srv.RedirectAuthorizeResponse(w, r, req, tt.data); err != nil {
+				t.Fatalf("RedirectAuthorizeResponse() error = %v", err)
+			}
+			if w.Code != http.StatusFound {
+				t.Fatalf("RedirectAuthorizeResponse() status = %d, want %d", w.Code, http.StatusFound)
+			}
+
+			u, err := url.Parse(w.Header().Get("Location"))
+			if err != nil {
+				t.Fatalf("invalid redirect location: %v", err)
+			}
+			if got := u.Query(); got.Encode() != tt.wantQuery.Encode() {
+				t.Errorf("RedirectAuthorizeResponse() query = %v, want %v", got, tt.wantQuery)
+			}
+			fragment, _ := url.ParseQuery(u.Fragment)
+			if tt.wantFragment == nil {
+				tt.wantFragment = url.Values{}
+			}
+			if fragment.Encode() != tt.wantFragment.Encode() {
+				t.Errorf("RedirectAuthorizeResponse() fragment = %v, want %v", fragment, tt.wantFragment)
+			}
+		})
+	}
+}
+
+// clientTokenStoreMock serves the clients to the oauth2 server
+type clientTokenStoreMock struct {
+	clients map[string]*oauth.Client
+}
+
+func (m clientTokenStoreMock) GetByID(ctx context.Context, id string) (oauth2.ClientInfo, error) {
+	return m.clients[id], nil
+}
+
+func TestValidationAuthorizeRequest_ResponseMode(t *testing.T) {
+	tokens, err := store.NewMemoryTokenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, _ := oauth.NewOauth2Server(
+		generates.NewAccessGenerate(),
+		generates.NewAuthorizeGenerate(),
+		tokens,
+		clientTokenStoreMock{clients: map[string]*oauth.Client{
+			"any-mode":       {ID: "any-mode", RedirectURIs: []string{"https://client.example.com/callback"}},
+			"form-post-only": {ID: "form-post-only", RedirectURIs: []string{"https://client.example.com/callback"}, ResponseModes: []string{oauth.ResponseModeFormPost}},
+		}},
+		oauth.NewHandler(nil),
+	)
+	// the allowed grants are checked by the handler with the client repository
+	srv.ClientAuthorizedHandler = nil
+
+	tests := []struct {
+		name         string
+		clientID     string
+		responseType oauth2.ResponseType
+		responseMode string
+		wantErr      error
+	}{
+		{name: "default response mode", clientID: "form-post-only", responseType: oauth2.Code},
+		{name: "any supported mode", clientID: "any-mode", responseType: oauth2.Code, responseMode: oauth.ResponseModeFragment},
+		{name: "allowed mode", clientID: "form-post-only", responseType: oauth2.Code, responseMode: oauth.ResponseModeFormPost},
+		{name: "mode not allowed for the client", clientID: "form-post-only", responseType: oauth2.Code, responseMode: oauth.ResponseModeQuery, wantErr: oauth2Errors.ErrUnauthorizedClient},
+		{name: "unsupported mode", clientID: "any-mode", responseType: oauth2.Code, responseMode: "web_message", wantErr: oauth2Errors.ErrInvalidRequest},
+		{name: "implicit token in the query", clientID: "any-mode", responseType: oauth2.Token, responseMode: oauth.ResponseModeQuery, wantErr: oauth2Errors.ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+url.Values{
+				"client_id":     {tt.clientID},
+				"response_type": {tt.responseType.String()},
+				"response_mode": {tt.responseMode},
+				"redirect_uri":  {"https://client.example.com/callback"},
+			}.Encode(), nil)
+
+			if _, err := srv.ValidationAuthorizeRequest(r); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidationAuthorizeRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
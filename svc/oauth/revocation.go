@@ -0,0 +1,168 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
+)
+
+type (
+	// TokenRevocation revokes the tokens on the client request or in bulk
+	// for the user, the client or the user authorization of the client.
+	// The revoked JWT access tokens are rejected by the introspection endpoint,
+	// the resource servers verifying them locally accept them until they expire.
+	// See: https://www.rfc-editor.org/rfc/rfc7009
+	TokenRevocation struct {
+		repo revocationRepository
+	}
+
+	revocationRepository interface {
+		GetTokenByAccess(ctx context.Context, access string) (repository.Token, error)
+		GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error)
+		DeleteByAccess(ctx context.Context, access string) error
+		DeleteTokensByFamily(ctx context.Context, familyID uuid.UUID) error
+		DeleteTokensByUserID(ctx context.Context, userID uuid.NullUUID) (int64, error)
+		DeleteTokensByClientID(ctx context.Context, clientID string) (int64, error)
+		DeleteTokensByUserIDAndClientID(ctx context.Context, arg repository.DeleteTokensByUserIDAndClientIDParams) (int64, error)
+	}
+)
+
+// NewTokenRevocation creates a new token revocation instance.
+func NewTokenRevocation(repo revocationRepository) *TokenRevocation {
+	return &TokenRevocation{repo: repo}
+}
+
+// Revoke revokes the token issued to the client.
+// The refresh token is revoked with all the tokens issued from the same authorization,
+// including the access tokens, the access token is revoked alone.
+// The unknown token is ignored, the token of another client is refused.
+func (rv *TokenRevocation) Revoke(ctx context.Context, clientID, token, tokenTypeHint string) error {
+	t, tokenType, err := rv.load(ctx, token, tokenTypeHint)
+	if err != nil || t == nil {
+		return err
+	}
+	if t.ClientID != clientID {
+		return oauthErrors.ErrUnauthorizedClient
+	}
+
+	if tokenType == tokenTypeHintRefreshToken {
+		if err := rv.repo.DeleteTokensByFamily(ctx, t.FamilyID); err != nil {
+			return fmt.Errorf("failed to delete tokens by family: %w", err)
+		}
+		return nil
+	}
+
+	if err := rv.repo.DeleteByAccess(ctx, token); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to delete by access: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserTokens revokes all the tokens issued on behalf of the user
+// and returns the number of the revoked tokens.
+func (rv *TokenRevocation) RevokeUserTokens(ctx context.Context, userID uuid.UUID) (int64, error) {
+	n, err := rv.repo.DeleteTokensByUserID(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tokens by user id: %w", err)
+	}
+	return n, nil
+}
+
+// RevokeClientTokens revokes all the tokens issued to the client
+// and returns the number of the revoked tokens.
+func (rv *TokenRevocation) RevokeClientTokens(ctx context.Context, clientID string) (int64, error) {
+	n, err := rv.repo.DeleteTokensByClientID(ctx, clientID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tokens by client id: %w", err)
+	}
+	return n, nil
+}
+
+// RevokeUserClientTokens revokes all the tokens issued to the client on behalf of the user
+// and returns the number of the revoked tokens.
+func (rv *TokenRevocation) RevokeUserClientTokens(ctx context.Context, userID uuid.UUID, clientID string) (int64, error) {
+	n, err := rv.repo.DeleteTokensByUserIDAndClientID(ctx, repository.DeleteTokensByUserIDAndClientIDParams{
+		UserID:   uuid.NullUUID{UUID: userID, Valid: true},
+		ClientID: clientID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tokens by user id and client id: %w", err)
+	}
+	return n, nil
+}
+
+// load looks up the token starting with the type of the hint,
+// the hint doesn't limit the lookup, see: https://www.rfc-editor.org/rfc/rfc7009#section-2.1
+func (rv *TokenRevocation) load(ctx context.Context, token, tokenTypeHint string) (*repository.Token, string, error) {
+	tokenTypes := []string{tokenTypeHintAccessToken, tokenTypeHintRefreshToken}
+	if tokenTypeHint == tokenTypeHintRefreshToken {
+		tokenTypes = []string{tokenTypeHintRefreshToken, tokenTypeHintAccessToken}
+	}
+
+	for _, tokenType := range tokenTypes {
+		var (
+			t   repository.Token
+			err error
+		)
+		if tokenType == tokenTypeHintAccessToken {
+			t, err = rv.repo.GetTokenByAccess(ctx, token)
+		} else {
+			t, err = rv.repo.GetTokenByRefresh(ctx, token)
+		}
+		if err == nil {
+			return &t, tokenType, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("failed to get token: %w", err)
+		}
+	}
+
+	return nil, "", nil
+}
+
+// SetTokenRevocation enables the token revocation endpoint.
+func (s *Server) SetTokenRevocation(rv *TokenRevocation) {
+	s.revocation = rv
+}
+
+// HandleRevocationRequest handles the token revocation request of the client.
+// The confidential client authenticates the same way as on the token endpoint,
+// the public client passes its client_id.
+// The response is the same for the revoked and the unknown token.
+func (s *Server) HandleRevocationRequest(w http.ResponseWriter, r *http.Request) error {
+	if s.revocation == nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+	if err := r.ParseForm(); err != nil {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+
+	client, _, err := s.verifyClient(r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		return s.tokenError(w, oauthErrors.ErrInvalidRequest)
+	}
+
+	if err := s.revocation.Revoke(r.Context(), client.GetID(), token, r.PostForm.Get("token_type_hint")); err != nil {
+		if errors.Is(err, oauthErrors.ErrUnauthorizedClient) {
+			return s.tokenError(w, err)
+		}
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
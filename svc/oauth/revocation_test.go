@@ -0,0 +1,170 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
+)
+
+type revocationRepoMock struct {
+	tokens []repository.Token
+}
+
+func (m *revocationRepoMock) find(match func(t repository.Token) bool) (repository.Token, error) {
+	for _, t := range m.tokens {
+		if match(t) {
+			return t, nil
+		}
+	}
+	return repository.Token{}, sql.ErrNoRows
+}
+
+func (m *revocationRepoMock) delete(match func(t repository.Token) bool) int64 {
+	var n int64
+	tokens := m.tokens[:0]
+	for _, t := range m.tokens {
+		if match(t) {
+			n++
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+	m.tokens = tokens
+	return n
+}
+
+func (m *revocationRepoMock) GetTokenByAccess(ctx context.Context, access string) (repository.Token, error) {
+	return m.find(func(t repository.Token) bool { return t.Access == access })
+}
+
+func (m *revocationRepoMock) GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error) {
+	return m.find(func(t repository.Token) bool { return t.Refresh == refresh })
+}
+
+func (m *revocationRepoMock) DeleteByAccess(ctx context.Context, access string) error {
+	m.delete(func(t repository.Token) bool { return t.Access == access })
+	return nil
+}
+
+func (m *revocationRepoMock) DeleteTokensByFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.delete(func(t repository.Token) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+func (m *revocationRepoMock) DeleteTokensByUserID(ctx context.Context, userID uuid.NullUUID) (int64, error) {
+	return m.delete(func(t repository.Token) bool { return t.UserID == userID }), nil
+}
+
+func (m *revocationRepoMock) DeleteTokensByClientID(ctx context.Context, clientID string) (int64, error) {
+	return m.delete(func(t repository.Token) bool { return t.ClientID == clientID }), nil
+}
+
+func (m *revocationRepoMock) DeleteTokensByUserIDAndClientID(ctx context.Context, arg repository.DeleteTokensByUserIDAndClientIDParams) (int64, error) {
+	return m.delete(func(t repository.Token) bool { return t.UserID == arg.UserID && t.ClientID == arg.ClientID }), nil
+}
+
+func TestTokenRevocation(t *testing.T) {
+	familyID := uuid.New()
+	newRepo := func() *revocationRepoMock {
+		return &revocationRepoMock{tokens: []repository.Token{
+			{ClientID: "client", FamilyID: familyID, Access: "", Refresh: "refresh-1"},
+			{ClientID: "client", FamilyID: familyID, Access: "access-2", Refresh: "refresh-2"},
+			{ClientID: "client", FamilyID: uuid.New(), Access: "access-3", Refresh: "refresh-3"},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		clientID string
+		token    string
+		hint     string
+		wantErr  error
+		wantLeft int
+	}{
+		{name: "refresh token revokes the token family", clientID: "client", token: "refresh-2", hint: "refresh_token", wantLeft: 1},
+		{name: "rotated refresh token revokes the token family", clientID: "client", token: "refresh-1", wantLeft: 1},
+		{name: "access token", clientID: "client", token: "access-2", wantLeft: 2},
+		{name: "access token with wrong hint", clientID: "client", token: "access-3", hint: "refresh_token", wantLeft: 2},
+		{name: "unknown token", clientID: "client", token: "unknown", wantLeft: 3},
+		{name: "token of another client", clientID: "another-client", token: "refresh-2", wantErr: oauth2Errors.ErrUnauthorizedClient, wantLeft: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			err := oauth.NewTokenRevocation(repo).Revoke(context.Background(), tt.clientID, tt.token, tt.hint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Revoke() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.tokens) != tt.wantLeft {
+				t.Errorf("Revoke() left %d tokens, want %d", len(repo.tokens), tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestTokenRevocation_Bulk(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	newRepo := func() *revocationRepoMock {
+		return &revocationRepoMock{tokens: []repository.Token{
+			{ClientID: "client", UserID: uuid.NullUUID{UUID: alice, Valid: true}, Access: "access-1"},
+			{ClientID: "client", UserID: uuid.NullUUID{UUID: bob, Valid: true}, Access: "access-2"},
+			{ClientID: "another-client", UserID: uuid.NullUUID{UUID: alice, Valid: true}, Access: "access-3"},
+			{ClientID: "client", Access: "access-4"},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		revoke   func(rv *oauth.TokenRevocation) (int64, error)
+		wantLeft []string
+	}{
+		{
+			name: "user tokens",
+			revoke: func(rv *oauth.TokenRevocation) (int64, error) {
+				return rv.RevokeUserTokens(context.Background(), alice)
+			},
+			wantLeft: []string{"access-2", "access-4"},
+		},
+		{
+			name: "client tokens",
+			revoke: func(rv *oauth.TokenRevocation) (int64, error) {
+				return rv.RevokeClientTokens(context.Background(), "client")
+			},
+			wantLeft: []string{"access-3"},
+		},
+		{
+			name: "user tokens of the client",
+			revoke: func(rv *oauth.TokenRevocation) (int64, error) {
+				return rv.RevokeUserClientTokens(context.Background(), alice, "client")
+			},
+			wantLeft: []string{"access-2", "access-3", "access-4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			n, err := tt.revoke(oauth.NewTokenRevocation(repo))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(n) != 4-len(tt.wantLeft) {
+				t.Errorf("revoked %d tokens, want %d", n, 4-len(tt.wantLeft))
+			}
+
+			left := make([]string, 0, len(repo.tokens))
+			for _, token := range repo.tokens {
+				left = append(left, token.Access)
+			}
+			if !reflect.DeepEqual(left, tt.wantLeft) {
+				t.Errorf("left tokens = %v, want %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
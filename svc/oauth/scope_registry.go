@@ -0,0 +1,213 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// default lifetime of the registered scopes cache
+const defaultScopeRegistryCacheTTL = time.Minute
+
+type (
+	// ScopeRegistry is the list of scopes which can be granted by the server.
+	// The scopes are stored in the database and managed with the scope API,
+	// the registered scope name can contain a wildcard, e.g. user:*, to register the scope family.
+	ScopeRegistry struct {
+		repo     scopeRegistryRepository
+		cacheTTL time.Duration
+
+		mu       sync.RWMutex
+		scopes   []repository.Scope
+		loadedAt time.Time
+	}
+
+	scopeRegistryOption func(r *ScopeRegistry)
+
+	scopeRegistryRepository interface {
+		GetScopes(ctx context.Context) ([]repository.Scope, error)
+	}
+
+	// ScopeDescription is the scope displayed on the consent page.
+	ScopeDescription struct {
+		Name        string
+		Description string
+	}
+)
+
+// WithScopeRegistryCacheTTL sets the lifetime of the in-memory scopes cache.
+// The changes made with the scope API are applied by all server instances after the cache is expired.
+func WithScopeRegistryCacheTTL(ttl time.Duration) scopeRegistryOption {
+	return func(r *ScopeRegistry) {
+		if ttl > 0 {
+			r.cacheTTL = ttl
+		}
+	}
+}
+
+// NewScopeRegistry creates a new scope registry instance.
+func NewScopeRegistry(repo scopeRegistryRepository, opts ...scopeRegistryOption) *ScopeRegistry {
+	r := &ScopeRegistry{
+		repo:     repo,
+		cacheTTL: defaultScopeRegistryCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Names returns the names of all registered scopes.
+func (r *ScopeRegistry) Names(ctx context.Context) ([]string, error) {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		result = append(result, s.Name)
+	}
+	return result, nil
+}
+
+// Validate checks all requested scopes are registered.
+// The scopes of the user resources can't be granted without the user authorization,
+// e.g. with the client_credentials grant, so withUser must be false in this case.
+func (r *ScopeRegistry) Validate(ctx context.Context, scope string, withUser bool) error {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Fields(scope) {
+		s, ok := findScope(scopes, name)
+		if !ok || (!withUser && s.ResourceOwner == repository.ScopeResourceOwnerUser) {
+			return errors.ErrInvalidScope
+		}
+	}
+
+	return nil
+}
+
+// DefaultScope returns the scope granted when the client omits the scope parameter:
+// the default scopes allowed by the client scope.
+func (r *ScopeRegistry) DefaultScope(ctx context.Context, clientScope string, withUser bool) (string, error) {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if !s.IsDefault || (!withUser && s.ResourceOwner == repository.ScopeResourceOwnerUser) {
+			continue
+		}
+		if contains(oidcScopes, s.Name) || MatchScope(s.Name, clientScope) {
+			result = append(result, s.Name)
+		}
+	}
+	return strings.Join(result, " "), nil
+}
+
+// ClientScope returns the scope of the new client which omits it on registration:
+// all registered default scopes.
+func (r *ScopeRegistry) ClientScope(ctx context.Context) (string, error) {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if s.IsDefault {
+			result = append(result, s.Name)
+		}
+	}
+	return strings.Join(result, " "), nil
+}
+
+// ConsentScope returns the requested scopes which must be approved by the user,
+// the unknown scopes require the consent as well.
+func (r *ScopeRegistry) ConsentScope(ctx context.Context, scope string) (string, error) {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]string, 0)
+	for _, name := range strings.Fields(scope) {
+		if s, ok := findScope(scopes, name); !ok || s.RequiresConsent {
+			result = append(result, name)
+		}
+	}
+	return strings.Join(result, " "), nil
+}
+
+// Describe returns the requested scopes with the registered descriptions,
+// the scope name is used if the scope has no description.
+func (r *ScopeRegistry) Describe(ctx context.Context, scope string) ([]ScopeDescription, error) {
+	scopes, err := r.cachedScopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ScopeDescription, 0)
+	for _, name := range strings.Fields(scope) {
+		d := ScopeDescription{Name: name, Description: name}
+		if s, ok := findScope(scopes, name); ok && s.Description != "" {
+			d.Description = s.Description
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// cachedScopes returns the scopes from the cache or reloads them from the database.
+func (r *ScopeRegistry) cachedScopes(ctx context.Context) ([]repository.Scope, error) {
+	r.mu.RLock()
+	scopes, loadedAt := r.scopes, r.loadedAt
+	r.mu.RUnlock()
+
+	if scopes != nil && time.Since(loadedAt) < r.cacheTTL {
+		return scopes, nil
+	}
+
+	scopes, err := r.repo.GetScopes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scopes: %w", err)
+	}
+	if scopes == nil {
+		scopes = []repository.Scope{}
+	}
+
+	r.mu.Lock()
+	r.scopes = scopes
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+
+	return scopes, nil
+}
+
+// findScope returns the registered scope by the exact name,
+// otherwise the first registered wildcard scope which matches the name.
+func findScope(scopes []repository.Scope, name string) (repository.Scope, bool) {
+	for _, s := range scopes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	for _, s := range scopes {
+		if strings.Contains(s.Name, "*") && MatchScope(name, s.Name) {
+			return s, true
+		}
+	}
+	return repository.Scope{}, false
+}
@@ -0,0 +1,128 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
+)
+
+type scopeRepoMock struct {
+	scopes []repository.Scope
+	calls  int
+}
+
+func (m *scopeRepoMock) GetScopes(ctx context.Context) ([]repository.Scope, error) {
+	m.calls++
+	return m.scopes, nil
+}
+
+func newScopeRepo() *scopeRepoMock {
+	return &scopeRepoMock{scopes: []repository.Scope{
+		{Name: "openid", Description: "Sign you in", IsDefault: true, ResourceOwner: repository.ScopeResourceOwnerUser},
+		{Name: "user:read", Description: "View your profile", RequiresConsent: true, IsDefault: true, ResourceOwner: repository.ScopeResourceOwnerUser},
+		{Name: "user:*", Description: "Manage your profile", RequiresConsent: true, ResourceOwner: repository.ScopeResourceOwnerUser},
+		{Name: "client:read", RequiresConsent: true, IsDefault: true, ResourceOwner: repository.ScopeResourceOwnerClient},
+	}}
+}
+
+func TestScopeRegistry_Validate(t *testing.T) {
+	r := oauth.NewScopeRegistry(newScopeRepo())
+
+	tests := []struct {
+		name     string
+		scope    string
+		withUser bool
+		wantErr  error
+	}{
+		{name: "empty scope", scope: ""},
+		{name: "registered scopes", scope: "openid user:read client:read", withUser: true},
+		{name: "wildcard scope", scope: "user:write", withUser: true},
+		{name: "unknown scope", scope: "user:read admin:read", withUser: true, wantErr: oauth2Errors.ErrInvalidScope},
+		{name: "client scope without user", scope: "client:read"},
+		{name: "user scope without user", scope: "client:read user:read", wantErr: oauth2Errors.ErrInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Validate(context.Background(), tt.scope, tt.withUser); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScopeRegistry_DefaultScope(t *testing.T) {
+	r := oauth.NewScopeRegistry(newScopeRepo())
+	ctx := context.Background()
+
+	if got, _ := r.DefaultScope(ctx, "user:* client:*", true); got != "openid user:read client:read" {
+		t.Errorf("DefaultScope() = %q, want all default scopes", got)
+	}
+	if got, _ := r.DefaultScope(ctx, "user:*", true); got != "openid user:read" {
+		t.Errorf("DefaultScope() = %q, want the default scopes allowed by the client", got)
+	}
+	if got, _ := r.DefaultScope(ctx, "user:* client:*", false); got != "client:read" {
+		t.Errorf("DefaultScope() = %q, want the client scopes without user", got)
+	}
+}
+
+func TestScopeRegistry_ClientScope(t *testing.T) {
+	r := oauth.NewScopeRegistry(newScopeRepo())
+
+	if got, _ := r.ClientScope(context.Background()); got != "openid user:read client:read" {
+		t.Errorf("ClientScope() = %q, want all default scopes", got)
+	}
+}
+
+func TestScopeRegistry_Describe(t *testing.T) {
+	repo := newScopeRepo()
+	r := oauth.NewScopeRegistry(repo)
+	ctx := context.Background()
+
+	got, err := r.Describe(ctx, "user:read user:write client:read unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []oauth.ScopeDescription{
+		{Name: "user:read", Description: "View your profile"},
+		{Name: "user:write", Description: "Manage your profile"},
+		{Name: "client:read", Description: "client:read"},
+		{Name: "unknown", Description: "unknown"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Describe() = %+v, want %+v", got, want)
+	}
+
+	if got, _ := r.ConsentScope(ctx, "openid user:read unknown"); got != "user:read unknown" {
+		t.Errorf("ConsentScope() = %q, want the scopes which require consent", got)
+	}
+
+	if repo.calls != 1 {
+		t.Errorf("scopes are loaded %d times, want once while the cache is valid", repo.calls)
+	}
+}
+
+func TestConsentManager_WithScopeRegistry(t *testing.T) {
+	ctx := context.Background()
+	uid := uuid.New()
+	m := oauth.NewConsentManager(&consentRepoMock{}, oauth.WithConsentScopeRegistry(oauth.NewScopeRegistry(newScopeRepo())))
+
+	if required, err := m.Required(ctx, uid, "client", "openid"); err != nil || required {
+		t.Errorf("Required() = %v, %v; want false for the scope without consent", required, err)
+	}
+	if required, err := m.Required(ctx, uid, "client", ""); err != nil || !required {
+		t.Errorf("Required() = %v, %v; want true for the default scopes", required, err)
+	}
+
+	if err := m.Grant(ctx, uid, "client", ""); err != nil {
+		t.Fatal(err)
+	}
+	if required, err := m.Required(ctx, uid, "client", "user:read"); err != nil || required {
+		t.Errorf("Required() = %v, %v; want false after the default scopes are approved", required, err)
+	}
+}
@@ -1,11 +1,54 @@
 package oauth
 
 import (
+	"sort"
 	"strings"
 
 	scopes "github.com/SonicRoshan/scope"
 )
 
+// OpenID Connect scopes
+const (
+	ScopeOpenID = "openid"
+	ScopeEmail  = "email"
+)
+
+// oidcScopes are always allowed, since they only control the ID token and userinfo claims.
+var oidcScopes = []string{ScopeOpenID, ScopeEmail}
+
+// withoutOIDCScopes returns the scope string without OpenID Connect scopes.
+func withoutOIDCScopes(scope string) string {
+	result := make([]string, 0, len(oidcScopes))
+	for _, s := range strings.Fields(scope) {
+		isOIDC := false
+		for _, o := range oidcScopes {
+			if s == o {
+				isOIDC = true
+				break
+			}
+		}
+		if !isOIDC {
+			result = append(result, s)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// normalizeScope returns the sorted list of unique scopes as a string,
+// so the same scope set is always represented by the same string.
+func normalizeScope(scope string) string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, s := range strings.Fields(scope) {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return strings.Join(result, " ")
+}
+
 // MatchScopesStrict verifies if the all scopes is allowed.
 // It returns true if the scope is allowed, false otherwise.
 func MatchScopesStrict(requiredScopes string, allowedScopes string) bool {
@@ -1,11 +1,32 @@
 package oauth
 
 import (
+	"net/http"
+
 	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
 	"github.com/go-oauth2/oauth2/v4/manage"
 	"github.com/go-oauth2/oauth2/v4/server"
 )
 
+// Server extends the go-oauth2 server with the grant types
+// which are not supported by the library, e.g. device_code grant.
+type Server struct {
+	*server.Server
+	manager *manage.Manager
+	grants  map[oauth2.GrantType]GrantHandler
+	dpop    *DPoPVerifier
+	par     *PushedAuthorizations
+
+	requestObjects *RequestObjectVerifier
+	jarm           *ResponseSigner
+	resources      *ResourceIndicators
+	detailsTypes   map[string]AuthorizationDetailsValidator
+	introspection  *TokenIntrospection
+	revocation     *TokenRevocation
+	logout         *Logout
+}
+
 // NewOauth2Server initializes the OAuth2 server.
 func NewOauth2Server(
 	jwtGen oauth2.AccessGenerate,
@@ -13,7 +34,7 @@ func NewOauth2Server(
 	tokenStorage oauth2.TokenStore,
 	clientStorage oauth2.ClientStore,
 	authHandler Handler,
-) (*server.Server, *manage.Manager) {
+) (*Server, *manage.Manager) {
 	manager := manage.NewDefaultManager()
 
 	manager.SetAuthorizeCodeTokenCfg(manage.DefaultAuthorizeCodeTokenCfg)
@@ -23,8 +44,12 @@ func NewOauth2Server(
 
 	manager.MapTokenStorage(tokenStorage)
 	manager.MapClientStorage(clientStorage)
-	manager.MapAccessGenerate(jwtGen)
-	manager.MapAuthorizeGenerate(codeGen)
+	// the token lifetimes of the grant types are overridden with the client token config
+	manager.MapAccessGenerate(clientAccessGenerate{jwtGen})
+	manager.MapAuthorizeGenerate(clientAuthorizeGenerate{codeGen})
+
+	// the redirect uri is validated by the Server against the registered client redirect uris
+	manager.SetValidateURIHandler(func(baseURI, redirectURI string) error { return nil })
 
 	// Create OAuth2 server
 	srv := server.NewDefaultServer(manager)
@@ -53,5 +78,171 @@ func NewOauth2Server(
 	srv.SetExtensionFieldsHandler(authHandler.ExtensionFieldsHandler)
 	srv.SetAuthorizeScopeHandler(authHandler.AuthorizeScopeHandler)
 
-	return srv, manager
+	return &Server{Server: srv, manager: manager}, manager
+}
+
+// ValidationAuthorizeRequest validates the authorization request including the client
+// and the redirect uri, which go-oauth2 checks only when the authorization code is issued.
+func (s *Server) ValidationAuthorizeRequest(r *http.Request) (*server.AuthorizeRequest, error) {
+	req, err := s.Server.ValidationAuthorizeRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.manager.GetClient(r.Context(), req.ClientID)
+	if err != nil {
+		return nil, errors.ErrInvalidClient
+	}
+
+	redirectURI, err := validateRedirectURI(client, req.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if redirectURI == "" {
+		redirectURI = client.GetDomain()
+	}
+	req.RedirectURI = redirectURI
+
+	// the implicit grant is enabled per client, so the response type is checked
+	// before the user is asked for the consent
+	if fn := s.ClientAuthorizedHandler; fn != nil {
+		allowed, err := fn(req.ClientID, authorizeGrantType(req.ResponseType.String()))
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errors.ErrUnauthorizedClient
+		}
+	}
+
+	if err := s.validateResponseMode(r, client, req.ResponseType); err != nil {
+		return nil, err
+	}
+	if err := s.validateAuthorizeResources(r, req.Scope); err != nil {
+		return nil, err
+	}
+	if _, err := s.validateAuthorizationDetails(r.Context(), req.ClientID, r.FormValue(authorizationDetailsParam)); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// HandleAuthorizeRequest handles the authorization request.
+// The request with an invalid client or redirect uri isn't redirected back to the client.
+// The flow follows the go-oauth2 one, but the response is returned in the requested response mode.
+func (s *Server) HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	req, err := s.ValidationAuthorizeRequest(r)
+	if err != nil {
+		return err
+	}
+
+	// the omitted redirect uri is resolved from the registered ones,
+	// so the same uri is stored with the authorization code
+	if r.FormValue("redirect_uri") == "" {
+		client, err := s.manager.GetClient(ctx, req.ClientID)
+		if err != nil {
+			return errors.ErrInvalidClient
+		}
+		if redirectURI, _ := validateRedirectURI(client, ""); redirectURI != "" {
+			r.Form.Set("redirect_uri", redirectURI)
+		}
+	}
+
+	userID, err := s.UserAuthorizationHandler(w, r)
+	if err != nil {
+		return redirectAuthorizeError(w, r, s, req, err)
+	} else if userID == "" {
+		return nil
+	}
+	req.UserID = userID
+
+	if fn := s.AuthorizeScopeHandler; fn != nil {
+		scope, err := fn(w, r)
+		if err != nil {
+			return err
+		} else if scope != "" {
+			req.Scope = scope
+		}
+	}
+
+	if fn := s.AccessTokenExpHandler; fn != nil {
+		exp, err := fn(w, r)
+		if err != nil {
+			return err
+		}
+		req.AccessTokenExp = exp
+	}
+
+	// the resources and the authorization details are stored with the authorization code,
+	// the implicit token is issued for them
+	resources := r.Form["resource"]
+	details, _ := s.validateAuthorizationDetails(ctx, req.ClientID, r.FormValue(authorizationDetailsParam))
+	if len(resources) > 0 || len(details) > 0 {
+		meta, ok := TokenMetaFromContext(ctx)
+		if !ok {
+			meta = &TokenMeta{}
+			ctx = WithTokenMeta(ctx, meta)
+		}
+		meta.Audience = resources
+		meta.Resources = resources
+		meta.AuthorizationDetails = details
+		meta.GrantedAuthorizationDetails = details
+	}
+
+	// the client domain isn't bound to the authorization code,
+	// it's used only to return the response
+	tokenReq := *req
+	tokenReq.RedirectURI = r.FormValue("redirect_uri")
+	ti, err := s.GetAuthorizeToken(ctx, &tokenReq)
+	if err != nil {
+		return redirectAuthorizeError(w, r, s, req, err)
+	}
+
+	if err := s.RedirectAuthorizeResponse(w, r, req, s.GetAuthorizeData(req.ResponseType, ti)); err != nil {
+		return err
+	}
+
+	// the request_uri of the pushed authorization request can be used only once
+	if requestURI := r.Form.Get("request_uri"); s.par != nil && requestURI != "" {
+		return s.par.Remove(ctx, requestURI)
+	}
+
+	return nil
+}
+
+// validateRedirectURI returns the redirect uri to use for the client.
+// If the client has registered redirect uris, the requested one must exactly match one of them,
+// or it may be omitted if only one uri is registered.
+// Otherwise, the redirect uri must belong to the client domain.
+// The empty string is returned if the redirect uri is omitted and can't be resolved.
+func validateRedirectURI(client oauth2.ClientInfo, redirectURI string) (string, error) {
+	var registered []string
+	if c, ok := client.(redirectURIsProvider); ok {
+		registered = c.GetRedirectURIs()
+	}
+
+	if len(registered) == 0 {
+		if redirectURI == "" {
+			return "", nil
+		}
+		if err := manage.DefaultValidateURI(client.GetDomain(), redirectURI); err != nil {
+			return "", errors.ErrInvalidRedirectURI
+		}
+		return redirectURI, nil
+	}
+
+	if redirectURI == "" {
+		if len(registered) > 1 {
+			return "", errors.ErrInvalidRequest
+		}
+		return registered[0], nil
+	}
+	if !MatchRedirectURI(registered, redirectURI) {
+		return "", errors.ErrInvalidRedirectURI
+	}
+
+	return redirectURI, nil
 }
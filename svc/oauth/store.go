@@ -15,8 +15,11 @@ import (
 type (
 	Store struct {
 		repo oauthRepository
+		log  logger
 	}
 
+	storeOption func(s *Store)
+
 	oauthRepository interface {
 		GetClientByID(ctx context.Context, id string) (repository.Client, error)
 
@@ -28,16 +31,31 @@ type (
 		GetTokenByAccess(ctx context.Context, access string) (repository.Token, error)
 		GetTokenByCode(ctx context.Context, code string) (repository.Token, error)
 		GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error)
+		RotateToken(ctx context.Context, id uuid.UUID) (int64, error)
+		DeleteTokensByFamily(ctx context.Context, familyID uuid.UUID) error
 	}
 )
 
+// WithStoreLogger sets the logger to report the refresh token reuse.
+func WithStoreLogger(log logger) storeOption {
+	return func(s *Store) {
+		s.log = log
+	}
+}
+
 // NewStore creates a new store instance.
 // The store is used to manage the client and token information.
 // Implements the interface of the oauth2.ClientStore and oauth2.TokenStore.
-func NewStore(repo oauthRepository) *Store {
-	return &Store{
+func NewStore(repo oauthRepository, opts ...storeOption) *Store {
+	s := &Store{
 		repo: repo,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // according to the ID for the client information
@@ -47,7 +65,12 @@ func (s *Store) GetByID(ctx context.Context, id string) (oauth2.ClientInfo, erro
 		return nil, fmt.Errorf("failed to get client by id: %w", err)
 	}
 
-	return NewClient(client, ""), nil
+	c := NewClient(client, "")
+	if auth, ok := ClientAuthFromContext(ctx); ok && auth.ClientID == c.ID && (isAssertionAuthMethod(auth.Method) || isTLSAuthMethod(auth.Method)) {
+		c.authVerified = true
+	}
+
+	return c, nil
 }
 
 // create and store the new token information
@@ -61,6 +84,42 @@ func (s *Store) Create(ctx context.Context, info oauth2.TokenInfo) error {
 		uid = id
 	}
 
+	var nonce, dpopJKT, acr, sid string
+	var amr []string
+	var resources []string
+	var details string
+	var authTime sql.NullTime
+	familyID, parentID := uuid.New(), uuid.NullUUID{}
+	meta, hasMeta := TokenMetaFromContext(ctx)
+	if hasMeta {
+		// the token requested with the DPoP proof is bound to the proof key
+		dpopJKT = meta.DPoPJKT
+		resources = meta.Resources
+
+		var err error
+		if details, err = encodeAuthorizationDetails(meta.GrantedAuthorizationDetails); err != nil {
+			return err
+		}
+	}
+	if t, ok := info.(*Token); ok {
+		// refresh token flow: token info is loaded from the storage
+		nonce, acr, amr, sid = t.Nonce, t.ACR, t.AMR, t.SID
+		if t.AuthTime != nil {
+			authTime = sql.NullTime{Time: *t.AuthTime, Valid: true}
+		}
+
+		// the new token continues the family of the rotated one
+		if err := s.rotate(ctx, t); err != nil {
+			return err
+		}
+		familyID, parentID = t.FamilyID, uuid.NullUUID{UUID: t.ID, Valid: true}
+	} else if hasMeta {
+		nonce, acr, amr, sid = meta.Nonce, meta.ACR, meta.AMR, meta.SessionID
+		if !meta.AuthTime.IsZero() {
+			authTime = sql.NullTime{Time: meta.AuthTime, Valid: true}
+		}
+	}
+
 	if _, err := s.repo.CreateToken(ctx, repository.CreateTokenParams{
 		ClientID:    info.GetClientID(),
 		UserID:      uuid.NullUUID{UUID: uid, Valid: uid != uuid.Nil},
@@ -100,7 +159,17 @@ func (s *Store) Create(ctx context.Context, info oauth2.TokenInfo) error {
 				Valid: true,
 			}
 		}(),
-		RefreshExpiresIn: int64(info.GetRefreshExpiresIn().Seconds()),
+		RefreshExpiresIn:     int64(info.GetRefreshExpiresIn().Seconds()),
+		Nonce:                nonce,
+		AuthTime:             authTime,
+		FamilyID:             familyID,
+		ParentID:             parentID,
+		DpopJkt:              dpopJKT,
+		Resources:            append([]string{}, resources...),
+		AuthorizationDetails: details,
+		Acr:                  acr,
+		Amr:                  append([]string{}, amr...),
+		Sid:                  sid,
 	}); err != nil {
 		return fmt.Errorf("failed to create token: %w", err)
 	}
@@ -128,7 +197,37 @@ func (s *Store) RemoveByAccess(ctx context.Context, access string) error {
 	return nil
 }
 
-// use the refresh token to delete the token information
+// rotate marks the refresh token as used, so it can't be used again.
+// The rotated token is kept until it expires to detect the reuse.
+func (s *Store) rotate(ctx context.Context, t *Token) error {
+	rotated, err := s.repo.RotateToken(ctx, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to rotate token: %w", err)
+	}
+	if rotated == 0 {
+		// the token has been rotated by a concurrent request
+		return s.revokeFamily(ctx, t)
+	}
+	return nil
+}
+
+// revokeFamily revokes all tokens issued from the same authorization
+// when the rotated refresh token is presented again, since it's likely leaked.
+func (s *Store) revokeFamily(ctx context.Context, t *Token) error {
+	if s.log != nil {
+		s.log.Warnf("refresh token reuse detected: client_id=%s, user_id=%s, family_id=%s; token family is revoked",
+			t.ClientID, t.GetUserID(), t.FamilyID)
+	}
+
+	if err := s.repo.DeleteTokensByFamily(ctx, t.FamilyID); err != nil {
+		return fmt.Errorf("failed to delete tokens by family: %w", err)
+	}
+
+	return oauth2Errors.ErrInvalidRefreshToken
+}
+
+// use the refresh token to delete the token information.
+// Rotated refresh tokens are kept until they expire to detect the reuse.
 func (s *Store) RemoveByRefresh(ctx context.Context, refresh string) error {
 	if err := s.repo.DeleteByRefresh(ctx, refresh); err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
@@ -148,6 +247,20 @@ func (s *Store) GetByCode(ctx context.Context, code string) (oauth2.TokenInfo, e
 		return nil, oauth2Errors.ErrInvalidAuthorizeCode
 	}
 
+	// pass the authorization request data to the token which will be issued for this code
+	if meta, ok := TokenMetaFromContext(ctx); ok {
+		meta.Nonce, meta.ACR, meta.AMR, meta.SessionID = token.Nonce, token.Acr, token.Amr, token.Sid
+		if token.AuthTime.Valid {
+			meta.AuthTime = token.AuthTime.Time
+		}
+	}
+	if err := grantResources(ctx, token.Resources); err != nil {
+		return nil, err
+	}
+	if err := grantAuthorizationDetails(ctx, token.AuthorizationDetails); err != nil {
+		return nil, err
+	}
+
 	return NewToken(token), nil
 }
 
@@ -174,5 +287,43 @@ func (s *Store) GetByRefresh(ctx context.Context, refresh string) (oauth2.TokenI
 		return nil, oauth2Errors.ErrInvalidRefreshToken
 	}
 
-	return NewToken(token), nil
+	t := NewToken(token)
+	if t.RotatedAt != nil {
+		return nil, s.revokeFamily(ctx, t)
+	}
+	if err := s.checkDPoPBinding(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := grantResources(ctx, t.Resources); err != nil {
+		return nil, err
+	}
+	if err := grantAuthorizationDetails(ctx, token.AuthorizationDetails); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// checkDPoPBinding checks the refresh token of the public client is presented
+// with the DPoP proof of the same key the token has been issued for.
+// The refresh tokens of the confidential clients are bound to the client authentication,
+// see: https://www.rfc-editor.org/rfc/rfc9449#section-5-8
+func (s *Store) checkDPoPBinding(ctx context.Context, t *Token) error {
+	if t.DPoPJKT == "" {
+		return nil
+	}
+
+	client, err := s.repo.GetClientByID(ctx, t.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client by id: %w", err)
+	}
+	if !client.IsPublic {
+		return nil
+	}
+
+	if meta, ok := TokenMetaFromContext(ctx); !ok || meta.DPoPJKT != t.DPoPJKT {
+		return oauth2Errors.ErrInvalidGrant
+	}
+
+	return nil
 }
@@ -0,0 +1,127 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/google/uuid"
+)
+
+type tokenRepoMock struct {
+	tokens []repository.Token
+}
+
+func (m *tokenRepoMock) GetClientByID(ctx context.Context, id string) (repository.Client, error) {
+	return repository.Client{ID: id}, nil
+}
+
+func (m *tokenRepoMock) CreateToken(ctx context.Context, arg repository.CreateTokenParams) (repository.Token, error) {
+	t := repository.Token{
+		ID:       uuid.New(),
+		ClientID: arg.ClientID,
+		UserID:   arg.UserID,
+		Access:   arg.Access,
+		Refresh:  arg.Refresh,
+		FamilyID: arg.FamilyID,
+		ParentID: arg.ParentID,
+	}
+	t.Resources = arg.Resources
+	t.AuthorizationDetails = arg.AuthorizationDetails
+	m.tokens = append(m.tokens, t)
+	return t, nil
+}
+
+func (m *tokenRepoMock) DeleteByAccess(ctx context.Context, access string) error { return nil }
+func (m *tokenRepoMock) DeleteByCode(ctx context.Context, code string) error     { return nil }
+func (m *tokenRepoMock) DeleteByRefresh(ctx context.Context, r string) error     { return nil }
+func (m *tokenRepoMock) DeleteExpiredTokens(ctx context.Context) error           { return nil }
+
+func (m *tokenRepoMock) GetTokenByAccess(ctx context.Context, access string) (repository.Token, error) {
+	return repository.Token{}, sql.ErrNoRows
+}
+
+func (m *tokenRepoMock) GetTokenByCode(ctx context.Context, code string) (repository.Token, error) {
+	return repository.Token{}, sql.ErrNoRows
+}
+
+func (m *tokenRepoMock) GetTokenByRefresh(ctx context.Context, refresh string) (repository.Token, error) {
+	for _, t := range m.tokens {
+		if t.Refresh == refresh {
+			return t, nil
+		}
+	}
+	return repository.Token{}, sql.ErrNoRows
+}
+
+func (m *tokenRepoMock) RotateToken(ctx context.Context, id uuid.UUID) (int64, error) {
+	for i, t := range m.tokens {
+		if t.ID == id && !t.RotatedAt.Valid {
+			m.tokens[i].Access = ""
+			m.tokens[i].RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *tokenRepoMock) DeleteTokensByFamily(ctx context.Context, familyID uuid.UUID) error {
+	result := m.tokens[:0]
+	for _, t := range m.tokens {
+		if t.FamilyID != familyID {
+			result = append(result, t)
+		}
+	}
+	m.tokens = result
+	return nil
+}
+
+func TestStoreRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	repo := &tokenRepoMock{}
+	store := oauth.NewStore(repo)
+
+	// initial tokens are issued by the manager as new token info instances
+	for _, suffix := range []string{"1", "x"} {
+		if err := store.Create(ctx, &models.Token{ClientID: "client", Access: "access-" + suffix, Refresh: "refresh-" + suffix}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// refresh: the token is loaded by the refresh token and stored with the new values
+	ti, err := store.GetByRefresh(ctx, "refresh-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := ti.(*oauth.Token)
+	ti.SetAccess("access-2")
+	ti.SetRefresh("refresh-2")
+	if err := store.Create(ctx, ti); err != nil {
+		t.Fatal(err)
+	}
+
+	child, err := store.GetByRefresh(ctx, "refresh-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := child.(*oauth.Token); got.FamilyID != parent.FamilyID || got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Fatalf("rotated token family = %s, parent = %v; want family %s, parent %s", got.FamilyID, got.ParentID, parent.FamilyID, parent.ID)
+	}
+
+	// reuse of the rotated refresh token revokes the whole family
+	if _, err := store.GetByRefresh(ctx, "refresh-1"); !errors.Is(err, oauth2Errors.ErrInvalidRefreshToken) {
+		t.Fatalf("GetByRefresh() reuse error = %v, want %v", err, oauth2Errors.ErrInvalidRefreshToken)
+	}
+	if _, err := store.GetByRefresh(ctx, "refresh-2"); !errors.Is(err, oauth2Errors.ErrInvalidRefreshToken) {
+		t.Errorf("GetByRefresh() after reuse error = %v, want the family to be revoked", err)
+	}
+	if _, err := store.GetByRefresh(ctx, "refresh-x"); err != nil {
+		t.Errorf("GetByRefresh() another family error = %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/go-oauth2/oauth2/v4"
+	oauthErrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenExchangeGrantType is the grant type of the token exchange.
+// See: https://www.rfc-editor.org/rfc/rfc8693
+const TokenExchangeGrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers,
+// see: https://www.rfc-editor.org/rfc/rfc8693#section-3
+const (
+	AccessTokenType  = "urn:ietf:params:oauth:token-type:access_token"
+	RefreshTokenType = "urn:ietf:params:oauth:token-type:refresh_token"
+)
+
+type (
+	// TokenExchangeGrant implements the token exchange grant.
+	// The client swaps the subject token for a down-scoped access token
+	// aimed at another audience. The client policy limits the subject token types,
+	// audiences and scopes the client may exchange for.
+	TokenExchangeGrant struct {
+		repo   tokenExchangeRepository
+		tokens tokenExchanger
+	}
+
+	tokenExchangeRepository interface {
+		GetTokenExchangePolicy(ctx context.Context, clientID string) (repository.TokenExchangePolicy, error)
+	}
+
+	tokenExchanger interface {
+		tokenGenerator
+		LoadAccessToken(ctx context.Context, access string) (oauth2.TokenInfo, error)
+		LoadRefreshToken(ctx context.Context, refresh string) (oauth2.TokenInfo, error)
+	}
+
+	// ActorClaim represents the act claim of the exchanged token.
+	// The nested act claim identifies the prior actor in the delegation chain.
+	// See: https://www.rfc-editor.org/rfc/rfc8693#section-4.1
+	ActorClaim struct {
+		Subject  string      `json:"sub"`
+		ClientID string      `json:"client_id,omitempty"`
+		Act      *ActorClaim `json:"act,omitempty"`
+	}
+)
+
+// NewTokenExchangeGrant creates a new token exchange grant handler.
+func NewTokenExchangeGrant(repo tokenExchangeRepository, tokens tokenExchanger) *TokenExchangeGrant {
+	return &TokenExchangeGrant{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+// GrantType returns the token exchange grant type.
+func (g *TokenExchangeGrant) GrantType() oauth2.GrantType {
+	return TokenExchangeGrantType
+}
+
+// Token validates the subject and actor tokens against the client policy
+// and issues the exchanged access token.
+func (g *TokenExchangeGrant) Token(ctx context.Context, client oauth2.ClientInfo, tgr *oauth2.TokenGenerateRequest, r *http.Request) (oauth2.TokenInfo, error) {
+	policy, err := g.repo.GetTokenExchangePolicy(ctx, client.GetID())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthErrors.ErrUnauthorizedClient
+		}
+		return nil, fmt.Errorf("failed to get token exchange policy: %w", err)
+	}
+
+	subjectToken, subjectTokenType := r.FormValue("subject_token"), r.FormValue("subject_token_type")
+	if subjectToken == "" || subjectTokenType == "" {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+	if t := r.FormValue("requested_token_type"); t != "" && t != AccessTokenType {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+	if !contains(policy.SubjectTokenTypes, subjectTokenType) {
+		return nil, oauthErrors.ErrUnauthorizedClient
+	}
+
+	subject, err := g.loadToken(ctx, subjectToken, subjectTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	// the target of the exchanged token may be set by both parameters
+	audience := make([]string, 0, len(r.Form["audience"])+len(r.Form["resource"]))
+	audience = append(audience, r.Form["audience"]...)
+	audience = append(audience, r.Form["resource"]...)
+	for _, aud := range audience {
+		if !contains(policy.Audiences, aud) {
+			return nil, ErrInvalidTarget
+		}
+	}
+
+	scope := tgr.Scope
+	if scope == "" {
+		scope = subject.GetScope()
+	}
+	if scope != "" && (!MatchScopesStrict(scope, subject.GetScope()) || !MatchScopesStrict(scope, policy.Scope)) {
+		return nil, oauthErrors.ErrInvalidScope
+	}
+
+	act, err := g.actor(ctx, client, r)
+	if err != nil {
+		return nil, err
+	}
+	if subjectTokenType == AccessTokenType {
+		act.Act = actorFromToken(subjectToken)
+	}
+
+	tgr.UserID = subject.GetUserID()
+	tgr.Scope = scope
+	// the exchanged token doesn't outlive the subject token
+	if subjectTokenType == AccessTokenType && subject.GetAccessExpiresIn() > 0 {
+		tgr.AccessTokenExp = time.Until(subject.GetAccessCreateAt().Add(subject.GetAccessExpiresIn()))
+	}
+
+	meta, ok := TokenMetaFromContext(ctx)
+	if !ok {
+		meta = &TokenMeta{}
+		ctx = WithTokenMeta(ctx, meta)
+	}
+	meta.Audience = audience
+	meta.Resources = audience
+	meta.Act = act
+	if subjectTokenType == AccessTokenType && subject.GetAccessExpiresIn() > 0 {
+		meta.AccessNotAfter = subject.GetAccessCreateAt().Add(subject.GetAccessExpiresIn())
+	}
+
+	// the exchanged token is issued with the client credentials config,
+	// so it has no refresh token and the public clients are rejected
+	return g.tokens.GenerateAccessToken(ctx, oauth2.ClientCredentials, tgr)
+}
+
+// ExtendTokenResponse adds the issued token type to the token response.
+func (g *TokenExchangeGrant) ExtendTokenResponse(ti oauth2.TokenInfo, data map[string]interface{}) {
+	data["issued_token_type"] = AccessTokenType
+}
+
+// loadToken loads the valid subject token of the given type
+func (g *TokenExchangeGrant) loadToken(ctx context.Context, token, tokenType string) (oauth2.TokenInfo, error) {
+	var ti oauth2.TokenInfo
+	var err error
+	switch tokenType {
+	case AccessTokenType:
+		ti, err = g.tokens.LoadAccessToken(ctx, token)
+	case RefreshTokenType:
+		ti, err = g.tokens.LoadRefreshToken(ctx, token)
+	default:
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+	if err != nil {
+		return nil, oauthErrors.ErrInvalidGrant
+	}
+
+	return ti, nil
+}
+
+// actor returns the party acting on behalf of the subject:
+// the subject of the actor token if it's passed, otherwise the client itself.
+func (g *TokenExchangeGrant) actor(ctx context.Context, client oauth2.ClientInfo, r *http.Request) (*ActorClaim, error) {
+	actorToken := r.FormValue("actor_token")
+	if actorToken == "" {
+		return &ActorClaim{Subject: client.GetID(), ClientID: client.GetID()}, nil
+	}
+	if r.FormValue("actor_token_type") != AccessTokenType {
+		return nil, oauthErrors.ErrInvalidRequest
+	}
+
+	ti, err := g.loadToken(ctx, actorToken, AccessTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	act := &ActorClaim{Subject: ti.GetUserID(), ClientID: ti.GetClientID()}
+	if act.Subject == "" {
+		act.Subject = ti.GetClientID()
+	}
+
+	return act, nil
+}
+
+// actorFromToken returns the act claim of the access token issued by this server.
+// The token has been already found in the storage, so the signature isn't verified.
+func actorFromToken(access string) *ActorClaim {
+	claims := &accessTokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(access, claims); err != nil {
+		return nil
+	}
+	return claims.Act
+}
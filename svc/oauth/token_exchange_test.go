@@ -0,0 +1,158 @@
+package oauth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+type tokenExchangeRepoMock struct {
+	policies map[string]repository.TokenExchangePolicy
+}
+
+func (m *tokenExchangeRepoMock) GetTokenExchangePolicy(ctx context.Context, clientID string) (repository.TokenExchangePolicy, error) {
+	p, ok := m.policies[clientID]
+	if !ok {
+		return repository.TokenExchangePolicy{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+type tokenExchangerMock struct {
+	tokens map[string]oauth2.TokenInfo
+	meta   *oauth.TokenMeta
+}
+
+func (m *tokenExchangerMock) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+	m.meta, _ = oauth.TokenMetaFromContext(ctx)
+	return &models.Token{ClientID: tgr.ClientID, UserID: tgr.UserID, Scope: tgr.Scope, AccessExpiresIn: tgr.AccessTokenExp}, nil
+}
+
+func (m *tokenExchangerMock) LoadAccessToken(ctx context.Context, access string) (oauth2.TokenInfo, error) {
+	if ti, ok := m.tokens[access]; ok {
+		return ti, nil
+	}
+	return nil, oauth2Errors.ErrInvalidAccessToken
+}
+
+func (m *tokenExchangerMock) LoadRefreshToken(ctx context.Context, refresh string) (oauth2.TokenInfo, error) {
+	return nil, oauth2Errors.ErrInvalidRefreshToken
+}
+
+func TestTokenExchangeGrant(t *testing.T) {
+	repo := &tokenExchangeRepoMock{policies: map[string]repository.TokenExchangePolicy{
+		"service": {
+			ClientID:          "service",
+			SubjectTokenTypes: []string{oauth.AccessTokenType},
+			Audiences:         []string{"https://api.example.com"},
+			Scope:             "user:*",
+		},
+	}}
+	subject := &models.Token{
+		ClientID:        "frontend",
+		UserID:          "user-id",
+		Scope:           "user:read user:write client:read",
+		AccessCreateAt:  time.Now(),
+		AccessExpiresIn: time.Hour,
+	}
+	grant := oauth.NewTokenExchangeGrant(repo, &tokenExchangerMock{tokens: map[string]oauth2.TokenInfo{"subject": subject}})
+
+	tests := []struct {
+		name     string
+		clientID string
+		form     url.Values
+		wantErr  error
+	}{
+		{
+			name:     "no policy",
+			clientID: "frontend",
+			form:     url.Values{"subject_token": {"subject"}, "subject_token_type": {oauth.AccessTokenType}},
+			wantErr:  oauth2Errors.ErrUnauthorizedClient,
+		},
+		{
+			name:     "subject token type is not allowed",
+			clientID: "service",
+			form:     url.Values{"subject_token": {"subject"}, "subject_token_type": {oauth.RefreshTokenType}},
+			wantErr:  oauth2Errors.ErrUnauthorizedClient,
+		},
+		{
+			name:     "invalid subject token",
+			clientID: "service",
+			form:     url.Values{"subject_token": {"invalid"}, "subject_token_type": {oauth.AccessTokenType}},
+			wantErr:  oauth2Errors.ErrInvalidGrant,
+		},
+		{
+			name:     "audience is not allowed",
+			clientID: "service",
+			form:     url.Values{"subject_token": {"subject"}, "subject_token_type": {oauth.AccessTokenType}, "audience": {"https://other.example.com"}},
+			wantErr:  oauth.ErrInvalidTarget,
+		},
+		{
+			name:     "scope is not granted to the subject",
+			clientID: "service",
+			form:     url.Values{"subject_token": {"subject"}, "subject_token_type": {oauth.AccessTokenType}, "scope": {"user:delete"}},
+			wantErr:  oauth2Errors.ErrInvalidScope,
+		},
+		{
+			name:     "scope is not allowed by the policy",
+			clientID: "service",
+			form:     url.Values{"subject_token": {"subject"}, "subject_token_type": {oauth.AccessTokenType}, "scope": {"client:read"}},
+			wantErr:  oauth2Errors.ErrInvalidScope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTokenExchangeRequest(tt.form)
+			_, err := grant.Token(r.Context(), &oauth.Client{ID: tt.clientID}, &oauth2.TokenGenerateRequest{ClientID: tt.clientID, Scope: r.FormValue("scope")}, r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Token() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("down-scoped token", func(t *testing.T) {
+		tokens := &tokenExchangerMock{tokens: map[string]oauth2.TokenInfo{"subject": subject}}
+		grant := oauth.NewTokenExchangeGrant(repo, tokens)
+
+		r := newTokenExchangeRequest(url.Values{
+			"subject_token":      {"subject"},
+			"subject_token_type": {oauth.AccessTokenType},
+			"audience":           {"https://api.example.com"},
+			"scope":              {"user:read"},
+		})
+		ti, err := grant.Token(r.Context(), &oauth.Client{ID: "service"}, &oauth2.TokenGenerateRequest{ClientID: "service", Scope: "user:read"}, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ti.GetUserID() != subject.UserID || ti.GetScope() != "user:read" {
+			t.Errorf("Token() user = %s, scope = %s; want %s, user:read", ti.GetUserID(), ti.GetScope(), subject.UserID)
+		}
+		if exp := ti.GetAccessExpiresIn(); exp <= 0 || exp > time.Hour {
+			t.Errorf("Token() expires in %s, want not longer than the subject token", exp)
+		}
+		if meta := tokens.meta; meta == nil || len(meta.Audience) != 1 || meta.Audience[0] != "https://api.example.com" ||
+			meta.Act == nil || meta.Act.Subject != "service" {
+			t.Errorf("Token() meta = %+v, want the requested audience and the client as the actor", meta)
+		}
+	})
+}
+
+func newTokenExchangeRequest(form url.Values) *http.Request {
+	form.Set("grant_type", string(oauth.TokenExchangeGrantType))
+	r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
@@ -2,22 +2,53 @@ package oauth
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/dmitrymomot/oauth2-server/internal/httpencoder"
 	"github.com/dmitrymomot/oauth2-server/internal/session"
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
 	"github.com/go-chi/chi/v5"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/google/uuid"
+)
+
+// Endpoint paths relative to the oauth handler mount point
+const (
+	AuthorizePath  = "/authorize"
+	TokenPath      = "/token"
+	RevokePath     = "/revoke"
+	IntrospectPath = "/introspect"
+	UserInfoPath   = "/userinfo"
+
+	DeviceAuthorizationPath        = "/device_authorization"
+	PushedAuthorizationRequestPath = "/par"
+	RegistrationPath               = "/register"
+	BackchannelAuthenticationPath  = "/bc-authorize"
+	LogoutPath                     = "/logout"
 )
 
 type (
 	oauth2Server interface {
 		HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request) error
 		HandleTokenRequest(w http.ResponseWriter, r *http.Request) error
+		HandleDeviceAuthorizationRequest(w http.ResponseWriter, r *http.Request) error
+		HandlePushedAuthorizationRequest(w http.ResponseWriter, r *http.Request) error
+		HandleBackchannelAuthenticationRequest(w http.ResponseWriter, r *http.Request) error
+		HandleIntrospectionRequest(w http.ResponseWriter, r *http.Request) error
+		HandleRevocationRequest(w http.ResponseWriter, r *http.Request) error
+		HandleLogoutRequest(w http.ResponseWriter, r *http.Request) error
+		ResolveAuthorizeRequest(r *http.Request) error
+		ValidationAuthorizeRequest(r *http.Request) (*server.AuthorizeRequest, error)
+		RedirectAuthorizeResponse(w http.ResponseWriter, r *http.Request, req *server.AuthorizeRequest, data map[string]interface{}) error
+		GetErrorData(err error) (map[string]interface{}, int, http.Header)
 	}
 
 	logger interface {
@@ -26,23 +57,53 @@ type (
 	}
 
 	tokenStoreManager interface {
-		RemoveAccessToken(ctx context.Context, access string) error
-		RemoveRefreshToken(ctx context.Context, refresh string) error
 		LoadAccessToken(ctx context.Context, access string) (oauth2.TokenInfo, error)
 		LoadRefreshToken(ctx context.Context, refresh string) (oauth2.TokenInfo, error)
 	}
+
+	userRepository interface {
+		GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error)
+	}
+
+	httpHandlerOptions struct {
+		dpop        *DPoPVerifier
+		userInfoURI string
+	}
+
+	httpHandlerOption func(o *httpHandlerOptions)
 )
 
+// WithUserInfoDPoP accepts the DPoP-bound access tokens on the userinfo endpoint,
+// the proofs are issued for the userInfoURI. If it's not set, the DPoP-bound tokens are rejected.
+func WithUserInfoDPoP(v *DPoPVerifier, userInfoURI string) httpHandlerOption {
+	return func(o *httpHandlerOptions) {
+		o.dpop = v
+		o.userInfoURI = userInfoURI
+	}
+}
+
 // MakeHTTPHandler returns a handler that makes a set of endpoints available on
 // predefined paths.
-func MakeHTTPHandler(srv oauth2Server, ts tokenStoreManager, log logger, loginURI string) http.Handler {
+func MakeHTTPHandler(srv oauth2Server, ts tokenStoreManager, repo userRepository, consent consentManager, log logger, loginURI string, opts ...httpHandlerOption) http.Handler {
 	r := chi.NewRouter()
 	errEncoder := httpencoder.EncodeError(log, codeAndMessageFrom)
 
-	r.Post("/token", httpTokenHandler(srv, errEncoder))
-	r.HandleFunc("/authorize", httpAuthorizeHandler(srv, errEncoder, loginURI))
-	r.Post("/revoke", httpRevokeTokenHandler(ts, errEncoder))
-	r.Post("/introspect", httpIntrospectTokenHandler(ts, errEncoder))
+	o := &httpHandlerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r.Post(TokenPath, httpTokenHandler(srv, errEncoder))
+	r.Post(DeviceAuthorizationPath, httpDeviceAuthorizationHandler(srv, errEncoder))
+	r.Post(PushedAuthorizationRequestPath, httpPushedAuthorizationHandler(srv, errEncoder))
+	r.Post(BackchannelAuthenticationPath, httpBackchannelAuthenticationHandler(srv, errEncoder))
+	r.HandleFunc(AuthorizePath, httpAuthorizeHandler(srv, consent, errEncoder, loginURI))
+	r.Post(RevokePath, httpRevokeTokenHandler(srv, errEncoder))
+	r.Post(IntrospectPath, httpIntrospectTokenHandler(srv, errEncoder))
+	r.Get(UserInfoPath, httpUserInfoHandler(ts, repo, o, errEncoder))
+	r.Post(UserInfoPath, httpUserInfoHandler(ts, repo, o, errEncoder))
+	r.Get(LogoutPath, httpLogoutHandler(srv, errEncoder))
+	r.Post(LogoutPath, httpLogoutHandler(srv, errEncoder))
 
 	return r
 }
@@ -59,6 +120,15 @@ func codeAndMessageFrom(err error) (int, interface{}) {
 // available on predefined paths.
 func httpTokenHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// token meta is filled in by the token store from the authorization code,
+		// the tokens requested with the TLS client certificate are bound to it
+		meta := &TokenMeta{}
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			meta.CertThumbprint = jwk.CertificateThumbprint(r.TLS.PeerCertificates[0])
+		}
+		r = r.WithContext(WithTokenMeta(r.Context(), meta))
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
+
 		if err := s.HandleTokenRequest(w, r); err != nil {
 			errEncoder(r.Context(), err, w)
 			return
@@ -66,9 +136,48 @@ func httpTokenHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) htt
 	}
 }
 
+// httpDeviceAuthorizationHandler returns an http.HandlerFunc that serves
+// the device authorization endpoint.
+func httpDeviceAuthorizationHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
+
+		if err := s.HandleDeviceAuthorizationRequest(w, r); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+	}
+}
+
+// httpPushedAuthorizationHandler returns an http.HandlerFunc that serves
+// the pushed authorization request endpoint.
+func httpPushedAuthorizationHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
+
+		if err := s.HandlePushedAuthorizationRequest(w, r); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+	}
+}
+
+// httpBackchannelAuthenticationHandler returns an http.HandlerFunc that serves
+// the backchannel authentication endpoint.
+func httpBackchannelAuthenticationHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
+
+		if err := s.HandleBackchannelAuthenticationRequest(w, r); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+	}
+}
+
 // httpAuthorizeHandler returns an http.HandlerFunc that makes a set of endpoints
 // available on predefined paths.
-func httpAuthorizeHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder, loginURI string) http.HandlerFunc {
+func httpAuthorizeHandler(s oauth2Server, consent consentManager, errEncoder httptransport.ErrorEncoder, loginURI string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodPost {
 			errEncoder(r.Context(), ErrMethodNotAllowed, w)
@@ -85,52 +194,68 @@ func httpAuthorizeHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder,
 			r.Form = session.GetRedirectData(r, w)
 		}
 
+		// the parameters are loaded from the pushed authorization request
+		// or the signed request object
+		if err := s.ResolveAuthorizeRequest(r); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+
+		// OpenID Connect request data to store with the code or implicit token,
+		// the user is authenticated with the password on the login page
+		meta := &TokenMeta{Nonce: r.FormValue("nonce"), AMR: []string{AMRPassword}}
+		if authTime, ok := session.GetAuthTime(r, w); ok {
+			meta.AuthTime = authTime
+		}
+		if sid, ok := session.GetSessionID(r, w); ok {
+			meta.SessionID = sid
+		}
+		r = r.WithContext(WithTokenMeta(r.Context(), meta))
+
+		// the consent page is rendered or the request is denied by the user
+		if ok, err := handleConsent(w, r, s, consent); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		} else if !ok {
+			return
+		}
+
 		if err := s.HandleAuthorizeRequest(w, r); err != nil {
 			errEncoder(r.Context(), err, w)
 			return
 		}
 
-		if err := session.Logout(r, w); err != nil {
-			log.Printf("failed to logout: %v", err)
+		// the session is kept for the single sign-on,
+		// the client is notified when the user logs out
+		if err := session.AddSignedInClient(r, w, r.FormValue("client_id")); err != nil {
+			log.Printf("failed to store signed in client: %v", err)
 		}
 
 		log.Println("user redirected to", r.FormValue("redirect_uri"))
 	}
 }
 
-// httpRevokeTokenHandler returns an http.HandlerFunc that makes a set of endpoints
-// available on predefined paths.
-func httpRevokeTokenHandler(ts tokenStoreManager, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+// httpLogoutHandler returns an http.HandlerFunc that serves
+// the RP-initiated logout endpoint.
+func httpLogoutHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := r.ParseForm(); err != nil {
+		if err := s.HandleLogoutRequest(w, r); err != nil {
 			errEncoder(r.Context(), err, w)
 			return
 		}
+	}
+}
 
-		token := r.PostForm.Get("token")
-		tokenType := r.PostForm.Get("token_type_hint")
+// httpRevokeTokenHandler returns an http.HandlerFunc that serves
+// the token revocation endpoint.
+func httpRevokeTokenHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
 
-		switch tokenType {
-		case "access_token":
-			if err := ts.RemoveAccessToken(r.Context(), token); err != nil {
-				errEncoder(r.Context(), err, w)
-				return
-			}
-		case "refresh_token":
-			if err := ts.RemoveRefreshToken(r.Context(), token); err != nil {
-				errEncoder(r.Context(), err, w)
-				return
-			}
-		default:
-			if err := ts.RemoveAccessToken(r.Context(), token); err != nil {
-				if err := ts.RemoveRefreshToken(r.Context(), token); err != nil {
-					errEncoder(r.Context(), err, w)
-					return
-				}
-			}
+		if err := s.HandleRevocationRequest(w, r); err != nil {
+			errEncoder(r.Context(), err, w)
+			return
 		}
-
-		w.WriteHeader(http.StatusOK)
 	}
 }
 
@@ -148,81 +273,122 @@ type (
 		Audience  string `json:"aud,omitempty"`
 		Issuer    string `json:"iss,omitempty"`
 		TokenID   string `json:"jti,omitempty"`
+
+		Cnf                  *ConfirmationClaim    `json:"cnf,omitempty"`
+		AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
 	}
 )
 
-// httpIntrospectTokenHandler returns an http.HandlerFunc that makes a set of endpoints
-// available on predefined paths.
-func httpIntrospectTokenHandler(ts tokenStoreManager, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+// httpIntrospectTokenHandler returns an http.HandlerFunc that serves
+// the token introspection endpoint.
+func httpIntrospectTokenHandler(s oauth2Server, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := r.ParseForm(); err != nil {
+		r = r.WithContext(WithClientAuth(r.Context(), &ClientAuth{}))
+
+		if err := s.HandleIntrospectionRequest(w, r); err != nil {
 			errEncoder(r.Context(), err, w)
 			return
 		}
+	}
+}
 
-		token := r.PostForm.Get("token")
-		tokenType := r.PostForm.Get("token_type_hint")
+type (
+	UserInfoResponse struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email,omitempty"`
+		EmailVerified *bool  `json:"email_verified,omitempty"`
+	}
+)
 
-		var (
-			ti     oauth2.TokenInfo
-			err    error
-			active bool
-			expAt  int64
-			iat    int64
-		)
+// httpUserInfoHandler returns an http.HandlerFunc that serves
+// the OpenID Connect userinfo endpoint.
+// The sender-constrained access tokens are accepted only from their holder.
+func httpUserInfoHandler(ts tokenStoreManager, repo userRepository, o *httpHandlerOptions, errEncoder httptransport.ErrorEncoder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scheme, token := getAccessTokenFromRequest(r)
+		if token == "" {
+			errEncoder(r.Context(), ErrInvalidAccessToken, w)
+			return
+		}
 
-		switch tokenType {
-		case "access_token":
-			ti, err = ts.LoadAccessToken(r.Context(), token)
-			if err == nil && ti != nil {
-				active = ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).After(time.Now())
-				expAt = ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).Unix()
-				iat = ti.GetAccessCreateAt().Unix()
-			}
-		case "refresh_token":
-			ti, err = ts.LoadRefreshToken(r.Context(), token)
-			if err == nil && ti != nil {
-				active = ti.GetRefreshCreateAt().Add(ti.GetRefreshExpiresIn()).After(time.Now())
-				expAt = ti.GetRefreshCreateAt().Add(ti.GetRefreshExpiresIn()).Unix()
-				iat = ti.GetRefreshCreateAt().Unix()
-			}
-		default:
-			ti, err = ts.LoadAccessToken(r.Context(), token)
-			if err == nil && ti != nil {
-				active = ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).After(time.Now())
-				expAt = ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).Unix()
-				iat = ti.GetAccessCreateAt().Unix()
-				tokenType = "access_token"
-			} else {
-				ti, err = ts.LoadRefreshToken(r.Context(), token)
-				if err == nil && ti != nil {
-					active = ti.GetRefreshCreateAt().Add(ti.GetRefreshExpiresIn()).After(time.Now())
-					expAt = ti.GetRefreshCreateAt().Add(ti.GetRefreshExpiresIn()).Unix()
-					iat = ti.GetRefreshCreateAt().Unix()
-					tokenType = "refresh_token"
-				}
+		ti, err := ts.LoadAccessToken(r.Context(), token)
+		if err != nil {
+			errEncoder(r.Context(), err, w)
+			return
+		}
+
+		if err := verifyAccessTokenBinding(r, scheme, token, o); err != nil {
+			if errors.Is(err, ErrInvalidDPoPProof) {
+				w.Header().Set("WWW-Authenticate", `DPoP error="invalid_dpop_proof"`)
 			}
+			errEncoder(r.Context(), ErrInvalidAccessToken, w)
+			return
 		}
 
+		if !MatchScope(ScopeOpenID, ti.GetScope()) {
+			errEncoder(r.Context(), ErrInsufficientScope, w)
+			return
+		}
+
+		uid, err := uuid.Parse(ti.GetUserID())
 		if err != nil {
-			errEncoder(r.Context(), err, w)
+			errEncoder(r.Context(), ErrInvalidAccessToken, w)
 			return
 		}
 
-		if err := json.NewEncoder(w).Encode(IntrospectResponse{
-			Active:    active,
-			Scope:     ti.GetScope(),
-			ClientID:  ti.GetClientID(),
-			UserID:    ti.GetUserID(),
-			TokenType: tokenType,
-			ExpiresAt: expAt,
-			IssuedAt:  iat,
-			NotBefore: iat,
-			Subject:   ti.GetUserID(),
-			Audience:  ti.GetClientID(),
-		}); err != nil {
+		user, err := repo.GetUserByID(r.Context(), uid)
+		if err != nil {
+			errEncoder(r.Context(), ErrInvalidAccessToken, w)
+			return
+		}
+
+		resp := UserInfoResponse{Subject: user.ID.String()}
+		if MatchScope(ScopeEmail, ti.GetScope()) {
+			verified := user.VerifiedAt.Valid
+			resp.Email = user.Email
+			resp.EmailVerified = &verified
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			errEncoder(r.Context(), err, w)
 			return
 		}
 	}
 }
+
+// verifyAccessTokenBinding checks the sender-constrained access token is presented by its holder:
+// the certificate-bound token with the same TLS client certificate (RFC 8705, section 3)
+// and the DPoP-bound token with the DPoP scheme and the proof of the same key (RFC 9449, section 7).
+// The bound tokens can't be passed in the access_token parameter.
+func verifyAccessTokenBinding(r *http.Request, scheme, token string, o *httpHandlerOptions) error {
+	var cnf ConfirmationClaim
+	if claims := claimsFromToken(token); claims != nil && claims.Cnf != nil {
+		cnf = *claims.Cnf
+	}
+
+	if scheme == "" && (cnf.X5tS256 != "" || cnf.JKT != "") {
+		return ErrInvalidAccessToken
+	}
+	if cnf.X5tS256 != "" {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return ErrInvalidAccessToken
+		}
+		thumbprint := jwk.CertificateThumbprint(r.TLS.PeerCertificates[0])
+		if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(cnf.X5tS256)) != 1 {
+			return ErrInvalidAccessToken
+		}
+	}
+
+	if scheme != dpop.TokenType {
+		if cnf.JKT != "" {
+			return ErrInvalidDPoPProof
+		}
+		return nil
+	}
+	if cnf.JKT == "" || o.dpop == nil {
+		return ErrInvalidDPoPProof
+	}
+
+	return o.dpop.VerifyAccess(r, o.userInfoURI, token, cnf.JKT)
+}
@@ -0,0 +1,242 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/oauth2-server/internal/testutil"
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
+	"github.com/dmitrymomot/oauth2-server/lib/jwk"
+	"github.com/dmitrymomot/oauth2-server/repository"
+	"github.com/dmitrymomot/oauth2-server/svc/oauth"
+	"github.com/go-oauth2/oauth2/v4"
+	oauth2Errors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type userInfoTokensMock struct {
+	tokens map[string]oauth2.TokenInfo
+}
+
+func (m userInfoTokensMock) LoadAccessToken(ctx context.Context, access string) (oauth2.TokenInfo, error) {
+	if ti, ok := m.tokens[access]; ok {
+		return ti, nil
+	}
+	return nil, oauth2Errors.ErrInvalidAccessToken
+}
+
+func (m userInfoTokensMock) LoadRefreshToken(ctx context.Context, refresh string) (oauth2.TokenInfo, error) {
+	return nil, oauth2Errors.ErrInvalidRefreshToken
+}
+
+type userInfoRepoMock struct {
+	users map[uuid.UUID]repository.User
+}
+
+func (m userInfoRepoMock) GetUserByID(ctx context.Context, id uuid.UUID) (repository.User, error) {
+	if u, ok := m.users[id]; ok {
+		return u, nil
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
+func TestUserInfo(t *testing.T) {
+	userID := uuid.New()
+	repo := userInfoRepoMock{users: map[uuid.UUID]repository.User{userID: {
+		ID:         userID,
+		Email:      "user@example.com",
+		VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}}}
+	tokens := userInfoTokensMock{tokens: map[string]oauth2.TokenInfo{
+		"openid":       &models.Token{UserID: userID.String(), Scope: "openid"},
+		"email":        &models.Token{UserID: userID.String(), Scope: "openid email"},
+		"no-openid":    &models.Token{UserID: userID.String(), Scope: "email"},
+		"client":       &models.Token{Scope: "openid"},
+		"unknown-user": &models.Token{UserID: uuid.NewString(), Scope: "openid"},
+	}}
+	h := oauth.MakeHTTPHandler(nil, tokens, repo, nil, &loggerMock{}, "/auth/login")
+
+	tests := []struct {
+		name      string
+		token     string
+		wantCode  int
+		wantEmail bool
+	}{
+		{name: "openid scope", token: "openid", wantCode: http.StatusOK},
+		{name: "email scope", token: "email", wantCode: http.StatusOK, wantEmail: true},
+		{name: "without openid scope", token: "no-openid", wantCode: http.StatusForbidden},
+		{name: "client credentials token", token: "client", wantCode: http.StatusUnauthorized},
+		{name: "unknown user", token: "unknown-user", wantCode: http.StatusUnauthorized},
+		{name: "unknown token", token: "unknown", wantCode: http.StatusUnauthorized},
+		{name: "without token", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, oauth.UserInfoPath, nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("userinfo status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp oauth.UserInfoResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Subject != userID.String() {
+				t.Errorf("userinfo sub = %s, want %s", resp.Subject, userID)
+			}
+			if tt.wantEmail && (resp.Email != "user@example.com" || resp.EmailVerified == nil || !*resp.EmailVerified) {
+				t.Errorf("userinfo email = %s, email_verified = %v", resp.Email, resp.EmailVerified)
+			}
+			if !tt.wantEmail && (resp.Email != "" || resp.EmailVerified != nil) {
+				t.Errorf("userinfo has the email claims without the email scope")
+			}
+		})
+	}
+}
+
+// userInfoURI is the userinfo endpoint URI the DPoP proofs are issued for
+const userInfoURI = "https://example.com/oauth/userinfo"
+
+// newBoundAccessToken returns the access token with the confirmation claim,
+// the signature isn't verified by the userinfo endpoint
+func newBoundAccessToken(t *testing.T, cnf oauth.ConfirmationClaim) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"cnf": cnf}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// newUserInfoProof signs the DPoP proof of the userinfo request presenting the access token
+func newUserInfoProof(t *testing.T, key *ecdsa.PrivateKey, method, token string) string {
+	t.Helper()
+
+	pub, err := jwk.NewKey("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := jwt.NewWithClaims(jwt.SigningMethodES256, dpop.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       uuid.NewString(),
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+		Method:          method,
+		URI:             userInfoURI,
+		AccessTokenHash: dpop.AccessTokenHash(token),
+	})
+	proof.Header["typ"] = "dpop+jwt"
+	proof.Header["jwk"] = pub
+
+	signed, err := proof.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestUserInfo_TokenBinding(t *testing.T) {
+	userID := uuid.New()
+	repo := userInfoRepoMock{users: map[uuid.UUID]repository.User{userID: {ID: userID, Email: "user@example.com"}}}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anotherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwk.NewKey("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt, err := pub.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, caKey := testutil.NewCertificate(t, "Test CA", nil, nil)
+	clientCert, _ := testutil.NewCertificate(t, "client", ca, caKey)
+	otherCert, _ := testutil.NewCertificate(t, "other", ca, caKey)
+
+	dpopBound := newBoundAccessToken(t, oauth.ConfirmationClaim{JKT: jkt})
+	certBound := newBoundAccessToken(t, oauth.ConfirmationClaim{X5tS256: jwk.CertificateThumbprint(clientCert)})
+	unbound := newBoundAccessToken(t, oauth.ConfirmationClaim{})
+	tokens := userInfoTokensMock{tokens: map[string]oauth2.TokenInfo{}}
+	for _, access := range []string{dpopBound, certBound, unbound} {
+		tokens.tokens[access] = &models.Token{UserID: userID.String(), Scope: "openid", Access: access}
+	}
+
+	verifier := oauth.NewDPoPVerifier("https://example.com/oauth/token", dpop.NewNonces("secret", time.Minute), dpop.NewMemoryReplayCache())
+	h := oauth.MakeHTTPHandler(nil, tokens, repo, nil, &loggerMock{}, "/auth/login", oauth.WithUserInfoDPoP(verifier, userInfoURI))
+
+	tests := []struct {
+		name     string
+		scheme   string
+		token    string
+		inForm   bool
+		proofKey *ecdsa.PrivateKey
+		cert     *x509.Certificate
+		wantCode int
+	}{
+		{name: "dpop-bound token with proof", scheme: "DPoP", token: dpopBound, proofKey: key, wantCode: http.StatusOK},
+		{name: "dpop-bound token without proof", scheme: "DPoP", token: dpopBound, wantCode: http.StatusUnauthorized},
+		{name: "dpop-bound token with proof of another key", scheme: "DPoP", token: dpopBound, proofKey: anotherKey, wantCode: http.StatusUnauthorized},
+		{name: "dpop-bound token as bearer token", scheme: "Bearer", token: dpopBound, proofKey: key, wantCode: http.StatusUnauthorized},
+		{name: "dpop-bound token in form parameter", token: dpopBound, inForm: true, proofKey: key, wantCode: http.StatusUnauthorized},
+		{name: "certificate-bound token with certificate", scheme: "Bearer", token: certBound, cert: clientCert, wantCode: http.StatusOK},
+		{name: "certificate-bound token without certificate", scheme: "Bearer", token: certBound, wantCode: http.StatusUnauthorized},
+		{name: "certificate-bound token with another certificate", scheme: "Bearer", token: certBound, cert: otherCert, wantCode: http.StatusUnauthorized},
+		{name: "certificate-bound token in form parameter", token: certBound, inForm: true, cert: clientCert, wantCode: http.StatusUnauthorized},
+		{name: "unbound token in form parameter", token: unbound, inForm: true, wantCode: http.StatusOK},
+		{name: "unbound token with dpop scheme", scheme: "DPoP", token: unbound, proofKey: key, wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, oauth.UserInfoPath, nil)
+			if tt.inForm {
+				form := url.Values{"access_token": {tt.token}}
+				r = httptest.NewRequest(http.MethodPost, oauth.UserInfoPath, strings.NewReader(form.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			} else {
+				r.Header.Set("Authorization", tt.scheme+" "+tt.token)
+			}
+			if tt.proofKey != nil {
+				r.Header.Set(dpop.HeaderName, newUserInfoProof(t, tt.proofKey, r.Method, tt.token))
+			}
+			if tt.cert != nil {
+				r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("userinfo status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
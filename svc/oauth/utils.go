@@ -2,6 +2,9 @@ package oauth
 
 import (
 	"net/http"
+	"strings"
+
+	"github.com/dmitrymomot/oauth2-server/lib/dpop"
 )
 
 // getScopeFromRequest get scope from request
@@ -12,3 +15,26 @@ func getScopeFromRequest(r *http.Request) string {
 	}
 	return scopes
 }
+
+// getAccessTokenFromRequest get access token and its scheme from the authorization header,
+// the Bearer or DPoP one, or from the access_token form parameter with the empty scheme
+func getAccessTokenFromRequest(r *http.Request) (string, string) {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || (parts[0] != "Bearer" && parts[0] != dpop.TokenType) {
+			return "", ""
+		}
+		return parts[0], parts[1]
+	}
+	return "", r.FormValue("access_token")
+}
+
+// contains checks if the list contains the value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
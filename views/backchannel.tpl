@@ -0,0 +1,42 @@
+{{ define "content"}}
+<div class="text-center">
+  {{include "partials/logo"}}
+  <h2 class="text-3xl font-bold tracking-tight text-gray-900 sm:text-4xl">Approve sign-in</h2>
+  {{if .request}}
+  <p class="mt-4 text-lg leading-6 text-gray-500"><span class="font-medium text-gray-700">{{.request.ClientName}}</span> is
+    requesting to sign you in</p>
+  {{end}}
+</div>
+<div class="mt-12">
+  <form action="/auth/backchannel" method="POST" role="form" id="form-backchannel" class="grid grid-cols-1 gap-y-6 sm:grid-cols-2 sm:gap-x-8">
+
+    {{template "messages" .}}
+
+    {{if .request}}
+    <input type="hidden" name="code" value="{{.request.ApprovalCode}}">
+    <input type="hidden" name="csrf_token" value="{{.csrf_token}}">
+
+    {{if .request.BindingMessage}}
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Make sure this message matches the one you were given</p>
+      <p class="mt-2 rounded-md border border-gray-200 py-3 px-4 text-center text-lg font-semibold tracking-wide text-gray-900">{{.request.BindingMessage}}</p>
+    </div>
+    {{end}}
+
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Requested permissions</p>
+      <p class="mt-2 rounded-md border border-gray-200 py-3 px-4 text-sm text-gray-900"><code>{{.request.Scope}}</code></p>
+    </div>
+
+    <div class="sm:col-span-1">
+      <button type="submit" name="action" value="deny"
+        class="inline-flex w-full items-center justify-center rounded-md border border-gray-300 bg-white px-6 py-3 text-base font-medium text-gray-700 shadow-sm hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Deny</button>
+    </div>
+    <div class="sm:col-span-1">
+      <button type="submit" name="action" value="approve"
+        class="inline-flex w-full items-center justify-center rounded-md border border-transparent bg-blue-600 px-6 py-3 text-base font-medium text-white shadow-sm hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Approve</button>
+    </div>
+    {{end}}
+  </form>
+</div>
+{{end}}
@@ -0,0 +1,26 @@
+{{define "content"}}
+<main class="flex-grow flex flex-col justify-center max-w-7xl w-full mx-auto px-4 sm:px-6 lg:px-8 sm:mt-12">
+  <div class="flex-shrink-0 flex justify-center">
+    <svg xmlns="http://www.w3.org/2000/svg" class="h-24 w-24 text-green-500" fill="none" viewBox="0 0 24 24"
+      stroke="currentColor" stroke-width="2">
+      <path stroke-linecap="round" stroke-linejoin="round" d="M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z" />
+    </svg>
+  </div>
+  <div class="py-8">
+    <div class="text-center">
+      <p class="text-sm font-semibold text-gray-400 uppercase tracking-wide">Success</p>
+      {{if .approved}}
+      <h1 class="mt-2 text-3xl font-extrabold text-gray-900 tracking-tight sm:text-4xl">Sign-in has been approved.</h1>
+      <p class="mt-2 text-base text-gray-500">
+        You can close this page, the sign-in will continue on the other device.
+      </p>
+      {{else}}
+      <h1 class="mt-2 text-3xl font-extrabold text-gray-900 tracking-tight sm:text-4xl">Request has been denied.</h1>
+      <p class="mt-2 text-base text-gray-500">
+        The client will not get access to your account.
+      </p>
+      {{end}}
+    </div>
+  </div>
+</main>
+{{end}}
@@ -0,0 +1,57 @@
+{{ define "content"}}
+<div class="text-center">
+  {{include "partials/logo"}}
+  <h2 class="text-3xl font-bold tracking-tight text-gray-900 sm:text-4xl">Authorize {{.client_name}}</h2>
+  <p class="mt-4 text-lg leading-6 text-gray-500"><span class="font-medium text-gray-700">{{.client_name}}</span> is
+    requesting access to your account</p>
+</div>
+<div class="mt-12">
+  <form action="{{.action}}" method="POST" role="form" id="form-consent"
+    class="grid grid-cols-1 gap-y-6 sm:grid-cols-2 sm:gap-x-8">
+
+    {{template "messages" .}}
+
+    {{range $key, $values := .params}}{{range $values}}
+    <input type="hidden" name="{{$key}}" value="{{.}}">
+    {{end}}{{end}}
+    <input type="hidden" name="csrf_token" value="{{.csrf_token}}">
+
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Requested permissions</p>
+      <ul role="list" class="mt-2 divide-y divide-gray-200 rounded-md border border-gray-200">
+        {{range .scopes}}
+        <li class="py-3 px-4 text-sm text-gray-900">
+          {{.Description}}
+          {{if ne .Description .Name}}<code class="block mt-1 text-xs text-gray-500">{{.Name}}</code>{{end}}
+        </li>
+        {{else}}
+        <li class="py-3 px-4 text-sm text-gray-500">Basic access to your account</li>
+        {{end}}
+      </ul>
+    </div>
+
+    {{if .authorization_details}}
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Authorization details</p>
+      <ul role="list" class="mt-2 divide-y divide-gray-200 rounded-md border border-gray-200">
+        {{range .authorization_details}}
+        <li class="py-3 px-4 text-sm text-gray-900">
+          <code>{{.type}}</code>
+          <pre class="mt-2 overflow-x-auto text-xs text-gray-500">{{.fields}}</pre>
+        </li>
+        {{end}}
+      </ul>
+    </div>
+    {{end}}
+
+    <div class="sm:col-span-1">
+      <button type="submit" name="consent" value="deny"
+        class="inline-flex w-full items-center justify-center rounded-md border border-gray-300 bg-white px-6 py-3 text-base font-medium text-gray-700 shadow-sm hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Deny</button>
+    </div>
+    <div class="sm:col-span-1">
+      <button type="submit" name="consent" value="approve"
+        class="inline-flex w-full items-center justify-center rounded-md border border-transparent bg-blue-600 px-6 py-3 text-base font-medium text-white shadow-sm hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Allow</button>
+    </div>
+  </form>
+</div>
+{{end}}
@@ -0,0 +1,81 @@
+{{ define "content"}}
+<div class="text-center">
+  {{include "partials/logo"}}
+  <h2 class="text-3xl font-bold tracking-tight text-gray-900 sm:text-4xl">Connect a device</h2>
+  {{if .request}}
+  <p class="mt-4 text-lg leading-6 text-gray-500"><span class="font-medium text-gray-700">{{.request.ClientName}}</span> is
+    requesting access to your account</p>
+  {{else}}
+  <p class="mt-4 text-lg leading-6 text-gray-500">Enter the code displayed on your device</p>
+  {{end}}
+</div>
+<div class="mt-12">
+  <form action="/auth/device" method="POST" role="form" id="form-device" class="grid grid-cols-1 gap-y-6 sm:grid-cols-2 sm:gap-x-8">
+
+    {{template "messages" .}}
+
+    <input type="hidden" name="csrf_token" value="{{.csrf_token}}">
+
+    {{if .request}}
+    <input type="hidden" name="user_code" value="{{.request.UserCode}}">
+
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Make sure this code matches the one displayed on your device</p>
+      <p class="mt-2 rounded-md border border-gray-200 py-3 px-4 text-center text-lg font-semibold tracking-widest text-gray-900">{{.request.UserCode}}</p>
+    </div>
+
+    <div class="sm:col-span-2">
+      <p class="block text-sm font-medium text-gray-700">Requested permissions</p>
+      <ul role="list" class="mt-2 divide-y divide-gray-200 rounded-md border border-gray-200">
+        {{range .request.Scopes}}
+        <li class="py-3 px-4 text-sm text-gray-900">
+          {{.Description}}
+          {{if ne .Description .Name}}<code class="block mt-1 text-xs text-gray-500">{{.Name}}</code>{{end}}
+        </li>
+        {{else}}
+        <li class="py-3 px-4 text-sm text-gray-500">Basic access to your account</li>
+        {{end}}
+      </ul>
+      {{if .request.Consented}}
+      <p class="mt-2 text-sm text-gray-500">You have already allowed {{.request.ClientName}} these permissions.</p>
+      {{end}}
+    </div>
+
+    <div class="sm:col-span-1">
+      <button type="submit" name="action" value="deny"
+        class="inline-flex w-full items-center justify-center rounded-md border border-gray-300 bg-white px-6 py-3 text-base font-medium text-gray-700 shadow-sm hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Deny</button>
+    </div>
+    <div class="sm:col-span-1">
+      <button type="submit" name="action" value="approve"
+        class="inline-flex w-full items-center justify-center rounded-md border border-transparent bg-blue-600 px-6 py-3 text-base font-medium text-white shadow-sm hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Approve</button>
+    </div>
+    {{else}}
+    <div class="sm:col-span-2">
+      <label for="user_code" class="block text-sm font-medium text-gray-700">Code</label>
+      <div class="relative mt-1">
+        {{if .validation.user_code}}
+        <input id="user_code" name="user_code" type="text" autocomplete="off" aria-invalid="true"
+          aria-describedby="user_code-error"
+          class="block w-full rounded-md border-rose-300 py-3 px-4 shadow-sm uppercase tracking-widest text-rose-900 focus:border-rose-500 focus:ring-blue-500 focus:outline-none"
+          value="{{.form.UserCode}}">
+        {{else}}
+        <input id="user_code" name="user_code" type="text" autocomplete="off" placeholder="XXXX-XXXX"
+          class="block w-full rounded-md border-gray-300 py-3 px-4 shadow-sm uppercase tracking-widest focus:border-blue-500 focus:ring-blue-500"
+          value="{{if .form.UserCode}}{{.form.UserCode}}{{end}}">
+        {{end}}
+      </div>
+      {{ if .validation.user_code }}
+      {{ range $key, $value := .validation.user_code }}
+      <p class="mt-2 text-sm text-rose-600" id="user_code-error-{{$key}}">{{$value}}</p>
+      {{end}}
+      {{end}}
+    </div>
+
+    <div class="sm:col-span-2">
+      <button type="submit"
+        class="inline-flex w-full items-center justify-center rounded-md border border-transparent bg-blue-600 px-6 py-3 text-base font-medium text-white shadow-sm hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Continue</button>
+    </div>
+    {{end}}
+  </form>
+</div>
+{{end}}
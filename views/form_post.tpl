@@ -0,0 +1,18 @@
+<!DOCTYPE html>
+<html lang="en">
+
+<head>
+  <title>Submit This Form</title>
+  <meta charset="UTF-8">
+</head>
+
+<body onload="javascript:document.forms[0].submit()">
+  <form method="POST" action="{{.redirect_uri}}">
+    {{range $key, $values := .params}}{{range $values}}
+    <input type="hidden" name="{{$key}}" value="{{.}}">
+    {{end}}{{end}}
+    <noscript><button type="submit">Continue</button></noscript>
+  </form>
+</body>
+
+</html>
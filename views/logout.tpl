@@ -0,0 +1,69 @@
+{{ define "content"}}
+{{if .confirm}}
+<div class="text-center">
+  {{include "partials/logo"}}
+  <h2 class="text-3xl font-bold tracking-tight text-gray-900 sm:text-4xl">Sign out</h2>
+  <p class="mt-4 text-lg leading-6 text-gray-500">Do you want to sign out of your account?
+    You will be signed out of all applications you have signed in to.</p>
+</div>
+<div class="mt-12">
+  <form action="{{.action}}" method="POST" role="form" id="form-logout" class="grid grid-cols-1 gap-y-6">
+
+    {{template "messages" .}}
+
+    {{range $key, $values := .params}}{{range $values}}
+    <input type="hidden" name="{{$key}}" value="{{.}}">
+    {{end}}{{end}}
+    <input type="hidden" name="csrf_token" value="{{.csrf_token}}">
+
+    <div>
+      <button type="submit" name="confirm" value="1"
+        class="inline-flex w-full items-center justify-center rounded-md border border-transparent bg-blue-600 px-6 py-3 text-base font-medium text-white shadow-sm hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2">Sign out</button>
+    </div>
+  </form>
+</div>
+{{else}}
+<main class="flex-grow flex flex-col justify-center max-w-7xl w-full mx-auto px-4 sm:px-6 lg:px-8 sm:mt-12">
+  <div class="flex-shrink-0 flex justify-center">
+    <svg xmlns="http://www.w3.org/2000/svg" class="h-24 w-24 text-green-500" fill="none" viewBox="0 0 24 24"
+      stroke="currentColor" stroke-width="2">
+      <path stroke-linecap="round" stroke-linejoin="round" d="M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z" />
+    </svg>
+  </div>
+  <div class="py-8">
+    <div class="text-center">
+      <p class="text-sm font-semibold text-gray-400 uppercase tracking-wide">Success</p>
+      <h1 class="mt-2 text-3xl font-extrabold text-gray-900 tracking-tight sm:text-4xl">You have been signed out.</h1>
+      {{if .redirect_uri}}
+      <p class="mt-2 text-base text-gray-500">
+        You will be redirected back in a moment. <a href="{{.redirect_uri}}" class="text-blue-600">Continue</a>
+      </p>
+      {{else}}
+      <p class="mt-2 text-base text-gray-500">You can close this page.</p>
+      {{end}}
+    </div>
+  </div>
+
+  {{range .frontchannel_uris}}
+  <iframe src="{{.}}" class="frontchannel-logout hidden" width="0" height="0" title="logout"></iframe>
+  {{end}}
+
+  {{if .redirect_uri}}
+  <script>
+    // the user is redirected once the clients are notified in the iframes,
+    // the slow clients are not waited for longer than a few seconds
+    (function () {
+      var redirectURI = "{{.redirect_uri}}";
+      var frames = document.querySelectorAll("iframe.frontchannel-logout");
+      var pending = frames.length;
+      var redirect = function () { window.location.replace(redirectURI); };
+      frames.forEach(function (f) {
+        f.addEventListener("load", function () { if (--pending === 0) { redirect(); } });
+      });
+      setTimeout(redirect, 3000);
+    })();
+  </script>
+  {{end}}
+</main>
+{{end}}
+{{end}}